@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/cache"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+const (
+	CACHE_TTL_QUERY_EMBEDDING = time.Hour * 24
+	CACHE_TTL_SPACE           = time.Minute * 5
+	CACHE_TTL_RESOURCE        = time.Minute * 5
+	CACHE_TTL_USER_SPACE_ROLE = time.Minute
+)
+
+// setupCache 配置了 [redis] 时使用 Redis 缓存，多节点共享；否则使用进程内 LRU 缓存
+func setupCache(core *Core) {
+	if core.cfg.Redis.Enabled() && core.redisClient != nil {
+		core.cache = cache.NewRedis(core.redisClient)
+		slog.Info("Cache initialized", slog.String("driver", "redis"))
+		return
+	}
+	core.cache = cache.NewLRU(cache.DEFAULT_LRU_SIZE)
+	slog.Info("Cache initialized", slog.String("driver", "lru"))
+}
+
+// cacheKey 为对象缓存的 key 加上配置的 Redis key_prefix，用于隔离共用同一 Redis 的不同环境/应用
+// 仅用于 core 自身缓存的对象，user:token 等由外部服务写入的 key 不加前缀
+func (s *Core) cacheKey(key string) string {
+	if s.cfg.Redis.KeyPrefix == "" {
+		return key
+	}
+	return s.cfg.Redis.KeyPrefix + ":" + key
+}
+
+func spaceCacheKey(spaceID string) string {
+	return fmt.Sprintf("space:info:%s", spaceID)
+}
+
+func resourceCacheKey(spaceID, resourceID string) string {
+	return fmt.Sprintf("space:resource:%s:%s", spaceID, resourceID)
+}
+
+func userSpaceRoleCacheKey(userID, spaceID string) string {
+	return fmt.Sprintf("space:role:%s:%s", spaceID, userID)
+}
+
+func queryEmbeddingCacheKey(model, query string) string {
+	return fmt.Sprintf("embedding:query:%s:%s", model, utils.MD5(query))
+}
+
+// getCachedObject 读取缓存，缓存未命中时通过 load 加载并写入缓存
+// 缓存读写失败只记录日志，不影响数据加载，load 返回的错误(包括 sql.ErrNoRows)原样返回且不缓存
+func getCachedObject[T any](ctx context.Context, c types.Cache, key string, ttl time.Duration, load func() (*T, error)) (*T, error) {
+	var data T
+	ok, err := cache.GetObject(ctx, c, key, &data)
+	if err != nil {
+		slog.Warn("Failed to get object from cache", slog.String("key", key), slog.String("error", err.Error()))
+	}
+	if ok {
+		return &data, nil
+	}
+
+	result, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, sql.ErrNoRows
+	}
+	if err = cache.SetObject(ctx, c, key, result, ttl); err != nil {
+		slog.Warn("Failed to set object to cache", slog.String("key", key), slog.String("error", err.Error()))
+	}
+	return result, nil
+}
+
+func (s *Core) delCache(ctx context.Context, keys ...string) {
+	prefixed := make([]string, 0, len(keys))
+	for _, v := range keys {
+		prefixed = append(prefixed, s.cacheKey(v))
+	}
+	if err := s.Cache().Del(ctx, prefixed...); err != nil {
+		slog.Warn("Failed to delete cache", slog.Any("keys", keys), slog.String("error", err.Error()))
+	}
+}
+
+// GetSpace 获取空间信息，结果会缓存 CACHE_TTL_SPACE，空间不存在时返回 sql.ErrNoRows
+// 修改或删除空间后需调用 InvalidateSpace
+func (s *Core) GetSpace(ctx context.Context, spaceID string) (*types.Space, error) {
+	return getCachedObject(ctx, s.Cache(), s.cacheKey(spaceCacheKey(spaceID)), CACHE_TTL_SPACE, func() (*types.Space, error) {
+		return s.Store().SpaceStore().GetSpace(ctx, spaceID)
+	})
+}
+
+// InvalidateSpace 清除空间信息缓存
+func (s *Core) InvalidateSpace(ctx context.Context, spaceID string) {
+	s.delCache(ctx, spaceCacheKey(spaceID))
+}
+
+// GetResource 获取空间下的资源信息，结果会缓存 CACHE_TTL_RESOURCE，资源不存在时返回 sql.ErrNoRows
+// 修改或删除资源后需调用 InvalidateResource
+func (s *Core) GetResource(ctx context.Context, spaceID, resourceID string) (*types.Resource, error) {
+	return getCachedObject(ctx, s.Cache(), s.cacheKey(resourceCacheKey(spaceID, resourceID)), CACHE_TTL_RESOURCE, func() (*types.Resource, error) {
+		return s.Store().ResourceStore().GetResource(ctx, spaceID, resourceID)
+	})
+}
+
+// InvalidateResource 清除资源信息缓存
+func (s *Core) InvalidateResource(ctx context.Context, spaceID, resourceID string) {
+	s.delCache(ctx, resourceCacheKey(spaceID, resourceID))
+}
+
+// GetUserSpaceRole 获取用户在空间中的角色，结果会缓存 CACHE_TTL_USER_SPACE_ROLE，用户不在空间中时返回 sql.ErrNoRows
+// 不存在的角色不会被缓存，用户加入空间后无需清除缓存；修改角色或移出空间后需调用 InvalidateUserSpaceRole
+func (s *Core) GetUserSpaceRole(ctx context.Context, userID, spaceID string) (*types.UserSpace, error) {
+	return getCachedObject(ctx, s.Cache(), s.cacheKey(userSpaceRoleCacheKey(userID, spaceID)), CACHE_TTL_USER_SPACE_ROLE, func() (*types.UserSpace, error) {
+		return s.Store().UserSpaceStore().GetUserSpaceRole(ctx, userID, spaceID)
+	})
+}
+
+// InvalidateUserSpaceRole 清除用户在空间中的角色缓存
+func (s *Core) InvalidateUserSpaceRole(ctx context.Context, spaceID string, userIDs ...string) {
+	keys := make([]string, 0, len(userIDs))
+	for _, v := range userIDs {
+		keys = append(keys, userSpaceRoleCacheKey(v, spaceID))
+	}
+	if len(keys) > 0 {
+		s.delCache(ctx, keys...)
+	}
+}
+
+// EmbeddingForQuery 生成查询向量，同一模型下相同查询的向量会缓存 CACHE_TTL_QUERY_EMBEDDING
+// 只有未命中缓存的查询会请求模型，全部命中时返回的 Usage 为 nil
+func (s *Core) EmbeddingForQuery(ctx context.Context, queries []string) (ai.EmbeddingResult, error) {
+	var (
+		model  = s.Srv().AI().EmbeddingModel()
+		result = ai.EmbeddingResult{
+			Model: model,
+			Data:  make([][]float32, len(queries)),
+		}
+		missIndex []int
+		missQuery []string
+	)
+	for i, v := range queries {
+		var vector []float32
+		ok, err := cache.GetObject(ctx, s.Cache(), s.cacheKey(queryEmbeddingCacheKey(model, v)), &vector)
+		if err != nil {
+			slog.Warn("Failed to get query embedding from cache", slog.String("error", err.Error()))
+		}
+		if ok && len(vector) > 0 {
+			result.Data[i] = vector
+			continue
+		}
+		missIndex = append(missIndex, i)
+		missQuery = append(missQuery, v)
+	}
+
+	if len(missQuery) == 0 {
+		return result, nil
+	}
+
+	res, err := s.Srv().AI().EmbeddingForQuery(ctx, missQuery)
+	if err != nil {
+		return res, err
+	}
+	if len(res.Data) != len(missQuery) {
+		return res, fmt.Errorf("unexpected embedding result count, expected %d, got %d", len(missQuery), len(res.Data))
+	}
+
+	result.Model = res.Model
+	result.Usage = res.Usage
+	for i, v := range res.Data {
+		result.Data[missIndex[i]] = v
+		if err = cache.SetObject(ctx, s.Cache(), s.cacheKey(queryEmbeddingCacheKey(model, missQuery[i])), v, CACHE_TTL_QUERY_EMBEDDING); err != nil {
+			slog.Warn("Failed to set query embedding to cache", slog.String("error", err.Error()))
+		}
+	}
+	return result, nil
+}
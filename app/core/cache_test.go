@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/app/store/sqlstore"
+	"github.com/quka-ai/quka-ai/pkg/auth"
+	"github.com/quka-ai/quka-ai/pkg/cache"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type cacheSpaceStore struct {
+	store.SpaceStore
+}
+
+func (cacheSpaceStore) GetSpace(ctx context.Context, spaceID string) (*types.Space, error) {
+	return &types.Space{SpaceID: spaceID}, nil
+}
+
+func TestCacheKeyPrefix(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		cfg   CoreConfig
+		token = "access-token"
+	)
+	cfg.Redis.KeyPrefix = "quka"
+	stores := sqlstore.NewProviderWithStores(&sqlstore.Stores{SpaceStore: cacheSpaceStore{}})
+	c := NewCore(cfg, stores, srv.SetupSrvs(), cache.NewLRU(cache.DEFAULT_LRU_SIZE))
+
+	// 外部认证服务写入的 token 不带前缀，配置前缀后仍能校验通过
+	meta, _ := json.Marshal(types.UserTokenMeta{UserID: "user", Appid: "app"})
+	if err := c.Cache().SetEx(ctx, fmt.Sprintf("user:token:%s", utils.MD5(token)), string(meta), CACHE_TTL_SPACE); err != nil {
+		t.Fatal(err)
+	}
+	tokenMeta, err := auth.ValidateTokenFromCache(ctx, token, c.Cache())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokenMeta.UserID != "user" {
+		t.Errorf("expected token of user, got %q", tokenMeta.UserID)
+	}
+
+	// core 缓存的对象带前缀
+	if _, err = c.GetSpace(ctx, "space"); err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := c.Cache().Get(ctx, "quka:space:info:space"); val == "" {
+		t.Error("expected space to be cached with prefix")
+	}
+	if val, _ := c.Cache().Get(ctx, "space:info:space"); val != "" {
+		t.Error("expected no unprefixed space cache")
+	}
+
+	c.InvalidateSpace(ctx, "space")
+	if val, _ := c.Cache().Get(ctx, "quka:space:info:space"); val != "" {
+		t.Error("expected prefixed space cache to be invalidated")
+	}
+}
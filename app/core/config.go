@@ -183,6 +183,14 @@ type RedisConfig struct {
 	KeyPrefix string `toml:"key_prefix"` // Redis键前缀，用于隔离不同环境/应用
 }
 
+// Enabled 是否配置了 Redis 地址
+func (r RedisConfig) Enabled() bool {
+	if r.Cluster {
+		return len(r.ClusterAddrs) > 0
+	}
+	return r.Addr != ""
+}
+
 func (r *RedisConfig) FromENV() {
 	r.Addr = os.Getenv("QUKA_REDIS_ADDR")
 	r.Password = os.Getenv("QUKA_REDIS_PASSWORD")
@@ -35,6 +35,7 @@ type Core struct {
 
 	stores           func() *sqlstore.Provider
 	redisClient      redis.UniversalClient
+	cache            types.Cache
 	asynqClient      *asynq.Client
 	httpClient       *http.Client
 	httpEngine       *gin.Engine
@@ -87,9 +88,26 @@ func MustSetupCore(cfg CoreConfig) *Core {
 	// setup redis
 	setupRedis(core)
 
+	// setup cache
+	setupCache(core)
+
 	return core
 }
 
+// NewCore 使用已创建的 store、服务与缓存组装 Core，不连接数据库与 Redis，用于在测试中替换依赖
+// 插件需通过 Plugins 字段另行设置
+func NewCore(cfg CoreConfig, stores *sqlstore.Provider, srv *srv.Srv, cache types.Cache) *Core {
+	return &Core{
+		cfg:           cfg,
+		srv:           srv,
+		stores:        func() *sqlstore.Provider { return stores },
+		cache:         cache,
+		httpClient:    &http.Client{Timeout: time.Second * 3},
+		prompt:        cfg.Prompt,
+		promptManager: ai.NewPromptManager(&ai.PromptConfig{}, ai.MODEL_BASE_LANGUAGE_CN),
+	}
+}
+
 // loadAIConfigFromDB 从数据库加载AI配置的公共方法
 func (s *Core) loadAIConfigFromDB(ctx context.Context) ([]types.ModelConfig, []types.ModelProvider, srv.Usage, error) {
 	statusEnabled := types.StatusEnabled
@@ -221,9 +239,7 @@ func (s *Core) Redis() redis.UniversalClient {
 }
 
 func (s *Core) Cache() types.Cache {
-	return &Cache{
-		redis: s.redisClient,
-	}
+	return s.cache
 }
 
 func (s *Core) Asynq() *asynq.Client {
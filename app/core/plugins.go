@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 
 	"github.com/quka-ai/quka-ai/pkg/ai"
 	"github.com/quka-ai/quka-ai/pkg/object-storage/s3"
@@ -26,7 +25,9 @@ type Plugins interface {
 	EncryptData(data []byte) ([]byte, error)
 	DecryptData(data []byte) ([]byte, error)
 	DeleteSpace(ctx context.Context, spaceID string) error
-	Rerank(query string, knowledges []*types.Knowledge) ([]*types.Knowledge, *ai.Usage, error)
+	// Rerank 对召回的知识重新排序，similarity 为各知识在向量召回阶段的最高相似度，可为空
+	// 远程重排模型的得分截断阈值由 profile 控制
+	Rerank(query string, knowledges []*types.Knowledge, similarity map[string]float32, profile types.RetrievalProfile) ([]*types.Knowledge, *ai.Usage, error)
 	AppendKnowledgeContentToDocs(docs []*types.PassageInfo, knowledges []*types.Knowledge) ([]*types.PassageInfo, error)
 }
 
@@ -85,25 +86,5 @@ func (c *Core) InstallPlugins(p Plugins) {
 	// after plugins installed
 	SetupSrv(c)
 	// 为 sqlstore.Provider 设置 cache 函数
-	c.stores().SetCacheFunc(func() types.Cache {
-		return &Cache{
-			redis: c.Redis(),
-		}
-	})
-}
-
-type Cache struct {
-	redis redis.UniversalClient
-}
-
-func (c *Cache) Expire(ctx context.Context, key string, expiration time.Duration) error {
-	return c.redis.Expire(ctx, key, expiration).Err()
-}
-
-func (c *Cache) SetEx(ctx context.Context, key, value string, expiresAt time.Duration) error {
-	return c.redis.SetEx(ctx, key, value, expiresAt).Err()
-}
-
-func (c *Cache) Get(ctx context.Context, key string) (string, error) {
-	return c.redis.Get(ctx, key).Result()
+	c.stores().SetCacheFunc(c.Cache)
 }
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// SystemRetrievalProfile 获取系统默认的检索参数，custom_config 中未配置的字段使用内置默认值
+func (s *Core) SystemRetrievalProfile(ctx context.Context) types.RetrievalProfile {
+	profile := types.DefaultRetrievalProfile()
+
+	config, err := s.Store().CustomConfigStore().Get(ctx, types.RETRIEVAL_PROFILE_CONFIG_NAME)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			slog.Error("Failed to get system retrieval profile", slog.String("error", err.Error()))
+		}
+		return profile
+	}
+
+	if config.Status != types.StatusEnabled || len(config.Value) == 0 {
+		return profile
+	}
+
+	var custom types.RetrievalProfileConfig
+	if err = json.Unmarshal(config.Value, &custom); err != nil {
+		slog.Error("Failed to unmarshal system retrieval profile", slog.String("error", err.Error()))
+		return profile
+	}
+	return custom.Apply(profile)
+}
+
+// RetrievalProfile 获取空间生效的检索参数，空间未设置的字段使用系统默认值
+func (s *Core) RetrievalProfile(ctx context.Context, spaceID string) types.RetrievalProfile {
+	profile := s.SystemRetrievalProfile(ctx)
+	if spaceID == "" {
+		return profile
+	}
+
+	space, err := s.GetSpace(ctx, spaceID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			slog.Error("Failed to get space retrieval profile", slog.String("space_id", spaceID), slog.String("error", err.Error()))
+		}
+		return profile
+	}
+
+	if space.Settings.RetrievalProfile == nil {
+		return profile
+	}
+	return space.Settings.RetrievalProfile.Apply(profile)
+}
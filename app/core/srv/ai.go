@@ -17,10 +17,12 @@ import (
 
 	"github.com/quka-ai/quka-ai/pkg/ai"
 	"github.com/quka-ai/quka-ai/pkg/ai/baidu"
+	"github.com/quka-ai/quka-ai/pkg/ai/bm25"
 	"github.com/quka-ai/quka-ai/pkg/ai/fusion"
 	"github.com/quka-ai/quka-ai/pkg/ai/jina"
 	"github.com/quka-ai/quka-ai/pkg/ai/volcengine/voice"
 	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/reader/readability"
 	"github.com/quka-ai/quka-ai/pkg/types"
 )
 
@@ -57,6 +59,8 @@ type AIDriver interface {
 	DescribeImage(ctx context.Context, lang, imageURL string) (*DescribeImageResult, error)
 	MsgIsOverLimit(msgs []*types.MessageContext) bool
 	GetConfig(modelType string) types.ModelConfig
+	EmbeddingModel() string
+	RerankModel() string
 	GetChatAI(needsThinking bool) types.ChatModel
 	GetVisionAI() types.ChatModel
 	GetEnhanceAI() types.ChatModel
@@ -106,9 +110,11 @@ type AI struct {
 	chatThinkingDefault types.ChatModel // 思考聊天模型
 	enhanceDefault      types.ChatModel
 	embedDefault        EmbeddingAI
+	embedDefaultModel   string
 	readerDefault       ReaderAI
 	visionDefault       types.ChatModel
 	rerankDefault       RerankAI
+	rerankDefaultModel  string
 	ocrDefault          OCRAI
 
 	allModels map[string]types.ModelConfig
@@ -281,6 +287,17 @@ func (s *AI) Rerank(ctx context.Context, query string, docs []*ai.RerankDoc) ([]
 	return s.rerankDefault.Rerank(ctx, query, docs)
 }
 
+// RerankModel 返回当前生效的重排模型名称，使用内置本地重排时返回 bm25.NAME
+func (s *AI) RerankModel() string {
+	if s.usage.Rerank == types.RERANK_MODEL_BUILTIN_BM25 {
+		return bm25.NAME
+	}
+	if s.rerankDrivers[s.usage.Rerank] != nil {
+		return s.allModels[s.usage.Rerank].ModelName
+	}
+	return s.rerankDefaultModel
+}
+
 func (s *AI) EmbeddingForQuery(ctx context.Context, content []string) (ai.EmbeddingResult, error) {
 	if d := s.embedDrivers[s.usage.Embedding]; d != nil {
 		return d.EmbeddingForQuery(ctx, content)
@@ -288,6 +305,14 @@ func (s *AI) EmbeddingForQuery(ctx context.Context, content []string) (ai.Embedd
 	return s.embedDefault.EmbeddingForQuery(ctx, content)
 }
 
+// EmbeddingModel 返回当前生效的 embedding 模型名称，向量数据以此进行标记与过滤
+func (s *AI) EmbeddingModel() string {
+	if s.embedDrivers[s.usage.Embedding] != nil {
+		return s.allModels[s.usage.Embedding].ModelName
+	}
+	return s.embedDefaultModel
+}
+
 func (s *AI) EmbeddingForDocument(ctx context.Context, title string, content []string) (ai.EmbeddingResult, error) {
 	if d := s.embedDrivers[s.usage.Embedding]; d != nil {
 		return d.EmbeddingForDocument(ctx, title, content)
@@ -394,6 +419,31 @@ func (s *AI) Chunk(ctx context.Context, doc *string) (*ai.ChunkResult, error) {
 			Desc:     "用户内容中提到的时间，格式为'年-月-日 时:分'。如果无法提取时间，则留空。",
 			Required: false,
 		},
+		"entities": {
+			Type: schema.Array,
+			Desc: "内容中提及的重要命名实体。",
+			ElemInfo: &schema.ParameterInfo{
+				Type: schema.Object,
+				SubParams: map[string]*schema.ParameterInfo{
+					"name": {Type: schema.String, Desc: "实体名称，与原文中的写法保持一致。", Required: true},
+					"type": {Type: schema.String, Desc: "实体类型。", Enum: []string{"person", "organization", "location", "product", "project", "technology", "event", "concept"}, Required: true},
+				},
+			},
+			Required: false,
+		},
+		"relations": {
+			Type: schema.Array,
+			Desc: "内容中明确描述的实体之间的关系。",
+			ElemInfo: &schema.ParameterInfo{
+				Type: schema.Object,
+				SubParams: map[string]*schema.ParameterInfo{
+					"source":   {Type: schema.String, Desc: "主体实体名称。", Required: true},
+					"target":   {Type: schema.String, Desc: "客体实体名称。", Required: true},
+					"relation": {Type: schema.String, Desc: "简短的关系描述，如'创立'、'属于'。", Required: true},
+				},
+			},
+			Required: false,
+		},
 	}
 
 	// 创建工具信息
@@ -452,14 +502,22 @@ type ReaderProvider interface {
 
 type ReaderProviderRegistry struct {
 	providers []ReaderProvider
+	fallback  ReaderProvider // 未配置 reader 提供商时使用
 }
 
-var rpr = &ReaderProviderRegistry{}
+var rpr = &ReaderProviderRegistry{
+	fallback: readability.NewReader(),
+}
 
 func RegisterReaderProvider(provider ReaderProvider) {
 	rpr.providers = append(rpr.providers, provider)
 }
 
+// RegisterFallbackReaderProvider 替换未配置 reader 提供商时使用的兜底实现，默认为内置的网页正文提取
+func RegisterFallbackReaderProvider(provider ReaderProvider) {
+	rpr.fallback = provider
+}
+
 // Option Feature
 func (s *AI) Reader(ctx context.Context, endpoint string) (*ai.ReaderResult, error) {
 	for _, v := range rpr.providers {
@@ -472,10 +530,14 @@ func (s *AI) Reader(ctx context.Context, endpoint string) (*ai.ReaderResult, err
 		return d.Reader(ctx, endpoint)
 	}
 
-	if s.readerDefault == nil {
-		return nil, errors.ERROR_UNSUPPORTED_FEATURE
+	if s.readerDefault != nil {
+		return s.readerDefault.Reader(ctx, endpoint)
+	}
+
+	if rpr.fallback != nil && rpr.fallback.Match(endpoint) {
+		return rpr.fallback.Reader(ctx, endpoint)
 	}
-	return s.readerDefault.Reader(ctx, endpoint)
+	return nil, errors.ERROR_UNSUPPORTED_FEATURE
 }
 
 type Usage struct {
@@ -558,12 +620,18 @@ func SetupAI(models []types.ModelConfig, modelProviders []types.ModelProvider, u
 		rerankDrivers:       make(map[string]RerankAI),
 		ocrDrivers:          make(map[string]OCRAI),
 
+		// 未配置重排模型时使用内置的本地重排
+		rerankDefault:      bm25.New(),
+		rerankDefaultModel: bm25.NAME,
+
 		allModels: lo.SliceToMap(models, func(item types.ModelConfig) (string, types.ModelConfig) {
 			return item.ID, item
 		}),
 		usage: usage,
 	}
 
+	a.rerankDrivers[types.RERANK_MODEL_BUILTIN_BM25] = a.rerankDefault
+
 	// 设置模型配置
 	for _, v := range models {
 		if v.Provider == nil {
@@ -595,10 +663,12 @@ func SetupAI(models []types.ModelConfig, modelProviders []types.ModelProvider, u
 			d := fusion.New(v.Provider.ApiKey, v.Provider.ApiUrl, v.ModelName)
 			a.embedDrivers[v.ID] = d
 			a.embedDefault = d
+			a.embedDefaultModel = v.ModelName
 		case types.MODEL_TYPE_RERANK:
 			d := fusion.New(v.Provider.ApiKey, v.Provider.ApiUrl, v.ModelName)
 			a.rerankDrivers[v.ID] = d
 			a.rerankDefault = d
+			a.rerankDefaultModel = v.ModelName
 		case types.MODEL_TYPE_VISION:
 			d, err := SetupAIDriver(context.Background(), v)
 			if err != nil {
@@ -694,6 +764,17 @@ func ApplyAI(providers []types.ModelConfig, modelProviders []types.ModelProvider
 	}
 }
 
+// ApplyEmbeddingAI 使用指定的向量模型驱动作为默认向量模型，用于在测试中替换模型调用
+func ApplyEmbeddingAI(model string, driver EmbeddingAI) ApplyFunc {
+	return func(s *Srv) {
+		if s.ai == nil {
+			s.ai = &AI{}
+		}
+		s.ai.embedDefault = driver
+		s.ai.embedDefaultModel = model
+	}
+}
+
 func ApplyPodcast(cfg volcenginePodcast) ApplyFunc {
 	return func(s *Srv) {
 		if cfg.Appid != "" {
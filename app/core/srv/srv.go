@@ -64,7 +64,9 @@ func (s *Srv) GetAIStatus() map[string]interface{} {
 		"embed_available":   s.ai.embedDefault != nil,
 		"vision_available":  s.ai.visionDefault != nil,
 		"rerank_available":  s.ai.rerankDefault != nil,
-		"reader_available":  s.ai.readerDefault != nil,
+		"rerank_model":      s.ai.RerankModel(),
+		"rerank_builtin":    types.RERANK_MODEL_BUILTIN_BM25, // 可用于 AI 使用配置 rerank 的内置本地重排
+		"reader_available":  s.ai.readerDefault != nil || rpr.fallback != nil,
 		"enhance_available": s.ai.enhanceDefault != nil,
 	}
 }
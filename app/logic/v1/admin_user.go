@@ -315,8 +315,9 @@ func (l *AdminUserLogic) DeleteUser(userID string) error {
 		return errors.New("AdminUserLogic.DeleteUser", i18n.ERROR_INTERNAL, err)
 	}
 
-	// 使用事务进行级联删除
-	return l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
+	// 使用事务进行级联删除，缓存在事务提交后再清除，避免提交前被并发请求重新写入旧数据
+	invalidation := &spaceCacheInvalidation{}
+	err = l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
 		// 1. 删除用户的全局角色记录
 		if err := l.core.Store().UserGlobalRoleStore().Delete(ctx, user.Appid, userID); err != nil {
 			return fmt.Errorf("failed to delete user global role: %w", err)
@@ -328,7 +329,7 @@ func (l *AdminUserLogic) DeleteUser(userID string) error {
 		}
 
 		// 3. 获取用户创建或拥有的空间，进行级联删除
-		if err := l.deleteUserSpaces(ctx, userID); err != nil {
+		if err := l.deleteUserSpaces(ctx, userID, invalidation); err != nil {
 			return fmt.Errorf("failed to delete user spaces: %w", err)
 		}
 
@@ -354,6 +355,34 @@ func (l *AdminUserLogic) DeleteUser(userID string) error {
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	invalidation.apply(l.ctx, l.core)
+	return nil
+}
+
+// spaceCacheInvalidation 记录事务中删除的空间与成员关系，事务提交后统一清除对应缓存
+type spaceCacheInvalidation struct {
+	spaces []string
+	roles  map[string][]string // spaceID -> userIDs
+}
+
+func (c *spaceCacheInvalidation) addRoles(spaceID string, userIDs ...string) {
+	if c.roles == nil {
+		c.roles = make(map[string][]string)
+	}
+	c.roles[spaceID] = append(c.roles[spaceID], userIDs...)
+}
+
+func (c *spaceCacheInvalidation) apply(ctx context.Context, core *core.Core) {
+	for _, v := range c.spaces {
+		core.InvalidateSpace(ctx, v)
+	}
+	for spaceID, userIDs := range c.roles {
+		core.InvalidateUserSpaceRole(ctx, spaceID, userIDs...)
+	}
 }
 
 // deleteUserAccessTokens 删除用户的所有访问令牌
@@ -367,7 +396,7 @@ func (l *AdminUserLogic) deleteUserAccessTokens(ctx context.Context, userID stri
 }
 
 // deleteUserSpaces 删除用户创建的空间和相关数据
-func (l *AdminUserLogic) deleteUserSpaces(ctx context.Context, userID string) error {
+func (l *AdminUserLogic) deleteUserSpaces(ctx context.Context, userID string, invalidation *spaceCacheInvalidation) error {
 	// 获取用户所属的所有空间
 	userSpaces, err := l.core.Store().UserSpaceStore().List(ctx, types.ListUserSpaceOptions{
 		UserID: userID,
@@ -380,7 +409,7 @@ func (l *AdminUserLogic) deleteUserSpaces(ctx context.Context, userID string) er
 		// 对于用户作为chief的空间，需要完全删除空间
 		if userSpace.Role == SpaceChiefRole {
 			// 删除空间下的所有资源、知识库等
-			if err := l.deleteSpaceData(ctx, userSpace.SpaceID); err != nil {
+			if err := l.deleteSpaceData(ctx, userSpace.SpaceID, invalidation); err != nil {
 				return fmt.Errorf("failed to delete space %s data: %w", userSpace.SpaceID, err)
 			}
 
@@ -388,11 +417,13 @@ func (l *AdminUserLogic) deleteUserSpaces(ctx context.Context, userID string) er
 			if err := l.core.Store().SpaceStore().Delete(ctx, userSpace.SpaceID); err != nil {
 				return fmt.Errorf("failed to delete space %s: %w", userSpace.SpaceID, err)
 			}
+			invalidation.spaces = append(invalidation.spaces, userSpace.SpaceID)
 		} else {
 			// 对于用户只是成员的空间，只删除用户与空间的关系
 			if err := l.core.Store().UserSpaceStore().Delete(ctx, userSpace.UserID, userSpace.SpaceID); err != nil {
 				return fmt.Errorf("failed to delete user space relation: %w", err)
 			}
+			invalidation.addRoles(userSpace.SpaceID, userSpace.UserID)
 		}
 	}
 
@@ -400,7 +431,7 @@ func (l *AdminUserLogic) deleteUserSpaces(ctx context.Context, userID string) er
 }
 
 // deleteSpaceData 删除空间下的所有数据
-func (l *AdminUserLogic) deleteSpaceData(ctx context.Context, spaceID string) error {
+func (l *AdminUserLogic) deleteSpaceData(ctx context.Context, spaceID string, invalidation *spaceCacheInvalidation) error {
 	// 1. 删除空间下的所有知识库相关数据
 	if err := l.deleteSpaceKnowledgeData(ctx, spaceID); err != nil {
 		return fmt.Errorf("failed to delete space knowledge data: %w", err)
@@ -417,9 +448,14 @@ func (l *AdminUserLogic) deleteSpaceData(ctx context.Context, spaceID string) er
 	}
 
 	// 4. 最后删除空间下的所有用户关系
+	members, err := l.core.Store().UserSpaceStore().ListSpaceUsers(ctx, spaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to list space users: %w", err)
+	}
 	if err := l.core.Store().UserSpaceStore().DeleteAll(ctx, spaceID); err != nil {
 		return fmt.Errorf("failed to delete space user relations: %w", err)
 	}
+	invalidation.addRoles(spaceID, members...)
 
 	return nil
 }
@@ -446,6 +482,20 @@ func (l *AdminUserLogic) deleteSpaceKnowledgeData(ctx context.Context, spaceID s
 		return fmt.Errorf("failed to delete knowledge meta: %w", err)
 	}
 
+	// 删除知识图谱数据
+	if err := l.core.Store().KnowledgeEntityMentionStore().DeleteAll(ctx, spaceID); err != nil {
+		return fmt.Errorf("failed to delete knowledge entity mentions: %w", err)
+	}
+	if err := l.core.Store().KnowledgeEntityStore().DeleteAll(ctx, spaceID); err != nil {
+		return fmt.Errorf("failed to delete knowledge entities: %w", err)
+	}
+	if err := l.core.Store().KnowledgeRelationStore().DeleteAll(ctx, spaceID); err != nil {
+		return fmt.Errorf("failed to delete knowledge relations: %w", err)
+	}
+	if err := l.core.Store().KnowledgeLinkStore().DeleteAll(ctx, spaceID); err != nil {
+		return fmt.Errorf("failed to delete knowledge links: %w", err)
+	}
+
 	// 删除内容任务
 	if err := l.core.Store().ContentTaskStore().DeleteAll(ctx, spaceID); err != nil {
 		return fmt.Errorf("failed to delete content tasks: %w", err)
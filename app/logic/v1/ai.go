@@ -24,6 +24,7 @@ import (
 	"github.com/quka-ai/quka-ai/pkg/ai"
 	"github.com/quka-ai/quka-ai/pkg/ai/agents/butler"
 	"github.com/quka-ai/quka-ai/pkg/ai/agents/journal"
+	"github.com/quka-ai/quka-ai/pkg/ai/agents/rag"
 	"github.com/quka-ai/quka-ai/pkg/errors"
 	"github.com/quka-ai/quka-ai/pkg/i18n"
 	"github.com/quka-ai/quka-ai/pkg/mark"
@@ -526,8 +527,9 @@ func (s *ButlerAssistant) RequestAssistant(ctx context.Context, reqMsg *types.Ch
 	enableThinking := aiCallOptions.EnableThinking
 	enableWebSearch := aiCallOptions.EnableSearch
 
+	citations := rag.NewCitationCollector()
 	agentCtx := types.NewAgentContextWithOptions(
-		ctx,
+		rag.WithCitationCollector(ctx, citations),
 		receiver,
 		reqMsg.SpaceID,
 		reqMsg.UserID,
@@ -559,6 +561,7 @@ func (s *ButlerAssistant) RequestAssistant(ctx context.Context, reqMsg *types.Ch
 	// 从这里开始，错误处理交给具体的 handler 方法
 	// 8. 创建响应处理器
 	responseHandler := NewEinoResponseHandler(receiver, reqMsg)
+	responseHandler.OnComplete(saveMessageCitations(s.core, citations))
 	callbackHandler := NewCallbackHandlers(s.core, modelConfig.ModelName, responseHandler)
 
 	// 9. 执行推理
@@ -688,8 +691,9 @@ func (s *JournalAssistant) RequestAssistant(ctx context.Context, reqMsg *types.C
 	enableThinking := aiCallOptions.EnableThinking
 	enableWebSearch := aiCallOptions.EnableSearch
 
+	citations := rag.NewCitationCollector()
 	agentCtx := types.NewAgentContextWithOptions(
-		ctx,
+		rag.WithCitationCollector(ctx, citations),
 		receiver,
 		reqMsg.SpaceID,
 		reqMsg.UserID,
@@ -721,6 +725,7 @@ func (s *JournalAssistant) RequestAssistant(ctx context.Context, reqMsg *types.C
 	// 从这里开始，错误处理交给具体的 handler 方法
 	// 8. 创建响应处理器
 	responseHandler := NewEinoResponseHandler(receiver, reqMsg)
+	responseHandler.OnComplete(saveMessageCitations(s.core, citations))
 	callbackHandler := NewCallbackHandlers(s.core, modelConfig.ModelName, responseHandler)
 
 	// 9. 执行推理
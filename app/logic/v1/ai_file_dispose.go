@@ -122,7 +122,11 @@ func (l *AIFileDisposeLogic) DeleteTask(taskID string) error {
 	})
 }
 
-func (l *AIFileDisposeLogic) CreateLongContentTask(spaceID, resource, meta, fileName, fileURL string) error {
+func (l *AIFileDisposeLogic) CreateLongContentTask(spaceID, resource, meta, fileName, fileURL, chunker string) error {
+	if err := (types.ChunkerConfig{Mode: chunker}).Validate(); err != nil {
+		return errors.New("AIFileDisposeLogic.CreateLongContentTask.Chunker", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+	}
+
 	taskID := utils.GenUniqIDStr()
 
 	parsedUrl, err := url.Parse(fileURL)
@@ -149,6 +153,7 @@ func (l *AIFileDisposeLogic) CreateLongContentTask(spaceID, resource, meta, file
 		FileName:  fileName,
 		Step:      types.LONG_CONTENT_STEP_CREATE_CHUNK,
 		TaskType:  "chunk",
+		Chunker:   chunker,
 		CreatedAt: time.Now().Unix(),
 		UpdatedAt: time.Now().Unix(),
 	})
@@ -194,6 +199,7 @@ type ChunkTaskDetail struct {
 	UserEmail     string `json:"user_email"`
 	Status        int    `json:"status" db:"status"`
 	TaskType      string `json:"task_type" db:"task_type"`   // 任务类型，表示任务的目的或用途
+	Chunker       string `json:"chunker" db:"chunker"`       // 分片方式，为空时使用空间的分片配置
 	CreatedAt     int64  `json:"created_at" db:"created_at"` // 任务创建时间，时间戳格式
 	UpdatedAt     int64  `json:"updated_at" db:"updated_at"` // 任务创建时间，时间戳格式
 	RetryTimes    int    `json:"retry_times" db:"retry_times"`
@@ -258,6 +264,7 @@ func (l *AIFileDisposeLogic) GetLongContentTaskList(spaceID string, page, pagesi
 			Status:        convertTaskStepToStatus(item.Step, item.RetryTimes),
 			RetryTimes:    item.RetryTimes,
 			TaskType:      item.TaskType,
+			Chunker:       item.Chunker,
 			CreatedAt:     item.CreatedAt,
 			UpdatedAt:     item.UpdatedAt,
 		}
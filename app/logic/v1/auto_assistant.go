@@ -108,7 +108,7 @@ func (a *AutoAssistant) GenSessionContext(ctx context.Context, prompt string, re
 // 实现与 NormalAssistant 相同的接口，但内部使用 eino ReAct Agent
 func (a *AutoAssistant) RequestAssistant(ctx context.Context, reqMsg *types.ChatMessage, receiver types.Receiver, aiCallOptions *types.AICallOptions) error {
 	// 1. 获取空间信息
-	space, err := a.core.Store().SpaceStore().GetSpace(ctx, reqMsg.SpaceID)
+	space, err := a.core.GetSpace(ctx, reqMsg.SpaceID)
 	if err != nil {
 		return HandleAssistantEarlyError(err, reqMsg, receiver, "获取空间信息失败")
 	}
@@ -126,8 +126,9 @@ func (a *AutoAssistant) RequestAssistant(ctx context.Context, reqMsg *types.Chat
 	}
 
 	// 4. 创建 AgentContext - 提取思考和搜索配置
+	citations := rag.NewCitationCollector()
 	agentCtx := types.NewAgentContextWithOptions(
-		ctx,
+		rag.WithCitationCollector(ctx, citations),
 		receiver,
 		reqMsg.SpaceID,
 		reqMsg.UserID,
@@ -158,6 +159,7 @@ func (a *AutoAssistant) RequestAssistant(ctx context.Context, reqMsg *types.Chat
 	// 从这里开始，错误处理交给具体的 handler 方法
 	// 创建响应处理器（传入数据库写入函数）
 	responseHandler := NewEinoResponseHandler(receiver, reqMsg)
+	responseHandler.OnComplete(saveMessageCitations(a.core, citations))
 	callbackHandler := NewCallbackHandlers(a.core, modelConfig.ModelName, responseHandler)
 
 	// 10. 执行推理
@@ -382,6 +384,7 @@ func (nt *NotifyingTool) InvokableRun(ctx context.Context, argumentsInJSON strin
 
 	// 执行实际工具
 	startTime := time.Now()
+	ctx = context.WithValue(ctx, types.ToolMessageIDKey{}, nt.receiver.MessageID())
 	result, err := nt.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
 	duration := time.Since(startTime)
 	if err != nil {
@@ -934,12 +937,32 @@ func (f *EinoAgentFactory) CreateReActAgentWithConfig(config *AgentConfig) (*rea
 	return agent, &modelConfig, nil
 }
 
+// saveMessageCitations 返回回答生成结束后解析并保存回答中引用标记的回调
+func saveMessageCitations(core *core.Core, collector *rag.CitationCollector) func(msg *types.ChatMessage) {
+	return func(msg *types.ChatMessage) {
+		if msg == nil {
+			return
+		}
+		citations := collector.Resolve(msg.Message)
+		if len(citations) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		if err := core.Store().ChatMessageExtStore().UpdateCitations(ctx, msg.SpaceID, msg.ID, citations); err != nil {
+			slog.Error("Failed to save message citations", slog.String("msg_id", msg.ID), slog.String("error", err.Error()))
+		}
+	}
+}
+
 // EinoResponseHandler 处理 eino Agent 的响应
 type EinoResponseHandler struct {
 	_receiveFunc types.ReceiveFunc
 	_doneFunc    types.DoneFunc
 	_receiver    types.Receiver
 	reqMsg       *types.ChatMessage
+	onComplete   func(msg *types.ChatMessage)
 }
 
 // NewEinoResponseHandler 创建响应处理器
@@ -969,11 +992,25 @@ func (h *EinoResponseHandler) GetReceiveFunc() types.ReceiveFunc {
 
 func (h *EinoResponseHandler) GetDoneFunc(callback func(msg *types.ChatMessage)) types.DoneFunc {
 	if h._doneFunc == nil {
+		if callback == nil && h.onComplete != nil {
+			onComplete := h.onComplete
+			// 非流式的 receiver 结束时以 nil 调用回调，此时没有可处理的回答
+			callback = func(msg *types.ChatMessage) {
+				if msg != nil {
+					onComplete(msg)
+				}
+			}
+		}
 		h._doneFunc = h._receiver.GetDoneFunc(callback)
 	}
 	return h._doneFunc
 }
 
+// OnComplete 设置每条助手回答生成结束后的回调，未指定回调的 GetDoneFunc 调用会使用该回调
+func (h *EinoResponseHandler) OnComplete(callback func(msg *types.ChatMessage)) {
+	h.onComplete = callback
+}
+
 // HandleStreamResponse 处理 eino Agent 的流式响应，返回 ResponseChoice 通道以兼容现有接口
 func (h *EinoResponseHandler) HandleStreamResponse(ctx context.Context, stream *schema.StreamReader[*model.CallbackOutput], needToCreateMessage func(ctx context.Context) error) error {
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/quka-ai/quka-ai/app/core"
 	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/ai/agents/rag"
 	"github.com/quka-ai/quka-ai/pkg/types"
 )
 
@@ -568,3 +569,28 @@ func (m *MockInvokableTool) InvokableRun(ctx context.Context, argumentsInJSON st
 	m.callCount++
 	return "mock result", nil
 }
+
+func TestEinoResponseHandler_DoneWithNonStreamReceiver(t *testing.T) {
+	receiver := &QueryReceiveHandler{
+		ctx:  context.Background(),
+		resp: make(chan types.MessageContent, 1),
+	}
+	handler := NewEinoResponseHandler(receiver, nil)
+
+	called := false
+	handler.OnComplete(func(msg *types.ChatMessage) {
+		called = true
+		_ = msg.Message
+	})
+
+	// QueryReceiveHandler 的 done 以 nil 调用回调，不应触发 OnComplete
+	if err := handler.GetDoneFunc(nil)(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called {
+		t.Error("expected OnComplete not to be called without message")
+	}
+
+	// 引用保存回调本身也需要容忍空消息
+	saveMessageCitations(nil, rag.NewCitationCollector())(nil)
+}
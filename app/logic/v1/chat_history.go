@@ -34,14 +34,17 @@ type RelDoc struct {
 }
 
 type ChatMessageExt struct {
-	MessageID        string                     `json:"message_id"`
-	SessionID        string                     `json:"session_id"`
-	Evaluate         types.EvaluateType         `json:"evaluate"`
-	GenerationStatus types.GenerationStatusType `json:"generation_status"`
-	RelDocs          []RelDoc                   `json:"rel_docs"` // relevance docs
-	ToolName         string                     `json:"tool_name"`
-	ToolArgs         string                     `json:"tool_args"`
-	Marks            map[string]string          `json:"marks"`
+	MessageID        string                      `json:"message_id"`
+	SessionID        string                      `json:"session_id"`
+	Evaluate         types.EvaluateType          `json:"evaluate"`
+	GenerationStatus types.GenerationStatusType  `json:"generation_status"`
+	RelDocs          []RelDoc                    `json:"rel_docs"` // relevance docs
+	ToolName         string                      `json:"tool_name"`
+	ToolArgs         string                      `json:"tool_args"`
+	QueryStrategy    string                      `json:"query_strategy,omitempty"`
+	ExpandedQueries  []string                    `json:"expanded_queries,omitempty"`
+	Citations        []types.ChatMessageCitation `json:"citations,omitempty"`
+	Marks            map[string]string           `json:"marks"`
 }
 
 func (l *HistoryLogic) GetMessageExt(spaceID, sessionID, messageID string) (*ChatMessageExt, error) {
@@ -63,6 +66,9 @@ func (l *HistoryLogic) GetMessageExt(spaceID, sessionID, messageID string) (*Cha
 	result.GenerationStatus = data.GenerationStatus
 	result.ToolName = data.ToolName
 	result.ToolArgs = data.ToolArgs.String
+	result.QueryStrategy = data.QueryStrategy
+	result.ExpandedQueries = data.ExpandedQueries
+	result.Citations = data.Citations
 
 	if len(data.RelDocs) > 0 {
 		docs, err := l.core.Store().KnowledgeStore().ListKnowledges(l.ctx, types.GetKnowledgeOptions{
@@ -92,12 +98,15 @@ type MessageDetail struct {
 }
 
 type MessageExt struct {
-	IsRead           []string           `json:"is_read"`
-	RelDocs          []RelDoc           `json:"rel_docs"`
-	Evaluate         types.EvaluateType `json:"evaluate"`
-	ToolName         string             `json:"tool_name"`
-	ToolArgs         string             `json:"tool_args"`
-	IsEvaluateEnable bool               `json:"is_evaluate_enable"`
+	IsRead           []string                    `json:"is_read"`
+	RelDocs          []RelDoc                    `json:"rel_docs"`
+	Evaluate         types.EvaluateType          `json:"evaluate"`
+	ToolName         string                      `json:"tool_name"`
+	ToolArgs         string                      `json:"tool_args"`
+	QueryStrategy    string                      `json:"query_strategy,omitempty"`
+	ExpandedQueries  []string                    `json:"expanded_queries,omitempty"`
+	Citations        []types.ChatMessageCitation `json:"citations,omitempty"`
+	IsEvaluateEnable bool                        `json:"is_evaluate_enable"`
 }
 
 func (l *HistoryLogic) GetHistoryMessage(spaceID, sessionID string, afterMsgSequence int64, page, pageSize uint64) ([]*MessageDetail, int64, error) {
@@ -192,6 +201,9 @@ func (l *HistoryLogic) GetHistoryMessage(spaceID, sessionID string, afterMsgSequ
 				RelDocs:          relDocs,
 				ToolName:         v.Ext.ToolName,
 				ToolArgs:         v.Ext.ToolArgs,
+				QueryStrategy:    v.Ext.QueryStrategy,
+				ExpandedQueries:  v.Ext.ExpandedQueries,
+				Citations:        v.Ext.Citations,
 			},
 		}
 	})
@@ -214,6 +226,9 @@ func chatMsgAndExtToMessageDetail(msg *types.ChatMessage, ext *types.ChatMessage
 			RelDocs:          ext.RelDocs,
 			ToolName:         ext.ToolName,
 			ToolArgs:         ext.ToolArgs.String,
+			QueryStrategy:    ext.QueryStrategy,
+			ExpandedQueries:  ext.ExpandedQueries,
+			Citations:        ext.Citations,
 			IsEvaluateEnable: lo.If(msg.Role == types.USER_ROLE_ASSISTANT, true).Else(false),
 		}
 	}
@@ -0,0 +1,288 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/backup"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/importer"
+	"github.com/quka-ai/quka-ai/pkg/safe"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type ImportLogic struct {
+	UserInfo
+	ctx  context.Context
+	core *core.Core
+}
+
+func NewImportLogic(ctx context.Context, core *core.Core) *ImportLogic {
+	return &ImportLogic{
+		ctx:      ctx,
+		core:     core,
+		UserInfo: SetupUserInfo(ctx, core),
+	}
+}
+
+// ImportOptions 导入时的公共选项
+type ImportOptions struct {
+	Resource   string // 默认写入的 resource，为空时写入默认 resource
+	FolderMode string // 目录结构的映射方式
+	Fetch      bool   // 书签导入时是否在后台抓取网页内容
+}
+
+// PrepareObsidianImport 解析 Obsidian vault 归档并创建导入任务，由调用方决定任务的执行方式
+func (l *ImportLogic) PrepareObsidianImport(spaceID, userID, fileName string, opts ImportOptions, data []byte) (*types.ImportTask, *importer.Vault, error) {
+	if err := l.checkOptions(spaceID, opts); err != nil {
+		return nil, nil, err
+	}
+
+	vault, err := importer.ParseObsidian(data)
+	if err != nil {
+		return nil, nil, errors.New("ImportLogic.PrepareObsidianImport.ParseObsidian", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+	}
+
+	task, err := l.createTask(spaceID, userID, types.IMPORT_SOURCE_OBSIDIAN, fileName, opts, int64(len(vault.Notes)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, vault, nil
+}
+
+// StartObsidianImport 创建 Obsidian 导入任务，并在后台执行
+func (l *ImportLogic) StartObsidianImport(spaceID, fileName string, opts ImportOptions, data []byte) (*types.ImportTask, error) {
+	task, vault, err := l.PrepareObsidianImport(spaceID, l.GetUserInfo().User, fileName, opts, data)
+	if err != nil {
+		return nil, err
+	}
+	return l.runVaultImport(task, vault), nil
+}
+
+// PrepareNotionImport 解析 Notion 导出归档并创建导入任务，由调用方决定任务的执行方式
+func (l *ImportLogic) PrepareNotionImport(spaceID, userID, fileName string, opts ImportOptions, data []byte) (*types.ImportTask, *importer.Vault, error) {
+	if err := l.checkOptions(spaceID, opts); err != nil {
+		return nil, nil, err
+	}
+
+	vault, err := importer.ParseNotion(data)
+	if err != nil {
+		return nil, nil, errors.New("ImportLogic.PrepareNotionImport.ParseNotion", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+	}
+
+	task, err := l.createTask(spaceID, userID, types.IMPORT_SOURCE_NOTION, fileName, opts, int64(len(vault.Notes)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, vault, nil
+}
+
+// StartNotionImport 创建 Notion 导入任务，并在后台执行，进度通过 Centrifuge 推送到空间的知识列表频道
+func (l *ImportLogic) StartNotionImport(spaceID, fileName string, opts ImportOptions, data []byte) (*types.ImportTask, error) {
+	task, vault, err := l.PrepareNotionImport(spaceID, l.GetUserInfo().User, fileName, opts, data)
+	if err != nil {
+		return nil, err
+	}
+	return l.runVaultImport(task, vault), nil
+}
+
+// PrepareBookmarkImport 解析书签 HTML 或稍后读 CSV 并创建导入任务，由调用方决定任务的执行方式
+func (l *ImportLogic) PrepareBookmarkImport(spaceID, userID, fileName string, opts ImportOptions, data []byte) (*types.ImportTask, []importer.Bookmark, error) {
+	if err := l.checkOptions(spaceID, opts); err != nil {
+		return nil, nil, err
+	}
+
+	_, bookmarks, err := importer.ParseBookmarks(data)
+	if err != nil {
+		return nil, nil, errors.New("ImportLogic.PrepareBookmarkImport.ParseBookmarks", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+	}
+
+	task, err := l.createTask(spaceID, userID, types.IMPORT_SOURCE_BOOKMARK, fileName, opts, int64(len(bookmarks)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, bookmarks, nil
+}
+
+// StartBookmarkImport 创建书签导入任务，并在后台执行
+func (l *ImportLogic) StartBookmarkImport(spaceID, fileName string, opts ImportOptions, data []byte) (*types.ImportTask, error) {
+	task, bookmarks, err := l.PrepareBookmarkImport(spaceID, l.GetUserInfo().User, fileName, opts, data)
+	if err != nil {
+		return nil, err
+	}
+
+	runner := process.NewBookmarkImportTask(l.core, task, bookmarks, opts.Fetch)
+	result := *task
+	go safe.Run(func() {
+		if err := runner.Run(context.Background()); err != nil {
+			slog.Error("Bookmark import failed", slog.String("task_id", task.ID), slog.String("error", err.Error()))
+		}
+	})
+	return &result, nil
+}
+
+// PrepareBackupImport 解析空间备份归档并创建恢复任务，spaceID 为空时按归档中的空间信息为 userID 创建新空间
+func (l *ImportLogic) PrepareBackupImport(spaceID, userID, fileName string, data []byte) (*types.ImportTask, *backup.Reader, error) {
+	reader, err := backup.Open(data)
+	if err != nil {
+		return nil, nil, errors.New("ImportLogic.PrepareBackupImport.Open", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+	}
+
+	if spaceID == "" {
+		if spaceID, err = l.createBackupSpace(userID, reader.Manifest.Space); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	task, err := l.createTask(spaceID, userID, types.IMPORT_SOURCE_BACKUP, fileName, ImportOptions{}, int64(reader.Manifest.Counts.Total()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, reader, nil
+}
+
+// StartBackupImport 创建备份恢复任务，并在后台执行，spaceID 为空时恢复到新建的空间
+func (l *ImportLogic) StartBackupImport(spaceID, fileName string, data []byte) (*types.ImportTask, error) {
+	task, reader, err := l.PrepareBackupImport(spaceID, l.GetUserInfo().User, fileName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	runner := process.NewBackupRestoreTask(l.core, task, reader)
+	result := *task
+	go safe.Run(func() {
+		if err := runner.Run(context.Background()); err != nil {
+			slog.Error("Backup restore failed", slog.String("task_id", task.ID), slog.String("space_id", task.SpaceID), slog.String("error", err.Error()))
+		}
+	})
+	return &result, nil
+}
+
+// createBackupSpace 按备份中的空间信息与配置创建空间，userID 成为空间的所有者
+func (l *ImportLogic) createBackupSpace(userID string, space backup.Space) (string, error) {
+	spaceID := utils.GenRandomID()
+	return spaceID, l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
+		err := l.core.Store().SpaceStore().Create(ctx, types.Space{
+			SpaceID:     spaceID,
+			Title:       space.Title,
+			Description: space.Description,
+			BasePrompt:  space.BasePrompt,
+			ChatPrompt:  space.ChatPrompt,
+			Settings:    space.Settings,
+			CreatedAt:   time.Now().Unix(),
+		})
+		if err != nil {
+			return errors.New("ImportLogic.createBackupSpace.SpaceStore.Create", i18n.ERROR_INTERNAL, err)
+		}
+
+		err = l.core.Store().UserSpaceStore().Create(ctx, types.UserSpace{
+			UserID:    userID,
+			SpaceID:   spaceID,
+			Role:      srv.RoleChief,
+			CreatedAt: time.Now().Unix(),
+		})
+		if err != nil {
+			return errors.New("ImportLogic.createBackupSpace.UserSpaceStore.Create", i18n.ERROR_INTERNAL, err)
+		}
+		return nil
+	})
+}
+
+// runVaultImport 在后台执行 markdown 归档导入，返回任务创建时的快照
+func (l *ImportLogic) runVaultImport(task *types.ImportTask, vault *importer.Vault) *types.ImportTask {
+	runner := process.NewVaultImportTask(l.core, task, vault)
+	result := *task
+	go safe.Run(func() {
+		if err := runner.Run(context.Background()); err != nil {
+			slog.Error("Vault import failed", slog.String("task_id", task.ID), slog.String("source", task.Source), slog.String("error", err.Error()))
+		}
+	})
+	return &result
+}
+
+func (l *ImportLogic) checkOptions(spaceID string, opts ImportOptions) error {
+	if !types.IsValidImportFolderMode(opts.FolderMode) {
+		return errors.New("ImportLogic.checkOptions.FolderMode", i18n.ERROR_INVALIDARGUMENT, nil).Code(http.StatusBadRequest)
+	}
+
+	if opts.Resource == "" || opts.Resource == types.DEFAULT_RESOURCE {
+		return nil
+	}
+	if _, err := l.core.GetResource(l.ctx, spaceID, opts.Resource); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("ImportLogic.checkOptions.GetResource", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNotFound)
+		}
+		return errors.New("ImportLogic.checkOptions.GetResource", i18n.ERROR_INTERNAL, err)
+	}
+	return nil
+}
+
+func (l *ImportLogic) createTask(spaceID, userID, source, fileName string, opts ImportOptions, total int64) (*types.ImportTask, error) {
+	task := types.ImportTask{
+		ID:         utils.GenUniqIDStr(),
+		SpaceID:    spaceID,
+		UserID:     userID,
+		Source:     source,
+		FileName:   fileName,
+		Resource:   opts.Resource,
+		FolderMode: opts.FolderMode,
+		Status:     types.IMPORT_TASK_STATUS_RUNNING,
+		Total:      total,
+	}
+	if err := l.core.Store().ImportTaskStore().Create(l.ctx, task); err != nil {
+		return nil, errors.New("ImportLogic.createTask.ImportTaskStore.Create", i18n.ERROR_INTERNAL, err)
+	}
+	return &task, nil
+}
+
+// GetTask 获取导入任务
+func (l *ImportLogic) GetTask(spaceID, id string) (*types.ImportTask, error) {
+	task, err := l.core.Store().ImportTaskStore().Get(l.ctx, spaceID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("ImportLogic.GetTask.ImportTaskStore.Get", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNotFound)
+		}
+		return nil, errors.New("ImportLogic.GetTask.ImportTaskStore.Get", i18n.ERROR_INTERNAL, err)
+	}
+	return task, nil
+}
+
+// ListTasks 分页获取空间下的导入任务
+func (l *ImportLogic) ListTasks(spaceID string, page, pageSize uint64) ([]types.ImportTask, int64, error) {
+	list, err := l.core.Store().ImportTaskStore().List(l.ctx, spaceID, page, pageSize)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, 0, errors.New("ImportLogic.ListTasks.ImportTaskStore.List", i18n.ERROR_INTERNAL, err)
+	}
+
+	total, err := l.core.Store().ImportTaskStore().Total(l.ctx, spaceID)
+	if err != nil {
+		return nil, 0, errors.New("ImportLogic.ListTasks.ImportTaskStore.Total", i18n.ERROR_INTERNAL, err)
+	}
+	return list, total, nil
+}
+
+// ListTaskItems 分页获取导入任务中各条目的处理结果，status 为空时返回全部
+func (l *ImportLogic) ListTaskItems(spaceID, taskID, status string, page, pageSize uint64) ([]types.ImportTaskItem, int64, error) {
+	if _, err := l.GetTask(spaceID, taskID); err != nil {
+		return nil, 0, err
+	}
+
+	list, err := l.core.Store().ImportTaskItemStore().List(l.ctx, taskID, status, page, pageSize)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, 0, errors.New("ImportLogic.ListTaskItems.ImportTaskItemStore.List", i18n.ERROR_INTERNAL, err)
+	}
+
+	total, err := l.core.Store().ImportTaskItemStore().Total(l.ctx, taskID, status)
+	if err != nil {
+		return nil, 0, errors.New("ImportLogic.ListTaskItems.ImportTaskItemStore.Total", i18n.ERROR_INTERNAL, err)
+	}
+	return list, total, nil
+}
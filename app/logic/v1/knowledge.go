@@ -19,6 +19,7 @@ import (
 	"github.com/quka-ai/quka-ai/app/core/srv"
 	"github.com/quka-ai/quka-ai/app/logic/v1/process"
 	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/ai/agents/rag"
 	"github.com/quka-ai/quka-ai/pkg/errors"
 	"github.com/quka-ai/quka-ai/pkg/i18n"
 	"github.com/quka-ai/quka-ai/pkg/safe"
@@ -49,7 +50,7 @@ func (l *KnowledgeLogic) GetKnowledge(spaceID, id string) (*types.Knowledge, err
 		return nil, errors.New("KnowledgeLogic.GetKnowledge.KnowledgeStore.GetKnowledge", i18n.ERROR_INTERNAL, err)
 	}
 
-	if data == nil {
+	if data == nil || data.DeletedAt != 0 {
 		return nil, errors.New("KnowledgeLogic.GetKnowledge.KnowledgeStore.GetKnowledge.nil", i18n.ERROR_NOT_FOUND, err).Code(http.StatusNotFound)
 	}
 
@@ -121,11 +122,12 @@ func (l *KnowledgeLogic) ListKnowledges(opts types.GetKnowledgeOptions, page, pa
 }
 
 // ListUserKnowledges 获取用户创建的知识（排除 chunk 类型）
-func (l *KnowledgeLogic) ListUserKnowledges(spaceID string, keywords string, resource *types.ResourceQuery, page, pagesize uint64) ([]*types.Knowledge, uint64, error) {
+func (l *KnowledgeLogic) ListUserKnowledges(spaceID string, keywords string, resource *types.ResourceQuery, tags []string, page, pagesize uint64) ([]*types.Knowledge, uint64, error) {
 	opts := types.GetKnowledgeOptions{
 		SpaceID:  spaceID,
 		Resource: resource,
 		Keywords: keywords,
+		Tags:     normalizeTags(tags),
 		// ExcludeKind: []types.KnowledgeKind{types.KNOWLEDGE_KIND_CHUNK},
 	}
 	return l.ListKnowledges(opts, page, pagesize)
@@ -159,6 +161,7 @@ func (l *KnowledgeLogic) GetTaskKnowledges(spaceID, taskID string, page, pagesiz
 	return l.ListKnowledges(opts, page, pagesize)
 }
 
+// Delete 将知识移入回收站，超过空间的回收站保留期后会被彻底删除
 func (l *KnowledgeLogic) Delete(spaceID, id string) error {
 	user := l.GetUserInfo()
 	if err := l.core.Srv().RBAC().Check(user, l.lazyRolerFromKnowledgeID(spaceID, id), srv.PermissionEdit); err != nil {
@@ -169,45 +172,31 @@ func (l *KnowledgeLogic) Delete(spaceID, id string) error {
 	if err != nil && err != sql.ErrNoRows {
 		return errors.New("KnowledgeLogic.KnowledgeStore.GetKnowledge", i18n.ERROR_INTERNAL, err)
 	}
-	if knowledge == nil {
+	if knowledge == nil || knowledge.DeletedAt != 0 {
 		return nil
 	}
 
 	return l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
-		if knowledge.ContentType == types.KNOWLEDGE_CONTENT_TYPE_BLOCKS {
-			actData, err := l.core.DecryptData(knowledge.Content)
-			if err != nil {
-				slog.Error("Failed to decrypt knowledge data for mark file status to delete", slog.String("error", err.Error()))
-				actData = knowledge.Content
-			}
-			if err = UpdateFilesToDelete(ctx, l.core, spaceID, actData); err != nil {
-				slog.Error("Failed to remark knowledge files to delete status", slog.String("knowledge_id", id), slog.String("space_id", spaceID), slog.Any("error", err))
-			}
-		}
-
-		if err := l.core.Store().KnowledgeStore().Delete(ctx, spaceID, id); err != nil {
-			return errors.New("KnowledgeLogic.Delete.KnowledgeStore.Delete", i18n.ERROR_INTERNAL, err)
-		}
-
-		if err := l.core.Store().KnowledgeChunkStore().BatchDelete(ctx, spaceID, id); err != nil {
-			return errors.New("KnowledgeLogic.Delete.KnowledgeChunkStore.BatchDelete", i18n.ERROR_INTERNAL, err)
-		}
-
-		if err := l.core.Store().VectorStore().BatchDelete(ctx, spaceID, []string{id}); err != nil {
-			return errors.New("KnowledgeLogic.Delete.VectorStore.Delete", i18n.ERROR_INTERNAL, err)
+		if err := process.SoftDeleteKnowledge(ctx, l.core, spaceID, id); err != nil {
+			return errors.New("KnowledgeLogic.Delete.SoftDeleteKnowledge", i18n.ERROR_INTERNAL, err)
 		}
-
 		return nil
 	})
 }
 
+// Update 通过接口修改知识，修改人为当前登录用户
 func (l *KnowledgeLogic) Update(spaceID, id string, args types.UpdateKnowledgeArgs) error {
+	return l.UpdateWithSource(spaceID, id, args, types.KNOWLEDGE_REVISION_SOURCE_HTTP, l.GetUserInfo().User)
+}
+
+// UpdateWithSource 修改知识并记录一个修订，source 与 userID 标记修改来源与修改者
+func (l *KnowledgeLogic) UpdateWithSource(spaceID, id string, args types.UpdateKnowledgeArgs, source, userID string) error {
 	oldKnowledge, err := l.core.Store().KnowledgeStore().GetKnowledge(l.ctx, spaceID, id)
 	if err != nil && err != sql.ErrNoRows {
 		return errors.New("KnowledgeLogic.Update.KnowledgeStore.GetKnowledge", i18n.ERROR_INTERNAL, err)
 	}
 
-	if oldKnowledge == nil {
+	if oldKnowledge == nil || oldKnowledge.DeletedAt != 0 {
 		return errors.New("KnowledgeLogic.Update.KnowledgeStore.GetKnowledge", i18n.ERROR_NOT_FOUND, err).Code(http.StatusNotFound)
 	}
 
@@ -245,6 +234,7 @@ func (l *KnowledgeLogic) Update(spaceID, id string, args types.UpdateKnowledgeAr
 		}
 	}
 
+	previous := *oldKnowledge
 	if oldKnowledge.Content, err = l.core.DecryptData(oldKnowledge.Content); err != nil {
 		return errors.New("KnowledgeLogic.Update.DecryptData.oldKnowledge", i18n.ERROR_INTERNAL, err)
 	}
@@ -260,22 +250,37 @@ func (l *KnowledgeLogic) Update(spaceID, id string, args types.UpdateKnowledgeAr
 		summary = append(summary, "title")
 	}
 
-	if args.Content, err = l.core.EncryptData([]byte(args.Content.String())); err != nil {
-		return errors.New("KnowledgeLogic.Update.EncryptData", i18n.ERROR_INTERNAL, err)
+	// 未提供正文时保留原有正文，避免只修改标题等字段时正文被清空
+	if len(args.Content) > 0 {
+		if args.Content, err = l.core.EncryptData([]byte(args.Content.String())); err != nil {
+			return errors.New("KnowledgeLogic.Update.EncryptData", i18n.ERROR_INTERNAL, err)
+		}
 	}
 
-	err = l.core.Store().KnowledgeStore().Update(l.ctx, spaceID, id, types.UpdateKnowledgeArgs{
-		Resource:    args.Resource,
-		Title:       args.Title,
-		Content:     args.Content,
-		ContentType: args.ContentType,
-		Tags:        args.Tags,
-		Stage:       types.KNOWLEDGE_STAGE_SUMMARIZE,
-		Kind:        args.Kind,
-		Summary:     strings.Join(summary, ","),
+	err = l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
+		// 锁定知识记录，避免并发修改时修订版本号冲突
+		if err := l.core.Store().KnowledgeStore().LockKnowledge(ctx, spaceID, id); err != nil {
+			return errors.New("KnowledgeLogic.Update.KnowledgeStore.LockKnowledge", i18n.ERROR_INTERNAL, err)
+		}
+
+		err := l.core.Store().KnowledgeStore().Update(ctx, spaceID, id, types.UpdateKnowledgeArgs{
+			Resource:    args.Resource,
+			Title:       args.Title,
+			Content:     args.Content,
+			ContentType: args.ContentType,
+			Tags:        args.Tags,
+			Stage:       types.KNOWLEDGE_STAGE_SUMMARIZE,
+			Kind:        args.Kind,
+			Summary:     strings.Join(summary, ","),
+		})
+		if err != nil {
+			return errors.New("KnowledgeLogic.Update.KnowledgeStore.Update", i18n.ERROR_INTERNAL, err)
+		}
+
+		return l.createRevision(ctx, previous, args, source, userID)
 	})
 	if err != nil {
-		return errors.New("KnowledgeLogic.Update.KnowledgeStore.Update", i18n.ERROR_INTERNAL, err)
+		return err
 	}
 
 	go safe.Run(func() {
@@ -309,22 +314,24 @@ func (l *KnowledgeLogic) Update(spaceID, id string, args types.UpdateKnowledgeAr
 	return nil
 }
 
-func (l *KnowledgeLogic) GetQueryRelevanceKnowledges(spaceID, userID, query string, resource *types.ResourceQuery) (types.RAGDocs, []ai.UsageItem, error) {
+func (l *KnowledgeLogic) GetQueryRelevanceKnowledges(spaceID, userID, query string, resource *types.ResourceQuery, tags []string) (types.RAGDocs, []ai.UsageItem, error) {
 	var (
 		result types.RAGDocs
 		usages []ai.UsageItem
 	)
 
-	vector, err := l.core.Srv().AI().EmbeddingForQuery(l.ctx, []string{query})
+	vector, err := l.core.EmbeddingForQuery(l.ctx, []string{query})
 	if err != nil || len(vector.Data) == 0 {
 		return types.RAGDocs{}, nil, errors.New("KnowledgeLogic.GetRelevanceKnowledges.AI.EmbeddingForQuery", i18n.ERROR_INTERNAL, err)
 	}
 
-	refs, err := l.core.Store().VectorStore().Query(l.ctx, types.GetVectorsOptions{
+	profile := l.core.RetrievalProfile(l.ctx, spaceID)
+	refs, err := rag.QueryRelevanceRefs(l.ctx, l.core, types.GetVectorsOptions{
 		SpaceID:  spaceID,
 		UserID:   userID,
 		Resource: resource,
-	}, pgvector.NewVector(vector.Data[0]), 100)
+		Tags:     tags,
+	}, query, pgvector.NewVector(vector.Data[0]), uint64(profile.CandidateLimit))
 	if err != nil {
 		return types.RAGDocs{}, nil, errors.New("KnowledgeLogic.GetRelevanceKnowledges.VectorStore.Query", i18n.ERROR_INTERNAL, err)
 	}
@@ -337,28 +344,9 @@ func (l *KnowledgeLogic) GetQueryRelevanceKnowledges(spaceID, userID, query stri
 	// rerank
 	var (
 		knowledgeIDs       []string
-		cosLimit           float32 = 0.5
 		highScoreKnowledge []types.QueryResult
 	)
-
-	if len(refs) > 10 && refs[0].Cos > 0.5 {
-		cosLimit = refs[0].Cos - 0.15
-	}
-	for i, v := range refs {
-		if i > 0 && (v.Cos < cosLimit && v.OriginalLength > 200) {
-			if len(result.Refs) > 15 {
-				break
-			}
-			// TODO：more and more verify best ratio
-			continue
-		}
-
-		if i < 3 {
-			highScoreKnowledge = append(highScoreKnowledge, v)
-		}
-
-		result.Refs = append(result.Refs, v)
-	}
+	result.Refs, highScoreKnowledge = rag.FilterRelevanceRefs(refs, profile)
 
 	result.Refs = lo.UniqBy(result.Refs, func(item types.QueryResult) string {
 		return item.KnowledgeID
@@ -401,7 +389,7 @@ func (l *KnowledgeLogic) GetQueryRelevanceKnowledges(spaceID, userID, query stri
 		return result, usages, nil
 	}
 
-	rankList, usage, err := l.core.Rerank(query, knowledges)
+	rankList, usage, err := l.core.Rerank(query, knowledges, rag.KnowledgeSimilarity(refs), profile)
 	if err != nil {
 		slog.Error("Failed to request rerank api", slog.String("error", err.Error()))
 		// return result, usage, errors.New("KnowledgeLogic.Query.Rerank", i18n.ERROR_INTERNAL, err)
@@ -437,6 +425,15 @@ func (l *KnowledgeLogic) GetQueryRelevanceKnowledges(spaceID, userID, query stri
 		})
 	}
 
+	if rankList, err = rag.ExpandKnowledgeChunks(l.ctx, l.core, rankList, profile); err != nil {
+		slog.Error("Failed to expand knowledge chunks", slog.String("space_id", spaceID), slog.String("error", err.Error()))
+	}
+
+	if rankList, err = rag.ExpandKnowledgeGraph(l.ctx, l.core, spaceID, userID, rankList, profile); err != nil {
+		slog.Error("Failed to expand knowledge graph", slog.String("space_id", spaceID), slog.String("error", err.Error()))
+	}
+
+	rankList = rag.LimitKnowledgeDocs(rankList, profile)
 	if result.Docs, err = l.core.AppendKnowledgeContentToDocs(result.Docs, rankList); err != nil {
 		return result, usages, errors.New("KnowledgeLogic.Query.AppendKnowledgeContentToDocs", i18n.ERROR_INTERNAL, err)
 	}
@@ -795,7 +792,7 @@ func (l *KnowledgeLogic) processKnowledgeAsync(knowledge types.Knowledge) error
 // calculateExpiredAtByResource 根据resource的cycle计算过期时间
 func (l *KnowledgeLogic) calculateExpiredAtByResource(spaceID, resourceID string, createdAt int64) int64 {
 	// 获取resource信息
-	resource, err := l.core.Store().ResourceStore().GetResource(l.ctx, spaceID, resourceID)
+	resource, err := l.core.GetResource(l.ctx, spaceID, resourceID)
 	if err != nil {
 		return 0 // 如果获取失败，默认不过期
 	}
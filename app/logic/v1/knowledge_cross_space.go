@@ -0,0 +1,293 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/pgvector/pgvector-go"
+	"github.com/samber/lo"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/ai/agents/rag"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// CROSS_SPACE_CONCURRENCY 跨空间检索时同时检索的空间数量
+const CROSS_SPACE_CONCURRENCY = 4
+
+// CrossSpaceSearchResult 跨空间检索结果
+type CrossSpaceSearchResult struct {
+	List   []types.KnowledgeSearchHit `json:"list"`
+	Total  int                        `json:"total"`  // 各空间命中的知识总数(候选范围内)
+	Spaces []types.SpaceSearchCount   `json:"spaces"` // 参与检索的空间及其命中数量
+}
+
+// CrossSpaceQueryResult 跨空间问答结果
+type CrossSpaceQueryResult struct {
+	Message string               `json:"message"`
+	Docs    []*types.PassageInfo `json:"docs"` // 回答参考的知识，标记了所属空间
+}
+
+// viewableSpaces 返回当前用户拥有查看权限的空间，spaceIDs 不为空时只保留其中的空间
+// 用户已退出或权限不足的空间会被直接忽略，不返回错误，避免暴露空间是否存在
+func (l *KnowledgeLogic) viewableSpaces(spaceIDs []string) ([]types.Space, error) {
+	list, err := l.core.Store().UserSpaceStore().List(l.ctx, types.ListUserSpaceOptions{
+		UserID: l.GetUserInfo().User,
+	}, 0, 0)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.viewableSpaces.UserSpaceStore.List", i18n.ERROR_INTERNAL, err)
+	}
+
+	var ids []string
+	for _, v := range list {
+		if !l.core.Srv().RBAC().CheckPermission(v.Role, srv.PermissionView) {
+			continue
+		}
+		if len(spaceIDs) > 0 && !lo.Contains(spaceIDs, v.SpaceID) {
+			continue
+		}
+		ids = append(ids, v.SpaceID)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	spaces, err := l.core.Store().SpaceStore().List(l.ctx, ids, 0, 0)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.viewableSpaces.SpaceStore.List", i18n.ERROR_INTERNAL, err)
+	}
+	return spaces, nil
+}
+
+// eachSpace 并发地对每个空间执行 fn，结果按 spaces 的顺序返回，出错的空间会被记录日志并跳过
+func eachSpace[T any](spaces []types.Space, fn func(space types.Space) (T, error)) []T {
+	var (
+		wg        sync.WaitGroup
+		results   = make([]T, len(spaces))
+		failed    = make([]bool, len(spaces))
+		semaphore = make(chan struct{}, CROSS_SPACE_CONCURRENCY)
+	)
+	for i, space := range spaces {
+		wg.Add(1)
+		go func(index int, space types.Space) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			res, err := fn(space)
+			if err != nil {
+				slog.Error("Failed to retrieve space knowledges", slog.String("space_id", space.SpaceID), slog.String("error", err.Error()))
+				failed[index] = true
+				return
+			}
+			results[index] = res
+		}(i, space)
+	}
+	wg.Wait()
+
+	return lo.Filter(results, func(_ T, i int) bool {
+		return !failed[i]
+	})
+}
+
+// SearchAllSpaces 在用户有查看权限的所有空间(或 spaceIDs 指定的空间)中检索知识
+// 各空间的结果合并后统一重排，每条结果都会标记所属空间
+func (l *KnowledgeLogic) SearchAllSpaces(spaceIDs []string, args KnowledgeSearchArgs) (*CrossSpaceSearchResult, error) {
+	args.Query = strings.TrimSpace(args.Query)
+	if args.Query == "" {
+		return nil, errors.New("KnowledgeLogic.SearchAllSpaces.Query", i18n.ERROR_INVALIDARGUMENT, nil).Code(http.StatusBadRequest)
+	}
+	if args.Limit <= 0 {
+		args.Limit = KNOWLEDGE_SEARCH_DEFAULT_LIMIT
+	}
+	args.Limit = min(args.Limit, KNOWLEDGE_SEARCH_MAX_LIMIT)
+	if _, err := args.knowledgeOptions(""); err != nil {
+		return nil, errors.New("KnowledgeLogic.SearchAllSpaces.DateRange", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+	}
+
+	result := &CrossSpaceSearchResult{
+		List:   []types.KnowledgeSearchHit{},
+		Spaces: []types.SpaceSearchCount{},
+	}
+	spaces, err := l.viewableSpaces(spaceIDs)
+	if err != nil {
+		return nil, errors.Trace("KnowledgeLogic.SearchAllSpaces", err)
+	}
+	if len(spaces) == 0 {
+		return result, nil
+	}
+
+	user := l.GetUserInfo().User
+	vector, err := l.core.EmbeddingForQuery(l.ctx, []string{args.Query})
+	if err != nil || len(vector.Data) == 0 {
+		return nil, errors.New("KnowledgeLogic.SearchAllSpaces.AI.EmbeddingForQuery", i18n.ERROR_INTERNAL, err)
+	}
+	if vector.Usage != nil {
+		process.NewRecordUsageRequest(vector.Model, types.USAGE_TYPE_USER, types.USAGE_SUB_TYPE_EMBEDDING, "", user, vector.Usage)
+	}
+
+	queryVector := pgvector.NewVector(vector.Data[0])
+	spaceResults := eachSpace(spaces, func(space types.Space) (*KnowledgeSearchResult, error) {
+		res, err := l.searchSpace(space.SpaceID, args, queryVector)
+		if err != nil {
+			return nil, err
+		}
+		for i := range res.List {
+			res.List[i].SpaceID = space.SpaceID
+			res.List[i].SpaceTitle = space.Title
+		}
+		return res, nil
+	})
+
+	var hits []types.KnowledgeSearchHit
+	for _, v := range spaceResults {
+		hits = append(hits, v.List...)
+		result.Total += v.Total
+	}
+	// 不同空间的融合得分不可比，合并时按向量相似度排序，再交给重排模型统一排序
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Similarity > hits[j].Similarity
+	})
+	hits = l.rerankSearchHits(args.Query, hits)
+	if len(hits) > args.Limit {
+		hits = hits[:args.Limit]
+	}
+	result.List = append(result.List, hits...)
+
+	counts := lo.CountValuesBy(hits, func(item types.KnowledgeSearchHit) string {
+		return item.SpaceID
+	})
+	for _, v := range spaces {
+		result.Spaces = append(result.Spaces, types.SpaceSearchCount{
+			SpaceID: v.SpaceID,
+			Title:   v.Title,
+			Count:   counts[v.SpaceID],
+		})
+	}
+	return result, nil
+}
+
+// rerankSearchHits 使用知识标题与匹配片段对合并后的检索结果重排，重排失败时保持原有顺序
+func (l *KnowledgeLogic) rerankSearchHits(query string, hits []types.KnowledgeSearchHit) []types.KnowledgeSearchHit {
+	passages := lo.Map(hits, func(item types.KnowledgeSearchHit, _ int) *types.PassageInfo {
+		return &types.PassageInfo{
+			ID:    item.ID,
+			Title: item.Title,
+			Content: strings.Join(lo.Map(item.Snippets, func(snippet types.KnowledgeSearchSnippet, _ int) string {
+				return snippet.Text
+			}), "\n"),
+		}
+	})
+
+	ranked, usage, err := rag.RerankPassages(l.ctx, l.core, query, passages)
+	if usage != nil {
+		l.recordRerankUsage(usage)
+	}
+	if err != nil {
+		slog.Error("Failed to rerank cross space search hits", slog.String("error", err.Error()))
+		return hits
+	}
+
+	hitMap := lo.SliceToMap(hits, func(item types.KnowledgeSearchHit) (string, types.KnowledgeSearchHit) {
+		return item.ID, item
+	})
+	return lo.Map(ranked, func(item *types.PassageInfo, _ int) types.KnowledgeSearchHit {
+		return hitMap[item.ID]
+	})
+}
+
+func (l *KnowledgeLogic) recordRerankUsage(usage *ai.Usage) {
+	if usage.Usage == nil {
+		return
+	}
+	process.NewRecordUsageRequest(usage.Model, types.USAGE_TYPE_USER, types.USAGE_SUB_TYPE_RERANK, "", l.GetUserInfo().User, &openai.Usage{
+		PromptTokens: usage.Usage.PromptTokens,
+	})
+}
+
+// QueryAllSpaces 基于用户有查看权限的所有空间(或 spaceIDs 指定的空间)中的知识回答问题
+// 各空间分别召回知识，合并重排后按系统默认的检索参数截取，参考的知识会标记所属空间
+// tags 不为空时仅召回带有其中任一标签的知识
+func (l *KnowledgeLogic) QueryAllSpaces(spaceIDs []string, query string, tags []string) (*CrossSpaceQueryResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("KnowledgeLogic.QueryAllSpaces.Query", i18n.ERROR_INVALIDARGUMENT, nil).Code(http.StatusBadRequest)
+	}
+
+	spaces, err := l.viewableSpaces(spaceIDs)
+	if err != nil {
+		return nil, errors.Trace("KnowledgeLogic.QueryAllSpaces", err)
+	}
+
+	ctx, cancel := context.WithTimeout(l.ctx, time.Minute*3)
+	defer cancel()
+
+	user := l.GetUserInfo().User
+	lists := eachSpace(spaces, func(space types.Space) ([]*types.PassageInfo, error) {
+		docs, usages, err := rag.GetQueryRelevanceKnowledges(l.core, space.SpaceID, user, query, nil, tags)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range usages {
+			process.NewRecordUsageRequest(v.Usage.Model, types.USAGE_TYPE_USER, v.Subject, space.SpaceID, user, v.Usage.Usage)
+		}
+		for _, v := range docs.Docs {
+			v.SpaceID = space.SpaceID
+			v.SpaceTitle = space.Title
+		}
+		return docs.Docs, nil
+	})
+
+	docs, usage, err := rag.RerankPassages(ctx, l.core, query, rag.InterleavePassages(lists...))
+	if usage != nil {
+		l.recordRerankUsage(usage)
+	}
+	if err != nil {
+		slog.Error("Failed to rerank cross space passages", slog.String("error", err.Error()))
+	}
+	docs = rag.LimitPassages(docs, l.core.RetrievalProfile(ctx, ""))
+
+	lang := l.core.Srv().AI().Lang()
+	prompt := l.core.PromptManager().GetRAGTemplate(lang, nil)
+	passages := ai.NewDocs(docs).ConvertPassageToPromptText(lang)
+	if passages == "" {
+		passages = "null"
+	}
+	prompt.SetVar(ai.PROMPT_VAR_RELEVANT_PASSAGE, passages)
+
+	chatAI := l.core.Srv().AI().GetChatAI(false)
+	resp, err := chatAI.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(prompt.Build()),
+		schema.UserMessage(query),
+	})
+	if err != nil {
+		return nil, errors.New("KnowledgeLogic.QueryAllSpaces.ChatAI.Generate", i18n.ERROR_INTERNAL, err)
+	}
+	if resp.ResponseMeta != nil && resp.ResponseMeta.Usage != nil {
+		process.NewRecordUsageRequest(chatAI.Config().ModelName, types.USAGE_TYPE_USER, types.USAGE_SUB_TYPE_QUERY, "", user, &openai.Usage{
+			TotalTokens:      resp.ResponseMeta.Usage.TotalTokens,
+			PromptTokens:     resp.ResponseMeta.Usage.PromptTokens,
+			CompletionTokens: resp.ResponseMeta.Usage.CompletionTokens,
+		})
+	}
+
+	if docs == nil {
+		docs = []*types.PassageInfo{}
+	}
+	return &CrossSpaceQueryResult{
+		Message: resp.Content,
+		Docs:    docs,
+	}, nil
+}
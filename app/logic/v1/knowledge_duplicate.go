@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// 疑似重复知识的处理方式
+const (
+	DUPLICATE_RESOLVE_MERGE  = "merge"  // 删除重复的知识，标签合并到已有知识
+	DUPLICATE_RESOLVE_IGNORE = "ignore" // 保留两条知识，不再提示
+)
+
+// GetKnowledgeDuplicates 获取与知识存在待处理重复关系的其他知识ID
+func (l *KnowledgeLogic) GetKnowledgeDuplicates(spaceID, id string) ([]string, error) {
+	list, err := l.core.Store().KnowledgeDuplicateStore().List(l.ctx, types.ListKnowledgeDuplicateOptions{
+		SpaceID:     spaceID,
+		KnowledgeID: id,
+		Status:      types.KNOWLEDGE_DUPLICATE_STATUS_PENDING,
+	}, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.GetKnowledgeDuplicates.KnowledgeDuplicateStore.List", i18n.ERROR_INTERNAL, err)
+	}
+
+	return lo.Map(list, func(item types.KnowledgeDuplicate, _ int) string {
+		return lo.If(item.KnowledgeID == id, item.DuplicateOf).Else(item.KnowledgeID)
+	}), nil
+}
+
+// ListDuplicateClusters 按已有知识分组列出疑似重复的知识，status 为空时列出待处理的记录
+func (l *KnowledgeLogic) ListDuplicateClusters(spaceID, status string, page, pageSize uint64) ([]types.KnowledgeDuplicateCluster, int64, error) {
+	if status == "" {
+		status = types.KNOWLEDGE_DUPLICATE_STATUS_PENDING
+	}
+	opts := types.ListKnowledgeDuplicateOptions{
+		SpaceID: spaceID,
+		Status:  status,
+	}
+
+	ids, err := l.core.Store().KnowledgeDuplicateStore().ListClusters(l.ctx, opts, page, pageSize)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, 0, errors.New("KnowledgeLogic.ListDuplicateClusters.KnowledgeDuplicateStore.ListClusters", i18n.ERROR_INTERNAL, err)
+	}
+
+	total, err := l.core.Store().KnowledgeDuplicateStore().TotalClusters(l.ctx, opts)
+	if err != nil {
+		return nil, 0, errors.New("KnowledgeLogic.ListDuplicateClusters.KnowledgeDuplicateStore.TotalClusters", i18n.ERROR_INTERNAL, err)
+	}
+
+	if len(ids) == 0 {
+		return []types.KnowledgeDuplicateCluster{}, total, nil
+	}
+
+	opts.DuplicateOfs = ids
+	list, err := l.core.Store().KnowledgeDuplicateStore().List(l.ctx, opts, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, 0, errors.New("KnowledgeLogic.ListDuplicateClusters.KnowledgeDuplicateStore.List", i18n.ERROR_INTERNAL, err)
+	}
+
+	knowledgeIDs := append(ids, lo.Map(list, func(item types.KnowledgeDuplicate, _ int) string {
+		return item.KnowledgeID
+	})...)
+	knowledges, err := l.core.Store().KnowledgeStore().ListLiteKnowledges(l.ctx, types.GetKnowledgeOptions{
+		SpaceID: spaceID,
+		IDs:     lo.Uniq(knowledgeIDs),
+	}, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, 0, errors.New("KnowledgeLogic.ListDuplicateClusters.KnowledgeStore.ListLiteKnowledges", i18n.ERROR_INTERNAL, err)
+	}
+	knowledgeMap := lo.SliceToMap(knowledges, func(item *types.KnowledgeLite) (string, *types.KnowledgeLite) {
+		return item.ID, item
+	})
+
+	clusters := make([]types.KnowledgeDuplicateCluster, 0, len(ids))
+	for _, id := range ids {
+		cluster := types.KnowledgeDuplicateCluster{
+			Knowledge:  knowledgeMap[id],
+			Duplicates: []types.KnowledgeDuplicateMember{},
+		}
+		if cluster.Knowledge == nil {
+			cluster.Knowledge = &types.KnowledgeLite{ID: id, SpaceID: spaceID}
+		}
+		for _, v := range list {
+			if v.DuplicateOf != id {
+				continue
+			}
+			member := types.KnowledgeDuplicateMember{
+				ID:         v.ID,
+				Knowledge:  knowledgeMap[v.KnowledgeID],
+				Similarity: v.Similarity,
+				Status:     v.Status,
+			}
+			if member.Knowledge == nil {
+				// 已合并的知识已被删除
+				member.Knowledge = &types.KnowledgeLite{ID: v.KnowledgeID, SpaceID: spaceID}
+			}
+			cluster.Duplicates = append(cluster.Duplicates, member)
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, total, nil
+}
+
+// ResolveDuplicates 处理已有知识 duplicateOf 下待处理的重复关系，ids 为空时处理该分组下的全部记录
+func (l *KnowledgeLogic) ResolveDuplicates(spaceID, duplicateOf string, ids []string, action string) error {
+	if action != DUPLICATE_RESOLVE_MERGE && action != DUPLICATE_RESOLVE_IGNORE {
+		return errors.New("KnowledgeLogic.ResolveDuplicates.Action", i18n.ERROR_INVALIDARGUMENT, nil).Code(http.StatusBadRequest)
+	}
+
+	list, err := l.core.Store().KnowledgeDuplicateStore().List(l.ctx, types.ListKnowledgeDuplicateOptions{
+		SpaceID:      spaceID,
+		Status:       types.KNOWLEDGE_DUPLICATE_STATUS_PENDING,
+		DuplicateOfs: []string{duplicateOf},
+	}, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return errors.New("KnowledgeLogic.ResolveDuplicates.KnowledgeDuplicateStore.List", i18n.ERROR_INTERNAL, err)
+	}
+	if len(ids) > 0 {
+		list = lo.Filter(list, func(item types.KnowledgeDuplicate, _ int) bool {
+			return lo.Contains(ids, item.ID)
+		})
+	}
+	if len(list) == 0 {
+		return errors.New("KnowledgeLogic.ResolveDuplicates.NotFound", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNotFound)
+	}
+
+	if action == DUPLICATE_RESOLVE_IGNORE {
+		if err = l.core.Store().KnowledgeDuplicateStore().UpdateStatus(l.ctx, spaceID, lo.Map(list, func(item types.KnowledgeDuplicate, _ int) string {
+			return item.ID
+		}), types.KNOWLEDGE_DUPLICATE_STATUS_IGNORED); err != nil {
+			return errors.New("KnowledgeLogic.ResolveDuplicates.KnowledgeDuplicateStore.UpdateStatus", i18n.ERROR_INTERNAL, err)
+		}
+		return nil
+	}
+
+	user := l.GetUserInfo()
+	for _, v := range list {
+		if err := l.core.Srv().RBAC().Check(user, l.lazyRolerFromKnowledgeID(spaceID, v.KnowledgeID), srv.PermissionEdit); err != nil {
+			return errors.Trace("KnowledgeLogic.ResolveDuplicates", err)
+		}
+	}
+
+	return l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
+		for _, v := range list {
+			knowledge, err := l.core.Store().KnowledgeStore().GetKnowledge(ctx, spaceID, v.KnowledgeID)
+			if err != nil {
+				return errors.New("KnowledgeLogic.ResolveDuplicates.KnowledgeStore.GetKnowledge", i18n.ERROR_INTERNAL, err)
+			}
+
+			if err = process.MergeDuplicateKnowledge(ctx, l.core, knowledge, v); err != nil {
+				return errors.New("KnowledgeLogic.ResolveDuplicates.MergeDuplicateKnowledge", i18n.ERROR_INTERNAL, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// KNOWLEDGE_ENTITY_MAX_RELATIONS 实体详情中返回的关系数量上限
+const KNOWLEDGE_ENTITY_MAX_RELATIONS = 50
+
+// ListEntities 分页获取空间内仍被知识提及的实体
+func (l *KnowledgeLogic) ListEntities(spaceID, keywords, entityType string, page, pageSize uint64) ([]types.KnowledgeEntityItem, int64, error) {
+	opts := types.ListKnowledgeEntityOptions{
+		SpaceID:  spaceID,
+		Keywords: keywords,
+		Type:     entityType,
+	}
+	list, err := l.core.Store().KnowledgeEntityStore().List(l.ctx, opts, page, pageSize)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, 0, errors.New("KnowledgeLogic.ListEntities.KnowledgeEntityStore.List", i18n.ERROR_INTERNAL, err)
+	}
+
+	total, err := l.core.Store().KnowledgeEntityStore().Total(l.ctx, opts)
+	if err != nil {
+		return nil, 0, errors.New("KnowledgeLogic.ListEntities.KnowledgeEntityStore.Total", i18n.ERROR_INTERNAL, err)
+	}
+
+	if list == nil {
+		list = []types.KnowledgeEntityItem{}
+	}
+	return list, total, nil
+}
+
+// GetEntity 获取实体详情，分页返回提及该实体的知识
+func (l *KnowledgeLogic) GetEntity(spaceID, id string, page, pageSize uint64) (*types.KnowledgeEntityDetail, error) {
+	entity, err := l.core.Store().KnowledgeEntityStore().Get(l.ctx, spaceID, id)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.GetEntity.KnowledgeEntityStore.Get", i18n.ERROR_INTERNAL, err)
+	}
+	if entity == nil {
+		return nil, errors.New("KnowledgeLogic.GetEntity.KnowledgeEntityStore.Get.nil", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNotFound)
+	}
+
+	knowledgeIDs, err := l.core.Store().KnowledgeEntityMentionStore().ListKnowledgeIDs(l.ctx, spaceID, id, page, pageSize)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.GetEntity.KnowledgeEntityMentionStore.ListKnowledgeIDs", i18n.ERROR_INTERNAL, err)
+	}
+	knowledges, err := l.listLiteKnowledgesInOrder(spaceID, knowledgeIDs)
+	if err != nil {
+		return nil, errors.Trace("KnowledgeLogic.GetEntity", err)
+	}
+
+	relations, err := l.core.Store().KnowledgeRelationStore().ListByEntity(l.ctx, spaceID, id, 1, KNOWLEDGE_ENTITY_MAX_RELATIONS)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.GetEntity.KnowledgeRelationStore.ListByEntity", i18n.ERROR_INTERNAL, err)
+	}
+
+	var entityIDs []string
+	for _, v := range relations {
+		entityIDs = append(entityIDs, v.SourceID, v.TargetID)
+	}
+	names := map[string]string{entity.ID: entity.Name}
+	if len(entityIDs) > 0 {
+		entities, err := l.core.Store().KnowledgeEntityStore().List(l.ctx, types.ListKnowledgeEntityOptions{
+			SpaceID: spaceID,
+			IDs:     lo.Uniq(entityIDs),
+		}, types.NO_PAGINATION, types.NO_PAGINATION)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, errors.New("KnowledgeLogic.GetEntity.KnowledgeEntityStore.List", i18n.ERROR_INTERNAL, err)
+		}
+		for _, v := range entities {
+			names[v.ID] = v.Name
+		}
+	}
+
+	detail := &types.KnowledgeEntityDetail{
+		Entity:     *entity,
+		Knowledges: knowledges,
+		Relations:  []types.KnowledgeRelationItem{},
+	}
+	for _, v := range relations {
+		// 关系另一端的实体已不再被任何知识提及
+		if names[v.SourceID] == "" || names[v.TargetID] == "" {
+			continue
+		}
+		detail.Relations = append(detail.Relations, types.KnowledgeRelationItem{
+			KnowledgeRelation: v,
+			SourceName:        names[v.SourceID],
+			TargetName:        names[v.TargetID],
+		})
+	}
+	return detail, nil
+}
+
+// GetKnowledgeLinks 获取知识引用的知识、引用了该知识的知识(反向链接)以及其提及的实体
+func (l *KnowledgeLogic) GetKnowledgeLinks(spaceID, id string) (*types.KnowledgeGraphLinks, error) {
+	targetIDs, err := l.core.Store().KnowledgeLinkStore().ListTargetIDs(l.ctx, spaceID, []string{id})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.GetKnowledgeLinks.KnowledgeLinkStore.ListTargetIDs", i18n.ERROR_INTERNAL, err)
+	}
+	backlinkIDs, err := l.core.Store().KnowledgeLinkStore().ListBacklinkIDs(l.ctx, spaceID, []string{id})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.GetKnowledgeLinks.KnowledgeLinkStore.ListBacklinkIDs", i18n.ERROR_INTERNAL, err)
+	}
+
+	result := &types.KnowledgeGraphLinks{}
+	if result.Links, err = l.listLiteKnowledgesInOrder(spaceID, targetIDs); err != nil {
+		return nil, errors.Trace("KnowledgeLogic.GetKnowledgeLinks", err)
+	}
+	if result.Backlinks, err = l.listLiteKnowledgesInOrder(spaceID, backlinkIDs); err != nil {
+		return nil, errors.Trace("KnowledgeLogic.GetKnowledgeLinks", err)
+	}
+
+	result.Entities, err = l.core.Store().KnowledgeEntityStore().List(l.ctx, types.ListKnowledgeEntityOptions{
+		SpaceID:     spaceID,
+		KnowledgeID: id,
+	}, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.GetKnowledgeLinks.KnowledgeEntityStore.List", i18n.ERROR_INTERNAL, err)
+	}
+	if result.Entities == nil {
+		result.Entities = []types.KnowledgeEntityItem{}
+	}
+	return result, nil
+}
+
+// listLiteKnowledgesInOrder 按 ids 的顺序返回知识，不存在的知识会被忽略
+func (l *KnowledgeLogic) listLiteKnowledgesInOrder(spaceID string, ids []string) ([]*types.KnowledgeLite, error) {
+	if len(ids) == 0 {
+		return []*types.KnowledgeLite{}, nil
+	}
+
+	list, err := l.core.Store().KnowledgeStore().ListLiteKnowledges(l.ctx, types.GetKnowledgeOptions{
+		SpaceID: spaceID,
+		IDs:     ids,
+	}, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.KnowledgeStore.ListLiteKnowledges", i18n.ERROR_INTERNAL, err)
+	}
+
+	knowledgeMap := lo.SliceToMap(list, func(item *types.KnowledgeLite) (string, *types.KnowledgeLite) {
+		return item.ID, item
+	})
+	result := make([]*types.KnowledgeLite, 0, len(list))
+	for _, id := range ids {
+		if v, ok := knowledgeMap[id]; ok {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
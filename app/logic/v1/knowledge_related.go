@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"database/sql"
+	"net/http"
+	"sort"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+const (
+	// RELATED_KNOWLEDGE_DEFAULT_LIMIT 默认返回的相关知识数量
+	RELATED_KNOWLEDGE_DEFAULT_LIMIT = 10
+	// RELATED_KNOWLEDGE_MAX_LIMIT 最多返回的相关知识数量
+	RELATED_KNOWLEDGE_MAX_LIMIT = 50
+	// RELATED_KNOWLEDGE_MAX_CHUNKS 参与计算的分片数量上限
+	RELATED_KNOWLEDGE_MAX_CHUNKS = 50
+)
+
+// ListRelatedKnowledges 使用知识已存储的分片向量检索空间内语义相近的知识，不包含自身及已过期的知识
+func (l *KnowledgeLogic) ListRelatedKnowledges(spaceID, id string, limit int) ([]types.RelatedKnowledge, error) {
+	if limit <= 0 {
+		limit = RELATED_KNOWLEDGE_DEFAULT_LIMIT
+	}
+	limit = min(limit, RELATED_KNOWLEDGE_MAX_LIMIT)
+
+	knowledge, err := l.core.Store().KnowledgeStore().GetKnowledge(l.ctx, spaceID, id)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.ListRelatedKnowledges.KnowledgeStore.GetKnowledge", i18n.ERROR_INTERNAL, err)
+	}
+	if knowledge == nil {
+		return nil, errors.New("KnowledgeLogic.ListRelatedKnowledges.KnowledgeStore.GetKnowledge.nil", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNotFound)
+	}
+
+	model := l.core.Srv().AI().EmbeddingModel()
+	vectors, err := l.core.Store().VectorStore().ListVectors(l.ctx, types.GetVectorsOptions{
+		SpaceID:     spaceID,
+		KnowledgeID: id,
+		Model:       model,
+	}, 1, RELATED_KNOWLEDGE_MAX_CHUNKS)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.ListRelatedKnowledges.VectorStore.ListVectors", i18n.ERROR_INTERNAL, err)
+	}
+	// 知识尚未完成向量化
+	if len(vectors) == 0 {
+		return []types.RelatedKnowledge{}, nil
+	}
+
+	// 检索结果为分片粒度，多召回一些候选后按知识去重
+	refs, err := l.core.Store().VectorStore().Query(l.ctx, types.GetVectorsOptions{
+		SpaceID:             spaceID,
+		Model:               model,
+		ExcludeKnowledgeIDs: []string{id},
+		Knowledge:           &types.GetKnowledgeOptions{SpaceID: spaceID},
+	}, types.MeanEmbedding(vectors), uint64(limit*4))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.ListRelatedKnowledges.VectorStore.Query", i18n.ERROR_INTERNAL, err)
+	}
+
+	similarity := make(map[string]float32)
+	for _, v := range refs {
+		if v.Cos > similarity[v.KnowledgeID] {
+			similarity[v.KnowledgeID] = v.Cos
+		}
+	}
+	if len(similarity) == 0 {
+		return []types.RelatedKnowledge{}, nil
+	}
+
+	knowledges, err := l.core.Store().KnowledgeStore().ListLiteKnowledges(l.ctx, types.GetKnowledgeOptions{
+		SpaceID: spaceID,
+		IDs:     lo.Keys(similarity),
+	}, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.ListRelatedKnowledges.KnowledgeStore.ListLiteKnowledges", i18n.ERROR_INTERNAL, err)
+	}
+
+	result := lo.Map(knowledges, func(item *types.KnowledgeLite, _ int) types.RelatedKnowledge {
+		return types.RelatedKnowledge{
+			KnowledgeLite: *item,
+			Similarity:    similarity[item.ID],
+		}
+	})
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Similarity > result[j].Similarity
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
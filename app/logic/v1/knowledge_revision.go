@@ -0,0 +1,227 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+	"github.com/quka-ai/quka-ai/pkg/utils/editorjs"
+)
+
+// createRevision 记录知识修改后的版本，知识首次被修改时会先保存修改前的原始版本，previous 与 args 中的正文均为加密后的内容
+func (l *KnowledgeLogic) createRevision(ctx context.Context, previous types.Knowledge, args types.UpdateKnowledgeArgs, source, userID string) error {
+	revisionStore := l.core.Store().KnowledgeRevisionStore()
+	total, err := revisionStore.Total(ctx, previous.SpaceID, previous.ID)
+	if err != nil {
+		return errors.New("KnowledgeLogic.createRevision.KnowledgeRevisionStore.Total", i18n.ERROR_INTERNAL, err)
+	}
+
+	if total == 0 {
+		original := knowledgeToRevision(previous, types.KNOWLEDGE_REVISION_SOURCE_ORIGINAL, previous.UserID)
+		if previous.UpdatedAt != 0 {
+			original.CreatedAt = previous.UpdatedAt
+		}
+		if _, err = revisionStore.Create(ctx, original); err != nil {
+			return errors.New("KnowledgeLogic.createRevision.KnowledgeRevisionStore.Create.original", i18n.ERROR_INTERNAL, err)
+		}
+	}
+
+	next := previous
+	if args.Title != "" {
+		next.Title = args.Title
+	}
+	if args.Resource != "" {
+		next.Resource = args.Resource
+	}
+	if len(args.Tags) > 0 {
+		next.Tags = args.Tags
+	}
+	if len(args.Content) > 0 {
+		next.Content = args.Content
+	}
+	if args.ContentType != "" {
+		next.ContentType = args.ContentType
+	}
+
+	revision := knowledgeToRevision(next, source, userID)
+	revision.CreatedAt = time.Now().Unix()
+	if _, err = revisionStore.Create(ctx, revision); err != nil {
+		return errors.New("KnowledgeLogic.createRevision.KnowledgeRevisionStore.Create", i18n.ERROR_INTERNAL, err)
+	}
+
+	if err = revisionStore.Prune(ctx, previous.ID, l.revisionRetention(ctx, previous.SpaceID)); err != nil {
+		return errors.New("KnowledgeLogic.createRevision.KnowledgeRevisionStore.Prune", i18n.ERROR_INTERNAL, err)
+	}
+	return nil
+}
+
+// revisionRetention 获取空间的修订保留数量，获取失败时使用默认值
+func (l *KnowledgeLogic) revisionRetention(ctx context.Context, spaceID string) int {
+	space, err := l.core.GetSpace(ctx, spaceID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			slog.Error("Failed to get space revision config", slog.String("space_id", spaceID), slog.String("error", err.Error()))
+		}
+		return types.RevisionConfig{}.WithDefault().Retention
+	}
+	return space.Settings.RevisionConfig().Retention
+}
+
+func knowledgeToRevision(knowledge types.Knowledge, source, userID string) types.KnowledgeRevision {
+	return types.KnowledgeRevision{
+		ID:          utils.GenUniqIDStr(),
+		SpaceID:     knowledge.SpaceID,
+		KnowledgeID: knowledge.ID,
+		Title:       knowledge.Title,
+		Resource:    knowledge.Resource,
+		Tags:        knowledge.Tags,
+		Content:     knowledge.Content,
+		ContentType: knowledge.ContentType,
+		UserID:      userID,
+		Source:      source,
+	}
+}
+
+// ListRevisions 分页获取知识的修订列表，列表中不包含正文
+func (l *KnowledgeLogic) ListRevisions(spaceID, knowledgeID string, page, pagesize uint64) ([]types.KnowledgeRevision, int64, error) {
+	list, err := l.core.Store().KnowledgeRevisionStore().List(l.ctx, spaceID, knowledgeID, page, pagesize)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, 0, errors.New("KnowledgeLogic.ListRevisions.KnowledgeRevisionStore.List", i18n.ERROR_INTERNAL, err)
+	}
+
+	total, err := l.core.Store().KnowledgeRevisionStore().Total(l.ctx, spaceID, knowledgeID)
+	if err != nil {
+		return nil, 0, errors.New("KnowledgeLogic.ListRevisions.KnowledgeRevisionStore.Total", i18n.ERROR_INTERNAL, err)
+	}
+	return list, total, nil
+}
+
+// getRevision 获取修订并解密正文
+func (l *KnowledgeLogic) getRevision(spaceID, knowledgeID string, version int64) (*types.KnowledgeRevision, error) {
+	revision, err := l.core.Store().KnowledgeRevisionStore().Get(l.ctx, spaceID, knowledgeID, version)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.getRevision.KnowledgeRevisionStore.Get", i18n.ERROR_INTERNAL, err)
+	}
+	if revision == nil {
+		return nil, errors.New("KnowledgeLogic.getRevision.KnowledgeRevisionStore.Get.nil", i18n.ERROR_NOT_FOUND, err).Code(http.StatusNotFound)
+	}
+
+	if len(revision.Content) > 0 {
+		if revision.Content, err = l.core.DecryptData(revision.Content); err != nil {
+			return nil, errors.New("KnowledgeLogic.getRevision.DecryptData", i18n.ERROR_INTERNAL, err)
+		}
+	}
+	return revision, nil
+}
+
+// GetRevision 获取知识的指定修订，正文中的静态资源会替换为预签名地址
+func (l *KnowledgeLogic) GetRevision(spaceID, knowledgeID string, version int64) (*types.KnowledgeRevision, error) {
+	revision, err := l.getRevision(spaceID, knowledgeID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	contentStr := string(revision.Content)
+	switch revision.ContentType {
+	case types.KNOWLEDGE_CONTENT_TYPE_BLOCKS:
+		contentStr = editorjs.ReplaceEditorJSBlocksJsonStaticResourcesWithPresignedURL(contentStr, l.core.Plugins.FileStorage())
+	default:
+		contentStr = editorjs.ReplaceMarkdownStaticResourcesWithPresignedURL(contentStr, l.core.Plugins.FileStorage())
+	}
+	revision.Content = types.KnowledgeContent(contentStr)
+	return revision, nil
+}
+
+// DiffRevisions 对比知识的两个修订，正文统一转换为 markdown 后按行生成 unified diff
+func (l *KnowledgeLogic) DiffRevisions(spaceID, knowledgeID string, from, to int64) (*types.KnowledgeRevisionDiff, error) {
+	fromRevision, err := l.getRevision(spaceID, knowledgeID, from)
+	if err != nil {
+		return nil, errors.Trace("KnowledgeLogic.DiffRevisions.from", err)
+	}
+	toRevision, err := l.getRevision(spaceID, knowledgeID, to)
+	if err != nil {
+		return nil, errors.Trace("KnowledgeLogic.DiffRevisions.to", err)
+	}
+
+	diff, err := diffRevisionContent(fromRevision, toRevision)
+	if err != nil {
+		return nil, errors.New("KnowledgeLogic.DiffRevisions.diffRevisionContent", i18n.ERROR_INTERNAL, err)
+	}
+
+	result := &types.KnowledgeRevisionDiff{
+		From:         fromRevision,
+		To:           toRevision,
+		TitleChanged: fromRevision.Title != toRevision.Title,
+		TagsChanged:  !slices.Equal(fromRevision.Tags, toRevision.Tags),
+		Diff:         diff,
+	}
+	// 差异中已包含正文变化，不再返回两个版本的完整正文
+	result.From.Content = nil
+	result.To.Content = nil
+	return result, nil
+}
+
+func diffRevisionContent(from, to *types.KnowledgeRevision) (string, error) {
+	fromText, err := revisionMarkdown(from)
+	if err != nil {
+		return "", err
+	}
+	toText, err := revisionMarkdown(to)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(ensureTrailingNewline(fromText)),
+		B:        difflib.SplitLines(ensureTrailingNewline(toText)),
+		FromFile: fmt.Sprintf("v%d", from.Version),
+		ToFile:   fmt.Sprintf("v%d", to.Version),
+		Context:  3,
+	})
+}
+
+// revisionMarkdown 将修订正文转换为 markdown，便于按行对比 editorjs 文档
+func revisionMarkdown(revision *types.KnowledgeRevision) (string, error) {
+	if revision.ContentType != types.KNOWLEDGE_CONTENT_TYPE_BLOCKS || len(revision.Content) == 0 {
+		return string(revision.Content), nil
+	}
+	return editorjs.ConvertEditorJSRawToMarkdown(json.RawMessage(revision.Content))
+}
+
+func ensureTrailingNewline(s string) string {
+	if s == "" || strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
+
+// RestoreRevision 将知识恢复为指定修订的标题、标签与正文，恢复本身也会记录为一个新的修订，分类保持当前值
+func (l *KnowledgeLogic) RestoreRevision(spaceID, knowledgeID string, version int64) error {
+	revision, err := l.getRevision(spaceID, knowledgeID, version)
+	if err != nil {
+		return errors.Trace("KnowledgeLogic.RestoreRevision", err)
+	}
+
+	err = l.UpdateWithSource(spaceID, knowledgeID, types.UpdateKnowledgeArgs{
+		Title:       revision.Title,
+		Tags:        revision.Tags,
+		Content:     revision.Content,
+		ContentType: revision.ContentType,
+	}, types.KNOWLEDGE_REVISION_SOURCE_RESTORE, l.GetUserInfo().User)
+	if err != nil {
+		return errors.Trace("KnowledgeLogic.RestoreRevision", err)
+	}
+	return nil
+}
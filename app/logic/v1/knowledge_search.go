@@ -0,0 +1,377 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pgvector/pgvector-go"
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/ai/agents/rag"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+	"github.com/quka-ai/quka-ai/pkg/utils/editorjs"
+)
+
+const (
+	KNOWLEDGE_SEARCH_DEFAULT_LIMIT = 20
+	KNOWLEDGE_SEARCH_MAX_LIMIT     = 50
+
+	knowledgeSearchSnippetSize = 160
+	knowledgeSearchMaxSnippets = 3
+)
+
+// KnowledgeSearchArgs 知识检索参数
+type KnowledgeSearchArgs struct {
+	Query     string
+	Kind      []types.KnowledgeKind
+	Source    string
+	Resource  string
+	Tags      []string
+	CreatedSt int64  // 创建时间起始(秒)
+	CreatedEt int64  // 创建时间截止(秒)
+	DateSt    string // maybe_date 起始日期，格式 2006-01-02
+	DateEt    string // maybe_date 截止日期(包含当天)，格式 2006-01-02
+	Limit     int
+}
+
+// KnowledgeSearchResult 知识检索结果
+type KnowledgeSearchResult struct {
+	List   []types.KnowledgeSearchHit  `json:"list"`
+	Total  int                         `json:"total"` // 命中的知识总数(候选范围内)
+	Facets types.KnowledgeSearchFacets `json:"facets"`
+}
+
+func (args KnowledgeSearchArgs) knowledgeOptions(spaceID string) (types.GetKnowledgeOptions, error) {
+	opts := types.GetKnowledgeOptions{
+		SpaceID: spaceID,
+		Kind:    args.Kind,
+		Source:  args.Source,
+		Tags:    normalizeTags(args.Tags),
+	}
+	if args.Resource != "" {
+		opts.Resource = &types.ResourceQuery{Include: []string{args.Resource}}
+	}
+
+	if args.CreatedSt > 0 || args.CreatedEt > 0 {
+		et := args.CreatedEt
+		if et == 0 {
+			et = math.MaxInt64
+		}
+		opts.TimeRange = &struct {
+			St int64
+			Et int64
+		}{St: args.CreatedSt, Et: et}
+	}
+
+	if args.DateSt != "" || args.DateEt != "" {
+		opts.MaybeDateRange = &struct {
+			St string
+			Et string
+		}{}
+		if args.DateSt != "" {
+			st, err := time.Parse(time.DateOnly, args.DateSt)
+			if err != nil {
+				return opts, err
+			}
+			opts.MaybeDateRange.St = st.Format(time.DateOnly)
+		}
+		if args.DateEt != "" {
+			et, err := time.Parse(time.DateOnly, args.DateEt)
+			if err != nil {
+				return opts, err
+			}
+			opts.MaybeDateRange.Et = et.AddDate(0, 0, 1).Format(time.DateOnly)
+		}
+	}
+	return opts, nil
+}
+
+// hasFilters 是否设置了筛选条件
+func (args KnowledgeSearchArgs) hasFilters() bool {
+	return len(args.Kind) > 0 || args.Source != "" || args.Resource != "" || len(args.Tags) > 0 ||
+		args.CreatedSt > 0 || args.CreatedEt > 0 || args.DateSt != "" || args.DateEt != ""
+}
+
+// Search 检索空间内的知识，返回命中的知识、匹配片段与分面统计，不调用对话模型
+func (l *KnowledgeLogic) Search(spaceID string, args KnowledgeSearchArgs) (*KnowledgeSearchResult, error) {
+	args.Query = strings.TrimSpace(args.Query)
+	if args.Query == "" {
+		return nil, errors.New("KnowledgeLogic.Search.Query", i18n.ERROR_INVALIDARGUMENT, nil).Code(http.StatusBadRequest)
+	}
+	if args.Limit <= 0 {
+		args.Limit = KNOWLEDGE_SEARCH_DEFAULT_LIMIT
+	}
+	args.Limit = min(args.Limit, KNOWLEDGE_SEARCH_MAX_LIMIT)
+
+	if _, err := args.knowledgeOptions(spaceID); err != nil {
+		return nil, errors.New("KnowledgeLogic.Search.DateRange", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+	}
+
+	vector, err := l.core.EmbeddingForQuery(l.ctx, []string{args.Query})
+	if err != nil || len(vector.Data) == 0 {
+		return nil, errors.New("KnowledgeLogic.Search.AI.EmbeddingForQuery", i18n.ERROR_INTERNAL, err)
+	}
+	if vector.Usage != nil {
+		process.NewRecordUsageRequest(vector.Model, types.USAGE_TYPE_USER, types.USAGE_SUB_TYPE_EMBEDDING, spaceID, l.GetUserInfo().User, vector.Usage)
+	}
+
+	return l.searchSpace(spaceID, args, pgvector.NewVector(vector.Data[0]))
+}
+
+// searchSpace 使用已生成的查询向量检索单个空间内的知识，args 需已完成校验
+func (l *KnowledgeLogic) searchSpace(spaceID string, args KnowledgeSearchArgs, vector pgvector.Vector) (*KnowledgeSearchResult, error) {
+	opts, err := args.knowledgeOptions(spaceID)
+	if err != nil {
+		return nil, errors.New("KnowledgeLogic.Search.DateRange", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+	}
+
+	// 召回的候选分片数量由空间的检索参数决定
+	profile := l.core.RetrievalProfile(l.ctx, spaceID)
+	refs, err := l.searchRefs(spaceID, &opts, args.Query, vector, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &KnowledgeSearchResult{
+		List: []types.KnowledgeSearchHit{},
+	}
+
+	// 分面统计基于未应用筛选条件的全部候选，切换筛选条件时仍可看到其他取值的数量
+	facetRefs := refs
+	if args.hasFilters() {
+		if facetRefs, err = l.searchRefs(spaceID, nil, args.Query, vector, profile); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(refs) == 0 && len(facetRefs) == 0 {
+		return result, nil
+	}
+
+	// 按知识聚合命中的分片，保持召回顺序
+	knowledgeIDs, knowledgeRef := groupSearchRefs(refs)
+	facetKnowledgeIDs, _ := groupSearchRefs(facetRefs)
+
+	knowledgeMap, err := l.listSearchKnowledges(spaceID, lo.Union(knowledgeIDs, facetKnowledgeIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	var facetKnowledges []*types.Knowledge
+	for _, id := range facetKnowledgeIDs {
+		if knowledge, exist := knowledgeMap[id]; exist {
+			facetKnowledges = append(facetKnowledges, knowledge)
+		}
+	}
+	result.Facets = buildKnowledgeSearchFacets(facetKnowledges)
+
+	var (
+		hits     []*types.Knowledge
+		chunkIDs []string
+	)
+	for _, id := range knowledgeIDs {
+		knowledge, exist := knowledgeMap[id]
+		if !exist {
+			continue
+		}
+		hits = append(hits, knowledge)
+		if len(hits) > args.Limit {
+			continue
+		}
+		for i, ref := range knowledgeRef[id] {
+			if i >= knowledgeSearchMaxSnippets {
+				break
+			}
+			if ref.ID != ref.KnowledgeID {
+				chunkIDs = append(chunkIDs, ref.ID)
+			}
+		}
+	}
+
+	result.Total = len(hits)
+
+	chunks := make(map[string]string, len(chunkIDs))
+	if len(chunkIDs) > 0 {
+		list, err := l.core.Store().KnowledgeChunkStore().ListByIDs(l.ctx, spaceID, chunkIDs)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, errors.New("KnowledgeLogic.Search.KnowledgeChunkStore.ListByIDs", i18n.ERROR_INTERNAL, err)
+		}
+		for _, v := range list {
+			chunk, err := l.core.DecryptData([]byte(v.Chunk))
+			if err != nil {
+				return nil, errors.New("KnowledgeLogic.Search.DecryptData", i18n.ERROR_INTERNAL, err)
+			}
+			chunks[v.ID] = string(chunk)
+		}
+	}
+
+	terms := utils.FTSTerms(args.Query)
+	for _, knowledge := range hits[:min(len(hits), args.Limit)] {
+		hit := types.KnowledgeSearchHit{
+			ID:        knowledge.ID,
+			Title:     knowledge.Title,
+			Kind:      knowledge.Kind,
+			Resource:  knowledge.Resource,
+			Source:    knowledge.Source,
+			Tags:      knowledge.Tags,
+			MaybeDate: knowledge.MaybeDate,
+			CreatedAt: knowledge.CreatedAt,
+			Snippets:  []types.KnowledgeSearchSnippet{},
+		}
+
+		for i, ref := range knowledgeRef[knowledge.ID] {
+			hit.Similarity = max(hit.Similarity, ref.Cos)
+			if i == 0 {
+				hit.Score = ref.Score
+				if hit.Score == 0 {
+					hit.Score = float64(ref.Cos)
+				}
+			}
+			if i >= knowledgeSearchMaxSnippets {
+				break
+			}
+
+			text, exist := chunks[ref.ID]
+			if !exist && ref.ID == ref.KnowledgeID {
+				// 未切分的知识直接使用正文作为分片
+				if text, err = l.knowledgeMarkdownContent(knowledge); err != nil {
+					slog.Error("Failed to get knowledge content", slog.String("knowledge_id", knowledge.ID), slog.String("error", err.Error()))
+					continue
+				}
+				exist = true
+			}
+			if !exist {
+				continue
+			}
+
+			snippet, highlights := utils.HighlightSnippet(text, terms, knowledgeSearchSnippetSize)
+			hit.Snippets = append(hit.Snippets, types.KnowledgeSearchSnippet{
+				ChunkID:    ref.ID,
+				Text:       snippet,
+				Highlights: highlights,
+			})
+		}
+
+		result.List = append(result.List, hit)
+	}
+
+	return result, nil
+}
+
+// searchRefs 召回空间内与查询相关的分片并过滤相似度过低的结果，opts 为 nil 时不做筛选
+func (l *KnowledgeLogic) searchRefs(spaceID string, opts *types.GetKnowledgeOptions, query string, vector pgvector.Vector, profile types.RetrievalProfile) ([]types.QueryResult, error) {
+	refs, err := rag.QueryRelevanceRefs(l.ctx, l.core, types.GetVectorsOptions{
+		SpaceID:   spaceID,
+		Knowledge: opts,
+	}, query, vector, uint64(profile.CandidateLimit))
+	if err != nil {
+		return nil, errors.New("KnowledgeLogic.Search.QueryRelevanceRefs", i18n.ERROR_INTERNAL, err)
+	}
+	return filterSearchRefs(refs, profile), nil
+}
+
+// groupSearchRefs 按知识聚合命中的分片，返回的知识ID保持召回顺序
+func groupSearchRefs(refs []types.QueryResult) ([]string, map[string][]types.QueryResult) {
+	var (
+		knowledgeIDs []string
+		knowledgeRef = make(map[string][]types.QueryResult)
+	)
+	for _, v := range refs {
+		if _, exist := knowledgeRef[v.KnowledgeID]; !exist {
+			knowledgeIDs = append(knowledgeIDs, v.KnowledgeID)
+		}
+		knowledgeRef[v.KnowledgeID] = append(knowledgeRef[v.KnowledgeID], v)
+	}
+	return knowledgeIDs, knowledgeRef
+}
+
+func (l *KnowledgeLogic) listSearchKnowledges(spaceID string, ids []string) (map[string]*types.Knowledge, error) {
+	knowledges, err := l.core.Store().KnowledgeStore().ListKnowledges(l.ctx, types.GetKnowledgeOptions{
+		IDs:     ids,
+		SpaceID: spaceID,
+	}, 1, uint64(len(ids)))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.Search.KnowledgeStore.ListKnowledges", i18n.ERROR_INTERNAL, err)
+	}
+
+	result := make(map[string]*types.Knowledge, len(knowledges))
+	for _, v := range knowledges {
+		result[v.ID] = v
+	}
+	return result, nil
+}
+
+// knowledgeMarkdownContent 解密知识正文并转换为 markdown
+func (l *KnowledgeLogic) knowledgeMarkdownContent(knowledge *types.Knowledge) (string, error) {
+	content, err := l.core.DecryptData(knowledge.Content)
+	if err != nil {
+		return "", err
+	}
+	if knowledge.ContentType == types.KNOWLEDGE_CONTENT_TYPE_BLOCKS {
+		return editorjs.ConvertEditorJSRawToMarkdown(json.RawMessage(content))
+	}
+	return string(content), nil
+}
+
+// filterSearchRefs 过滤相似度过低的向量召回结果，关键词检索命中的记录始终保留
+func filterSearchRefs(refs []types.QueryResult, profile types.RetrievalProfile) []types.QueryResult {
+	cosLimit := min(profile.CosFloor, rag.TopCos(refs)-profile.CosDrop)
+
+	var result []types.QueryResult
+	for _, v := range refs {
+		if v.Rank == 0 && v.Cos < cosLimit {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+func buildKnowledgeSearchFacets(knowledges []*types.Knowledge) types.KnowledgeSearchFacets {
+	var (
+		kinds     = make(map[string]int)
+		sources   = make(map[string]int)
+		resources = make(map[string]int)
+		tags      = make(map[string]int)
+	)
+	for _, v := range knowledges {
+		kinds[string(v.Kind)]++
+		sources[v.Source]++
+		resources[v.Resource]++
+		for _, tag := range v.Tags {
+			tags[tag]++
+		}
+	}
+
+	return types.KnowledgeSearchFacets{
+		Kind:     facetCounts(kinds),
+		Source:   facetCounts(sources),
+		Resource: facetCounts(resources),
+		Tags:     facetCounts(tags),
+	}
+}
+
+// facetCounts 按数量降序输出分面统计，数量相同时按值排序
+func facetCounts(counts map[string]int) []types.FacetCount {
+	result := make([]types.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, types.FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/pgvector/pgvector-go"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/app/store/sqlstore"
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/cache"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+type searchEmbedding struct{}
+
+func (searchEmbedding) EmbeddingForQuery(ctx context.Context, content []string) (ai.EmbeddingResult, error) {
+	result := ai.EmbeddingResult{Model: "fake"}
+	for range content {
+		result.Data = append(result.Data, []float32{1, 0})
+	}
+	return result, nil
+}
+
+func (searchEmbedding) EmbeddingForDocument(ctx context.Context, title string, content []string) (ai.EmbeddingResult, error) {
+	return searchEmbedding{}.EmbeddingForQuery(ctx, content)
+}
+
+// searchVectorStore 所有知识均命中查询，仅按知识类型与标签筛选
+type searchVectorStore struct {
+	store.VectorStore
+	knowledges []*types.Knowledge
+}
+
+func (s *searchVectorStore) Query(ctx context.Context, opts types.GetVectorsOptions, vector pgvector.Vector, limit uint64) ([]types.QueryResult, error) {
+	var result []types.QueryResult
+	for _, v := range s.knowledges {
+		if opts.Knowledge != nil {
+			if len(opts.Knowledge.Kind) > 0 && !slices.Contains(opts.Knowledge.Kind, v.Kind) {
+				continue
+			}
+			if len(opts.Knowledge.Tags) > 0 && !slices.ContainsFunc(opts.Knowledge.Tags, func(tag string) bool {
+				return slices.Contains(v.Tags, tag)
+			}) {
+				continue
+			}
+		}
+		result = append(result, types.QueryResult{ID: v.ID, KnowledgeID: v.ID, Cos: 0.9, OriginalLength: 100})
+	}
+	return result, nil
+}
+
+type searchKnowledgeStore struct {
+	store.KnowledgeStore
+	knowledges []*types.Knowledge
+}
+
+func (s *searchKnowledgeStore) ListKnowledges(ctx context.Context, opts types.GetKnowledgeOptions, page, pageSize uint64) ([]*types.Knowledge, error) {
+	var result []*types.Knowledge
+	for _, v := range s.knowledges {
+		if slices.Contains(opts.IDs, v.ID) {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+type searchSpaceStore struct {
+	store.SpaceStore
+}
+
+func (searchSpaceStore) GetSpace(ctx context.Context, spaceID string) (*types.Space, error) {
+	return nil, sql.ErrNoRows
+}
+
+type searchCustomConfigStore struct {
+	store.CustomConfigStore
+}
+
+func (searchCustomConfigStore) Get(ctx context.Context, name string) (*types.CustomConfig, error) {
+	return nil, sql.ErrNoRows
+}
+
+type searchPlugins struct {
+	core.Plugins
+}
+
+func (searchPlugins) DecryptData(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+func TestKnowledgeSearchFacets(t *testing.T) {
+	knowledges := []*types.Knowledge{
+		{ID: "k1", SpaceID: "space", Title: "a", Kind: types.KNOWLEDGE_KIND_TEXT, Tags: []string{"go"}, Content: types.KnowledgeContent("golang")},
+		{ID: "k2", SpaceID: "space", Title: "b", Kind: types.KNOWLEDGE_KIND_TEXT, Tags: []string{"db"}, Content: types.KnowledgeContent("postgres")},
+		{ID: "k3", SpaceID: "space", Title: "c", Kind: types.KNOWLEDGE_KIND_IMAGE, Tags: []string{"go"}, Content: types.KnowledgeContent("gopher")},
+	}
+	stores := sqlstore.NewProviderWithStores(&sqlstore.Stores{
+		VectorStore:       &searchVectorStore{knowledges: knowledges},
+		KnowledgeStore:    &searchKnowledgeStore{knowledges: knowledges},
+		SpaceStore:        searchSpaceStore{},
+		CustomConfigStore: searchCustomConfigStore{},
+	})
+	c := core.NewCore(core.CoreConfig{}, stores, srv.SetupSrvs(srv.ApplyEmbeddingAI("fake", searchEmbedding{})), cache.NewLRU(cache.DEFAULT_LRU_SIZE))
+	c.Plugins = searchPlugins{}
+
+	result, err := NewKnowledgeLogic(context.Background(), c).Search("space", KnowledgeSearchArgs{
+		Query: "go",
+		Tags:  []string{"go"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Total != 2 || len(result.List) != 2 {
+		t.Fatalf("expected 2 filtered hits, got total %d, list %d", result.Total, len(result.List))
+	}
+	// 分面统计包含被筛选条件排除的知识
+	expectedTags := []types.FacetCount{{Value: "go", Count: 2}, {Value: "db", Count: 1}}
+	if !slices.Equal(result.Facets.Tags, expectedTags) {
+		t.Errorf("expected tag facets over unfiltered candidates %v, got %v", expectedTags, result.Facets.Tags)
+	}
+	expectedKinds := []types.FacetCount{{Value: string(types.KNOWLEDGE_KIND_TEXT), Count: 2}, {Value: string(types.KNOWLEDGE_KIND_IMAGE), Count: 1}}
+	if !slices.Equal(result.Facets.Kind, expectedKinds) {
+		t.Errorf("expected kind facets over unfiltered candidates %v, got %v", expectedKinds, result.Facets.Kind)
+	}
+}
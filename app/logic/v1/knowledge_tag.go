@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// normalizeTags 去除标签首尾空白、空标签与重复标签
+func normalizeTags(tags []string) []string {
+	return lo.Uniq(lo.FilterMap(tags, func(item string, _ int) (string, bool) {
+		item = strings.TrimSpace(item)
+		return item, item != ""
+	}))
+}
+
+// ListSpaceTags 获取空间内所有标签及其使用数量
+func (l *KnowledgeLogic) ListSpaceTags(spaceID string) ([]types.KnowledgeTag, error) {
+	list, err := l.core.Store().KnowledgeStore().ListTags(l.ctx, types.GetKnowledgeOptions{
+		SpaceID: spaceID,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.ListSpaceTags.KnowledgeStore.ListTags", i18n.ERROR_INTERNAL, err)
+	}
+	if list == nil {
+		list = []types.KnowledgeTag{}
+	}
+	return list, nil
+}
+
+// RenameTag 重命名空间内的标签，若新标签已存在则与之合并
+func (l *KnowledgeLogic) RenameTag(spaceID, from, to string) (int64, error) {
+	return l.MergeTags(spaceID, []string{from}, to)
+}
+
+// MergeTags 将多个标签合并为 target
+func (l *KnowledgeLogic) MergeTags(spaceID string, sources []string, target string) (int64, error) {
+	target = strings.TrimSpace(target)
+	sources = lo.Without(normalizeTags(sources), target)
+	if target == "" || len(sources) == 0 {
+		return 0, errors.New("KnowledgeLogic.MergeTags.Args", i18n.ERROR_INVALIDARGUMENT, nil).Code(http.StatusBadRequest)
+	}
+
+	affected, err := l.core.Store().KnowledgeStore().ReplaceTags(l.ctx, spaceID, sources, target)
+	if err != nil {
+		return 0, errors.New("KnowledgeLogic.MergeTags.KnowledgeStore.ReplaceTags", i18n.ERROR_INTERNAL, err)
+	}
+	return affected, nil
+}
+
+// DeleteTags 从空间内所有知识中批量移除标签
+func (l *KnowledgeLogic) DeleteTags(spaceID string, tags []string) (int64, error) {
+	if tags = normalizeTags(tags); len(tags) == 0 {
+		return 0, errors.New("KnowledgeLogic.DeleteTags.Args", i18n.ERROR_INVALIDARGUMENT, nil).Code(http.StatusBadRequest)
+	}
+
+	affected, err := l.core.Store().KnowledgeStore().RemoveTags(l.ctx, spaceID, tags)
+	if err != nil {
+		return 0, errors.New("KnowledgeLogic.DeleteTags.KnowledgeStore.RemoveTags", i18n.ERROR_INTERNAL, err)
+	}
+	return affected, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/quka-ai/quka-ai/app/core"
 	"github.com/quka-ai/quka-ai/pkg/ai/agents/knowledge"
+	"github.com/quka-ai/quka-ai/pkg/types"
 )
 
 // NewKnowledgeToolsWithLogic 创建 knowledge tools,通过闭包注入 logic 层方法
@@ -16,10 +17,16 @@ func NewKnowledgeToolsWithLogic(core *core.Core, spaceID, sessionID, userID stri
 	knowledgeLogic := NewKnowledgeLogic(ctx, core)
 	resourceLogic := NewResourceLogic(ctx, core)
 
+	// AI 通过工具修改知识时记录为 agent 来源的修订
+	updateByAgent := func(spaceID, id string, args types.UpdateKnowledgeArgs) error {
+		return knowledgeLogic.UpdateWithSource(spaceID, id, args, types.KNOWLEDGE_REVISION_SOURCE_AGENT, userID)
+	}
+
 	knowledgeFuncs := knowledge.KnowledgeLogicFunctions{
 		InsertContentAsyncWithSource: knowledgeLogic.InsertContentAsyncWithSource,
 		GetKnowledge:                 knowledgeLogic.GetKnowledge,
-		Update:                       knowledgeLogic.Update,
+		Update:                       updateByAgent,
+		ListRelatedKnowledges:        knowledgeLogic.ListRelatedKnowledges,
 	}
 
 	resourceFuncs := knowledge.ResourceLogicFunctions{
@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/safe"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc(process.ProcessKey{}, func(provider *process.Process) {
+		provider.Cron().AddFunc("30 3 * * *", func() {
+			if err := PurgeExpiredTrash(context.Background(), provider.Core()); err != nil {
+				slog.Error("Failed to purge expired knowledge trash", slog.String("error", err.Error()))
+			}
+		})
+	})
+}
+
+// ListTrash 分页获取空间回收站中的知识
+func (l *KnowledgeLogic) ListTrash(spaceID, keywords string, page, pagesize uint64) ([]*types.Knowledge, uint64, error) {
+	return l.ListKnowledges(types.GetKnowledgeOptions{
+		SpaceID:        spaceID,
+		Keywords:       keywords,
+		IncludeExpired: true,
+		DeletedOnly:    true,
+	}, page, pagesize)
+}
+
+// getTrashedKnowledge 获取回收站中的知识，知识不存在或未被删除时返回 not found
+func (l *KnowledgeLogic) getTrashedKnowledge(spaceID, id string) (*types.Knowledge, error) {
+	user := l.GetUserInfo()
+	if err := l.core.Srv().RBAC().Check(user, l.lazyRolerFromKnowledgeID(spaceID, id), srv.PermissionEdit); err != nil {
+		return nil, errors.Trace("KnowledgeLogic.getTrashedKnowledge", err)
+	}
+
+	knowledge, err := l.core.Store().KnowledgeStore().GetKnowledge(l.ctx, spaceID, id)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("KnowledgeLogic.getTrashedKnowledge.KnowledgeStore.GetKnowledge", i18n.ERROR_INTERNAL, err)
+	}
+	if knowledge == nil || knowledge.DeletedAt == 0 {
+		return nil, errors.New("KnowledgeLogic.getTrashedKnowledge.KnowledgeStore.GetKnowledge.nil", i18n.ERROR_NOT_FOUND, err).Code(http.StatusNotFound)
+	}
+	return knowledge, nil
+}
+
+// RestoreFromTrash 将知识从回收站恢复，移入回收站时被移除的向量会重新生成
+func (l *KnowledgeLogic) RestoreFromTrash(spaceID, id string) error {
+	knowledge, err := l.getTrashedKnowledge(spaceID, id)
+	if err != nil {
+		return err
+	}
+
+	vector, err := l.core.Store().VectorStore().GetVector(l.ctx, spaceID, id)
+	if err != nil && err != sql.ErrNoRows {
+		return errors.New("KnowledgeLogic.RestoreFromTrash.VectorStore.GetVector", i18n.ERROR_INTERNAL, err)
+	}
+
+	err = l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
+		if err := l.core.Store().KnowledgeStore().SetDeletedAt(ctx, spaceID, id, 0); err != nil {
+			return errors.New("KnowledgeLogic.RestoreFromTrash.KnowledgeStore.SetDeletedAt", i18n.ERROR_INTERNAL, err)
+		}
+
+		// 已处理完成但向量已被移除的知识，基于保留的分片重新生成向量
+		if vector == nil && knowledge.Stage == types.KNOWLEDGE_STAGE_DONE {
+			knowledge.Stage = types.KNOWLEDGE_STAGE_EMBEDDING
+			if err := l.core.Store().KnowledgeStore().Update(ctx, spaceID, id, types.UpdateKnowledgeArgs{
+				Stage: knowledge.Stage,
+			}); err != nil {
+				return errors.New("KnowledgeLogic.RestoreFromTrash.KnowledgeStore.Update", i18n.ERROR_INTERNAL, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if knowledge.Stage != types.KNOWLEDGE_STAGE_SUMMARIZE && knowledge.Stage != types.KNOWLEDGE_STAGE_EMBEDDING {
+		return nil
+	}
+
+	if knowledge.Content, err = l.core.DecryptData(knowledge.Content); err != nil {
+		return errors.New("KnowledgeLogic.RestoreFromTrash.DecryptData", i18n.ERROR_INTERNAL, err)
+	}
+	knowledge.DeletedAt = 0
+
+	go safe.Run(func() {
+		if knowledge.Stage == types.KNOWLEDGE_STAGE_EMBEDDING {
+			process.NewEmbeddingRequest(*knowledge)
+			return
+		}
+		if err := l.processKnowledgeAsync(*knowledge); err != nil {
+			slog.Error("Process restored knowledge async failed",
+				slog.String("space_id", knowledge.SpaceID),
+				slog.String("knowledge_id", knowledge.ID),
+				slog.Any("error", err))
+		}
+	})
+	return nil
+}
+
+// PurgeFromTrash 彻底删除回收站中的知识
+func (l *KnowledgeLogic) PurgeFromTrash(spaceID, id string) error {
+	knowledge, err := l.getTrashedKnowledge(spaceID, id)
+	if err != nil {
+		return err
+	}
+
+	if err = purgeKnowledge(l.ctx, l.core, knowledge); err != nil {
+		return errors.Trace("KnowledgeLogic.PurgeFromTrash", err)
+	}
+	return nil
+}
+
+// purgeKnowledge 彻底删除知识，编辑器内容引用的文件会被标记为待删除
+func purgeKnowledge(ctx context.Context, core *core.Core, knowledge *types.Knowledge) error {
+	return core.Store().Transaction(ctx, func(ctx context.Context) error {
+		if knowledge.ContentType == types.KNOWLEDGE_CONTENT_TYPE_BLOCKS {
+			actData, err := core.DecryptData(knowledge.Content)
+			if err != nil {
+				slog.Error("Failed to decrypt knowledge data for mark file status to delete", slog.String("error", err.Error()))
+				actData = knowledge.Content
+			}
+			if err = UpdateFilesToDelete(ctx, core, knowledge.SpaceID, actData); err != nil {
+				slog.Error("Failed to remark knowledge files to delete status", slog.String("knowledge_id", knowledge.ID), slog.String("space_id", knowledge.SpaceID), slog.Any("error", err))
+			}
+		}
+
+		if err := process.PurgeKnowledge(ctx, core, knowledge.SpaceID, knowledge.ID); err != nil {
+			return errors.New("purgeKnowledge.PurgeKnowledge", i18n.ERROR_INTERNAL, err)
+		}
+		return nil
+	})
+}
+
+// PurgeExpiredTrash 彻底删除超过所在空间回收站保留期的知识
+func PurgeExpiredTrash(ctx context.Context, core *core.Core) error {
+	var (
+		now      = time.Now()
+		deadline = make(map[string]int64)
+		afterID  string
+		purged   int
+	)
+
+	for {
+		list, err := core.Store().KnowledgeStore().ListKnowledgesAfterID(ctx, types.GetKnowledgeOptions{
+			IncludeExpired: true,
+			DeletedOnly:    true,
+		}, afterID, 200)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if len(list) == 0 {
+			break
+		}
+		afterID = list[len(list)-1].ID
+
+		for _, v := range list {
+			before, ok := deadline[v.SpaceID]
+			if !ok {
+				conf := types.TrashConfig{}
+				if space, err := core.GetSpace(ctx, v.SpaceID); err == nil {
+					conf = space.Settings.TrashConfig()
+				}
+				before = conf.PurgeBefore(now)
+				deadline[v.SpaceID] = before
+			}
+			if v.DeletedAt > before {
+				continue
+			}
+
+			if err = purgeKnowledge(ctx, core, v); err != nil {
+				slog.Error("Failed to purge trashed knowledge", slog.String("space_id", v.SpaceID), slog.String("knowledge_id", v.ID), slog.String("error", err.Error()))
+				continue
+			}
+			purged++
+		}
+	}
+
+	if purged > 0 {
+		slog.Info("Purged expired knowledge trash", slog.Int("count", purged))
+	}
+	return nil
+}
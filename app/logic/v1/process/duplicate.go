@@ -0,0 +1,173 @@
+package process
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sort"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+const (
+	// DUPLICATE_DETECTION_MAX_CHUNKS 参与重复检测的分片数量上限
+	DUPLICATE_DETECTION_MAX_CHUNKS = 10
+	// DUPLICATE_DETECTION_CANDIDATES 每个分片召回的相似向量数量
+	DUPLICATE_DETECTION_CANDIDATES = 5
+)
+
+// detectDuplicates 在知识写入向量前，检测空间内与之近似重复的已有知识
+// merge 表示按空间配置需要将知识自动合并到最相似的已有知识(duplicates[0])，否则仅标记疑似重复
+func (p *KnowledgeProcess) detectDuplicates(ctx context.Context, knowledge *types.Knowledge, vectors []types.Vector) (duplicates []types.KnowledgeDuplicate, merge bool) {
+	logAttrs := []any{
+		slog.String("space_id", knowledge.SpaceID),
+		slog.String("knowledge_id", knowledge.ID),
+		slog.String("component", "KnowledgeProcess.detectDuplicates"),
+	}
+
+	space, err := p.core.Store().SpaceStore().GetSpace(ctx, knowledge.SpaceID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			slog.Error("Failed to get space settings", append(logAttrs, slog.String("error", err.Error()))...)
+		}
+		return nil, false
+	}
+
+	conf := space.Settings.DuplicateDetectionConfig()
+	if conf.Mode == types.DUPLICATE_DETECTION_OFF {
+		return nil, false
+	}
+
+	duplicates, err = FindDuplicateKnowledges(ctx, p.core, knowledge, vectors, conf.Threshold)
+	if err != nil {
+		slog.Error("Failed to find duplicate knowledges", append(logAttrs, slog.String("error", err.Error()))...)
+		return nil, false
+	}
+	if len(duplicates) == 0 {
+		return nil, false
+	}
+
+	// 编辑器内容可能引用了上传的文件，需要由用户确认后再合并
+	return duplicates, conf.Mode == types.DUPLICATE_DETECTION_MERGE && knowledge.ContentType != types.KNOWLEDGE_CONTENT_TYPE_BLOCKS
+}
+
+// publishKnowledgeMergedMessage 通知客户端知识已被合并到已有知识并移入回收站
+func publishKnowledgeMergedMessage(centrifuge srv.CentrifugeManager, spaceID, knowledgeID, duplicateOf string) {
+	topic := "/knowledge/list/" + spaceID
+	data := map[string]interface{}{
+		"knowledge_id": knowledgeID,
+		"duplicate_of": duplicateOf,
+	}
+	if err := centrifuge.PublishStreamMessageWithSubject(topic, "knowledge_merged", types.WS_EVENT_OTHERS, data); err != nil {
+		slog.Error("Failed to publish knowledge merged message", slog.String("topic", topic), slog.String("knowledge_id", knowledgeID), slog.String("error", err.Error()))
+	}
+}
+
+// FindDuplicateKnowledges 使用知识的分片向量检索空间内相似的已有知识，返回相似度不低于 threshold 的结果，按相似度降序排列
+func FindDuplicateKnowledges(ctx context.Context, core *core.Core, knowledge *types.Knowledge, vectors []types.Vector, threshold float32) ([]types.KnowledgeDuplicate, error) {
+	if len(vectors) > DUPLICATE_DETECTION_MAX_CHUNKS {
+		vectors = vectors[:DUPLICATE_DETECTION_MAX_CHUNKS]
+	}
+
+	matches := make([][]types.QueryResult, 0, len(vectors))
+	for _, v := range vectors {
+		refs, err := core.Store().VectorStore().Query(ctx, types.GetVectorsOptions{
+			SpaceID:             knowledge.SpaceID,
+			Model:               v.Model,
+			ExcludeKnowledgeIDs: []string{knowledge.ID},
+		}, v.Embedding, DUPLICATE_DETECTION_CANDIDATES)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		matches = append(matches, refs)
+	}
+
+	// 已经存在的知识对(包括已被忽略的)不再重复记录
+	exists, err := core.Store().KnowledgeDuplicateStore().List(ctx, types.ListKnowledgeDuplicateOptions{
+		SpaceID:     knowledge.SpaceID,
+		KnowledgeID: knowledge.ID,
+	}, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	related := make(map[string]struct{}, len(exists))
+	for _, v := range exists {
+		related[v.KnowledgeID] = struct{}{}
+		related[v.DuplicateOf] = struct{}{}
+	}
+
+	var result []types.KnowledgeDuplicate
+	for id, similarity := range DuplicateSimilarity(matches) {
+		if _, ok := related[id]; ok || similarity < threshold {
+			continue
+		}
+		result = append(result, types.KnowledgeDuplicate{
+			ID:          utils.GenUniqIDStr(),
+			SpaceID:     knowledge.SpaceID,
+			KnowledgeID: knowledge.ID,
+			DuplicateOf: id,
+			Similarity:  similarity,
+			Status:      types.KNOWLEDGE_DUPLICATE_STATUS_PENDING,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Similarity > result[j].Similarity
+	})
+	return result, nil
+}
+
+// DuplicateSimilarity 根据每个分片召回的相似向量计算知识级别的相似度
+// 已有知识的相似度为各分片与其最相似向量的相似度均值，分片未召回该知识时按 0 计算，
+// 因此只有大部分分片都高度相似时才会被判定为重复
+func DuplicateSimilarity(matches [][]types.QueryResult) map[string]float32 {
+	result := make(map[string]float32)
+	if len(matches) == 0 {
+		return result
+	}
+
+	for _, refs := range matches {
+		best := make(map[string]float32)
+		for _, v := range refs {
+			if v.Cos > best[v.KnowledgeID] {
+				best[v.KnowledgeID] = v.Cos
+			}
+		}
+		for id, cos := range best {
+			result[id] += cos
+		}
+	}
+
+	for id := range result {
+		result[id] /= float32(len(matches))
+	}
+	return result
+}
+
+// MergeDuplicateKnowledge 将重复的知识合并到已有知识：合并标签后将重复的知识移入回收站，
+// 其内容与修订保留在回收站中，误合并时可以恢复。需在事务中调用
+func MergeDuplicateKnowledge(ctx context.Context, core *core.Core, knowledge *types.Knowledge, duplicate types.KnowledgeDuplicate) error {
+	target, err := core.Store().KnowledgeStore().GetKnowledge(ctx, knowledge.SpaceID, duplicate.DuplicateOf)
+	if err != nil {
+		return err
+	}
+
+	if tags := lo.Union(target.Tags, knowledge.Tags); len(tags) > len(target.Tags) {
+		if err = core.Store().KnowledgeStore().Update(ctx, target.SpaceID, target.ID, types.UpdateKnowledgeArgs{
+			Tags: tags,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err = core.Store().KnowledgeDuplicateStore().UpdateStatus(ctx, knowledge.SpaceID, []string{duplicate.ID}, types.KNOWLEDGE_DUPLICATE_STATUS_MERGED); err != nil {
+		return err
+	}
+	// 修订与引用的文件在回收站清理时一并删除
+	return SoftDeleteKnowledge(ctx, core, knowledge.SpaceID, knowledge.ID)
+}
@@ -0,0 +1,34 @@
+package process
+
+import (
+	"math"
+	"testing"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func TestDuplicateSimilarity(t *testing.T) {
+	matches := [][]types.QueryResult{
+		{
+			{KnowledgeID: "a", Cos: 0.98},
+			{KnowledgeID: "a", Cos: 0.90},
+			{KnowledgeID: "b", Cos: 0.97},
+		},
+		{
+			{KnowledgeID: "a", Cos: 0.96},
+		},
+	}
+
+	result := DuplicateSimilarity(matches)
+	if math.Abs(float64(result["a"]-0.97)) > 1e-6 {
+		t.Errorf("similarity of a = %v, want 0.97", result["a"])
+	}
+	// b 只被一个分片召回
+	if math.Abs(float64(result["b"]-0.485)) > 1e-6 {
+		t.Errorf("similarity of b = %v, want 0.485", result["b"])
+	}
+
+	if len(DuplicateSimilarity(nil)) != 0 {
+		t.Error("expected empty result without matches")
+	}
+}
@@ -148,13 +148,11 @@ func (t *ExpirationCleanupTask) hardDelete(ctx context.Context, knowledge *types
 	})
 }
 
-// softDelete 软删除：标记删除状态（需要在数据结构中添加deleted_at字段）
+// softDelete 软删除：将过期知识移入回收站，超过空间的回收站保留期后再被彻底删除
 func (t *ExpirationCleanupTask) softDelete(ctx context.Context, knowledge *types.Knowledge) error {
-	// 注意：这里需要在Knowledge表中添加deleted_at字段
-	// 目前暂时使用硬删除，后续可以扩展软删除功能
-	slog.Warn("Soft delete not implemented yet, using hard delete",
-		slog.String("knowledge_id", knowledge.ID))
-	return t.hardDelete(ctx, knowledge)
+	return t.core.Store().Transaction(ctx, func(txCtx context.Context) error {
+		return SoftDeleteKnowledge(txCtx, t.core, knowledge.SpaceID, knowledge.ID)
+	})
 }
 
 // archive 归档：移动到归档表（需要创建归档表）
@@ -0,0 +1,180 @@
+package process
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+const (
+	// KNOWLEDGE_GRAPH_MAX_ENTITIES 单条知识保存的实体数量上限
+	KNOWLEDGE_GRAPH_MAX_ENTITIES = 30
+	// KNOWLEDGE_GRAPH_MAX_RELATIONS 单条知识保存的实体关系数量上限
+	KNOWLEDGE_GRAPH_MAX_RELATIONS = 30
+	// KNOWLEDGE_GRAPH_MAX_NAME_LENGTH 实体名称的最大长度
+	KNOWLEDGE_GRAPH_MAX_NAME_LENGTH = 100
+)
+
+// updateKnowledgeGraph 在知识总结完成后更新其提及的实体、实体关系及显式引用
+// 实体与关系以明文存储，空间未开启知识图谱时只更新显式引用
+func (p *KnowledgeProcess) updateKnowledgeGraph(ctx context.Context, knowledge *types.Knowledge, content string, entities []ai.ChunkEntity, relations []ai.ChunkRelation) {
+	space, err := p.core.GetSpace(ctx, knowledge.SpaceID)
+	if err != nil && err != sql.ErrNoRows {
+		slog.Error("Failed to get space knowledge graph setting",
+			slog.String("space_id", knowledge.SpaceID),
+			slog.String("knowledge_id", knowledge.ID),
+			slog.String("component", "KnowledgeProcess.updateKnowledgeGraph"),
+			slog.String("error", err.Error()))
+		return
+	}
+	if space == nil || !space.Settings.IsKnowledgeGraphEnabled() {
+		entities, relations = nil, nil
+	}
+
+	err = p.core.Store().Transaction(ctx, func(ctx context.Context) error {
+		return UpdateKnowledgeGraph(ctx, p.core, knowledge, content, entities, relations)
+	})
+	if err != nil {
+		slog.Error("Failed to update knowledge graph",
+			slog.String("space_id", knowledge.SpaceID),
+			slog.String("knowledge_id", knowledge.ID),
+			slog.String("component", "KnowledgeProcess.updateKnowledgeGraph"),
+			slog.String("error", err.Error()))
+	}
+}
+
+// UpdateKnowledgeGraph 使用新抽取的实体、关系及内容中的 [[knowledge-id]] 引用替换知识原有的图谱数据，需在事务中调用
+func UpdateKnowledgeGraph(ctx context.Context, core *core.Core, knowledge *types.Knowledge, content string, entities []ai.ChunkEntity, relations []ai.ChunkRelation) error {
+	if err := core.Store().KnowledgeEntityMentionStore().DeleteByKnowledge(ctx, knowledge.SpaceID, knowledge.ID); err != nil {
+		return err
+	}
+	if err := core.Store().KnowledgeRelationStore().DeleteByKnowledge(ctx, knowledge.SpaceID, knowledge.ID); err != nil {
+		return err
+	}
+	if err := core.Store().KnowledgeLinkStore().DeleteByKnowledge(ctx, knowledge.SpaceID, knowledge.ID); err != nil {
+		return err
+	}
+
+	saved, err := core.Store().KnowledgeEntityStore().Upsert(ctx, BuildKnowledgeEntities(knowledge.SpaceID, entities, relations))
+	if err != nil {
+		return err
+	}
+
+	entityIDs := lo.SliceToMap(saved, func(item types.KnowledgeEntity) (string, string) {
+		return item.NameKey, item.ID
+	})
+	if err = core.Store().KnowledgeEntityMentionStore().BatchCreate(ctx, lo.Map(saved, func(item types.KnowledgeEntity, _ int) types.KnowledgeEntityMention {
+		return types.KnowledgeEntityMention{
+			SpaceID:     knowledge.SpaceID,
+			EntityID:    item.ID,
+			KnowledgeID: knowledge.ID,
+		}
+	})); err != nil {
+		return err
+	}
+
+	var relationList []types.KnowledgeRelation
+	for _, v := range relations {
+		sourceID, ok1 := entityIDs[types.EntityNameKey(v.Source)]
+		targetID, ok2 := entityIDs[types.EntityNameKey(v.Target)]
+		relation := strings.TrimSpace(v.Relation)
+		if !ok1 || !ok2 || sourceID == targetID || relation == "" {
+			continue
+		}
+		relationList = append(relationList, types.KnowledgeRelation{
+			ID:          utils.GenUniqIDStr(),
+			SpaceID:     knowledge.SpaceID,
+			KnowledgeID: knowledge.ID,
+			SourceID:    sourceID,
+			TargetID:    targetID,
+			Relation:    lo.Substring(relation, 0, KNOWLEDGE_GRAPH_MAX_NAME_LENGTH),
+		})
+		if len(relationList) >= KNOWLEDGE_GRAPH_MAX_RELATIONS {
+			break
+		}
+	}
+	if err = core.Store().KnowledgeRelationStore().BatchCreate(ctx, relationList); err != nil {
+		return err
+	}
+
+	linkIDs := lo.Without(utils.ParseKnowledgeLinks(content), knowledge.ID)
+	if len(linkIDs) == 0 {
+		return nil
+	}
+	// 只保留同一空间内存在的知识
+	targets, err := core.Store().KnowledgeStore().ListLiteKnowledges(ctx, types.GetKnowledgeOptions{
+		SpaceID:        knowledge.SpaceID,
+		IDs:            linkIDs,
+		IncludeExpired: true,
+	}, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	return core.Store().KnowledgeLinkStore().BatchCreate(ctx, lo.Map(targets, func(item *types.KnowledgeLite, _ int) types.KnowledgeLink {
+		return types.KnowledgeLink{
+			SpaceID:     knowledge.SpaceID,
+			KnowledgeID: knowledge.ID,
+			TargetID:    item.ID,
+		}
+	}))
+}
+
+// BuildKnowledgeEntities 清洗模型抽取的实体，按归一化名称去重，关系中出现但未单独列出的实体也会被补充
+func BuildKnowledgeEntities(spaceID string, entities []ai.ChunkEntity, relations []ai.ChunkRelation) []types.KnowledgeEntity {
+	for _, v := range relations {
+		entities = append(entities, ai.ChunkEntity{Name: v.Source}, ai.ChunkEntity{Name: v.Target})
+	}
+
+	var (
+		result []types.KnowledgeEntity
+		index  = make(map[string]int)
+	)
+	for _, v := range entities {
+		name := lo.Substring(strings.Join(strings.Fields(v.Name), " "), 0, KNOWLEDGE_GRAPH_MAX_NAME_LENGTH)
+		if name == "" {
+			continue
+		}
+		key := types.EntityNameKey(name)
+		if i, ok := index[key]; ok {
+			if result[i].Type == "" {
+				result[i].Type = strings.ToLower(strings.TrimSpace(v.Type))
+			}
+			continue
+		}
+		if len(result) >= KNOWLEDGE_GRAPH_MAX_ENTITIES {
+			continue
+		}
+
+		index[key] = len(result)
+		result = append(result, types.KnowledgeEntity{
+			ID:      utils.GenUniqIDStr(),
+			SpaceID: spaceID,
+			Name:    name,
+			NameKey: key,
+			Type:    strings.ToLower(strings.TrimSpace(v.Type)),
+		})
+	}
+	return result
+}
+
+// DeleteKnowledgeGraph 删除知识的图谱数据，包括指向该知识的引用，需在事务中调用
+func DeleteKnowledgeGraph(ctx context.Context, core *core.Core, spaceID, knowledgeID string) error {
+	if err := core.Store().KnowledgeEntityMentionStore().DeleteByKnowledge(ctx, spaceID, knowledgeID); err != nil {
+		return err
+	}
+	if err := core.Store().KnowledgeRelationStore().DeleteByKnowledge(ctx, spaceID, knowledgeID); err != nil {
+		return err
+	}
+	if err := core.Store().KnowledgeLinkStore().DeleteByKnowledge(ctx, spaceID, knowledgeID); err != nil {
+		return err
+	}
+	return core.Store().KnowledgeLinkStore().DeleteByTarget(ctx, spaceID, knowledgeID)
+}
@@ -0,0 +1,44 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+func TestBuildKnowledgeEntities(t *testing.T) {
+	utils.SetupIDWorker(1)
+
+	entities := []ai.ChunkEntity{
+		{Name: "OpenAI", Type: "Organization"},
+		{Name: "  openai ", Type: "product"},
+		{Name: "Sam  Altman"},
+		{Name: "", Type: "person"},
+	}
+	relations := []ai.ChunkRelation{
+		{Source: "sam altman", Target: "OpenAI", Relation: "leads"},
+		{Source: "OpenAI", Target: "ChatGPT", Relation: "develops"},
+	}
+
+	result := BuildKnowledgeEntities("space", entities, relations)
+	if len(result) != 3 {
+		t.Fatalf("expected 3 entities, got %+v", result)
+	}
+
+	if result[0].Name != "OpenAI" || result[0].Type != "organization" || result[0].NameKey != "openai" {
+		t.Errorf("unexpected first entity: %+v", result[0])
+	}
+	if result[1].Name != "Sam Altman" || result[1].NameKey != "sam altman" {
+		t.Errorf("unexpected second entity: %+v", result[1])
+	}
+	// 仅在关系中出现的实体也会被补充
+	if result[2].Name != "ChatGPT" || result[2].Type != "" {
+		t.Errorf("unexpected third entity: %+v", result[2])
+	}
+	for _, v := range result {
+		if v.SpaceID != "space" || v.ID == "" {
+			t.Errorf("expected space id and id to be set: %+v", v)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/types/protocol"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+const (
+	// IMPORT_TASK_STALE_TIMEOUT 导入任务超过该时间没有进展时视为已中断(如服务重启)
+	IMPORT_TASK_STALE_TIMEOUT = 30 * time.Minute
+	// IMPORT_PROGRESS_PUBLISH_INTERVAL 通过 Centrifuge 推送导入进度的最小间隔
+	IMPORT_PROGRESS_PUBLISH_INTERVAL = time.Second
+)
+
+func init() {
+	register.RegisterFunc(ProcessKey{}, func(provider *Process) {
+		provider.Cron().AddFunc("*/10 * * * *", func() {
+			if err := FailStaleImportTasks(context.Background(), provider.Core()); err != nil {
+				slog.Error("Failed to check stale import tasks", slog.String("error", err.Error()))
+			}
+		})
+	})
+}
+
+// FailStaleImportTasks 将长时间没有进展的导入任务标记为失败
+// 导入数据只保存在执行任务的进程内存中，进程退出后任务无法继续
+func FailStaleImportTasks(ctx context.Context, core *core.Core) error {
+	tasks, err := core.Store().ImportTaskStore().ListByStatus(ctx, types.IMPORT_TASK_STATUS_RUNNING)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(-IMPORT_TASK_STALE_TIMEOUT).Unix()
+	for _, task := range tasks {
+		if task.UpdatedAt > deadline {
+			continue
+		}
+		if err = core.Store().ImportTaskStore().UpdateStatus(ctx, task.ID, types.IMPORT_TASK_STATUS_FAILED, "import interrupted"); err != nil {
+			return err
+		}
+		slog.Warn("Import task interrupted", slog.String("task_id", task.ID), slog.String("space_id", task.SpaceID))
+	}
+	return nil
+}
+
+// importRunner 各类导入共用的进度记录、resource 映射与知识创建逻辑
+type importRunner struct {
+	core       *core.Core
+	task       *types.ImportTask
+	onProgress func(task types.ImportTask)
+
+	resources   map[string]string // 目录名(小写) -> resource id
+	publishedAt time.Time
+}
+
+func newImportRunner(core *core.Core, task *types.ImportTask) *importRunner {
+	return &importRunner{
+		core: core,
+		task: task,
+	}
+}
+
+// record 保存单个条目的处理结果并更新任务进度
+func (r *importRunner) record(ctx context.Context, item types.ImportTaskItem) {
+	item.ID = utils.GenUniqIDStr()
+	item.TaskID = r.task.ID
+	item.SpaceID = r.task.SpaceID
+	if err := r.core.Store().ImportTaskItemStore().Create(ctx, item); err != nil {
+		slog.Error("Failed to save import item", slog.String("task_id", r.task.ID), slog.String("path", item.Path), slog.String("error", err.Error()))
+	}
+
+	r.task.Processed++
+	if item.Status == types.IMPORT_ITEM_STATUS_FAILED {
+		r.task.Failed++
+	}
+	if err := r.core.Store().ImportTaskStore().UpdateProgress(ctx, r.task.ID, r.task.Processed, r.task.Failed); err != nil {
+		slog.Error("Failed to update import progress", slog.String("task_id", r.task.ID), slog.String("error", err.Error()))
+	}
+	r.task.UpdatedAt = time.Now().Unix()
+
+	if time.Since(r.publishedAt) >= IMPORT_PROGRESS_PUBLISH_INTERVAL {
+		r.publishProgress()
+	}
+	if r.onProgress != nil {
+		r.onProgress(*r.task)
+	}
+}
+
+// finish 结束任务，err 不为空时任务标记为失败
+func (r *importRunner) finish(ctx context.Context, err error) error {
+	status, errMsg := types.IMPORT_TASK_STATUS_FINISHED, ""
+	if err != nil {
+		status, errMsg = types.IMPORT_TASK_STATUS_FAILED, err.Error()
+	}
+
+	// 使用独立的 ctx，确保任务被取消时状态依然能够写入
+	if updateErr := r.core.Store().ImportTaskStore().UpdateStatus(context.WithoutCancel(ctx), r.task.ID, status, errMsg); updateErr != nil {
+		return fmt.Errorf("failed to update import task status: %w", updateErr)
+	}
+	r.task.Status = status
+	r.task.Error = errMsg
+	r.task.FinishedAt = time.Now().Unix()
+
+	slog.Info("Import task finished", slog.String("task_id", r.task.ID), slog.String("status", status),
+		slog.Int64("processed", r.task.Processed), slog.Int64("failed", r.task.Failed))
+	r.publishProgress()
+	if r.onProgress != nil {
+		r.onProgress(*r.task)
+	}
+	return err
+}
+
+// publishProgress 向空间的知识列表频道推送导入进度
+func (r *importRunner) publishProgress() {
+	r.publishedAt = time.Now()
+	centrifuge := r.core.Srv().Centrifuge()
+	if centrifuge == nil {
+		return
+	}
+
+	topic := protocol.KnowledgeListTopicPrefix + r.task.SpaceID
+	if err := centrifuge.PublishStreamMessageWithSubject(topic, "import_progress", types.WS_EVENT_OTHERS, *r.task); err != nil {
+		slog.Error("Failed to publish import progress", slog.String("topic", topic), slog.String("task_id", r.task.ID), slog.String("error", err.Error()))
+	}
+}
+
+// applyFolders 按任务的目录映射方式将目录层级映射为知识的 resource 或标签
+func (r *importRunner) applyFolders(ctx context.Context, knowledge *types.Knowledge, folders []string) error {
+	switch r.task.FolderMode {
+	case types.IMPORT_FOLDER_MODE_RESOURCE:
+		if len(folders) > 0 {
+			resource, err := r.folderResource(ctx, folders[0])
+			if err != nil {
+				return err
+			}
+			knowledge.Resource = resource
+			knowledge.Tags = appendTags(knowledge.Tags, folders[1:]...)
+		}
+	case types.IMPORT_FOLDER_MODE_TAGS:
+		knowledge.Tags = appendTags(knowledge.Tags, folders...)
+	}
+	return nil
+}
+
+// folderResource 返回目录对应的 resource，不存在同名 resource 时创建
+func (r *importRunner) folderResource(ctx context.Context, folder string) (string, error) {
+	folder = strings.TrimSpace(folder)
+	if folder == "" || strings.EqualFold(folder, types.DEFAULT_RESOURCE) {
+		return r.task.Resource, nil
+	}
+
+	if r.resources == nil {
+		list, err := r.core.Store().ResourceStore().ListResources(ctx, r.task.SpaceID, types.NO_PAGINATION, types.NO_PAGINATION)
+		if err != nil {
+			return "", fmt.Errorf("failed to list resources: %w", err)
+		}
+		r.resources = make(map[string]string, len(list))
+		for _, v := range list {
+			r.resources[strings.ToLower(v.Title)] = v.ID
+			r.resources[strings.ToLower(v.ID)] = v.ID
+		}
+	}
+
+	key := strings.ToLower(folder)
+	if id, ok := r.resources[key]; ok {
+		return id, nil
+	}
+
+	resource := types.Resource{
+		ID:        utils.GenUniqIDStr(),
+		UserID:    r.task.UserID,
+		SpaceID:   r.task.SpaceID,
+		Title:     folder,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := r.core.Store().ResourceStore().Create(ctx, resource); err != nil {
+		return "", fmt.Errorf("failed to create resource: %w", err)
+	}
+	r.resources[key] = resource.ID
+	return resource.ID, nil
+}
+
+// createKnowledge 加密内容并创建知识，stage 为 summarize 时后续由 KnowledgeProcess 完成总结与向量化
+func (r *importRunner) createKnowledge(ctx context.Context, knowledge types.Knowledge, content string, stage types.KnowledgeStage) error {
+	encryptData, err := r.core.EncryptData([]byte(content))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt content: %w", err)
+	}
+
+	now := time.Now().Unix()
+	knowledge.SpaceID = r.task.SpaceID
+	knowledge.UserID = r.task.UserID
+	knowledge.Content = encryptData
+	knowledge.Stage = stage
+	knowledge.Source = types.KNOWLEDGE_SOURCE_IMPORT.String()
+	knowledge.SourceRef = r.task.ID
+	if knowledge.Resource == "" {
+		knowledge.Resource = types.DEFAULT_RESOURCE
+	}
+	if knowledge.CreatedAt == 0 {
+		knowledge.CreatedAt = now
+	}
+	if knowledge.UpdatedAt == 0 {
+		knowledge.UpdatedAt = now
+	}
+	if knowledge.MaybeDate == "" {
+		knowledge.MaybeDate = time.Unix(knowledge.CreatedAt, 0).Local().Format("2006-01-02 15:04")
+	}
+
+	// 过期时间从导入时开始计算，避免历史笔记导入后立即过期
+	if knowledge.Resource != types.DEFAULT_RESOURCE {
+		if resource, err := r.core.GetResource(ctx, r.task.SpaceID, knowledge.Resource); err == nil {
+			knowledge.ExpiredAt = types.CalculateExpiredAt(now, resource.Cycle)
+		}
+	}
+
+	if err = r.core.Store().KnowledgeStore().Create(ctx, knowledge); err != nil {
+		return fmt.Errorf("failed to create knowledge: %w", err)
+	}
+	return nil
+}
+
+// appendTags 追加标签并去重
+func appendTags(tags []string, more ...string) []string {
+	for _, tag := range more {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || lo.Contains(tags, tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,181 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/importer"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// BOOKMARK_DEDUP_BATCH_SIZE 每次查询空间内已导入地址的数量
+const BOOKMARK_DEDUP_BATCH_SIZE = 200
+
+// BookmarkImportTask 将书签与稍后读条目导入为 url 类型的知识，空间内已导入过的地址会被跳过
+// 开启抓取时知识先以 none 阶段创建，由 FetchImportedBookmarks 在后台限速抓取网页内容后再进入总结流程
+type BookmarkImportTask struct {
+	*importRunner
+	bookmarks []importer.Bookmark
+	fetch     bool
+}
+
+// NewBookmarkImportTask 创建书签导入任务执行器
+func NewBookmarkImportTask(core *core.Core, task *types.ImportTask, bookmarks []importer.Bookmark, fetch bool) *BookmarkImportTask {
+	return &BookmarkImportTask{
+		importRunner: newImportRunner(core, task),
+		bookmarks:    bookmarks,
+		fetch:        fetch,
+	}
+}
+
+// OnProgress 设置每个条目处理完成后的进度回调
+func (t *BookmarkImportTask) OnProgress(f func(task types.ImportTask)) *BookmarkImportTask {
+	t.onProgress = f
+	return t
+}
+
+// Run 依次导入全部书签，单个条目失败不会中断任务
+func (t *BookmarkImportTask) Run(ctx context.Context) error {
+	slog.Info("Bookmark import started", slog.String("task_id", t.task.ID), slog.String("space_id", t.task.SpaceID),
+		slog.Int("bookmarks", len(t.bookmarks)), slog.Bool("fetch", t.fetch))
+
+	seen := make(map[string]bool, len(t.bookmarks))
+	for i := 0; i < len(t.bookmarks); i += BOOKMARK_DEDUP_BATCH_SIZE {
+		batch := t.bookmarks[i:min(i+BOOKMARK_DEDUP_BATCH_SIZE, len(t.bookmarks))]
+		exists, err := t.existingURLs(ctx, batch)
+		if err != nil {
+			return t.finish(ctx, err)
+		}
+
+		for _, bookmark := range batch {
+			if ctx.Err() != nil {
+				return t.finish(ctx, ctx.Err())
+			}
+
+			normalized := importer.NormalizeURL(bookmark.URL)
+			item := types.ImportTaskItem{
+				Path:   bookmark.URL,
+				Title:  bookmarkTitle(bookmark),
+				URL:    normalized,
+				Status: types.IMPORT_ITEM_STATUS_SUCCESS,
+			}
+			switch {
+			case seen[normalized]:
+				item.Status = types.IMPORT_ITEM_STATUS_SKIPPED
+				item.Error = "duplicate url"
+			case exists[normalized]:
+				item.Status = types.IMPORT_ITEM_STATUS_SKIPPED
+				item.Error = "url already exists in space"
+			default:
+				id, err := t.importBookmark(ctx, bookmark)
+				if err != nil {
+					item.Status = types.IMPORT_ITEM_STATUS_FAILED
+					item.Error = err.Error()
+					slog.Error("Failed to import bookmark", slog.String("task_id", t.task.ID), slog.String("url", bookmark.URL), slog.String("error", err.Error()))
+					break
+				}
+				item.KnowledgeID = id
+				if t.fetch {
+					item.FetchStatus = types.IMPORT_FETCH_STATUS_PENDING
+				}
+			}
+			// 只有成功导入的地址参与去重，失败的条目可以重新导入
+			if item.Status != types.IMPORT_ITEM_STATUS_FAILED {
+				seen[normalized] = true
+			}
+			t.record(ctx, item)
+		}
+	}
+
+	return t.finish(ctx, nil)
+}
+
+// existingURLs 返回本批书签中已导入到空间且知识依然存在的地址
+func (t *BookmarkImportTask) existingURLs(ctx context.Context, batch []importer.Bookmark) (map[string]bool, error) {
+	urls := lo.Uniq(lo.Map(batch, func(item importer.Bookmark, _ int) string {
+		return importer.NormalizeURL(item.URL)
+	}))
+	items, err := t.core.Store().ImportTaskItemStore().ListByURLs(ctx, t.task.SpaceID, urls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list imported urls: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	ids, err := t.core.Store().KnowledgeStore().ListKnowledgeIDs(ctx, types.GetKnowledgeOptions{
+		SpaceID:        t.task.SpaceID,
+		IDs:            lo.Map(items, func(item types.ImportTaskItem, _ int) string { return item.KnowledgeID }),
+		IncludeExpired: true,
+	}, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list imported knowledge: %w", err)
+	}
+
+	alive := lo.SliceToMap(ids, func(id string) (string, bool) { return id, true })
+	res := make(map[string]bool, len(items))
+	for _, item := range items {
+		if alive[item.KnowledgeID] {
+			res[item.URL] = true
+		}
+	}
+	return res, nil
+}
+
+func (t *BookmarkImportTask) importBookmark(ctx context.Context, bookmark importer.Bookmark) (string, error) {
+	knowledge := types.Knowledge{
+		ID:          utils.GenUniqIDStr(),
+		Kind:        types.KNOWLEDGE_KIND_URL,
+		ContentType: types.KNOWLEDGE_CONTENT_TYPE_MARKDOWN,
+		Title:       bookmarkTitle(bookmark),
+		Tags:        bookmark.Tags,
+		Resource:    t.task.Resource,
+	}
+	if !bookmark.AddedAt.IsZero() {
+		knowledge.CreatedAt = bookmark.AddedAt.Unix()
+	}
+	if err := t.applyFolders(ctx, &knowledge, bookmark.Folders); err != nil {
+		return "", err
+	}
+
+	knowledge.Summary = "content"
+	if len(knowledge.Tags) == 0 {
+		knowledge.Summary = "content,tags"
+	}
+
+	stage := types.KNOWLEDGE_STAGE_SUMMARIZE
+	if t.fetch {
+		stage = types.KNOWLEDGE_STAGE_NONE
+	}
+	if err := t.createKnowledge(ctx, knowledge, BookmarkContent(knowledge.Title, bookmark.URL, bookmark.Description), stage); err != nil {
+		return "", err
+	}
+	return knowledge.ID, nil
+}
+
+// bookmarkTitle 书签没有标题时使用网页域名与路径
+func bookmarkTitle(bookmark importer.Bookmark) string {
+	if title := strings.TrimSpace(bookmark.Title); title != "" {
+		return title
+	}
+	if u, err := url.Parse(bookmark.URL); err == nil && u.Host != "" {
+		return strings.TrimSuffix(u.Host+u.Path, "/")
+	}
+	return bookmark.URL
+}
+
+// BookmarkContent 生成 url 知识的 markdown 正文，body 为书签描述或抓取到的网页内容
+func BookmarkContent(title, link, body string) string {
+	content := fmt.Sprintf("[%s](%s)", strings.NewReplacer("[", "(", "]", ")").Replace(title), link)
+	if body = strings.TrimSpace(body); body != "" {
+		content += "\n\n" + body
+	}
+	return content
+}
@@ -0,0 +1,113 @@
+package process
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+const (
+	// IMPORT_FETCH_BATCH_SIZE 每轮最多抓取的网页数量
+	IMPORT_FETCH_BATCH_SIZE = 20
+	// IMPORT_FETCH_INTERVAL 两次抓取之间的间隔，避免触发 reader 服务的限流
+	IMPORT_FETCH_INTERVAL = 2 * time.Second
+	// IMPORT_FETCH_TIMEOUT 单个网页的抓取超时时间
+	IMPORT_FETCH_TIMEOUT = time.Minute
+
+	IMPORT_FETCH_LOCK_KEY = "import:bookmark:fetch"
+)
+
+func init() {
+	register.RegisterFunc(ProcessKey{}, func(provider *Process) {
+		provider.Cron().AddFunc("*/1 * * * *", func() {
+			if err := FetchImportedBookmarks(context.Background(), provider.Core()); err != nil {
+				slog.Error("Failed to fetch imported bookmarks", slog.String("error", err.Error()))
+			}
+		})
+	})
+}
+
+// FetchImportedBookmarks 通过 Reader 限速抓取书签导入时等待抓取的网页内容，抓取完成(无论成功与否)后知识进入总结流程
+// 每轮最多处理 IMPORT_FETCH_BATCH_SIZE 个条目，上一轮未结束时跳过本轮
+func FetchImportedBookmarks(ctx context.Context, core *core.Core) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ok, err := core.TryLock(ctx, IMPORT_FETCH_LOCK_KEY)
+	if err != nil || !ok {
+		return err
+	}
+
+	items, err := core.Store().ImportTaskItemStore().ListPendingFetch(ctx, IMPORT_FETCH_BATCH_SIZE)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			time.Sleep(IMPORT_FETCH_INTERVAL)
+		}
+
+		status, errMsg := types.IMPORT_FETCH_STATUS_SUCCESS, ""
+		if err = fetchBookmark(ctx, core, item); err != nil {
+			status, errMsg = types.IMPORT_FETCH_STATUS_FAILED, err.Error()
+			slog.Warn("Failed to fetch bookmark content", slog.String("task_id", item.TaskID), slog.String("url", item.Path), slog.String("error", errMsg))
+		}
+		if err = core.Store().ImportTaskItemStore().UpdateFetchStatus(ctx, item.ID, status, errMsg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchBookmark 抓取网页内容写入知识，抓取失败时保留书签原有内容，两种情况下知识都会进入总结流程
+func fetchBookmark(ctx context.Context, core *core.Core, item types.ImportTaskItem) error {
+	knowledge, err := core.Store().KnowledgeStore().GetKnowledge(ctx, item.SpaceID, item.KnowledgeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("knowledge has been deleted")
+		}
+		return err
+	}
+	if knowledge.Stage != types.KNOWLEDGE_STAGE_NONE {
+		// 知识已被用户编辑并进入处理流程
+		return nil
+	}
+
+	update := types.UpdateKnowledgeArgs{
+		Stage: types.KNOWLEDGE_STAGE_SUMMARIZE,
+	}
+	defer func() {
+		if updateErr := core.Store().KnowledgeStore().Update(ctx, item.SpaceID, item.KnowledgeID, update); updateErr != nil {
+			slog.Error("Failed to update fetched bookmark", slog.String("knowledge_id", item.KnowledgeID), slog.String("error", updateErr.Error()))
+		}
+	}()
+
+	fetchCtx, cancel := context.WithTimeout(ctx, IMPORT_FETCH_TIMEOUT)
+	defer cancel()
+	res, err := core.Srv().AI().Reader(fetchCtx, item.Path)
+	if err != nil {
+		return err
+	}
+	NewRecordUsageRequest("", types.USAGE_TYPE_USER, types.USAGE_SUB_TYPE_READ, item.SpaceID, knowledge.UserID, &openai.Usage{
+		CompletionTokens: res.Usage.Tokens,
+	})
+	if res.Content == "" {
+		return fmt.Errorf("empty page content")
+	}
+
+	content, err := core.EncryptData([]byte(BookmarkContent(knowledge.Title, item.Path, res.Content)))
+	if err != nil {
+		return err
+	}
+	update.Content = content
+	return nil
+}
@@ -0,0 +1,166 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/importer"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// VaultImportTask 将 Obsidian vault、Notion 导出等 markdown 归档中的笔记逐篇导入为知识
+// 所有笔记的知识ID在导入前预先生成，笔记之间的 [[wikilink]] 与相对路径链接会被改写为 [[knowledge-id|标题]] 形式的知识引用
+type VaultImportTask struct {
+	*importRunner
+	vault *importer.Vault
+
+	knowledgeIDs map[*importer.Note]string
+	fileURLs     map[string]string
+}
+
+// NewVaultImportTask 创建 markdown 归档导入任务执行器
+func NewVaultImportTask(core *core.Core, task *types.ImportTask, vault *importer.Vault) *VaultImportTask {
+	t := &VaultImportTask{
+		importRunner: newImportRunner(core, task),
+		vault:        vault,
+		knowledgeIDs: make(map[*importer.Note]string, len(vault.Notes)),
+		fileURLs:     make(map[string]string),
+	}
+	for _, note := range vault.Notes {
+		t.knowledgeIDs[note] = utils.GenUniqIDStr()
+	}
+	return t
+}
+
+// OnProgress 设置每篇笔记处理完成后的进度回调
+func (t *VaultImportTask) OnProgress(f func(task types.ImportTask)) *VaultImportTask {
+	t.onProgress = f
+	return t
+}
+
+// Run 依次导入全部笔记，单篇笔记失败不会中断任务
+func (t *VaultImportTask) Run(ctx context.Context) error {
+	slog.Info("Vault import started", slog.String("task_id", t.task.ID), slog.String("source", t.task.Source), slog.String("space_id", t.task.SpaceID), slog.Int("notes", len(t.vault.Notes)))
+
+	for _, note := range t.vault.Notes {
+		if ctx.Err() != nil {
+			return t.finish(ctx, ctx.Err())
+		}
+
+		item := types.ImportTaskItem{
+			Path:   note.Path,
+			Title:  note.Title,
+			Status: types.IMPORT_ITEM_STATUS_SUCCESS,
+		}
+		if isEmptyNote(note) {
+			item.Status = types.IMPORT_ITEM_STATUS_SKIPPED
+			item.Error = "empty note"
+		} else if err := t.importNote(ctx, note); err != nil {
+			item.Status = types.IMPORT_ITEM_STATUS_FAILED
+			item.Error = err.Error()
+			slog.Error("Failed to import note", slog.String("task_id", t.task.ID), slog.String("path", note.Path), slog.String("error", err.Error()))
+		} else {
+			item.KnowledgeID = t.knowledgeIDs[note]
+		}
+		t.record(ctx, item)
+	}
+
+	return t.finish(ctx, nil)
+}
+
+func (t *VaultImportTask) importNote(ctx context.Context, note *importer.Note) error {
+	var uploadErr error
+	content := t.vault.Rewrite(note, importer.LinkRewriter{
+		Note: func(target *importer.Note, text string) string {
+			if isEmptyNote(target) {
+				// 空笔记不会被导入，链接保留为纯文本
+				return text
+			}
+			return fmt.Sprintf("[[%s|%s]]", t.knowledgeIDs[target], text)
+		},
+		File: func(p string) (string, error) {
+			url, err := t.uploadFile(p)
+			if err != nil {
+				uploadErr = err
+			}
+			return url, err
+		},
+	})
+	if len(note.Properties) > 0 {
+		content = strings.TrimSpace(renderProperties(note.Properties) + "\n\n" + content)
+	}
+	if uploadErr != nil {
+		slog.Warn("Failed to upload note attachment", slog.String("task_id", t.task.ID), slog.String("path", note.Path), slog.String("error", uploadErr.Error()))
+	}
+
+	knowledge := types.Knowledge{
+		ID:          t.knowledgeIDs[note],
+		Kind:        types.KNOWLEDGE_KIND_TEXT,
+		ContentType: types.KNOWLEDGE_CONTENT_TYPE_MARKDOWN,
+		Title:       note.Title,
+		Tags:        note.Tags,
+		Resource:    t.task.Resource,
+	}
+	if !note.CreatedAt.IsZero() {
+		knowledge.CreatedAt = note.CreatedAt.Unix()
+		knowledge.MaybeDate = note.CreatedAt.Local().Format("2006-01-02 15:04")
+	}
+	if !note.UpdatedAt.IsZero() {
+		knowledge.UpdatedAt = note.UpdatedAt.Unix()
+	}
+
+	if err := t.applyFolders(ctx, &knowledge, note.Folders); err != nil {
+		return err
+	}
+
+	// 标题与已有标签保留笔记中的值，只让 AI 生成分片，笔记没有标签时一并生成标签
+	knowledge.Summary = "content"
+	if len(knowledge.Tags) == 0 {
+		knowledge.Summary = "content,tags"
+	}
+
+	return t.createKnowledge(ctx, knowledge, content, types.KNOWLEDGE_STAGE_SUMMARIZE)
+}
+
+func isEmptyNote(note *importer.Note) bool {
+	return strings.TrimSpace(note.Content) == "" && len(note.Properties) == 0
+}
+
+// renderProperties 将笔记属性(如 Notion 数据库的列)渲染为正文前的 markdown 列表
+func renderProperties(properties []importer.Property) string {
+	var sb strings.Builder
+	for i, property := range properties {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("- **%s**: %s", property.Name, strings.ReplaceAll(property.Value, "\n", " ")))
+	}
+	return sb.String()
+}
+
+// uploadFile 上传附件并返回访问地址，同一附件只上传一次
+func (t *VaultImportTask) uploadFile(p string) (string, error) {
+	if url, ok := t.fileURLs[p]; ok {
+		return url, nil
+	}
+
+	data, err := t.vault.ReadFile(p)
+	if err != nil {
+		return "", err
+	}
+
+	storage := t.core.Plugins.FileStorage()
+	filePath := types.GenS3FilePath(t.task.SpaceID, "import", utils.GenUniqIDStr()+strings.ToLower(path.Ext(p)))
+	if err = storage.SaveFile(filePath, data); err != nil {
+		return "", fmt.Errorf("failed to save file %s: %w", p, err)
+	}
+
+	url := fmt.Sprintf("https://%s/%s", storage.GetStaticDomain(), strings.TrimPrefix(filePath, "/"))
+	t.fileURLs[p] = url
+	return url, nil
+}
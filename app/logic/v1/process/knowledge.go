@@ -15,6 +15,8 @@ import (
 
 	"github.com/quka-ai/quka-ai/app/core"
 	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/ai/chunker"
 	"github.com/quka-ai/quka-ai/pkg/mark"
 	"github.com/quka-ai/quka-ai/pkg/safe"
 	"github.com/quka-ai/quka-ai/pkg/types"
@@ -239,7 +241,8 @@ func (p *KnowledgeProcess) processEmbedding(req *EmbeddingRequest) {
 		return
 	}
 
-	if knowledge.Stage != types.KNOWLEDGE_STAGE_EMBEDDING {
+	// 处理期间被移入回收站的知识不再生成向量，恢复时会重新处理
+	if knowledge.Stage != types.KNOWLEDGE_STAGE_EMBEDDING || knowledge.DeletedAt != 0 {
 		return
 	}
 
@@ -273,75 +276,16 @@ func (p *KnowledgeProcess) processEmbedding(req *EmbeddingRequest) {
 	// 	return
 	// }
 
-	sw := mark.NewSensitiveWork()
-	// content := sw.Do(req.data.Summary)
-
-	var chunksData []types.KnowledgeChunk
-
-	if req.data.Kind == types.KNOWLEDGE_KIND_CHUNK {
-		// type KnowledgeChunk struct {
-		// 	ID             string `json:"id" db:"id"`                           // 主键，字符串类型
-		// 	KnowledgeID    string `json:"knowledge_id" db:"knowledge_id"`       // 知识点ID
-		// 	SpaceID        string `json:"space_id" db:"space_id"`               // 空间ID
-		// 	UserID         string `json:"user_id" db:"user_id"`                 // 用户ID
-		// 	Chunk          string `json:"chunk" db:"chunk"`                     // 知识片段
-		// 	OriginalLength int    `json:"original_length" db:"original_length"` // 原文长度
-		// 	UpdatedAt      int64  `json:"updated_at" db:"updated_at"`           // 更新时间
-		// 	CreatedAt      int64  `json:"created_at" db:"created_at"`           // 创建时间
-		// }
-
-		markdownContent := string(req.data.Content)
-		if req.data.ContentType == types.KNOWLEDGE_CONTENT_TYPE_BLOCKS {
-			markdownContent, err = editorjs.ConvertEditorJSRawToMarkdown(json.RawMessage(req.data.Content))
-			if err != nil {
-				slog.Error("Failed to convert editor blocks to markdown", append(logAttrs, slog.String("error", err.Error()))...)
-				return
-			}
-		}
-		chunksData = append(chunksData, types.KnowledgeChunk{
-			ID:             req.data.ID,
-			KnowledgeID:    req.data.ID,
-			SpaceID:        req.data.SpaceID,
-			UserID:         req.data.UserID,
-			Chunk:          markdownContent,
-			OriginalLength: len([]rune(markdownContent)),
-			UpdatedAt:      time.Now().Unix(),
-			CreatedAt:      time.Now().Unix(),
-		})
-	} else {
-		chunksData, err = p.core.Store().KnowledgeChunkStore().List(ctx, req.data.SpaceID, req.data.ID)
-		if err != nil {
-			slog.Error("Failed to list knowledge chuns", append(logAttrs, slog.String("error", err.Error()))...)
-			return
-		}
-	}
-
 	var (
 		vectors []types.Vector
 		chunks  []string
 	)
-	for _, v := range chunksData {
-		decryptData, err := p.core.DecryptData([]byte(v.Chunk))
-		if err != nil {
-			slog.Error("Failed to decrypt knowledge chunk", slog.String("error", err.Error()))
-			continue
-		}
-		v.Chunk = string(decryptData)
-		chunks = append(chunks, sw.Do(v.Chunk))
-
-		vectors = append(vectors, types.Vector{
-			ID:             v.ID,
-			KnowledgeID:    v.KnowledgeID,
-			SpaceID:        v.SpaceID,
-			UserID:         v.UserID,
-			Resource:       req.data.Resource,
-			OriginalLength: v.OriginalLength,
-			// Embedding:   pgvector.NewVector(vector),
-			CreatedAt: time.Now().Unix(),
-			UpdatedAt: time.Now().Unix(),
-		})
+	if vectors, chunks, err = buildKnowledgeVectors(ctx, p.core, req.data); err != nil {
+		slog.Error("Failed to build knowledge vectors", append(logAttrs, slog.String("error", err.Error()))...)
+		return
 	}
 
+	embeddingModel := p.core.Srv().AI().EmbeddingModel()
 	vectorResults, err := p.core.Srv().AI().EmbeddingForDocument(ctx, "", chunks)
 	if err != nil {
 		slog.Error("Failed to embedding for document", append(logAttrs, slog.String("error", err.Error()))...)
@@ -357,10 +301,14 @@ func (p *KnowledgeProcess) processEmbedding(req *EmbeddingRequest) {
 
 	for i, v := range vectorResults.Data {
 		vectors[i].Embedding = pgvector.NewVector(v)
+		vectors[i].Model = embeddingModel
 	}
 
+	// 在知识对外可见(DONE)前确定是否需要自动合并到已有的重复知识
+	duplicates, merge := p.detectDuplicates(ctx, knowledge, vectors)
+
 	err = p.core.Store().Transaction(req.ctx, func(ctx context.Context) error {
-		ctx, cancel := context.WithTimeout(req.ctx, time.Minute)
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
 		defer cancel()
 
 		// exist, err := p.core.Store().VectorStore().GetVector(ctx, req.data.SpaceID, req.data.ID)
@@ -406,9 +354,34 @@ func (p *KnowledgeProcess) processEmbedding(req *EmbeddingRequest) {
 			return err
 		}
 
-		publishStageChangedMessage(p.core.Srv().Centrifuge(), req.data.SpaceID, req.data.ID, types.KNOWLEDGE_STAGE_DONE)
+		// 疑似重复记录与向量在同一事务中写入，知识对外可见(DONE)时重复标记已存在
+		if len(duplicates) > 0 {
+			if err = p.core.Store().KnowledgeDuplicateStore().BatchCreate(ctx, duplicates); err != nil {
+				slog.Error("Failed to save duplicate knowledges", append(logAttrs, slog.String("error", err.Error()))...)
+				return err
+			}
+		}
+
+		if !merge {
+			return nil
+		}
+		if err = MergeDuplicateKnowledge(ctx, p.core, knowledge, duplicates[0]); err != nil {
+			slog.Error("Failed to merge duplicate knowledge", append(logAttrs, slog.String("error", err.Error()))...)
+			return err
+		}
 		return nil
 	})
+	if err != nil {
+		return
+	}
+
+	if merge {
+		slog.Info("Knowledge merged into duplicate", append(logAttrs, slog.String("duplicate_of", duplicates[0].DuplicateOf))...)
+		publishKnowledgeMergedMessage(p.core.Srv().Centrifuge(), req.data.SpaceID, req.data.ID, duplicates[0].DuplicateOf)
+		return
+	}
+
+	publishStageChangedMessage(p.core.Srv().Centrifuge(), req.data.SpaceID, req.data.ID, types.KNOWLEDGE_STAGE_DONE)
 }
 
 func (p *KnowledgeProcess) processSummary(req *SummaryRequest) {
@@ -464,15 +437,25 @@ func (p *KnowledgeProcess) processSummary(req *SummaryRequest) {
 		}
 	}
 
-	secretContent := sw.Do(markdownContent)
-
-	summary, err := p.core.Srv().AI().Chunk(ctx, &secretContent)
+	// 使用本地分片时不会生成标题、标签与知识图谱实体，内容中的 [[knowledge-id]] 引用仍会被记录
+	var summary *ai.ChunkResult
+	if conf := p.chunkerConfig(ctx, req.data.SpaceID); conf.IsLocal() {
+		summary = &ai.ChunkResult{
+			Chunks: chunker.NewMarkdownChunker(conf).Split(markdownContent),
+			Model:  chunker.MODEL_NAME,
+		}
+	} else {
+		secretContent := sw.Do(markdownContent)
+		summary, err = p.core.Srv().AI().Chunk(ctx, &secretContent)
+	}
 	if err != nil {
 		slog.Error("Failed to summarize knowledge", append(logAttrs, slog.String("error", err.Error()))...)
 		return
 	}
 
-	NewRecordKnowledgeUsageRequest(summary.Model, types.USAGE_SUB_TYPE_SUMMARY, req.data, summary.Usage)
+	if summary.Usage != nil {
+		NewRecordKnowledgeUsageRequest(summary.Model, types.USAGE_SUB_TYPE_SUMMARY, req.data, summary.Usage)
+	}
 
 	slog.Debug("Knowledge summary result", slog.String("knowledge_id", req.data.ID), slog.String("space_id", req.data.SpaceID), slog.Any("result", summary))
 
@@ -551,6 +534,30 @@ func (p *KnowledgeProcess) processSummary(req *SummaryRequest) {
 		publishStageChangedMessage(p.core.Srv().Centrifuge(), req.data.SpaceID, req.data.ID, types.KNOWLEDGE_STAGE_EMBEDDING)
 		return nil
 	})
+
+	// 内容发生变化时重新抽取知识图谱
+	if err == nil && len(chunks) > 0 {
+		for i := range summary.Entities {
+			summary.Entities[i].Name = sw.Undo(summary.Entities[i].Name)
+		}
+		for i := range summary.Relations {
+			summary.Relations[i].Source = sw.Undo(summary.Relations[i].Source)
+			summary.Relations[i].Target = sw.Undo(summary.Relations[i].Target)
+		}
+		p.updateKnowledgeGraph(req.ctx, knowledge, markdownContent, summary.Entities, summary.Relations)
+	}
+}
+
+// chunkerConfig 获取空间的知识分片配置，获取失败时使用对话模型分片
+func (p *KnowledgeProcess) chunkerConfig(ctx context.Context, spaceID string) types.ChunkerConfig {
+	space, err := p.core.GetSpace(ctx, spaceID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			slog.Error("Failed to get space chunker config", slog.String("space_id", spaceID), slog.String("error", err.Error()))
+		}
+		return types.ChunkerConfig{}.WithDefault()
+	}
+	return space.Settings.ChunkerConfig()
 }
 
 func publishStageChangedMessage(centrifuge srv.CentrifugeManager, spaceID, knowledgeID string, stage types.KnowledgeStage) {
@@ -829,3 +836,73 @@ func (p *KnowledgeProcess) RecordChatUsage(req *RecordChatUsageRequest) error {
 	}
 	return nil
 }
+
+// buildKnowledgeVectors 解密知识分片并构建待写入的向量记录(不含 embedding)
+// 返回的 chunks 与 vectors 一一对应，为脱敏后用于 embedding 的文本。
+// 全文检索词向量是明文索引，仅在空间开启混合检索时写入。
+// knowledge 的正文需为解密后的内容
+func buildKnowledgeVectors(ctx context.Context, core *core.Core, knowledge *types.Knowledge) ([]types.Vector, []string, error) {
+	sw := mark.NewSensitiveWork()
+
+	space, err := core.GetSpace(ctx, knowledge.SpaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("failed to get space: %w", err)
+	}
+	withKeywords := space != nil && space.Settings.IsHybridRetrieval()
+
+	var chunksData []types.KnowledgeChunk
+	if knowledge.Kind == types.KNOWLEDGE_KIND_CHUNK {
+		markdownContent := string(knowledge.Content)
+		if knowledge.ContentType == types.KNOWLEDGE_CONTENT_TYPE_BLOCKS {
+			markdownContent, err = editorjs.ConvertEditorJSRawToMarkdown(json.RawMessage(knowledge.Content))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to convert editor blocks to markdown: %w", err)
+			}
+		}
+		chunksData = append(chunksData, types.KnowledgeChunk{
+			ID:             knowledge.ID,
+			KnowledgeID:    knowledge.ID,
+			SpaceID:        knowledge.SpaceID,
+			UserID:         knowledge.UserID,
+			Chunk:          markdownContent,
+			OriginalLength: len([]rune(markdownContent)),
+			UpdatedAt:      time.Now().Unix(),
+			CreatedAt:      time.Now().Unix(),
+		})
+	} else {
+		chunksData, err = core.Store().KnowledgeChunkStore().List(ctx, knowledge.SpaceID, knowledge.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list knowledge chunks: %w", err)
+		}
+	}
+
+	var (
+		vectors []types.Vector
+		chunks  []string
+	)
+	for _, v := range chunksData {
+		decryptData, err := core.DecryptData([]byte(v.Chunk))
+		if err != nil {
+			slog.Error("Failed to decrypt knowledge chunk", slog.String("error", err.Error()))
+			continue
+		}
+		v.Chunk = string(decryptData)
+		chunks = append(chunks, sw.Do(v.Chunk))
+
+		vector := types.Vector{
+			ID:             v.ID,
+			KnowledgeID:    v.KnowledgeID,
+			SpaceID:        v.SpaceID,
+			UserID:         v.UserID,
+			Resource:       knowledge.Resource,
+			OriginalLength: v.OriginalLength,
+			CreatedAt:      time.Now().Unix(),
+			UpdatedAt:      time.Now().Unix(),
+		}
+		if withKeywords {
+			vector.Keywords = utils.FTSDocument(knowledge.Title + "\n" + v.Chunk)
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, chunks, nil
+}
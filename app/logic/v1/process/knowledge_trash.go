@@ -0,0 +1,42 @@
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/quka-ai/quka-ai/app/core"
+)
+
+// SoftDeleteKnowledge 将知识移入回收站：标记删除时间并移除其向量，使其不再出现在列表与检索结果中
+// 内容、分片与图谱数据保留用于恢复，需在事务中调用
+func SoftDeleteKnowledge(ctx context.Context, core *core.Core, spaceID, knowledgeID string) error {
+	if err := core.Store().KnowledgeStore().SetDeletedAt(ctx, spaceID, knowledgeID, time.Now().Unix()); err != nil {
+		return err
+	}
+	if err := core.Store().VectorStore().BatchDelete(ctx, spaceID, []string{knowledgeID}); err != nil {
+		return err
+	}
+	// 已删除知识的待处理重复关系已无意义
+	return core.Store().KnowledgeDuplicateStore().DeletePendingByKnowledge(ctx, spaceID, knowledgeID)
+}
+
+// PurgeKnowledge 彻底删除知识及其分片、向量、图谱与修订数据
+// 需在事务中调用，编辑器内容引用的文件需由调用方处理
+func PurgeKnowledge(ctx context.Context, core *core.Core, spaceID, knowledgeID string) error {
+	if err := core.Store().KnowledgeStore().Delete(ctx, spaceID, knowledgeID); err != nil {
+		return err
+	}
+	if err := core.Store().KnowledgeChunkStore().BatchDelete(ctx, spaceID, knowledgeID); err != nil {
+		return err
+	}
+	if err := core.Store().VectorStore().BatchDelete(ctx, spaceID, []string{knowledgeID}); err != nil {
+		return err
+	}
+	if err := core.Store().KnowledgeDuplicateStore().DeletePendingByKnowledge(ctx, spaceID, knowledgeID); err != nil {
+		return err
+	}
+	if err := DeleteKnowledgeGraph(ctx, core, spaceID, knowledgeID); err != nil {
+		return err
+	}
+	return core.Store().KnowledgeRevisionStore().Delete(ctx, spaceID, knowledgeID)
+}
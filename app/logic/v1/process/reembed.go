@@ -0,0 +1,248 @@
+package process
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pgvector/pgvector-go"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/safe"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+const (
+	REEMBED_JOB_LOCK_KEY          = "reembed_job"
+	REEMBED_DEFAULT_BATCH_SIZE    = 20
+	REEMBED_EMBEDDING_CHUNK_BATCH = 32
+)
+
+func init() {
+	register.RegisterFunc[*Process](ProcessKey{}, func(p *Process) {
+		// 服务重启后继续执行未完成的重建任务
+		go safe.Run(func() {
+			ResumeReembedJobs(p.Core())
+		})
+	})
+}
+
+// ResumeReembedJobs 恢复所有处于运行状态的向量重建任务
+func ResumeReembedJobs(core *core.Core) {
+	jobs, err := core.Store().ReembedJobStore().ListByStatus(context.Background(), types.REEMBED_JOB_STATUS_RUNNING)
+	if err != nil {
+		slog.Error("Failed to list running reembed jobs", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, job := range jobs {
+		if err := NewReembedTask(core, job.ID).Run(context.Background()); err != nil {
+			slog.Error("Failed to resume reembed job", slog.String("job_id", job.ID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// ReembedTask 使用当前 embedding 模型重新生成全部知识向量
+// 任务按 knowledge id 顺序遍历，每处理完一批记录游标，中断后可从游标处继续
+type ReembedTask struct {
+	core       *core.Core
+	jobID      string
+	onProgress func(job types.ReembedJob)
+}
+
+// NewReembedTask 创建向量重建任务执行器
+func NewReembedTask(core *core.Core, jobID string) *ReembedTask {
+	return &ReembedTask{
+		core:  core,
+		jobID: jobID,
+	}
+}
+
+// OnProgress 设置每批处理完成后的进度回调
+func (t *ReembedTask) OnProgress(f func(job types.ReembedJob)) *ReembedTask {
+	t.onProgress = f
+	return t
+}
+
+// ErrReembedJobLocked 已有重建任务正在执行
+var ErrReembedJobLocked = errors.New("another reembed job is running")
+
+// Run 执行任务，直到任务完成、被暂停/取消或 ctx 结束
+func (t *ReembedTask) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ok, err := t.core.TryLock(ctx, REEMBED_JOB_LOCK_KEY)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrReembedJobLocked
+	}
+
+	job, err := t.core.Store().ReembedJobStore().Get(ctx, t.jobID)
+	if err != nil {
+		return fmt.Errorf("failed to get reembed job: %w", err)
+	}
+	if job.Status != types.REEMBED_JOB_STATUS_RUNNING {
+		return nil
+	}
+
+	batchSize := job.BatchSize
+	if batchSize <= 0 {
+		batchSize = REEMBED_DEFAULT_BATCH_SIZE
+	}
+
+	slog.Info("Reembed job started", slog.String("job_id", job.ID), slog.String("model", job.Model),
+		slog.String("cursor", job.Cursor), slog.Int64("processed", job.Processed))
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// 每批开始前检查任务状态，以响应暂停/取消操作
+		current, err := t.core.Store().ReembedJobStore().Get(ctx, job.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get reembed job: %w", err)
+		}
+		if current.Status != types.REEMBED_JOB_STATUS_RUNNING {
+			slog.Info("Reembed job stopped", slog.String("job_id", job.ID), slog.String("status", current.Status))
+			return nil
+		}
+
+		if t.core.Srv().AI().EmbeddingModel() != job.Model {
+			// 执行期间 embedding 模型再次变更，当前任务已失去意义
+			return t.fail(job, "embedding model changed during reembedding")
+		}
+
+		knowledges, err := t.core.Store().KnowledgeStore().ListKnowledgesAfterID(ctx, types.GetKnowledgeOptions{
+			Stage:          types.KNOWLEDGE_STAGE_DONE,
+			IncludeExpired: true,
+		}, job.Cursor, uint64(batchSize))
+		if err != nil {
+			return t.fail(job, fmt.Sprintf("failed to list knowledges: %s", err))
+		}
+
+		if len(knowledges) == 0 {
+			if err = t.core.Store().ReembedJobStore().UpdateStatus(ctx, job.ID, types.REEMBED_JOB_STATUS_FINISHED, ""); err != nil {
+				return err
+			}
+			job.Status = types.REEMBED_JOB_STATUS_FINISHED
+			t.progress(*job)
+			slog.Info("Reembed job finished", slog.String("job_id", job.ID),
+				slog.Int64("processed", job.Processed), slog.Int64("failed", job.Failed))
+			return nil
+		}
+
+		for _, knowledge := range knowledges {
+			if err := t.reembedKnowledge(ctx, job.Model, knowledge); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				job.Failed++
+				slog.Error("Failed to reembed knowledge", slog.String("job_id", job.ID),
+					slog.String("space_id", knowledge.SpaceID),
+					slog.String("knowledge_id", knowledge.ID),
+					slog.String("error", err.Error()))
+			}
+			job.Processed++
+			job.Cursor = knowledge.ID
+		}
+
+		if err = t.core.Store().ReembedJobStore().UpdateProgress(ctx, job.ID, job.Cursor, job.Processed, job.Failed); err != nil {
+			return fmt.Errorf("failed to update reembed job progress: %w", err)
+		}
+		t.progress(*job)
+
+		// 批次间隔，避免占满 embedding 服务的额度影响正常对话
+		if job.Interval > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(job.Interval) * time.Millisecond):
+			}
+		}
+	}
+}
+
+func (t *ReembedTask) progress(job types.ReembedJob) {
+	if t.onProgress != nil {
+		t.onProgress(job)
+	}
+}
+
+func (t *ReembedTask) fail(job *types.ReembedJob, reason string) error {
+	slog.Error("Reembed job failed", slog.String("job_id", job.ID), slog.String("error", reason))
+	if err := t.core.Store().ReembedJobStore().UpdateStatus(context.Background(), job.ID, types.REEMBED_JOB_STATUS_FAILED, reason); err != nil {
+		return err
+	}
+	job.Status = types.REEMBED_JOB_STATUS_FAILED
+	job.Error = reason
+	t.progress(*job)
+	return nil
+}
+
+// reembedKnowledge 为单个知识重新生成向量，并在事务中替换旧向量
+func (t *ReembedTask) reembedKnowledge(ctx context.Context, model string, knowledge *types.Knowledge) error {
+	// 已使用目标模型生成过向量的知识直接跳过（新写入的知识或上次中断前已处理）
+	exists, err := t.core.Store().VectorStore().ListVectors(ctx, types.GetVectorsOptions{
+		SpaceID:     knowledge.SpaceID,
+		KnowledgeID: knowledge.ID,
+		Model:       model,
+	}, 1, 1)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if len(exists) > 0 {
+		return nil
+	}
+
+	if knowledge.Content, err = t.core.DecryptData(knowledge.Content); err != nil {
+		return fmt.Errorf("failed to decrypt knowledge content: %w", err)
+	}
+
+	vectors, chunks, err := buildKnowledgeVectors(ctx, t.core, knowledge)
+	if err != nil {
+		return err
+	}
+	if len(vectors) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(chunks); start += REEMBED_EMBEDDING_CHUNK_BATCH {
+		end := min(start+REEMBED_EMBEDDING_CHUNK_BATCH, len(chunks))
+		res, err := t.core.Srv().AI().EmbeddingForDocument(ctx, "", chunks[start:end])
+		if err != nil {
+			return fmt.Errorf("failed to embedding for document: %w", err)
+		}
+		if len(res.Data) != end-start {
+			return fmt.Errorf("embedding result length not match")
+		}
+
+		if knowledgeProcess != nil {
+			NewRecordKnowledgeUsageRequest(res.Model, types.USAGE_SUB_TYPE_EMBEDDING, knowledge, res.Usage)
+		}
+
+		for i, v := range res.Data {
+			vectors[start+i].Embedding = pgvector.NewVector(v)
+			vectors[start+i].Model = model
+		}
+	}
+
+	// 新模型的向量维度可能与现有索引不同，写入前先确保对应维度的索引已创建
+	if err := t.core.Store().VectorStore().EnsureDimensionIndex(ctx, len(vectors[0].Embedding.Slice())); err != nil {
+		return fmt.Errorf("failed to ensure vector dimension index: %w", err)
+	}
+
+	return t.core.Store().Transaction(ctx, func(ctx context.Context) error {
+		if err := t.core.Store().VectorStore().BatchDelete(ctx, knowledge.SpaceID, []string{knowledge.ID}); err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		return t.core.Store().VectorStore().BatchCreate(ctx, vectors)
+	})
+}
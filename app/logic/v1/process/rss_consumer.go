@@ -243,7 +243,7 @@ func processArticle(ctx context.Context, core *core.Core, subscription *types.RS
 	}
 
 	// 4. 获取 Resource 配置以确定过期时间
-	resource, err := core.Store().ResourceStore().GetResource(ctx, subscription.SpaceID, subscription.ResourceID)
+	resource, err := core.GetResource(ctx, subscription.SpaceID, subscription.ResourceID)
 	if err != nil {
 		return fmt.Errorf("failed to get resource config: %w", err)
 	}
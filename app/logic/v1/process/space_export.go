@@ -0,0 +1,420 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/backup"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/types/protocol"
+	"github.com/quka-ai/quka-ai/pkg/utils/editorjs"
+)
+
+const (
+	// SPACE_EXPORT_BATCH_SIZE 导出时每次读取的知识数量
+	SPACE_EXPORT_BATCH_SIZE = 100
+	// SPACE_EXPORT_STALE_TIMEOUT 导出任务超过该时间仍未结束时视为已中断(如服务重启)
+	SPACE_EXPORT_STALE_TIMEOUT = 2 * time.Hour
+)
+
+func init() {
+	register.RegisterFunc(ProcessKey{}, func(provider *Process) {
+		provider.Cron().AddFunc("*/10 * * * *", func() {
+			if err := FailStaleSpaceExports(context.Background(), provider.Core()); err != nil {
+				slog.Error("Failed to check stale space exports", slog.String("error", err.Error()))
+			}
+		})
+	})
+}
+
+// FailStaleSpaceExports 将长时间未结束的导出任务标记为失败
+func FailStaleSpaceExports(ctx context.Context, core *core.Core) error {
+	tasks, err := core.Store().SpaceExportStore().ListByStatus(ctx, types.SPACE_EXPORT_STATUS_RUNNING)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(-SPACE_EXPORT_STALE_TIMEOUT).Unix()
+	for _, task := range tasks {
+		if task.CreatedAt > deadline {
+			continue
+		}
+		if err = core.Store().SpaceExportStore().Finish(ctx, task.ID, types.SPACE_EXPORT_STATUS_FAILED, "", 0, "export interrupted"); err != nil {
+			return err
+		}
+		slog.Warn("Space export interrupted", slog.String("export_id", task.ID), slog.String("space_id", task.SpaceID))
+	}
+	return nil
+}
+
+// RunSpaceExport 生成空间备份归档并保存到对象存储，结束后通过 Centrifuge 通知空间的知识列表频道
+func RunSpaceExport(ctx context.Context, core *core.Core, task *types.SpaceExport) error {
+	var (
+		buf      bytes.Buffer
+		filePath string
+	)
+	_, err := NewSpaceExporter(core, task.SpaceID, task.UserID).Export(ctx, &buf)
+	if err == nil {
+		filePath = types.GenS3FilePath(task.SpaceID, "export", task.ID+".zip")
+		if err = core.Plugins.FileStorage().SaveFile(filePath, buf.Bytes()); err != nil {
+			err = fmt.Errorf("failed to save backup archive: %w", err)
+		}
+	}
+
+	task.Status, task.File, task.FileSize, task.Error = types.SPACE_EXPORT_STATUS_FINISHED, filePath, int64(buf.Len()), ""
+	if err != nil {
+		task.Status, task.File, task.FileSize, task.Error = types.SPACE_EXPORT_STATUS_FAILED, "", 0, err.Error()
+	}
+	if updateErr := core.Store().SpaceExportStore().Finish(context.WithoutCancel(ctx), task.ID, task.Status, task.File, task.FileSize, task.Error); updateErr != nil {
+		return fmt.Errorf("failed to update space export status: %w", updateErr)
+	}
+	task.FinishedAt = time.Now().Unix()
+	slog.Info("Space export finished", slog.String("export_id", task.ID), slog.String("space_id", task.SpaceID),
+		slog.String("status", task.Status), slog.Int64("size", task.FileSize))
+
+	if centrifuge := core.Srv().Centrifuge(); centrifuge != nil {
+		topic := protocol.KnowledgeListTopicPrefix + task.SpaceID
+		if publishErr := centrifuge.PublishStreamMessageWithSubject(topic, "space_export", types.WS_EVENT_OTHERS, *task); publishErr != nil {
+			slog.Error("Failed to publish space export status", slog.String("topic", topic), slog.String("export_id", task.ID), slog.String("error", publishErr.Error()))
+		}
+	}
+	return err
+}
+
+// SpaceExporter 将空间数据解密后写入备份归档
+// 知识、resource 与引用的文件按空间导出，日记、聊天会话与 RSS 订阅导出空间内所有成员的数据，butler 数据表只导出发起导出的用户
+type SpaceExporter struct {
+	core    *core.Core
+	spaceID string
+	userID  string
+
+	writer   *backup.Writer
+	manifest backup.Manifest
+	files    map[string]*backup.File
+}
+
+func NewSpaceExporter(core *core.Core, spaceID, userID string) *SpaceExporter {
+	return &SpaceExporter{
+		core:    core,
+		spaceID: spaceID,
+		userID:  userID,
+		files:   make(map[string]*backup.File),
+	}
+}
+
+// Export 将空间数据写入 w，返回归档的 manifest
+func (e *SpaceExporter) Export(ctx context.Context, w io.Writer) (backup.Manifest, error) {
+	space, err := e.core.Store().SpaceStore().GetSpace(ctx, e.spaceID)
+	if err != nil {
+		return e.manifest, fmt.Errorf("failed to get space: %w", err)
+	}
+	e.writer = backup.NewWriter(w)
+	e.manifest = backup.Manifest{
+		ExportedBy: e.userID,
+		Space: backup.Space{
+			SpaceID:     space.SpaceID,
+			Title:       space.Title,
+			Description: space.Description,
+			BasePrompt:  space.BasePrompt,
+			ChatPrompt:  space.ChatPrompt,
+			Settings:    space.Settings,
+		},
+	}
+
+	members, err := e.core.Store().UserSpaceStore().ListSpaceUsers(ctx, e.spaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return e.manifest, fmt.Errorf("failed to list space users: %w", err)
+	}
+
+	steps := []struct {
+		name string
+		f    func(ctx context.Context, members []string) error
+	}{
+		{"resources", e.exportResources},
+		{"knowledge", e.exportKnowledges},
+		{"journals", e.exportJournals},
+		{"chat sessions", e.exportChatSessions},
+		{"butler tables", e.exportButlerTables},
+		{"rss subscriptions", e.exportSubscriptions},
+		{"files", e.exportFiles},
+	}
+	for _, step := range steps {
+		if err = ctx.Err(); err != nil {
+			return e.manifest, err
+		}
+		if err = step.f(ctx, members); err != nil {
+			return e.manifest, fmt.Errorf("failed to export %s: %w", step.name, err)
+		}
+	}
+
+	if err = e.writer.Close(e.manifest); err != nil {
+		return e.manifest, err
+	}
+	return e.manifest, nil
+}
+
+func (e *SpaceExporter) exportResources(ctx context.Context, _ []string) error {
+	list, err := e.core.Store().ResourceStore().ListResources(ctx, e.spaceID, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	e.manifest.Counts.Resources = len(list)
+	return e.writer.WriteJSON(backup.RESOURCES_FILE, list)
+}
+
+func (e *SpaceExporter) exportKnowledges(ctx context.Context, _ []string) error {
+	resources, err := e.core.Store().ResourceStore().ListResources(ctx, e.spaceID, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	resourceTitles := make(map[string]string, len(resources))
+	for _, v := range resources {
+		resourceTitles[v.ID] = v.Title
+	}
+
+	var (
+		records []backup.Knowledge
+		afterID string
+	)
+	for {
+		list, err := e.core.Store().KnowledgeStore().ListKnowledgesAfterID(ctx, types.GetKnowledgeOptions{
+			SpaceID:        e.spaceID,
+			IncludeExpired: true,
+		}, afterID, SPACE_EXPORT_BATCH_SIZE)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		for _, knowledge := range list {
+			record, err := e.exportKnowledge(ctx, knowledge, resourceTitles[knowledge.Resource])
+			if err != nil {
+				return fmt.Errorf("knowledge %s: %w", knowledge.ID, err)
+			}
+			records = append(records, record)
+		}
+		if len(list) < SPACE_EXPORT_BATCH_SIZE {
+			break
+		}
+		afterID = list[len(list)-1].ID
+	}
+
+	e.manifest.Counts.Knowledges = len(records)
+	return e.writer.WriteJSON(backup.KNOWLEDGE_FILE, records)
+}
+
+func (e *SpaceExporter) exportKnowledge(ctx context.Context, knowledge *types.Knowledge, resourceTitle string) (backup.Knowledge, error) {
+	content, err := e.core.DecryptData(knowledge.Content)
+	if err != nil {
+		return backup.Knowledge{}, fmt.Errorf("failed to decrypt content: %w", err)
+	}
+
+	if resourceTitle == "" {
+		resourceTitle = knowledge.Resource
+	}
+	record := backup.Knowledge{
+		ID:          knowledge.ID,
+		Kind:        knowledge.Kind,
+		Resource:    knowledge.Resource,
+		Title:       knowledge.Title,
+		Tags:        knowledge.Tags,
+		ContentType: knowledge.ContentType,
+		UserID:      knowledge.UserID,
+		MaybeDate:   knowledge.MaybeDate,
+		Stage:       knowledge.Stage,
+		Source:      knowledge.Source,
+		SourceRef:   knowledge.SourceRef,
+		CreatedAt:   knowledge.CreatedAt,
+		UpdatedAt:   knowledge.UpdatedAt,
+		ExpiredAt:   knowledge.ExpiredAt,
+		Path:        backup.KnowledgePath(resourceTitle, knowledge.Title, knowledge.ID, knowledge.ContentType),
+	}
+
+	body := string(content)
+	if knowledge.ContentType == types.KNOWLEDGE_CONTENT_TYPE_BLOCKS {
+		record.Blocks = json.RawMessage(content)
+		if body, err = editorjs.ConvertEditorJSRawToMarkdown(record.Blocks); err != nil {
+			return record, fmt.Errorf("failed to convert editor blocks to markdown: %w", err)
+		}
+	}
+	e.addFileRefs(string(content))
+
+	// 已完成总结的知识导出分片，恢复时只需重新向量化
+	if knowledge.Kind != types.KNOWLEDGE_KIND_CHUNK && (knowledge.Stage == types.KNOWLEDGE_STAGE_EMBEDDING || knowledge.Stage == types.KNOWLEDGE_STAGE_DONE) {
+		chunks, err := e.core.Store().KnowledgeChunkStore().List(ctx, e.spaceID, knowledge.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return record, fmt.Errorf("failed to list chunks: %w", err)
+		}
+		for _, v := range chunks {
+			chunk, err := e.core.DecryptData([]byte(v.Chunk))
+			if err != nil {
+				return record, fmt.Errorf("failed to decrypt chunk: %w", err)
+			}
+			record.Chunks = append(record.Chunks, backup.Chunk{
+				Chunk:          string(chunk),
+				OriginalLength: v.OriginalLength,
+			})
+		}
+	}
+
+	return record, e.writer.WriteFile(record.Path, []byte(body))
+}
+
+func (e *SpaceExporter) exportJournals(ctx context.Context, members []string) error {
+	var records []backup.Journal
+	for _, userID := range members {
+		list, err := e.core.Store().JournalStore().List(ctx, e.spaceID, userID, types.NO_PAGINATION, types.NO_PAGINATION)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		for _, journal := range list {
+			content, err := e.core.DecryptData(journal.Content)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt journal %s: %w", journal.Date, err)
+			}
+			e.addFileRefs(string(content))
+			records = append(records, backup.Journal{
+				UserID:    journal.UserID,
+				Date:      journal.Date,
+				Content:   string(content),
+				CreatedAt: journal.CreatedAt,
+				UpdatedAt: journal.UpdatedAt,
+			})
+		}
+	}
+
+	e.manifest.Counts.Journals = len(records)
+	return e.writer.WriteJSON(backup.JOURNALS_FILE, records)
+}
+
+func (e *SpaceExporter) exportChatSessions(ctx context.Context, members []string) error {
+	var sessions []backup.ChatSession
+	for _, userID := range members {
+		list, err := e.core.Store().ChatSessionStore().List(ctx, e.spaceID, userID, types.NO_PAGINATION, types.NO_PAGINATION)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		for _, session := range list {
+			messages, err := e.core.Store().ChatMessageStore().ListSessionMessage(ctx, e.spaceID, session.ID, 0, types.NO_PAGINATION, types.NO_PAGINATION)
+			if err != nil && err != sql.ErrNoRows {
+				return fmt.Errorf("failed to list messages of session %s: %w", session.ID, err)
+			}
+			// 按发送顺序保存
+			slices.Reverse(messages)
+			for _, message := range messages {
+				if message.IsEncrypt == types.MESSAGE_IS_ENCRYPT {
+					content, err := e.core.DecryptData([]byte(message.Message))
+					if err != nil {
+						return fmt.Errorf("failed to decrypt message %s: %w", message.ID, err)
+					}
+					message.Message = string(content)
+					message.IsEncrypt = 0
+				}
+				e.addFileRefs(message.Message)
+				for _, attach := range message.Attach {
+					e.addFileRefs(attach.URL)
+				}
+			}
+
+			if err = e.writer.WriteJSON(backup.ChatMessagesFile(session.ID), messages); err != nil {
+				return err
+			}
+			sessions = append(sessions, backup.ChatSession{
+				ChatSession: session,
+				Messages:    len(messages),
+			})
+			e.manifest.Counts.ChatMessages += len(messages)
+		}
+	}
+
+	e.manifest.Counts.ChatSessions = len(sessions)
+	return e.writer.WriteJSON(backup.CHAT_SESSIONS_FILE, sessions)
+}
+
+func (e *SpaceExporter) exportButlerTables(ctx context.Context, _ []string) error {
+	list, err := e.core.Store().BulterTableStore().ListButlerTables(ctx, e.userID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	e.manifest.Counts.ButlerTables = len(list)
+	return e.writer.WriteJSON(backup.BUTLER_FILE, list)
+}
+
+func (e *SpaceExporter) exportSubscriptions(ctx context.Context, members []string) error {
+	var list []*types.RSSSubscription
+	for _, userID := range members {
+		subscriptions, err := e.core.Store().RSSSubscriptionStore().List(ctx, userID, e.spaceID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		list = append(list, subscriptions...)
+	}
+	e.manifest.Counts.Subscriptions = len(list)
+	return e.writer.WriteJSON(backup.RSS_FILE, list)
+}
+
+// exportFiles 导出文件管理中记录的文件与内容中引用的空间文件，下载失败的文件会被跳过
+func (e *SpaceExporter) exportFiles(ctx context.Context, _ []string) error {
+	managed, err := e.core.Store().FileManagementStore().ListBySpace(ctx, e.spaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	for _, v := range managed {
+		if v.Status != types.FILE_UPLOAD_STATUS_UPLOADED {
+			continue
+		}
+		e.files[v.File] = &backup.File{
+			Path:       v.File,
+			UserID:     v.UserID,
+			ObjectType: v.ObjectType,
+			Kind:       v.Kind,
+			Size:       v.FileSize,
+			Managed:    true,
+			CreatedAt:  v.CreatedAt,
+		}
+	}
+
+	paths := make([]string, 0, len(e.files))
+	for p := range e.files {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	storage := e.core.Plugins.FileStorage()
+	var list []backup.File
+	for _, p := range paths {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		res, err := storage.DownloadFile(ctx, p)
+		if err != nil {
+			slog.Warn("Failed to download space file for export", slog.String("space_id", e.spaceID), slog.String("file", p), slog.String("error", err.Error()))
+			continue
+		}
+		if err = e.writer.WriteFile(backup.ObjectFile(p), res.File); err != nil {
+			return err
+		}
+		file := *e.files[p]
+		file.Size = int64(len(res.File))
+		list = append(list, file)
+	}
+
+	e.manifest.Counts.Files = len(list)
+	return e.writer.WriteJSON(backup.FILES_FILE, list)
+}
+
+// addFileRefs 记录内容中引用的空间文件
+func (e *SpaceExporter) addFileRefs(content string) {
+	for _, p := range backup.ObjectPaths(e.spaceID, content) {
+		if _, ok := e.files[p]; !ok {
+			e.files[p] = &backup.File{Path: p}
+		}
+	}
+}
@@ -0,0 +1,439 @@
+package process
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/backup"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// BackupRestoreTask 将空间备份归档恢复到任务所在空间
+// 知识、会话等数据使用新的ID创建，知识之间的 [[knowledge-id]] 引用与空间文件路径会被改写；
+// 备份中的用户不是目标空间成员时，数据归属于发起恢复的用户。
+// 已完成总结的知识直接从分片重新向量化，其余知识重新进入总结流程
+type BackupRestoreTask struct {
+	*importRunner
+	reader *backup.Reader
+
+	members  map[string]bool
+	replacer *strings.Replacer
+}
+
+// NewBackupRestoreTask 创建备份恢复任务执行器
+func NewBackupRestoreTask(core *core.Core, task *types.ImportTask, reader *backup.Reader) *BackupRestoreTask {
+	return &BackupRestoreTask{
+		importRunner: newImportRunner(core, task),
+		reader:       reader,
+	}
+}
+
+// OnProgress 设置每个条目处理完成后的进度回调
+func (t *BackupRestoreTask) OnProgress(f func(task types.ImportTask)) *BackupRestoreTask {
+	t.onProgress = f
+	return t
+}
+
+// Run 依次恢复文件、resource、知识、日记、聊天会话、butler 数据表与 RSS 订阅，单个条目失败不会中断任务
+func (t *BackupRestoreTask) Run(ctx context.Context) error {
+	manifest := t.reader.Manifest
+	slog.Info("Backup restore started", slog.String("task_id", t.task.ID), slog.String("space_id", t.task.SpaceID),
+		slog.String("from_space", manifest.Space.SpaceID), slog.Int("version", manifest.Version))
+
+	members, err := t.core.Store().UserSpaceStore().ListSpaceUsers(ctx, t.task.SpaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return t.finish(ctx, fmt.Errorf("failed to list space users: %w", err))
+	}
+	t.members = lo.SliceToMap(members, func(id string) (string, bool) { return id, true })
+
+	var knowledges []backup.Knowledge
+	if err = t.reader.ReadJSON(backup.KNOWLEDGE_FILE, &knowledges); err != nil {
+		return t.finish(ctx, err)
+	}
+	knowledgeIDs := make(map[string]string, len(knowledges))
+	for _, v := range knowledges {
+		knowledgeIDs[v.ID] = utils.GenUniqIDStr()
+	}
+	t.replacer = newRestoreReplacer(manifest.Space.SpaceID, t.task.SpaceID, knowledgeIDs)
+
+	steps := []func(ctx context.Context) error{
+		t.restoreFiles,
+		t.restoreResources,
+		func(ctx context.Context) error { return t.restoreKnowledges(ctx, knowledges, knowledgeIDs) },
+		t.restoreJournals,
+		t.restoreChatSessions,
+		t.restoreButlerTables,
+		t.restoreSubscriptions,
+	}
+	for _, step := range steps {
+		if ctx.Err() != nil {
+			return t.finish(ctx, ctx.Err())
+		}
+		if err = step(ctx); err != nil {
+			return t.finish(ctx, err)
+		}
+	}
+	return t.finish(ctx, nil)
+}
+
+// newRestoreReplacer 改写内容中的空间文件路径与知识引用
+func newRestoreReplacer(fromSpaceID, toSpaceID string, knowledgeIDs map[string]string) *strings.Replacer {
+	var pairs []string
+	if fromSpaceID != "" && fromSpaceID != toSpaceID {
+		pairs = append(pairs, backup.SpaceObjectPrefix(fromSpaceID), backup.SpaceObjectPrefix(toSpaceID))
+	}
+	for from, to := range knowledgeIDs {
+		pairs = append(pairs, "[["+from+"|", "[["+to+"|", "[["+from+"]]", "[["+to+"]]")
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// owner 备份中的用户是空间成员时保留，否则归属于发起恢复的用户
+func (t *BackupRestoreTask) owner(userID string) string {
+	if t.members[userID] {
+		return userID
+	}
+	return t.task.UserID
+}
+
+// step 执行单个条目的恢复并记录结果，f 返回 skip 不为空时条目记为跳过
+func (t *BackupRestoreTask) step(ctx context.Context, itemPath, title string, f func() (knowledgeID, skip string, err error)) {
+	item := types.ImportTaskItem{
+		Path:   itemPath,
+		Title:  title,
+		Status: types.IMPORT_ITEM_STATUS_SUCCESS,
+	}
+	id, skip, err := f()
+	switch {
+	case err != nil:
+		item.Status = types.IMPORT_ITEM_STATUS_FAILED
+		item.Error = err.Error()
+		slog.Error("Failed to restore backup item", slog.String("task_id", t.task.ID), slog.String("path", itemPath), slog.String("error", err.Error()))
+	case skip != "":
+		item.Status = types.IMPORT_ITEM_STATUS_SKIPPED
+		item.Error = skip
+	default:
+		item.KnowledgeID = id
+	}
+	t.record(ctx, item)
+}
+
+// restoreFilePath 改写备份中的文件路径，改写后的路径必须位于目标空间的文件目录下，
+// 防止通过构造的 files.json 写入任意路径或覆盖其他空间的文件
+func restoreFilePath(replacer *strings.Replacer, spaceID, filePath string) (string, error) {
+	result := path.Clean(replacer.Replace(filePath))
+	if !strings.HasPrefix(result, backup.SpaceObjectPrefix(spaceID)) {
+		return "", fmt.Errorf("invalid file path %q: outside of space", filePath)
+	}
+	return result, nil
+}
+
+func (t *BackupRestoreTask) restoreFiles(ctx context.Context) error {
+	var files []backup.File
+	if err := t.reader.ReadJSON(backup.FILES_FILE, &files); err != nil {
+		return err
+	}
+
+	storage := t.core.Plugins.FileStorage()
+	for _, file := range files {
+		t.step(ctx, file.Path, path.Base(file.Path), func() (string, string, error) {
+			filePath, err := restoreFilePath(t.replacer, t.task.SpaceID, file.Path)
+			if err != nil {
+				return "", "", err
+			}
+			data, err := t.reader.ReadFile(backup.ObjectFile(file.Path))
+			if err != nil {
+				return "", "", err
+			}
+			if err = storage.SaveFile(filePath, data); err != nil {
+				return "", "", fmt.Errorf("failed to save file: %w", err)
+			}
+			if !file.Managed {
+				return "", "", nil
+			}
+
+			if _, err = t.core.Store().FileManagementStore().GetByID(ctx, t.task.SpaceID, filePath); err == nil {
+				return "", "", nil
+			} else if err != sql.ErrNoRows {
+				return "", "", fmt.Errorf("failed to get file record: %w", err)
+			}
+			err = t.core.Store().FileManagementStore().Create(ctx, types.FileManagement{
+				SpaceID:    t.task.SpaceID,
+				UserID:     t.owner(file.UserID),
+				File:       filePath,
+				FileSize:   int64(len(data)),
+				ObjectType: file.ObjectType,
+				Kind:       file.Kind,
+				Status:     types.FILE_UPLOAD_STATUS_UPLOADED,
+				CreatedAt:  file.CreatedAt,
+			})
+			return "", "", err
+		})
+	}
+	return nil
+}
+
+// restoreResources 按ID恢复 resource，目标空间已存在同ID的 resource 时沿用
+func (t *BackupRestoreTask) restoreResources(ctx context.Context) error {
+	var resources []types.Resource
+	if err := t.reader.ReadJSON(backup.RESOURCES_FILE, &resources); err != nil {
+		return err
+	}
+
+	for _, resource := range resources {
+		t.step(ctx, path.Join("resources", resource.ID), resource.Title, func() (string, string, error) {
+			if _, err := t.core.Store().ResourceStore().GetResource(ctx, t.task.SpaceID, resource.ID); err == nil {
+				return "", "resource already exists", nil
+			} else if err != sql.ErrNoRows {
+				return "", "", err
+			}
+
+			resource.SpaceID = t.task.SpaceID
+			resource.UserID = t.owner(resource.UserID)
+			return "", "", t.core.Store().ResourceStore().Create(ctx, resource)
+		})
+	}
+	return nil
+}
+
+func (t *BackupRestoreTask) restoreKnowledges(ctx context.Context, knowledges []backup.Knowledge, knowledgeIDs map[string]string) error {
+	for _, record := range knowledges {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		t.step(ctx, record.Path, record.Title, func() (string, string, error) {
+			id := knowledgeIDs[record.ID]
+			return id, "", t.restoreKnowledge(ctx, id, record)
+		})
+	}
+	return nil
+}
+
+func (t *BackupRestoreTask) restoreKnowledge(ctx context.Context, id string, record backup.Knowledge) error {
+	var content string
+	if record.ContentType == types.KNOWLEDGE_CONTENT_TYPE_BLOCKS && len(record.Blocks) > 0 {
+		content = string(record.Blocks)
+	} else {
+		data, err := t.reader.ReadFile(record.Path)
+		if err != nil {
+			return err
+		}
+		content = string(data)
+	}
+	encryptData, err := t.core.EncryptData([]byte(t.replacer.Replace(content)))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt content: %w", err)
+	}
+
+	knowledge := types.Knowledge{
+		ID:          id,
+		SpaceID:     t.task.SpaceID,
+		Kind:        record.Kind,
+		Resource:    lo.If(record.Resource == "", types.DEFAULT_RESOURCE).Else(record.Resource),
+		Title:       record.Title,
+		Tags:        record.Tags,
+		Content:     encryptData,
+		ContentType: record.ContentType,
+		UserID:      t.owner(record.UserID),
+		MaybeDate:   record.MaybeDate,
+		Source:      record.Source,
+		SourceRef:   record.SourceRef,
+		CreatedAt:   record.CreatedAt,
+		UpdatedAt:   record.UpdatedAt,
+		ExpiredAt:   record.ExpiredAt,
+		Stage:       types.KNOWLEDGE_STAGE_SUMMARIZE,
+		// 保留备份中的标题与标签，只重新生成分片
+		Summary: "content",
+	}
+
+	var chunks []*types.KnowledgeChunk
+	switch {
+	case record.Kind == types.KNOWLEDGE_KIND_CHUNK:
+		// chunk 类型的知识直接使用正文向量化
+		knowledge.Stage = types.KNOWLEDGE_STAGE_EMBEDDING
+	case len(record.Chunks) > 0:
+		now := time.Now().Unix()
+		for _, v := range record.Chunks {
+			chunk, err := t.core.EncryptData([]byte(t.replacer.Replace(v.Chunk)))
+			if err != nil {
+				return fmt.Errorf("failed to encrypt chunk: %w", err)
+			}
+			chunks = append(chunks, &types.KnowledgeChunk{
+				ID:             utils.GenUniqIDStr(),
+				KnowledgeID:    id,
+				SpaceID:        t.task.SpaceID,
+				UserID:         knowledge.UserID,
+				Chunk:          string(chunk),
+				OriginalLength: v.OriginalLength,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			})
+		}
+		knowledge.Stage = types.KNOWLEDGE_STAGE_EMBEDDING
+	}
+
+	return t.core.Store().Transaction(ctx, func(ctx context.Context) error {
+		if err := t.core.Store().KnowledgeStore().Create(ctx, knowledge); err != nil {
+			return fmt.Errorf("failed to create knowledge: %w", err)
+		}
+		if len(chunks) > 0 {
+			if err := t.core.Store().KnowledgeChunkStore().BatchCreate(ctx, chunks); err != nil {
+				return fmt.Errorf("failed to create knowledge chunks: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (t *BackupRestoreTask) restoreJournals(ctx context.Context) error {
+	var journals []backup.Journal
+	if err := t.reader.ReadJSON(backup.JOURNALS_FILE, &journals); err != nil {
+		return err
+	}
+
+	for _, journal := range journals {
+		t.step(ctx, path.Join("journals", journal.Date), journal.Date, func() (string, string, error) {
+			userID := t.owner(journal.UserID)
+			exist, err := t.core.Store().JournalStore().Exist(ctx, t.task.SpaceID, userID, journal.Date)
+			if err != nil {
+				return "", "", err
+			}
+			if exist {
+				return "", "journal already exists", nil
+			}
+
+			content, err := t.core.EncryptData([]byte(t.replacer.Replace(journal.Content)))
+			if err != nil {
+				return "", "", fmt.Errorf("failed to encrypt content: %w", err)
+			}
+			return "", "", t.core.Store().JournalStore().Create(ctx, types.Journal{
+				ID:        utils.GenUniqID(),
+				SpaceID:   t.task.SpaceID,
+				UserID:    userID,
+				Date:      journal.Date,
+				Content:   content,
+				CreatedAt: journal.CreatedAt,
+				UpdatedAt: journal.UpdatedAt,
+			})
+		})
+	}
+	return nil
+}
+
+func (t *BackupRestoreTask) restoreChatSessions(ctx context.Context) error {
+	var sessions []backup.ChatSession
+	if err := t.reader.ReadJSON(backup.CHAT_SESSIONS_FILE, &sessions); err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		t.step(ctx, backup.ChatMessagesFile(session.ID), session.Title, func() (string, string, error) {
+			return "", "", t.restoreChatSession(ctx, session.ChatSession)
+		})
+	}
+	return nil
+}
+
+// restoreChatSession 恢复会话与消息，消息的检索记录与会话摘要不会恢复
+func (t *BackupRestoreTask) restoreChatSession(ctx context.Context, session types.ChatSession) error {
+	var messages []*types.ChatMessage
+	if err := t.reader.ReadJSON(backup.ChatMessagesFile(session.ID), &messages); err != nil {
+		return err
+	}
+
+	session.ID = utils.GenSpecIDStr()
+	session.SpaceID = t.task.SpaceID
+	session.UserID = t.owner(session.UserID)
+	return t.core.Store().Transaction(ctx, func(ctx context.Context) error {
+		if err := t.core.Store().ChatSessionStore().Create(ctx, session); err != nil {
+			return fmt.Errorf("failed to create chat session: %w", err)
+		}
+
+		for _, message := range messages {
+			content, err := t.core.EncryptData([]byte(t.replacer.Replace(message.Message)))
+			if err != nil {
+				return fmt.Errorf("failed to encrypt message: %w", err)
+			}
+			message.ID = utils.GenUniqIDStr()
+			message.SpaceID = session.SpaceID
+			message.SessionID = session.ID
+			message.UserID = t.owner(message.UserID)
+			message.Message = string(content)
+			message.IsEncrypt = types.MESSAGE_IS_ENCRYPT
+			for i := range message.Attach {
+				message.Attach[i].URL = t.replacer.Replace(message.Attach[i].URL)
+			}
+			if err = t.core.Store().ChatMessageStore().Create(ctx, message); err != nil {
+				return fmt.Errorf("failed to create chat message: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// restoreButlerTables 将 butler 数据表恢复给发起恢复的用户，已存在同名数据表时跳过
+func (t *BackupRestoreTask) restoreButlerTables(ctx context.Context) error {
+	var tables []types.ButlerTable
+	if err := t.reader.ReadJSON(backup.BUTLER_FILE, &tables); err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+
+	exists, err := t.core.Store().BulterTableStore().ListButlerTables(ctx, t.task.UserID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to list butler tables: %w", err)
+	}
+	names := lo.SliceToMap(exists, func(item types.ButlerTable) (string, bool) { return item.TableName, true })
+
+	for _, table := range tables {
+		t.step(ctx, path.Join("butler", table.TableID), table.TableName, func() (string, string, error) {
+			if names[table.TableName] {
+				return "", "butler table already exists", nil
+			}
+			table.TableID = utils.GenUniqIDStr()
+			table.UserID = t.task.UserID
+			return "", "", t.core.Store().BulterTableStore().Create(ctx, table)
+		})
+	}
+	return nil
+}
+
+// restoreSubscriptions 恢复 RSS 订阅，同一用户已订阅相同地址时跳过
+func (t *BackupRestoreTask) restoreSubscriptions(ctx context.Context) error {
+	var subscriptions []*types.RSSSubscription
+	if err := t.reader.ReadJSON(backup.RSS_FILE, &subscriptions); err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		t.step(ctx, path.Join("rss", subscription.ID), subscription.Title, func() (string, string, error) {
+			userID := t.owner(subscription.UserID)
+			if _, err := t.core.Store().RSSSubscriptionStore().GetByUserAndURL(ctx, userID, subscription.URL); err == nil {
+				return "", "subscription already exists", nil
+			} else if err != sql.ErrNoRows {
+				return "", "", err
+			}
+
+			now := time.Now().Unix()
+			subscription.ID = utils.GenUniqIDStr()
+			subscription.UserID = userID
+			subscription.SpaceID = t.task.SpaceID
+			subscription.LastFetchedAt = 0
+			subscription.CreatedAt = now
+			subscription.UpdatedAt = now
+			return "", "", t.core.Store().RSSSubscriptionStore().Create(ctx, subscription)
+		})
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package process
+
+import "testing"
+
+func TestRestoreFilePath(t *testing.T) {
+	replacer := newRestoreReplacer("old", "new", nil)
+
+	cases := []struct {
+		path    string
+		want    string
+		invalid bool
+	}{
+		{path: "/assets/s3/old/knowledge/a.png", want: "/assets/s3/new/knowledge/a.png"},
+		{path: "/assets/s3/old/knowledge/../b.png", want: "/assets/s3/new/b.png"},
+		{path: "/assets/s3/old/../other/a.png", invalid: true},
+		{path: "/assets/s3/old/../../../etc/passwd", invalid: true},
+		{path: "/assets/s3/other/a.png", invalid: true},
+		{path: "../../etc/passwd", invalid: true},
+	}
+	for _, c := range cases {
+		got, err := restoreFilePath(replacer, "new", c.path)
+		if c.invalid {
+			if err == nil {
+				t.Errorf("restoreFilePath(%q) = %q, expected error", c.path, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("restoreFilePath(%q) unexpected error: %v", c.path, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("restoreFilePath(%q) = %q, want %q", c.path, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/safe"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type ReembedLogic struct {
+	ctx  context.Context
+	core *core.Core
+}
+
+func NewReembedLogic(ctx context.Context, core *core.Core) *ReembedLogic {
+	return &ReembedLogic{
+		ctx:  ctx,
+		core: core,
+	}
+}
+
+// PrepareJob 获取或创建当前 embedding 模型对应的重建任务
+// 若已有同模型的未完成任务则继续该任务，其他模型的未完成任务会被取消
+func (l *ReembedLogic) PrepareJob(batchSize int, interval int64) (*types.ReembedJob, error) {
+	model := l.core.Srv().AI().EmbeddingModel()
+	if model == "" {
+		return nil, errors.New("ReembedLogic.PrepareJob.EmbeddingModel", i18n.ERROR_AI_EMBEDDING_MODEL_NOT_FOUND, nil).Code(http.StatusBadRequest)
+	}
+
+	latest, err := l.core.Store().ReembedJobStore().GetLatest(l.ctx)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("ReembedLogic.PrepareJob.ReembedJobStore.GetLatest", i18n.ERROR_INTERNAL, err)
+	}
+
+	if latest != nil && latest.IsActive() {
+		if latest.Model == model {
+			if latest.Status != types.REEMBED_JOB_STATUS_RUNNING {
+				if err = l.core.Store().ReembedJobStore().UpdateStatus(l.ctx, latest.ID, types.REEMBED_JOB_STATUS_RUNNING, ""); err != nil {
+					return nil, errors.New("ReembedLogic.PrepareJob.ReembedJobStore.UpdateStatus", i18n.ERROR_INTERNAL, err)
+				}
+				latest.Status = types.REEMBED_JOB_STATUS_RUNNING
+			}
+			return latest, nil
+		}
+
+		if err = l.core.Store().ReembedJobStore().UpdateStatus(l.ctx, latest.ID, types.REEMBED_JOB_STATUS_CANCELED, "embedding model changed"); err != nil {
+			return nil, errors.New("ReembedLogic.PrepareJob.ReembedJobStore.UpdateStatus", i18n.ERROR_INTERNAL, err)
+		}
+	}
+
+	total, err := l.core.Store().KnowledgeStore().Total(l.ctx, types.GetKnowledgeOptions{
+		Stage:          types.KNOWLEDGE_STAGE_DONE,
+		IncludeExpired: true,
+	})
+	if err != nil {
+		return nil, errors.New("ReembedLogic.PrepareJob.KnowledgeStore.Total", i18n.ERROR_INTERNAL, err)
+	}
+
+	if batchSize <= 0 {
+		batchSize = process.REEMBED_DEFAULT_BATCH_SIZE
+	}
+
+	job := types.ReembedJob{
+		ID:        utils.GenUniqIDStr(),
+		Model:     model,
+		Status:    types.REEMBED_JOB_STATUS_RUNNING,
+		Total:     int64(total),
+		BatchSize: batchSize,
+		Interval:  interval,
+	}
+	if err = l.core.Store().ReembedJobStore().Create(l.ctx, job); err != nil {
+		return nil, errors.New("ReembedLogic.PrepareJob.ReembedJobStore.Create", i18n.ERROR_INTERNAL, err)
+	}
+	return &job, nil
+}
+
+// StartJob 创建或继续重建任务，并在后台执行
+func (l *ReembedLogic) StartJob(batchSize int, interval int64) (*types.ReembedJob, error) {
+	job, err := l.PrepareJob(batchSize, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	go safe.Run(func() {
+		if err := process.NewReembedTask(l.core, job.ID).Run(context.Background()); err != nil {
+			// 任务已在执行中时，执行中的任务会自行读取到最新状态
+			slog.Warn("Reembed job not started", slog.String("job_id", job.ID), slog.String("error", err.Error()))
+		}
+	})
+	return job, nil
+}
+
+// GetLatestJob 获取最近一次重建任务，不存在时返回 nil
+func (l *ReembedLogic) GetLatestJob() (*types.ReembedJob, error) {
+	job, err := l.core.Store().ReembedJobStore().GetLatest(l.ctx)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("ReembedLogic.GetLatestJob.ReembedJobStore.GetLatest", i18n.ERROR_INTERNAL, err)
+	}
+	return job, nil
+}
+
+// PauseJob 暂停执行中的任务，执行器会在当前批次结束后停止
+func (l *ReembedLogic) PauseJob(id string) error {
+	job, err := l.getJob(id)
+	if err != nil {
+		return err
+	}
+	if job.Status != types.REEMBED_JOB_STATUS_RUNNING {
+		return errors.New("ReembedLogic.PauseJob.Status", i18n.ERROR_FORBIDDEN, nil).Code(http.StatusBadRequest)
+	}
+
+	if err = l.core.Store().ReembedJobStore().UpdateStatus(l.ctx, id, types.REEMBED_JOB_STATUS_PAUSED, ""); err != nil {
+		return errors.New("ReembedLogic.PauseJob.ReembedJobStore.UpdateStatus", i18n.ERROR_INTERNAL, err)
+	}
+	return nil
+}
+
+// CancelJob 取消未完成的任务，已重建的向量会保留
+func (l *ReembedLogic) CancelJob(id string) error {
+	job, err := l.getJob(id)
+	if err != nil {
+		return err
+	}
+	if !job.IsActive() {
+		return errors.New("ReembedLogic.CancelJob.Status", i18n.ERROR_FORBIDDEN, nil).Code(http.StatusBadRequest)
+	}
+
+	if err = l.core.Store().ReembedJobStore().UpdateStatus(l.ctx, id, types.REEMBED_JOB_STATUS_CANCELED, ""); err != nil {
+		return errors.New("ReembedLogic.CancelJob.ReembedJobStore.UpdateStatus", i18n.ERROR_INTERNAL, err)
+	}
+	return nil
+}
+
+func (l *ReembedLogic) getJob(id string) (*types.ReembedJob, error) {
+	job, err := l.core.Store().ReembedJobStore().Get(l.ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("ReembedLogic.getJob.ReembedJobStore.Get", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNotFound)
+		}
+		return nil, errors.New("ReembedLogic.getJob.ReembedJobStore.Get", i18n.ERROR_INTERNAL, err)
+	}
+	return job, nil
+}
@@ -62,7 +62,7 @@ func (l *ResourceLogic) CreateResource(spaceID, userID, id, title, desc, tag str
 }
 
 func (l *ResourceLogic) Delete(spaceID, id string) error {
-	return l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
+	err := l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
 		knowledgeIDs, err := l.core.Store().KnowledgeStore().ListKnowledgeIDs(ctx, types.GetKnowledgeOptions{
 			Resource: &types.ResourceQuery{
 				Include: []string{id},
@@ -91,7 +91,12 @@ func (l *ResourceLogic) Delete(spaceID, id string) error {
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
+	l.core.InvalidateResource(l.ctx, spaceID, id)
+	return nil
 }
 
 func (l *ResourceLogic) Update(spaceID, id, title, desc, tag string, cycle int) error {
@@ -112,7 +117,7 @@ func (l *ResourceLogic) Update(spaceID, id, title, desc, tag string, cycle int)
 		}
 	}
 
-	return l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
+	err = l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
 		// 更新resource
 		err = l.core.Store().ResourceStore().Update(ctx, spaceID, id, title, desc, tag, cycle)
 		if err != nil {
@@ -129,6 +134,12 @@ func (l *ResourceLogic) Update(spaceID, id, title, desc, tag string, cycle int)
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	l.core.InvalidateResource(l.ctx, spaceID, id)
+	return nil
 }
 
 func (l *ResourceLogic) ListSpaceResources(spaceID string) ([]types.Resource, error) {
@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// SpaceRetrievalProfile 空间检索参数
+type SpaceRetrievalProfile struct {
+	Space     *types.RetrievalProfileConfig `json:"space"`     // 空间单独设置的参数
+	System    types.RetrievalProfile        `json:"system"`    // 系统默认参数
+	Effective types.RetrievalProfile        `json:"effective"` // 实际生效的参数
+}
+
+// GetRetrievalProfile 获取空间的检索参数
+func (l *SpaceLogic) GetRetrievalProfile(spaceID string) (*SpaceRetrievalProfile, error) {
+	space, err := l.core.Store().SpaceStore().GetSpace(l.ctx, spaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("SpaceLogic.GetRetrievalProfile.SpaceStore.GetSpace", i18n.ERROR_INTERNAL, err)
+	}
+
+	if space == nil {
+		return nil, errors.New("SpaceLogic.GetRetrievalProfile.SpaceStore.GetSpace.nil", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNotFound)
+	}
+
+	result := &SpaceRetrievalProfile{
+		Space:  space.Settings.RetrievalProfile,
+		System: l.core.SystemRetrievalProfile(l.ctx),
+	}
+	result.Effective = result.System
+	if result.Space != nil {
+		result.Effective = result.Space.Apply(result.System)
+	}
+	return result, nil
+}
+
+// UpdateRetrievalProfile 更新空间的检索参数，profile 为 nil 时恢复使用系统默认参数
+func (l *SpaceLogic) UpdateRetrievalProfile(spaceID string, profile *types.RetrievalProfileConfig) error {
+	user := l.GetUserInfo()
+	if !l.core.Srv().RBAC().CheckPermission(user.GetRole(), srv.PermissionAdmin) {
+		return errors.New("SpaceLogic.UpdateRetrievalProfile.CheckPermission", i18n.ERROR_PERMISSION_DENIED, nil).Code(http.StatusForbidden)
+	}
+
+	if profile != nil {
+		if err := profile.Validate(); err != nil {
+			return errors.New("SpaceLogic.UpdateRetrievalProfile.Validate", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+		}
+		if profile.IsEmpty() {
+			profile = nil
+		}
+	}
+
+	space, err := l.core.Store().SpaceStore().GetSpace(l.ctx, spaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return errors.New("SpaceLogic.UpdateRetrievalProfile.SpaceStore.GetSpace", i18n.ERROR_INTERNAL, err)
+	}
+
+	if space == nil {
+		return errors.New("SpaceLogic.UpdateRetrievalProfile.SpaceStore.GetSpace.nil", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNotFound)
+	}
+
+	settings := space.Settings
+	settings.RetrievalProfile = profile
+	if err = l.core.Store().SpaceStore().UpdateSettings(l.ctx, spaceID, settings); err != nil {
+		return errors.New("SpaceLogic.UpdateRetrievalProfile.SpaceStore.UpdateSettings", i18n.ERROR_INTERNAL, err)
+	}
+	l.core.InvalidateSpace(l.ctx, spaceID)
+	return nil
+}
+
+// GetSystemRetrievalProfile 获取系统默认的检索参数
+func (l *CustomConfigLogic) GetSystemRetrievalProfile() types.RetrievalProfile {
+	return l.core.SystemRetrievalProfile(l.ctx)
+}
+
+// UpdateSystemRetrievalProfile 更新系统默认的检索参数，未设置的字段使用内置默认值
+func (l *CustomConfigLogic) UpdateSystemRetrievalProfile(profile types.RetrievalProfileConfig) (types.RetrievalProfile, error) {
+	if err := profile.Validate(); err != nil {
+		return types.RetrievalProfile{}, errors.New("CustomConfigLogic.UpdateSystemRetrievalProfile.Validate", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+	}
+
+	if _, err := l.UpsertCustomConfig(types.RETRIEVAL_PROFILE_CONFIG_NAME, types.RETRIEVAL_PROFILE_CONFIG_DESC,
+		types.RETRIEVAL_PROFILE_CATEGORY, profile, types.StatusEnabled); err != nil {
+		return types.RetrievalProfile{}, err
+	}
+	return l.GetSystemRetrievalProfile(), nil
+}
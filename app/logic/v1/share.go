@@ -200,7 +200,8 @@ func (l *ShareLogic) GetKnowledgeByShareToken(token string) (*KnowledgeShareInfo
 		return nil, errors.New("ShareLogic.GetKnowledgeByShareToken.KnowledgeStore.GetKnowledge", i18n.ERROR_INTERNAL, err)
 	}
 
-	if knowledge == nil {
+	// 回收站中的知识不再对外分享
+	if knowledge == nil || knowledge.DeletedAt != 0 {
 		return nil, errors.New("ShareLogic.GetKnowledgeByShareToken.KnowledgeStore.GetKnowledge.nil", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNoContent)
 	}
 
@@ -361,7 +362,7 @@ func (l *ShareLogic) CopyKnowledgeByShareToken(token, toSpaceID, toResource stri
 		return errors.New("ShareLogic.CopyKnowledgeByShareToken.KnowledgeStore.GetKnowledge", i18n.ERROR_INTERNAL, err)
 	}
 
-	if originKnowledge == nil {
+	if originKnowledge == nil || originKnowledge.DeletedAt != 0 {
 		return errors.New("ShareLogic.CopyKnowledgeByShareToken.KnowledgeStore.GetKnowledge.nil", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNoContent)
 	}
 
@@ -99,6 +99,7 @@ func (l *SpaceLogic) SetUserSpaceRole(spaceID, userID, role string) error {
 		if err = l.core.Store().UserSpaceStore().Update(l.ctx, userID, spaceID, role); err != nil {
 			return errors.New("SpaceLogic.SetUserSpaceRole.UserSpaceStore.Update", i18n.ERROR_INTERNAL, err)
 		}
+		l.core.InvalidateUserSpaceRole(l.ctx, spaceID, userID)
 	}
 
 	return nil
@@ -184,6 +185,119 @@ func (l *SpaceLogic) UpdateSpace(spaceID, title, desc, basePrompt, chatPrompt st
 	if err = l.core.Store().SpaceStore().Update(l.ctx, spaceID, title, desc, basePrompt, chatPrompt); err != nil {
 		return errors.New("SpaceLogic.UpdateSpace.SpaceStore.Update", i18n.ERROR_INTERNAL, err)
 	}
+	l.core.InvalidateSpace(l.ctx, spaceID)
+
+	return nil
+}
+
+func (l *SpaceLogic) GetSpaceSettings(spaceID string) (*types.SpaceSettings, error) {
+	space, err := l.core.Store().SpaceStore().GetSpace(l.ctx, spaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("SpaceLogic.GetSpaceSettings.SpaceStore.GetSpace", i18n.ERROR_INTERNAL, err)
+	}
+
+	if space == nil {
+		return nil, errors.New("SpaceLogic.GetSpaceSettings.SpaceStore.GetSpace.nil", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNotFound)
+	}
+
+	return &space.Settings, nil
+}
+
+func (l *SpaceLogic) UpdateSpaceSettings(spaceID string, settings types.SpaceSettings) error {
+	user := l.GetUserInfo()
+	if !l.core.Srv().RBAC().CheckPermission(user.GetRole(), srv.PermissionAdmin) {
+		return errors.New("SpaceLogic.UpdateSpaceSettings.CheckPermission", i18n.ERROR_PERMISSION_DENIED, nil).Code(http.StatusForbidden)
+	}
+
+	switch settings.RetrievalMode {
+	case "", types.RETRIEVAL_MODE_VECTOR, types.RETRIEVAL_MODE_HYBRID:
+	default:
+		return errors.New("SpaceLogic.UpdateSpaceSettings.UnknownRetrievalMode", i18n.ERROR_INVALIDARGUMENT, nil).Code(http.StatusBadRequest)
+	}
+
+	if settings.RetrievalProfile != nil {
+		if err := settings.RetrievalProfile.Validate(); err != nil {
+			return errors.New("SpaceLogic.UpdateSpaceSettings.RetrievalProfile", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+		}
+	}
+	if settings.DuplicateDetection != nil {
+		if err := settings.DuplicateDetection.Validate(); err != nil {
+			return errors.New("SpaceLogic.UpdateSpaceSettings.DuplicateDetection", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+		}
+	}
+	if settings.Chunker != nil {
+		if err := settings.Chunker.Validate(); err != nil {
+			return errors.New("SpaceLogic.UpdateSpaceSettings.Chunker", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+		}
+	}
+	if settings.Revision != nil {
+		if err := settings.Revision.Validate(); err != nil {
+			return errors.New("SpaceLogic.UpdateSpaceSettings.Revision", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+		}
+	}
+	if settings.Trash != nil {
+		if err := settings.Trash.Validate(); err != nil {
+			return errors.New("SpaceLogic.UpdateSpaceSettings.Trash", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+		}
+	}
+
+	space, err := l.core.Store().SpaceStore().GetSpace(l.ctx, spaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return errors.New("SpaceLogic.UpdateSpaceSettings.SpaceStore.GetSpace", i18n.ERROR_INTERNAL, err)
+	}
+	if space != nil {
+		// 未传入的配置项保留空间原有的设置
+		if settings.RetrievalMode == "" {
+			settings.RetrievalMode = space.Settings.RetrievalMode
+		}
+		if settings.RetrievalProfile == nil {
+			settings.RetrievalProfile = space.Settings.RetrievalProfile
+		}
+		if settings.DuplicateDetection == nil {
+			settings.DuplicateDetection = space.Settings.DuplicateDetection
+		}
+		if settings.Chunker == nil {
+			settings.Chunker = space.Settings.Chunker
+		}
+		if settings.Revision == nil {
+			settings.Revision = space.Settings.Revision
+		}
+		if settings.Trash == nil {
+			settings.Trash = space.Settings.Trash
+		}
+		if settings.KnowledgeGraph == nil {
+			settings.KnowledgeGraph = space.Settings.KnowledgeGraph
+		}
+	}
+
+	err = l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
+		if err := l.core.Store().SpaceStore().UpdateSettings(ctx, spaceID, settings); err != nil {
+			return errors.New("SpaceLogic.UpdateSpaceSettings.SpaceStore.UpdateSettings", i18n.ERROR_INTERNAL, err)
+		}
+		// 显式切换为向量检索后清除明文的关键词索引，未传入检索模式时沿用原有模式
+		if space != nil && space.Settings.IsHybridRetrieval() && !settings.IsHybridRetrieval() {
+			if err := l.core.Store().VectorStore().ClearKeywords(ctx, spaceID); err != nil {
+				return errors.New("SpaceLogic.UpdateSpaceSettings.VectorStore.ClearKeywords", i18n.ERROR_INTERNAL, err)
+			}
+		}
+		// 显式关闭知识图谱后清除明文的实体与关系，未传入时沿用原有设置
+		if space != nil && space.Settings.IsKnowledgeGraphEnabled() && !settings.IsKnowledgeGraphEnabled() {
+			if err := l.core.Store().KnowledgeEntityStore().DeleteAll(ctx, spaceID); err != nil {
+				return errors.New("SpaceLogic.UpdateSpaceSettings.KnowledgeEntityStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+			}
+			if err := l.core.Store().KnowledgeEntityMentionStore().DeleteAll(ctx, spaceID); err != nil {
+				return errors.New("SpaceLogic.UpdateSpaceSettings.KnowledgeEntityMentionStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+			}
+			if err := l.core.Store().KnowledgeRelationStore().DeleteAll(ctx, spaceID); err != nil {
+				return errors.New("SpaceLogic.UpdateSpaceSettings.KnowledgeRelationStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	l.core.InvalidateSpace(l.ctx, spaceID)
 
 	return nil
 }
@@ -215,6 +329,7 @@ func (l *SpaceLogic) LeaveSpace(spaceID string) error {
 	if err = l.core.Store().UserSpaceStore().Delete(l.ctx, user.User, spaceID); err != nil {
 		return errors.New("SpaceLogic.LeaveSpace.UserSpaceStore.Delete", i18n.ERROR_INTERNAL, err)
 	}
+	l.core.InvalidateUserSpaceRole(l.ctx, spaceID, user.User)
 	return nil
 }
 
@@ -241,7 +356,12 @@ func (l *SpaceLogic) DeleteUserSpace(spaceID string) error {
 		return errors.New("SpaceLogic.DeleteUserSpace.RBAC.CheckPermission", i18n.ERROR_PERMISSION_DENIED, nil).Code(http.StatusForbidden)
 	}
 
-	return l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
+	members, err := l.core.Store().UserSpaceStore().ListSpaceUsers(l.ctx, spaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return errors.New("SpaceLogic.DeleteUserSpace.UserSpaceStore.ListSpaceUsers", i18n.ERROR_INTERNAL, err)
+	}
+
+	err = l.core.Store().Transaction(l.ctx, func(ctx context.Context) error {
 		if err := l.core.Store().UserSpaceStore().DeleteAll(ctx, spaceID); err != nil {
 			return errors.New("SpaceLogic.DeleteUserSpace.UserSpaceStore.Delete", i18n.ERROR_INTERNAL, err)
 		}
@@ -281,8 +401,47 @@ func (l *SpaceLogic) DeleteUserSpace(spaceID string) error {
 		if err := l.core.Store().ChatSummaryStore().DeleteAll(ctx, spaceID); err != nil {
 			return errors.New("SpaceLogic.DeleteUserSpace.ChatSummaryStore.DeleteAll", i18n.ERROR_INTERNAL, err)
 		}
+
+		if err := l.core.Store().KnowledgeEntityStore().DeleteAll(ctx, spaceID); err != nil {
+			return errors.New("SpaceLogic.DeleteUserSpace.KnowledgeEntityStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+		}
+
+		if err := l.core.Store().KnowledgeEntityMentionStore().DeleteAll(ctx, spaceID); err != nil {
+			return errors.New("SpaceLogic.DeleteUserSpace.KnowledgeEntityMentionStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+		}
+
+		if err := l.core.Store().KnowledgeRelationStore().DeleteAll(ctx, spaceID); err != nil {
+			return errors.New("SpaceLogic.DeleteUserSpace.KnowledgeRelationStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+		}
+
+		if err := l.core.Store().KnowledgeLinkStore().DeleteAll(ctx, spaceID); err != nil {
+			return errors.New("SpaceLogic.DeleteUserSpace.KnowledgeLinkStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+		}
+
+		if err := l.core.Store().ImportTaskStore().DeleteAll(ctx, spaceID); err != nil {
+			return errors.New("SpaceLogic.DeleteUserSpace.ImportTaskStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+		}
+
+		if err := l.core.Store().ImportTaskItemStore().DeleteAll(ctx, spaceID); err != nil {
+			return errors.New("SpaceLogic.DeleteUserSpace.ImportTaskItemStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+		}
+
+		if err := l.core.Store().SpaceExportStore().DeleteAll(ctx, spaceID); err != nil {
+			return errors.New("SpaceLogic.DeleteUserSpace.SpaceExportStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+		}
+
+		if err := l.core.Store().KnowledgeRevisionStore().DeleteAll(ctx, spaceID); err != nil {
+			return errors.New("SpaceLogic.DeleteUserSpace.KnowledgeRevisionStore.DeleteAll", i18n.ERROR_INTERNAL, err)
+		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	l.core.InvalidateSpace(l.ctx, spaceID)
+	l.core.InvalidateUserSpaceRole(l.ctx, spaceID, members...)
+	return nil
 }
 
 func (l *SpaceLogic) ListUserSpace() ([]types.UserSpaceDetail, error) {
@@ -359,6 +518,7 @@ func (l *SpaceLogic) DeleteSpaceUser(spaceID, userID string) error {
 	if err = l.core.Store().UserSpaceStore().Delete(l.ctx, userID, spaceID); err != nil {
 		return errors.New("SpaceLogic.DeleteSpaceUser.UserSpaceStore.Delete", i18n.ERROR_INTERNAL, nil)
 	}
+	l.core.InvalidateUserSpaceRole(l.ctx, spaceID, userID)
 
 	return nil
 }
@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/safe"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type SpaceExportLogic struct {
+	UserInfo
+	ctx  context.Context
+	core *core.Core
+}
+
+func NewSpaceExportLogic(ctx context.Context, core *core.Core) *SpaceExportLogic {
+	return &SpaceExportLogic{
+		ctx:      ctx,
+		core:     core,
+		UserInfo: SetupUserInfo(ctx, core),
+	}
+}
+
+// StartExport 创建空间导出任务并在后台生成备份归档，同一空间同时只能存在一个进行中的导出任务
+func (l *SpaceExportLogic) StartExport(spaceID string) (*types.SpaceExport, error) {
+	running, err := l.core.Store().SpaceExportStore().ListByStatus(l.ctx, types.SPACE_EXPORT_STATUS_RUNNING)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.New("SpaceExportLogic.StartExport.SpaceExportStore.ListByStatus", i18n.ERROR_INTERNAL, err)
+	}
+	for _, v := range running {
+		if v.SpaceID == spaceID {
+			return nil, errors.New("SpaceExportLogic.StartExport.Running", i18n.ERROR_EXIST, nil).Code(http.StatusConflict)
+		}
+	}
+
+	now := time.Now().Unix()
+	task := types.SpaceExport{
+		ID:        utils.GenUniqIDStr(),
+		SpaceID:   spaceID,
+		UserID:    l.GetUserInfo().User,
+		Status:    types.SPACE_EXPORT_STATUS_RUNNING,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err = l.core.Store().SpaceExportStore().Create(l.ctx, task); err != nil {
+		return nil, errors.New("SpaceExportLogic.StartExport.SpaceExportStore.Create", i18n.ERROR_INTERNAL, err)
+	}
+
+	result := task
+	go safe.Run(func() {
+		if err := process.RunSpaceExport(context.Background(), l.core, &task); err != nil {
+			slog.Error("Space export failed", slog.String("export_id", task.ID), slog.String("space_id", spaceID), slog.String("error", err.Error()))
+		}
+	})
+	return &result, nil
+}
+
+// GetExport 获取导出任务，任务完成时附带归档的下载地址
+func (l *SpaceExportLogic) GetExport(spaceID, id string) (*types.SpaceExport, error) {
+	task, err := l.core.Store().SpaceExportStore().Get(l.ctx, spaceID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("SpaceExportLogic.GetExport.SpaceExportStore.Get", i18n.ERROR_NOT_FOUND, nil).Code(http.StatusNotFound)
+		}
+		return nil, errors.New("SpaceExportLogic.GetExport.SpaceExportStore.Get", i18n.ERROR_INTERNAL, err)
+	}
+
+	if task.Status == types.SPACE_EXPORT_STATUS_FINISHED && task.File != "" {
+		if task.DownloadURL, err = l.core.FileStorage().GenGetObjectPreSignURL(task.File); err != nil {
+			return nil, errors.New("SpaceExportLogic.GetExport.FileStorage.GenGetObjectPreSignURL", i18n.ERROR_INTERNAL, err)
+		}
+	}
+	return task, nil
+}
+
+// ListExports 分页获取空间下的导出任务
+func (l *SpaceExportLogic) ListExports(spaceID string, page, pageSize uint64) ([]types.SpaceExport, int64, error) {
+	list, err := l.core.Store().SpaceExportStore().List(l.ctx, spaceID, page, pageSize)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, 0, errors.New("SpaceExportLogic.ListExports.SpaceExportStore.List", i18n.ERROR_INTERNAL, err)
+	}
+
+	total, err := l.core.Store().SpaceExportStore().Total(l.ctx, spaceID)
+	if err != nil {
+		return nil, 0, errors.New("SpaceExportLogic.ListExports.SpaceExportStore.Total", i18n.ERROR_INTERNAL, err)
+	}
+	return list, total, nil
+}
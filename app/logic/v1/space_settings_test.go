@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/app/store/sqlstore"
+	"github.com/quka-ai/quka-ai/pkg/cache"
+	"github.com/quka-ai/quka-ai/pkg/security"
+	pkgsqlstore "github.com/quka-ai/quka-ai/pkg/sqlstore"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+type settingsSpaceStore struct {
+	store.SpaceStore
+	space *types.Space
+}
+
+func (s *settingsSpaceStore) GetSpace(ctx context.Context, spaceID string) (*types.Space, error) {
+	if s.space == nil {
+		return nil, sql.ErrNoRows
+	}
+	space := *s.space
+	return &space, nil
+}
+
+func (s *settingsSpaceStore) UpdateSettings(ctx context.Context, spaceID string, settings types.SpaceSettings) error {
+	s.space.Settings = settings
+	return nil
+}
+
+type settingsVectorStore struct {
+	store.VectorStore
+	clearKeywords int
+}
+
+func (s *settingsVectorStore) ClearKeywords(ctx context.Context, spaceID string) error {
+	s.clearKeywords++
+	return nil
+}
+
+// settingsGraphStore 统计知识图谱实体、提及与关系被清除的次数
+type settingsGraphStore struct {
+	deleted int
+}
+
+type settingsEntityStore struct {
+	store.KnowledgeEntityStore
+	graph *settingsGraphStore
+}
+
+func (s settingsEntityStore) DeleteAll(ctx context.Context, spaceID string) error {
+	s.graph.deleted++
+	return nil
+}
+
+type settingsEntityMentionStore struct {
+	store.KnowledgeEntityMentionStore
+	graph *settingsGraphStore
+}
+
+func (s settingsEntityMentionStore) DeleteAll(ctx context.Context, spaceID string) error {
+	s.graph.deleted++
+	return nil
+}
+
+type settingsRelationStore struct {
+	store.KnowledgeRelationStore
+	graph *settingsGraphStore
+}
+
+func (s settingsRelationStore) DeleteAll(ctx context.Context, spaceID string) error {
+	s.graph.deleted++
+	return nil
+}
+
+func newSpaceSettingsLogic(settings types.SpaceSettings) (*SpaceLogic, *settingsSpaceStore, *settingsVectorStore, *settingsGraphStore) {
+	spaceStore := &settingsSpaceStore{space: &types.Space{SpaceID: "space", Settings: settings}}
+	vectorStore := &settingsVectorStore{}
+	graph := &settingsGraphStore{}
+	stores := sqlstore.NewProviderWithStores(&sqlstore.Stores{
+		SpaceStore:                  spaceStore,
+		VectorStore:                 vectorStore,
+		KnowledgeEntityStore:        settingsEntityStore{graph: graph},
+		KnowledgeEntityMentionStore: settingsEntityMentionStore{graph: graph},
+		KnowledgeRelationStore:      settingsRelationStore{graph: graph},
+	})
+	c := core.NewCore(core.CoreConfig{}, stores, srv.SetupSrvs(), cache.NewLRU(cache.DEFAULT_LRU_SIZE))
+
+	ctx := context.WithValue(context.Background(), TOKEN_CONTEXT_KEY, security.TokenClaims{
+		User:   "user",
+		Fields: map[string]string{security.ROLE_KEY: srv.RoleAdmin},
+	})
+	// 测试中不连接数据库，标记为已处于事务中使 Transaction 直接执行回调
+	ctx = context.WithValue(ctx, pkgsqlstore.TransactionKey{}, (*sql.Tx)(nil))
+	return NewSpaceLogic(ctx, c), spaceStore, vectorStore, graph
+}
+
+func TestUpdateSpaceSettingsKeepsRetrievalMode(t *testing.T) {
+	logic, spaceStore, vectorStore, _ := newSpaceSettingsLogic(types.SpaceSettings{RetrievalMode: types.RETRIEVAL_MODE_HYBRID})
+
+	// 仅更新回收站配置，未传入检索模式
+	if err := logic.UpdateSpaceSettings("space", types.SpaceSettings{Trash: &types.TrashConfig{RetentionDays: 7}}); err != nil {
+		t.Fatal(err)
+	}
+	if !spaceStore.space.Settings.IsHybridRetrieval() {
+		t.Errorf("expected retrieval mode to stay hybrid, got %q", spaceStore.space.Settings.RetrievalMode)
+	}
+	if vectorStore.clearKeywords != 0 {
+		t.Errorf("expected keywords to survive a partial update, cleared %d times", vectorStore.clearKeywords)
+	}
+
+	// 显式关闭混合检索时清除关键词索引
+	if err := logic.UpdateSpaceSettings("space", types.SpaceSettings{RetrievalMode: types.RETRIEVAL_MODE_VECTOR}); err != nil {
+		t.Fatal(err)
+	}
+	if spaceStore.space.Settings.IsHybridRetrieval() {
+		t.Error("expected retrieval mode to switch to vector")
+	}
+	if vectorStore.clearKeywords != 1 {
+		t.Errorf("expected keywords to be cleared once, cleared %d times", vectorStore.clearKeywords)
+	}
+}
+
+func TestUpdateSpaceSettingsKeepsKnowledgeGraph(t *testing.T) {
+	enabled, disabled := true, false
+	logic, spaceStore, _, graph := newSpaceSettingsLogic(types.SpaceSettings{KnowledgeGraph: &enabled})
+
+	// 仅更新回收站配置，未传入知识图谱开关
+	if err := logic.UpdateSpaceSettings("space", types.SpaceSettings{Trash: &types.TrashConfig{RetentionDays: 7}}); err != nil {
+		t.Fatal(err)
+	}
+	if !spaceStore.space.Settings.IsKnowledgeGraphEnabled() {
+		t.Error("expected knowledge graph to stay enabled")
+	}
+	if graph.deleted != 0 {
+		t.Errorf("expected knowledge graph to survive a partial update, purged %d stores", graph.deleted)
+	}
+
+	// 显式关闭知识图谱时清除实体、提及与关系
+	if err := logic.UpdateSpaceSettings("space", types.SpaceSettings{KnowledgeGraph: &disabled}); err != nil {
+		t.Fatal(err)
+	}
+	if spaceStore.space.Settings.IsKnowledgeGraphEnabled() {
+		t.Error("expected knowledge graph to be disabled")
+	}
+	if graph.deleted != 3 {
+		t.Errorf("expected entity, mention and relation stores to be purged, purged %d stores", graph.deleted)
+	}
+}
@@ -27,7 +27,7 @@ func NewChatMessageExtStore(provider SqlProviderAchieve) *ChatMessageExtStore {
 	store := &ChatMessageExtStore{}
 	store.SetProvider(provider)
 	store.SetTable(types.TABLE_CHAT_MESSAGE_EXT)
-	store.SetAllColumns("message_id", "space_id", "session_id", "evaluate", "generation_status", "rel_docs", "tool_name", "tool_args", "created_at", "updated_at")
+	store.SetAllColumns("message_id", "space_id", "session_id", "evaluate", "generation_status", "rel_docs", "tool_name", "tool_args", "query_strategy", "expanded_queries", "citations", "created_at", "updated_at")
 	return store
 }
 
@@ -42,8 +42,8 @@ func (s *ChatMessageExtStore) Create(ctx context.Context, data types.ChatMessage
 	}
 
 	query := sq.Insert(s.GetTable()).
-		Columns("message_id", "space_id", "session_id", "evaluate", "generation_status", "rel_docs", "tool_name", "tool_args", "created_at", "updated_at").
-		Values(data.MessageID, data.SpaceID, data.SessionID, data.Evaluate, data.GenerationStatus, pq.Array(data.RelDocs), data.ToolName, data.ToolArgs, data.CreatedAt, data.UpdatedAt)
+		Columns("message_id", "space_id", "session_id", "evaluate", "generation_status", "rel_docs", "tool_name", "tool_args", "query_strategy", "expanded_queries", "citations", "created_at", "updated_at").
+		Values(data.MessageID, data.SpaceID, data.SessionID, data.Evaluate, data.GenerationStatus, pq.Array(data.RelDocs), data.ToolName, data.ToolArgs, data.QueryStrategy, pq.Array(data.ExpandedQueries), data.Citations, data.CreatedAt, data.UpdatedAt)
 
 	queryString, args, err := query.ToSql()
 	if err != nil {
@@ -92,6 +92,40 @@ func (s *ChatMessageExtStore) Update(ctx context.Context, messageID string, data
 	return err
 }
 
+// UpdateRetrieval 记录消息检索时使用的查询扩展策略、扩展后的查询及召回的文档
+func (s *ChatMessageExtStore) UpdateRetrieval(ctx context.Context, spaceID, messageID, strategy string, queries, relDocs []string) error {
+	query := sq.Update(s.GetTable()).
+		Set("query_strategy", strategy).
+		Set("expanded_queries", pq.Array(queries)).
+		Set("rel_docs", pq.Array(relDocs)).
+		Set("updated_at", time.Now().Unix()).
+		Where(sq.Eq{"space_id": spaceID, "message_id": messageID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// UpdateCitations 记录回答中的引用标记及其指向的知识
+func (s *ChatMessageExtStore) UpdateCitations(ctx context.Context, spaceID, messageID string, citations types.ChatMessageCitations) error {
+	query := sq.Update(s.GetTable()).
+		Set("citations", citations).
+		Set("updated_at", time.Now().Unix()).
+		Where(sq.Eq{"space_id": spaceID, "message_id": messageID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
 // Delete 删除 ChatMessageExt 记录
 func (s *ChatMessageExtStore) Delete(ctx context.Context, id string) error {
 	query := sq.Delete(s.GetTable()).Where(sq.Eq{"id": id})
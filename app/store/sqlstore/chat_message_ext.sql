@@ -8,6 +8,9 @@ CREATE TABLE IF NOT EXISTS quka_chat_message_ext (
     rel_docs TEXT[],              -- 相关文档数组，存储多个文档标识符
     tool_name VARCHAR(100) NOT NULL DEFAULT '',
     tool_args TEXT,
+    query_strategy VARCHAR(20) NOT NULL DEFAULT '', -- 检索时使用的查询扩展策略
+    expanded_queries TEXT[],      -- 扩展后用于检索的查询
+    citations JSONB,              -- 回答中的引用标记及其指向的知识、分片
     created_at BIGINT NOT NULL,            -- 创建时间，Unix 时间戳
     updated_at BIGINT NOT NULL             -- 更新时间，Unix 时间戳
 );
@@ -21,5 +24,8 @@ COMMENT ON COLUMN quka_chat_message_ext.space_id IS '空间ID，表示消息所
 COMMENT ON COLUMN quka_chat_message_ext.evaluate IS '评价状态，使用 EvaluateType 枚举';
 COMMENT ON COLUMN quka_chat_message_ext.generation_status IS '生成状态，使用 GenerationStatusType 枚举';
 COMMENT ON COLUMN quka_chat_message_ext.rel_docs IS '相关文档数组，存储多个文档标识符';
+COMMENT ON COLUMN quka_chat_message_ext.query_strategy IS '检索时使用的查询扩展策略，rewrite / multi_query / hyde';
+COMMENT ON COLUMN quka_chat_message_ext.expanded_queries IS '扩展后用于检索的查询';
+COMMENT ON COLUMN quka_chat_message_ext.citations IS '回答中的引用标记及其指向的知识、分片，JSON 数组';
 COMMENT ON COLUMN quka_chat_message_ext.created_at IS '创建时间，Unix 时间戳';
 COMMENT ON COLUMN quka_chat_message_ext.updated_at IS '更新时间，Unix 时间戳';
\ No newline at end of file
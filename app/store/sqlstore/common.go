@@ -73,6 +73,9 @@ func (c *CommonFields) SetProvider(p SqlProviderAchieve) {
 
 type Master interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
+	// 带 RETURNING 的写入语句需要读取结果
+	Select(dest interface{}, query string, args ...interface{}) error
+	QueryRowx(query string, args ...interface{}) *sqlx.Row
 }
 
 func (c *CommonFields) GetMaster(ctx context.Context) Master {
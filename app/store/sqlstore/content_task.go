@@ -25,7 +25,7 @@ func NewContentTaskStore(provider SqlProviderAchieve) *ContentTaskImpl {
 	repo.SetProvider(provider)
 	repo.SetTable(types.TABLE_CONTENT_TASK)
 	repo.SetAllColumns(
-		"task_id", "space_id", "resource", "meta_info", "user_id", "file_url", "file_name", "ai_file_id", "step", "task_type", "retry_times", "updated_at", "created_at",
+		"task_id", "space_id", "resource", "meta_info", "user_id", "file_url", "file_name", "ai_file_id", "step", "task_type", "chunker", "retry_times", "updated_at", "created_at",
 	)
 	return repo
 }
@@ -39,8 +39,8 @@ func (s *ContentTaskImpl) Create(ctx context.Context, data types.ContentTask) er
 		data.UpdatedAt = time.Now().Unix()
 	}
 	query := sq.Insert(s.GetTable()).
-		Columns("task_id", "space_id", "resource", "meta_info", "user_id", "file_url", "file_name", "ai_file_id", "step", "task_type", "retry_times", "updated_at", "created_at").
-		Values(data.TaskID, data.SpaceID, data.Resource, data.MetaInfo, data.UserID, data.FileURL, data.FileName, data.AIFileID, data.Step, data.TaskType, data.RetryTimes, data.UpdatedAt, data.CreatedAt)
+		Columns("task_id", "space_id", "resource", "meta_info", "user_id", "file_url", "file_name", "ai_file_id", "step", "task_type", "chunker", "retry_times", "updated_at", "created_at").
+		Values(data.TaskID, data.SpaceID, data.Resource, data.MetaInfo, data.UserID, data.FileURL, data.FileName, data.AIFileID, data.Step, data.TaskType, data.Chunker, data.RetryTimes, data.UpdatedAt, data.CreatedAt)
 
 	queryString, args, err := query.ToSql()
 	if err != nil {
@@ -10,6 +10,7 @@ CREATE TABLE IF NOT EXISTS quka_content_task (
     step INT NOT NULL, -- 任务的当前阶段，例如：1-待处理，2-处理中，3-已完成等
     retry_times INT NOT NULL, -- 任务重试次数
     task_type VARCHAR(255) NOT NULL, -- 任务类型，表示任务的目的或用途
+    chunker VARCHAR(32) NOT NULL DEFAULT '', -- 分片方式，为空时使用空间的分片配置
     created_at BIGINT NOT NULL, -- 任务创建时间，时间戳格式
     updated_at BIGINT NOT NULL, -- 任务更新时间，时间戳格式
     CONSTRAINT quka_content_task_unique UNIQUE (task_id) -- 确保task_id唯一
@@ -26,6 +27,7 @@ COMMENT ON COLUMN quka_content_task.file_name IS '文件名，任务需要处理
 COMMENT ON COLUMN quka_content_task.ai_file_id IS 'ai 服务中该文件对应的id';
 COMMENT ON COLUMN quka_content_task.step IS '任务的当前阶段，例如：1-待处理，2-处理中，3-已完成等';
 COMMENT ON COLUMN quka_content_task.task_type IS '任务类型，表示任务的目的或用途';
+COMMENT ON COLUMN quka_content_task.chunker IS '分片方式，llm 或 local，为空时使用空间的分片配置';
 COMMENT ON COLUMN quka_content_task.retry_times IS '失败重试次数';
 COMMENT ON COLUMN quka_content_task.created_at IS '任务创建时间，时间戳格式';
 COMMENT ON COLUMN quka_content_task.updated_at IS '任务更新时间，时间戳格式';
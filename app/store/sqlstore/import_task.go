@@ -0,0 +1,173 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc[*Provider](RegisterKey{}, func(provider *Provider) {
+		provider.stores.ImportTaskStore = NewImportTaskStore(provider)
+	})
+}
+
+// ImportTaskImpl 处理导入任务表的操作
+type ImportTaskImpl struct {
+	CommonFields
+}
+
+// NewImportTaskStore 创建新的 ImportTaskStore 实例
+func NewImportTaskStore(provider SqlProviderAchieve) store.ImportTaskStore {
+	repo := &ImportTaskImpl{}
+	repo.SetProvider(provider)
+	repo.SetTable(types.TABLE_IMPORT_TASK)
+	repo.SetAllColumns("id", "space_id", "user_id", "source", "file_name", "resource", "folder_mode", "status", "total", "processed", "failed", "error", "created_at", "updated_at", "finished_at")
+	return repo
+}
+
+// Create 创建新的导入任务
+func (s *ImportTaskImpl) Create(ctx context.Context, data types.ImportTask) error {
+	now := time.Now().Unix()
+	if data.CreatedAt == 0 {
+		data.CreatedAt = now
+	}
+	if data.UpdatedAt == 0 {
+		data.UpdatedAt = now
+	}
+	query := sq.Insert(s.GetTable()).
+		Columns(s.GetAllColumns()...).
+		Values(data.ID, data.SpaceID, data.UserID, data.Source, data.FileName, data.Resource, data.FolderMode, data.Status, data.Total, data.Processed, data.Failed, data.Error, data.CreatedAt, data.UpdatedAt, data.FinishedAt)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// Get 获取空间下的导入任务
+func (s *ImportTaskImpl) Get(ctx context.Context, spaceID, id string) (*types.ImportTask, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "id": id})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res types.ImportTask
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// List 分页获取空间下的导入任务，按创建时间倒序
+func (s *ImportTaskImpl) List(ctx context.Context, spaceID string, page, pageSize uint64) ([]types.ImportTask, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"space_id": spaceID}).OrderBy("created_at DESC")
+	if page != 0 || pageSize != 0 {
+		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.ImportTask
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Total 获取空间下的导入任务总数
+func (s *ImportTaskImpl) Total(ctx context.Context, spaceID string) (int64, error) {
+	query := sq.Select("COUNT(*)").From(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return 0, ErrorSqlBuild(err)
+	}
+
+	var res int64
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// ListByStatus 获取指定状态的导入任务
+func (s *ImportTaskImpl) ListByStatus(ctx context.Context, status string) ([]types.ImportTask, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"status": status}).OrderBy("created_at")
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.ImportTask
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UpdateProgress 更新任务进度
+func (s *ImportTaskImpl) UpdateProgress(ctx context.Context, id string, processed, failed int64) error {
+	query := sq.Update(s.GetTable()).
+		Set("processed", processed).
+		Set("failed", failed).
+		Set("updated_at", time.Now().Unix()).
+		Where(sq.Eq{"id": id})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// UpdateStatus 更新任务状态，任务结束时记录完成时间
+func (s *ImportTaskImpl) UpdateStatus(ctx context.Context, id, status, errMsg string) error {
+	now := time.Now().Unix()
+	query := sq.Update(s.GetTable()).
+		Set("status", status).
+		Set("error", errMsg).
+		Set("updated_at", now).
+		Where(sq.Eq{"id": id})
+
+	if status != types.IMPORT_TASK_STATUS_RUNNING {
+		query = query.Set("finished_at", now)
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// DeleteAll 删除空间下的全部导入任务
+func (s *ImportTaskImpl) DeleteAll(ctx context.Context, spaceID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS quka_import_task (
+    id VARCHAR(32) PRIMARY KEY, -- 任务ID
+    space_id VARCHAR(32) NOT NULL, -- 空间ID
+    user_id VARCHAR(32) NOT NULL, -- 发起导入的用户ID
+    source VARCHAR(32) NOT NULL, -- 导入来源
+    file_name VARCHAR(255) NOT NULL DEFAULT '', -- 上传的归档文件名
+    resource VARCHAR(32) NOT NULL DEFAULT '', -- 默认写入的 resource
+    folder_mode VARCHAR(20) NOT NULL DEFAULT '', -- 目录结构的映射方式
+    status VARCHAR(20) NOT NULL, -- 任务状态
+    total BIGINT NOT NULL DEFAULT 0, -- 需要导入的条目总数
+    processed BIGINT NOT NULL DEFAULT 0, -- 已处理数量
+    failed BIGINT NOT NULL DEFAULT 0, -- 导入失败数量
+    error TEXT NOT NULL DEFAULT '', -- 任务失败原因
+    created_at BIGINT NOT NULL, -- 创建时间
+    updated_at BIGINT NOT NULL, -- 更新时间
+    finished_at BIGINT NOT NULL DEFAULT 0 -- 完成时间
+);
+
+-- 添加字段注释
+COMMENT ON TABLE quka_import_task IS '从外部笔记工具导入知识的任务';
+COMMENT ON COLUMN quka_import_task.id IS '任务ID';
+COMMENT ON COLUMN quka_import_task.space_id IS '空间ID';
+COMMENT ON COLUMN quka_import_task.user_id IS '发起导入的用户ID';
+COMMENT ON COLUMN quka_import_task.source IS '导入来源: obsidian, notion, bookmark, backup';
+COMMENT ON COLUMN quka_import_task.file_name IS '上传的归档文件名';
+COMMENT ON COLUMN quka_import_task.resource IS '默认写入的 resource';
+COMMENT ON COLUMN quka_import_task.folder_mode IS '目录结构的映射方式: 空字符串表示忽略, resource, tags';
+COMMENT ON COLUMN quka_import_task.status IS '任务状态: running, finished, failed';
+COMMENT ON COLUMN quka_import_task.total IS '需要导入的条目总数';
+COMMENT ON COLUMN quka_import_task.processed IS '已处理数量（包含失败与跳过）';
+COMMENT ON COLUMN quka_import_task.failed IS '导入失败数量';
+COMMENT ON COLUMN quka_import_task.error IS '任务失败原因';
+COMMENT ON COLUMN quka_import_task.created_at IS '创建时间，UNIX时间戳';
+COMMENT ON COLUMN quka_import_task.updated_at IS '更新时间，UNIX时间戳';
+COMMENT ON COLUMN quka_import_task.finished_at IS '完成时间，UNIX时间戳';
+
+CREATE INDEX IF NOT EXISTS idx_import_task_space_id ON quka_import_task (space_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_import_task_status ON quka_import_task (status);
@@ -0,0 +1,156 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc[*Provider](RegisterKey{}, func(provider *Provider) {
+		provider.stores.ImportTaskItemStore = NewImportTaskItemStore(provider)
+	})
+}
+
+// ImportTaskItemImpl 处理导入条目结果表的操作
+type ImportTaskItemImpl struct {
+	CommonFields
+}
+
+// NewImportTaskItemStore 创建新的 ImportTaskItemStore 实例
+func NewImportTaskItemStore(provider SqlProviderAchieve) store.ImportTaskItemStore {
+	repo := &ImportTaskItemImpl{}
+	repo.SetProvider(provider)
+	repo.SetTable(types.TABLE_IMPORT_TASK_ITEM)
+	repo.SetAllColumns("id", "task_id", "space_id", "path", "title", "knowledge_id", "status", "error", "url", "fetch_status", "fetch_error", "created_at")
+	return repo
+}
+
+// Create 记录单个条目的处理结果
+func (s *ImportTaskItemImpl) Create(ctx context.Context, data types.ImportTaskItem) error {
+	if data.CreatedAt == 0 {
+		data.CreatedAt = time.Now().Unix()
+	}
+	query := sq.Insert(s.GetTable()).
+		Columns(s.GetAllColumns()...).
+		Values(data.ID, data.TaskID, data.SpaceID, data.Path, data.Title, data.KnowledgeID, data.Status, data.Error, data.URL, data.FetchStatus, data.FetchError, data.CreatedAt)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// List 分页获取任务的条目结果，status 为空时返回全部
+func (s *ImportTaskItemImpl) List(ctx context.Context, taskID, status string, page, pageSize uint64) ([]types.ImportTaskItem, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"task_id": taskID}).OrderBy("created_at", "id")
+	if status != "" {
+		query = query.Where(sq.Eq{"status": status})
+	}
+	if page != 0 || pageSize != 0 {
+		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.ImportTaskItem
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Total 获取任务的条目结果数量，status 为空时统计全部
+func (s *ImportTaskItemImpl) Total(ctx context.Context, taskID, status string) (int64, error) {
+	query := sq.Select("COUNT(*)").From(s.GetTable()).Where(sq.Eq{"task_id": taskID})
+	if status != "" {
+		query = query.Where(sq.Eq{"status": status})
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return 0, ErrorSqlBuild(err)
+	}
+
+	var res int64
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// ListByURLs 获取空间内指定网页地址导入成功的条目，用于书签去重
+func (s *ImportTaskItemImpl) ListByURLs(ctx context.Context, spaceID string, urls []string) ([]types.ImportTaskItem, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).
+		Where(sq.Eq{"space_id": spaceID, "url": urls, "status": types.IMPORT_ITEM_STATUS_SUCCESS})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.ImportTaskItem
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListPendingFetch 按创建时间获取等待抓取网页内容的条目
+func (s *ImportTaskItemImpl) ListPendingFetch(ctx context.Context, limit uint64) ([]types.ImportTaskItem, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).
+		Where(sq.Eq{"fetch_status": types.IMPORT_FETCH_STATUS_PENDING}).
+		OrderBy("created_at", "id").
+		Limit(limit)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.ImportTaskItem
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UpdateFetchStatus 更新条目的网页内容抓取状态
+func (s *ImportTaskItemImpl) UpdateFetchStatus(ctx context.Context, id, status, errMsg string) error {
+	query := sq.Update(s.GetTable()).
+		Set("fetch_status", status).
+		Set("fetch_error", errMsg).
+		Where(sq.Eq{"id": id})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// DeleteAll 删除空间下的全部条目结果
+func (s *ImportTaskItemImpl) DeleteAll(ctx context.Context, spaceID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS quka_import_task_item (
+    id VARCHAR(32) PRIMARY KEY, -- 条目ID
+    task_id VARCHAR(32) NOT NULL, -- 所属导入任务ID
+    space_id VARCHAR(32) NOT NULL, -- 空间ID
+    path TEXT NOT NULL DEFAULT '', -- 条目在归档内的路径
+    title TEXT NOT NULL DEFAULT '', -- 导入后的知识标题
+    knowledge_id VARCHAR(32) NOT NULL DEFAULT '', -- 导入成功后创建的知识ID
+    status VARCHAR(20) NOT NULL, -- 处理结果
+    error TEXT NOT NULL DEFAULT '', -- 失败原因
+    url TEXT NOT NULL DEFAULT '', -- 书签导入时规范化后的网页地址
+    fetch_status VARCHAR(20) NOT NULL DEFAULT '', -- 网页内容抓取状态
+    fetch_error TEXT NOT NULL DEFAULT '', -- 网页内容抓取失败原因
+    created_at BIGINT NOT NULL -- 创建时间
+);
+
+-- 添加字段注释
+COMMENT ON TABLE quka_import_task_item IS '导入任务中单个文件/条目的处理结果';
+COMMENT ON COLUMN quka_import_task_item.id IS '条目ID';
+COMMENT ON COLUMN quka_import_task_item.task_id IS '所属导入任务ID';
+COMMENT ON COLUMN quka_import_task_item.space_id IS '空间ID';
+COMMENT ON COLUMN quka_import_task_item.path IS '条目在归档内的路径';
+COMMENT ON COLUMN quka_import_task_item.title IS '导入后的知识标题';
+COMMENT ON COLUMN quka_import_task_item.knowledge_id IS '导入成功后创建的知识ID';
+COMMENT ON COLUMN quka_import_task_item.status IS '处理结果: success, failed, skipped';
+COMMENT ON COLUMN quka_import_task_item.error IS '失败原因';
+COMMENT ON COLUMN quka_import_task_item.url IS '书签导入时规范化后的网页地址，用于空间内去重';
+COMMENT ON COLUMN quka_import_task_item.fetch_status IS '网页内容抓取状态: 空字符串表示不抓取, pending, success, failed';
+COMMENT ON COLUMN quka_import_task_item.fetch_error IS '网页内容抓取失败原因';
+COMMENT ON COLUMN quka_import_task_item.created_at IS '创建时间，UNIX时间戳';
+
+CREATE INDEX IF NOT EXISTS idx_import_task_item_task_id ON quka_import_task_item (task_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_import_task_item_space_id ON quka_import_task_item (space_id);
+CREATE INDEX IF NOT EXISTS idx_import_task_item_url ON quka_import_task_item (space_id, url) WHERE url != '';
+CREATE INDEX IF NOT EXISTS idx_import_task_item_fetch_pending ON quka_import_task_item (created_at) WHERE fetch_status = 'pending';
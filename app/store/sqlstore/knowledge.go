@@ -29,7 +29,7 @@ func NewKnowledgeStore(provider SqlProviderAchieve) *KnowledgeStore {
 	store := &KnowledgeStore{}
 	store.SetProvider(provider)
 	store.SetTable(types.TABLE_KNOWLEDGE)
-	store.SetAllColumns("id", "title", "user_id", "space_id", "tags", "content", "content_type", "resource", "kind", "summary", "maybe_date", "stage", "retry_times", "created_at", "updated_at", "expired_at", "rel_doc_id", "source", "source_ref")
+	store.SetAllColumns("id", "title", "user_id", "space_id", "tags", "content", "content_type", "resource", "kind", "summary", "maybe_date", "stage", "retry_times", "created_at", "updated_at", "expired_at", "rel_doc_id", "source", "source_ref", "deleted_at")
 	return store
 }
 
@@ -99,6 +99,19 @@ func (s *KnowledgeStore) GetKnowledge(ctx context.Context, spaceID string, id st
 	return &res, nil
 }
 
+// LockKnowledge 在事务中锁定知识记录，串行化同一知识的并发修改，需在事务中调用
+func (s *KnowledgeStore) LockKnowledge(ctx context.Context, spaceID, id string) error {
+	query := sq.Select("id").From(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "id": id}).Suffix("FOR UPDATE")
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	var res string
+	return s.GetMaster(ctx).QueryRowx(queryString, args...).Scan(&res)
+}
+
 // GetByRelDocID 根据 rel_doc_id 获取 Knowledge（用于 RSS 文章关联）
 func (s *KnowledgeStore) GetByRelDocID(ctx context.Context, userID, relDocID string) (*types.Knowledge, error) {
 	query := sq.Select(s.GetAllColumns()...).
@@ -260,6 +273,21 @@ func (s *KnowledgeStore) SetRetryTimes(ctx context.Context, spaceID, id string,
 	return err
 }
 
+// SetDeletedAt 设置知识移入回收站的时间，deletedAt 为 0 时表示从回收站恢复
+func (s *KnowledgeStore) SetDeletedAt(ctx context.Context, spaceID, id string, deletedAt int64) error {
+	query := sq.Update(s.GetTable()).
+		Set("deleted_at", deletedAt).
+		Where(sq.Eq{"space_id": spaceID, "id": id})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
 // Delete 删除知识记录
 func (s *KnowledgeStore) Delete(ctx context.Context, spaceID, id string) error {
 	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "id": id})
@@ -299,7 +327,7 @@ func (s *KnowledgeStore) BatchDelete(ctx context.Context, ids []string) error {
 }
 
 func (s *KnowledgeStore) ListFailedKnowledges(ctx context.Context, stage types.KnowledgeStage, retryTimes int, page, pageSize uint64) ([]types.Knowledge, error) {
-	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"stage": stage}, sq.Eq{"retry_times": retryTimes})
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"stage": stage}, sq.Eq{"retry_times": retryTimes}, sq.Eq{"deleted_at": 0})
 	if page != 0 || pageSize != 0 {
 		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
 	}
@@ -316,8 +344,9 @@ func (s *KnowledgeStore) ListFailedKnowledges(ctx context.Context, stage types.K
 	return res, nil
 }
 
+// ListProcessingKnowledges 获取处于总结或向量化阶段的知识，stage 为 none 的知识(如等待抓取网页内容的书签)与回收站中的知识不会被返回
 func (s *KnowledgeStore) ListProcessingKnowledges(ctx context.Context, retryTimes int, page, pageSize uint64) ([]types.Knowledge, error) {
-	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.And{sq.NotEq{"stage": types.KNOWLEDGE_STAGE_DONE}, sq.Lt{"retry_times": retryTimes}})
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.And{sq.Eq{"stage": []types.KnowledgeStage{types.KNOWLEDGE_STAGE_SUMMARIZE, types.KNOWLEDGE_STAGE_EMBEDDING}}, sq.Lt{"retry_times": retryTimes}, sq.Eq{"deleted_at": 0}})
 	if page != 0 || pageSize != 0 {
 		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
 	}
@@ -373,6 +402,23 @@ func (s *KnowledgeStore) ListKnowledges(ctx context.Context, opts types.GetKnowl
 	return res, nil
 }
 
+// ListKnowledgesAfterID 按 id 升序获取 afterID 之后的知识记录
+func (s *KnowledgeStore) ListKnowledgesAfterID(ctx context.Context, opts types.GetKnowledgeOptions, afterID string, limit uint64) ([]*types.Knowledge, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Gt{"id": afterID}).OrderBy("id").Limit(limit)
+	opts.Apply(&query)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []*types.Knowledge
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (s *KnowledgeStore) ListKnowledgeIDs(ctx context.Context, opts types.GetKnowledgeOptions, page, pageSize uint64) ([]string, error) {
 	query := sq.Select("id").From(s.GetTable())
 	if page != 0 || pageSize != 0 {
@@ -422,3 +468,72 @@ func (s *KnowledgeStore) UpdateExpiredAt(ctx context.Context, knowledgeID string
 	_, err = s.GetMaster(ctx).Exec(queryString, args...)
 	return err
 }
+
+// ListTags 统计符合条件的知识中各标签的使用数量，按数量降序
+func (s *KnowledgeStore) ListTags(ctx context.Context, opts types.GetKnowledgeOptions) ([]types.KnowledgeTag, error) {
+	query := sq.Select("tag", "COUNT(*) AS count").
+		From(s.GetTable()+", unnest(tags) AS tag").
+		GroupBy("tag").
+		OrderBy("count DESC", "tag")
+	opts.Apply(&query)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.KnowledgeTag
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ReplaceTags 将空间内 from 中的标签统一替换为 to
+// 替换后同一知识内的重复标签会被合并，并保持标签原有顺序
+func (s *KnowledgeStore) ReplaceTags(ctx context.Context, spaceID string, from []string, to string) (int64, error) {
+	query := sq.Update(s.GetTable()).
+		Set("tags", sq.Expr(`ARRAY(
+			SELECT y.t FROM (
+				SELECT CASE WHEN x.t = ANY(?::text[]) THEN ?::text ELSE x.t END AS t, x.i
+				FROM unnest(tags) WITH ORDINALITY AS x(t, i)
+			) y GROUP BY y.t ORDER BY MIN(y.i)
+		)`, pq.StringArray(from), to)).
+		Set("updated_at", time.Now().Unix()).
+		Where(sq.Eq{"space_id": spaceID}).
+		Where(sq.Expr("tags && ?::text[]", pq.StringArray(from)))
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return 0, ErrorSqlBuild(err)
+	}
+
+	res, err := s.GetMaster(ctx).Exec(queryString, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// RemoveTags 从空间内所有知识中移除指定标签
+func (s *KnowledgeStore) RemoveTags(ctx context.Context, spaceID string, tags []string) (int64, error) {
+	query := sq.Update(s.GetTable()).
+		Set("tags", sq.Expr(`ARRAY(
+			SELECT x.t FROM unnest(tags) WITH ORDINALITY AS x(t, i)
+			WHERE x.t <> ALL(?::text[]) ORDER BY x.i
+		)`, pq.StringArray(tags))).
+		Set("updated_at", time.Now().Unix()).
+		Where(sq.Eq{"space_id": spaceID}).
+		Where(sq.Expr("tags && ?::text[]", pq.StringArray(tags)))
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return 0, ErrorSqlBuild(err)
+	}
+
+	res, err := s.GetMaster(ctx).Exec(queryString, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
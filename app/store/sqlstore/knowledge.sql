@@ -18,7 +18,8 @@ CREATE TABLE IF NOT EXISTS quka_knowledge (
     source_ref VARCHAR(100) NOT NULL DEFAULT '',
     created_at BIGINT NOT NULL,
     updated_at BIGINT NOT NULL,
-    expired_at BIGINT NOT NULL DEFAULT 0
+    expired_at BIGINT NOT NULL DEFAULT 0,
+    deleted_at BIGINT NOT NULL DEFAULT 0
 );
 
 -- 添加字段注释
@@ -36,14 +37,17 @@ COMMENT ON COLUMN quka_knowledge.summary IS 'summary顾虑条件';
 COMMENT ON COLUMN quka_knowledge.maybe_date IS 'AI分析出的事件发生时间 / 创建时间';
 COMMENT ON COLUMN quka_knowledge.retry_times IS '流水线相关动作重试次数';
 COMMENT ON COLUMN quka_knowledge.rel_doc_id IS '关联的文档任务ID，如果是用户直接录入则为空字符串';
-COMMENT ON COLUMN quka_knowledge.source IS 'knowledge来源类型，空字符串表示平台内部创建，可选值: rss, podcast, mcp, chat';
+COMMENT ON COLUMN quka_knowledge.source IS 'knowledge来源类型，空字符串表示平台内部创建，可选值: rss, podcast, mcp, chat, import';
 COMMENT ON COLUMN quka_knowledge.source_ref IS 'knowledge来源引用ID，如chat_session_id、subscription_id等，空字符串表示无引用';
 COMMENT ON COLUMN quka_knowledge.created_at IS '创建时间';
 COMMENT ON COLUMN quka_knowledge.updated_at IS '更新时间';
 COMMENT ON COLUMN quka_knowledge.expired_at IS '过期时间戳，0表示永不过期';
+COMMENT ON COLUMN quka_knowledge.deleted_at IS '移入回收站的时间戳，0表示未删除';
 
 -- 创建索引
 CREATE INDEX IF NOT EXISTS idx_quka_knowledge_main ON quka_knowledge (space_id, resource);
 CREATE INDEX IF NOT EXISTS idx_quka_knowledge_retry ON quka_knowledge (stage, retry_times);
 CREATE INDEX IF NOT EXISTS idx_quka_knowledge_expired_at ON quka_knowledge(expired_at);
-CREATE INDEX IF NOT EXISTS idx_quka_knowledge_rel_doc_id ON quka_knowledge(rel_doc_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_quka_knowledge_deleted_at ON quka_knowledge(deleted_at) WHERE deleted_at > 0;
+CREATE INDEX IF NOT EXISTS idx_quka_knowledge_rel_doc_id ON quka_knowledge(rel_doc_id);
+CREATE INDEX IF NOT EXISTS idx_quka_knowledge_tags ON quka_knowledge USING gin (tags);
\ No newline at end of file
@@ -181,3 +181,20 @@ func (s *KnowledgeChunkStore) List(ctx context.Context, spaceID, knowledgeID str
 	}
 	return res, nil
 }
+
+// ListByIDs 根据分片ID批量获取分片
+func (s *KnowledgeChunkStore) ListByIDs(ctx context.Context, spaceID string, ids []string) ([]types.KnowledgeChunk, error) {
+	query := sq.Select(s.GetAllColumns()...).
+		From(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "id": ids})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.KnowledgeChunk
+	if err := s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,148 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc[*Provider](RegisterKey{}, func(provider *Provider) {
+		provider.stores.KnowledgeDuplicateStore = NewKnowledgeDuplicateStore(provider)
+	})
+}
+
+// KnowledgeDuplicateImpl 处理知识近似重复关系表的操作
+type KnowledgeDuplicateImpl struct {
+	CommonFields
+}
+
+// NewKnowledgeDuplicateStore 创建新的 KnowledgeDuplicateStore 实例
+func NewKnowledgeDuplicateStore(provider SqlProviderAchieve) store.KnowledgeDuplicateStore {
+	repo := &KnowledgeDuplicateImpl{}
+	repo.SetProvider(provider)
+	repo.SetTable(types.TABLE_KNOWLEDGE_DUPLICATE)
+	repo.SetAllColumns("id", "space_id", "knowledge_id", "duplicate_of", "similarity", "status", "created_at", "updated_at")
+	return repo
+}
+
+// BatchCreate 批量写入重复关系，已存在的知识对会被忽略
+func (s *KnowledgeDuplicateImpl) BatchCreate(ctx context.Context, list []types.KnowledgeDuplicate) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	query := sq.Insert(s.GetTable()).Columns(s.GetAllColumns()...)
+	for _, v := range list {
+		if v.CreatedAt == 0 {
+			v.CreatedAt = now
+		}
+		if v.UpdatedAt == 0 {
+			v.UpdatedAt = now
+		}
+		query = query.Values(v.ID, v.SpaceID, v.KnowledgeID, v.DuplicateOf, v.Similarity, v.Status, v.CreatedAt, v.UpdatedAt)
+	}
+	query = query.Suffix("ON CONFLICT (knowledge_id, duplicate_of) DO NOTHING")
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// List 获取重复关系记录，按相似度降序排列
+func (s *KnowledgeDuplicateImpl) List(ctx context.Context, opts types.ListKnowledgeDuplicateOptions, page, pageSize uint64) ([]types.KnowledgeDuplicate, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).OrderBy("similarity DESC")
+	if page != 0 || pageSize != 0 {
+		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
+	}
+	opts.Apply(&query)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.KnowledgeDuplicate
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListClusters 按已有知识分组，返回各组的已有知识ID，最近产生重复的分组在前
+func (s *KnowledgeDuplicateImpl) ListClusters(ctx context.Context, opts types.ListKnowledgeDuplicateOptions, page, pageSize uint64) ([]string, error) {
+	query := sq.Select("duplicate_of").From(s.GetTable()).GroupBy("duplicate_of").OrderBy("MAX(created_at) DESC")
+	if page != 0 || pageSize != 0 {
+		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
+	}
+	opts.Apply(&query)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []string
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// TotalClusters 获取分组数量
+func (s *KnowledgeDuplicateImpl) TotalClusters(ctx context.Context, opts types.ListKnowledgeDuplicateOptions) (int64, error) {
+	query := sq.Select("COUNT(DISTINCT duplicate_of)").From(s.GetTable())
+	opts.Apply(&query)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return 0, ErrorSqlBuild(err)
+	}
+
+	var res int64
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// UpdateStatus 更新重复关系的处理状态
+func (s *KnowledgeDuplicateImpl) UpdateStatus(ctx context.Context, spaceID string, ids []string, status string) error {
+	query := sq.Update(s.GetTable()).
+		Set("status", status).
+		Set("updated_at", time.Now().Unix()).
+		Where(sq.Eq{"space_id": spaceID, "id": ids})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// DeletePendingByKnowledge 删除涉及指定知识的待处理重复关系，知识被删除时调用
+func (s *KnowledgeDuplicateImpl) DeletePendingByKnowledge(ctx context.Context, spaceID, knowledgeID string) error {
+	query := sq.Delete(s.GetTable()).
+		Where(sq.Eq{"space_id": spaceID, "status": types.KNOWLEDGE_DUPLICATE_STATUS_PENDING}).
+		Where(sq.Or{sq.Eq{"knowledge_id": knowledgeID}, sq.Eq{"duplicate_of": knowledgeID}})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
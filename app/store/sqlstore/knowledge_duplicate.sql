@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS quka_knowledge_duplicate (
+    id VARCHAR(32) PRIMARY KEY, -- 记录ID
+    space_id VARCHAR(32) NOT NULL, -- 空间ID
+    knowledge_id VARCHAR(32) NOT NULL, -- 新写入的知识ID
+    duplicate_of VARCHAR(32) NOT NULL, -- 与之重复的已有知识ID
+    similarity REAL NOT NULL DEFAULT 0, -- 相似度
+    status VARCHAR(20) NOT NULL, -- 处理状态
+    created_at BIGINT NOT NULL, -- 创建时间
+    updated_at BIGINT NOT NULL -- 更新时间
+);
+
+-- 添加字段注释
+COMMENT ON TABLE quka_knowledge_duplicate IS '知识的近似重复关系，在知识完成向量化后检测生成';
+COMMENT ON COLUMN quka_knowledge_duplicate.id IS '记录ID';
+COMMENT ON COLUMN quka_knowledge_duplicate.space_id IS '空间ID';
+COMMENT ON COLUMN quka_knowledge_duplicate.knowledge_id IS '新写入的知识ID';
+COMMENT ON COLUMN quka_knowledge_duplicate.duplicate_of IS '与之重复的已有知识ID';
+COMMENT ON COLUMN quka_knowledge_duplicate.similarity IS '两条知识的向量相似度';
+COMMENT ON COLUMN quka_knowledge_duplicate.status IS '处理状态: pending, merged, ignored';
+COMMENT ON COLUMN quka_knowledge_duplicate.created_at IS '创建时间，UNIX时间戳';
+COMMENT ON COLUMN quka_knowledge_duplicate.updated_at IS '更新时间，UNIX时间戳';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_knowledge_duplicate_pair ON quka_knowledge_duplicate (knowledge_id, duplicate_of);
+CREATE INDEX IF NOT EXISTS idx_knowledge_duplicate_space_status ON quka_knowledge_duplicate (space_id, status);
+CREATE INDEX IF NOT EXISTS idx_knowledge_duplicate_duplicate_of ON quka_knowledge_duplicate (duplicate_of);
@@ -0,0 +1,137 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc[*Provider](RegisterKey{}, func(provider *Provider) {
+		provider.stores.KnowledgeEntityStore = NewKnowledgeEntityStore(provider)
+	})
+}
+
+// KnowledgeEntityImpl 处理知识实体表的操作
+type KnowledgeEntityImpl struct {
+	CommonFields
+}
+
+// NewKnowledgeEntityStore 创建新的 KnowledgeEntityStore 实例
+func NewKnowledgeEntityStore(provider SqlProviderAchieve) store.KnowledgeEntityStore {
+	repo := &KnowledgeEntityImpl{}
+	repo.SetProvider(provider)
+	repo.SetTable(types.TABLE_KNOWLEDGE_ENTITY)
+	repo.SetAllColumns("id", "space_id", "name", "name_key", "type", "created_at", "updated_at")
+	return repo
+}
+
+// Upsert 批量写入实体，空间内已存在的同名实体仅更新时间，返回写入后的全部实体
+// 调用方需保证 list 中的 name_key 不重复
+func (s *KnowledgeEntityImpl) Upsert(ctx context.Context, list []types.KnowledgeEntity) ([]types.KnowledgeEntity, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now().Unix()
+	query := sq.Insert(s.GetTable()).Columns(s.GetAllColumns()...)
+	for _, v := range list {
+		query = query.Values(v.ID, v.SpaceID, v.Name, v.NameKey, v.Type, now, now)
+	}
+	query = query.Suffix(fmt.Sprintf("ON CONFLICT (space_id, name_key) DO UPDATE SET updated_at = EXCLUDED.updated_at RETURNING %s", strings.Join(s.GetAllColumns(), ", ")))
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.KnowledgeEntity
+	if err = s.GetMaster(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Get 获取实体及提及该实体的知识数量
+func (s *KnowledgeEntityImpl) Get(ctx context.Context, spaceID, id string) (*types.KnowledgeEntityItem, error) {
+	query := s.selectWithCount().Where(sq.Eq{"space_id": spaceID, "id": id})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res types.KnowledgeEntityItem
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// List 获取仍被知识提及的实体，按提及的知识数量降序排列
+func (s *KnowledgeEntityImpl) List(ctx context.Context, opts types.ListKnowledgeEntityOptions, page, pageSize uint64) ([]types.KnowledgeEntityItem, error) {
+	query := s.selectWithCount().Where(s.mentionedExpr()).OrderBy("knowledge_count DESC", "updated_at DESC")
+	if page != 0 || pageSize != 0 {
+		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
+	}
+	opts.Apply(&query)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.KnowledgeEntityItem
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Total 获取仍被知识提及的实体数量
+func (s *KnowledgeEntityImpl) Total(ctx context.Context, opts types.ListKnowledgeEntityOptions) (int64, error) {
+	query := sq.Select("COUNT(*)").From(s.GetTable()).Where(s.mentionedExpr())
+	opts.Apply(&query)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return 0, ErrorSqlBuild(err)
+	}
+
+	var res int64
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// DeleteAll 删除空间下的全部实体
+func (s *KnowledgeEntityImpl) DeleteAll(ctx context.Context, spaceID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+func (s *KnowledgeEntityImpl) selectWithCount() sq.SelectBuilder {
+	columns := append(append([]string{}, s.GetAllColumns()...), fmt.Sprintf("(SELECT COUNT(*) FROM %s WHERE entity_id = %s.id) AS knowledge_count",
+		types.TABLE_KNOWLEDGE_ENTITY_MENTION.Name(), s.GetTable()))
+	return sq.Select(columns...).From(s.GetTable())
+}
+
+// mentionedExpr 知识被删除后实体记录会保留，列表中只展示仍被提及的实体
+func (s *KnowledgeEntityImpl) mentionedExpr() sq.Sqlizer {
+	return sq.Expr(fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE entity_id = %s.id)", types.TABLE_KNOWLEDGE_ENTITY_MENTION.Name(), s.GetTable()))
+}
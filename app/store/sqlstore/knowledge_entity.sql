@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS quka_knowledge_entity (
+    id VARCHAR(32) PRIMARY KEY, -- 实体ID
+    space_id VARCHAR(32) NOT NULL, -- 空间ID
+    name VARCHAR(255) NOT NULL, -- 实体名称
+    name_key VARCHAR(255) NOT NULL, -- 归一化后的实体名称
+    type VARCHAR(50) NOT NULL DEFAULT '', -- 实体类型
+    created_at BIGINT NOT NULL, -- 创建时间
+    updated_at BIGINT NOT NULL -- 更新时间
+);
+
+-- 添加字段注释
+COMMENT ON TABLE quka_knowledge_entity IS '知识中抽取的命名实体，同一空间内按归一化名称去重';
+COMMENT ON COLUMN quka_knowledge_entity.id IS '实体ID';
+COMMENT ON COLUMN quka_knowledge_entity.space_id IS '空间ID';
+COMMENT ON COLUMN quka_knowledge_entity.name IS '实体名称，保留首次抽取时的写法';
+COMMENT ON COLUMN quka_knowledge_entity.name_key IS '归一化后的实体名称(小写、合并空白)';
+COMMENT ON COLUMN quka_knowledge_entity.type IS '实体类型，如 person, organization, location, product, concept';
+COMMENT ON COLUMN quka_knowledge_entity.created_at IS '创建时间，UNIX时间戳';
+COMMENT ON COLUMN quka_knowledge_entity.updated_at IS '最近一次被提及的时间，UNIX时间戳';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_knowledge_entity_space_name ON quka_knowledge_entity (space_id, name_key);
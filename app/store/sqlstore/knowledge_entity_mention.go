@@ -0,0 +1,131 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc[*Provider](RegisterKey{}, func(provider *Provider) {
+		provider.stores.KnowledgeEntityMentionStore = NewKnowledgeEntityMentionStore(provider)
+	})
+}
+
+// KnowledgeEntityMentionImpl 处理知识提及实体表的操作
+type KnowledgeEntityMentionImpl struct {
+	CommonFields
+}
+
+// NewKnowledgeEntityMentionStore 创建新的 KnowledgeEntityMentionStore 实例
+func NewKnowledgeEntityMentionStore(provider SqlProviderAchieve) store.KnowledgeEntityMentionStore {
+	repo := &KnowledgeEntityMentionImpl{}
+	repo.SetProvider(provider)
+	repo.SetTable(types.TABLE_KNOWLEDGE_ENTITY_MENTION)
+	repo.SetAllColumns("space_id", "entity_id", "knowledge_id", "created_at")
+	return repo
+}
+
+// BatchCreate 批量写入知识提及的实体，已存在的记录会被忽略
+func (s *KnowledgeEntityMentionImpl) BatchCreate(ctx context.Context, list []types.KnowledgeEntityMention) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	query := sq.Insert(s.GetTable()).Columns(s.GetAllColumns()...)
+	for _, v := range list {
+		if v.CreatedAt == 0 {
+			v.CreatedAt = now
+		}
+		query = query.Values(v.SpaceID, v.EntityID, v.KnowledgeID, v.CreatedAt)
+	}
+	query = query.Suffix("ON CONFLICT (entity_id, knowledge_id) DO NOTHING")
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// ListKnowledgeIDs 获取提及指定实体的知识ID，最近提及的在前
+func (s *KnowledgeEntityMentionImpl) ListKnowledgeIDs(ctx context.Context, spaceID, entityID string, page, pageSize uint64) ([]string, error) {
+	query := sq.Select("knowledge_id").From(s.GetTable()).
+		Where(sq.Eq{"space_id": spaceID, "entity_id": entityID}).
+		OrderBy("created_at DESC")
+	if page != 0 || pageSize != 0 {
+		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []string
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListCoMentionedKnowledgeIDs 获取与指定知识提及了相同实体的其他知识ID，按共同实体数量降序排列
+func (s *KnowledgeEntityMentionImpl) ListCoMentionedKnowledgeIDs(ctx context.Context, spaceID string, knowledgeIDs []string, limit uint64) ([]string, error) {
+	if len(knowledgeIDs) == 0 {
+		return nil, nil
+	}
+
+	entities := sq.Select("entity_id").From(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "knowledge_id": knowledgeIDs})
+	query := sq.Select("knowledge_id").From(s.GetTable()).
+		Where(sq.Eq{"space_id": spaceID}).
+		Where(sq.NotEq{"knowledge_id": knowledgeIDs}).
+		Where(sq.Expr("entity_id IN (?)", entities)).
+		GroupBy("knowledge_id").
+		OrderBy("COUNT(*) DESC", "MAX(created_at) DESC").
+		Limit(limit)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []string
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteByKnowledge 删除知识提及的全部实体记录
+func (s *KnowledgeEntityMentionImpl) DeleteByKnowledge(ctx context.Context, spaceID, knowledgeID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "knowledge_id": knowledgeID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// DeleteAll 删除空间下的全部提及记录
+func (s *KnowledgeEntityMentionImpl) DeleteAll(ctx context.Context, spaceID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS quka_knowledge_entity_mention (
+    space_id VARCHAR(32) NOT NULL, -- 空间ID
+    entity_id VARCHAR(32) NOT NULL, -- 实体ID
+    knowledge_id VARCHAR(32) NOT NULL, -- 知识ID
+    created_at BIGINT NOT NULL, -- 创建时间
+    PRIMARY KEY (entity_id, knowledge_id)
+);
+
+-- 添加字段注释
+COMMENT ON TABLE quka_knowledge_entity_mention IS '知识提及的实体';
+COMMENT ON COLUMN quka_knowledge_entity_mention.space_id IS '空间ID';
+COMMENT ON COLUMN quka_knowledge_entity_mention.entity_id IS '实体ID';
+COMMENT ON COLUMN quka_knowledge_entity_mention.knowledge_id IS '提及该实体的知识ID';
+COMMENT ON COLUMN quka_knowledge_entity_mention.created_at IS '创建时间，UNIX时间戳';
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_entity_mention_knowledge ON quka_knowledge_entity_mention (knowledge_id);
+CREATE INDEX IF NOT EXISTS idx_knowledge_entity_mention_space ON quka_knowledge_entity_mention (space_id);
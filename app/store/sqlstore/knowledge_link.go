@@ -0,0 +1,121 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc[*Provider](RegisterKey{}, func(provider *Provider) {
+		provider.stores.KnowledgeLinkStore = NewKnowledgeLinkStore(provider)
+	})
+}
+
+// KnowledgeLinkImpl 处理知识引用关系表的操作
+type KnowledgeLinkImpl struct {
+	CommonFields
+}
+
+// NewKnowledgeLinkStore 创建新的 KnowledgeLinkStore 实例
+func NewKnowledgeLinkStore(provider SqlProviderAchieve) store.KnowledgeLinkStore {
+	repo := &KnowledgeLinkImpl{}
+	repo.SetProvider(provider)
+	repo.SetTable(types.TABLE_KNOWLEDGE_LINK)
+	repo.SetAllColumns("space_id", "knowledge_id", "target_id", "created_at")
+	return repo
+}
+
+// BatchCreate 批量写入引用关系，已存在的记录会被忽略
+func (s *KnowledgeLinkImpl) BatchCreate(ctx context.Context, list []types.KnowledgeLink) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	query := sq.Insert(s.GetTable()).Columns(s.GetAllColumns()...)
+	for _, v := range list {
+		if v.CreatedAt == 0 {
+			v.CreatedAt = now
+		}
+		query = query.Values(v.SpaceID, v.KnowledgeID, v.TargetID, v.CreatedAt)
+	}
+	query = query.Suffix("ON CONFLICT (knowledge_id, target_id) DO NOTHING")
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// ListTargetIDs 获取指定知识引用的知识ID
+func (s *KnowledgeLinkImpl) ListTargetIDs(ctx context.Context, spaceID string, knowledgeIDs []string) ([]string, error) {
+	return s.listColumn(ctx, "target_id", sq.Eq{"space_id": spaceID, "knowledge_id": knowledgeIDs})
+}
+
+// ListBacklinkIDs 获取引用了指定知识的知识ID
+func (s *KnowledgeLinkImpl) ListBacklinkIDs(ctx context.Context, spaceID string, knowledgeIDs []string) ([]string, error) {
+	return s.listColumn(ctx, "knowledge_id", sq.Eq{"space_id": spaceID, "target_id": knowledgeIDs})
+}
+
+func (s *KnowledgeLinkImpl) listColumn(ctx context.Context, column string, where sq.Eq) ([]string, error) {
+	query := sq.Select(column).From(s.GetTable()).Where(where).OrderBy("created_at DESC")
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []string
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteByKnowledge 删除指定知识发出的引用关系
+func (s *KnowledgeLinkImpl) DeleteByKnowledge(ctx context.Context, spaceID, knowledgeID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "knowledge_id": knowledgeID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// DeleteByTarget 删除指向指定知识的引用关系，知识被删除时调用
+func (s *KnowledgeLinkImpl) DeleteByTarget(ctx context.Context, spaceID, knowledgeID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "target_id": knowledgeID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// DeleteAll 删除空间下的全部引用关系
+func (s *KnowledgeLinkImpl) DeleteAll(ctx context.Context, spaceID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
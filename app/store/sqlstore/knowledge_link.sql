@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS quka_knowledge_link (
+    space_id VARCHAR(32) NOT NULL, -- 空间ID
+    knowledge_id VARCHAR(32) NOT NULL, -- 引用方知识ID
+    target_id VARCHAR(32) NOT NULL, -- 被引用的知识ID
+    created_at BIGINT NOT NULL, -- 创建时间
+    PRIMARY KEY (knowledge_id, target_id)
+);
+
+-- 添加字段注释
+COMMENT ON TABLE quka_knowledge_link IS '知识内容中通过 [[knowledge-id]] 显式引用的其他知识';
+COMMENT ON COLUMN quka_knowledge_link.space_id IS '空间ID';
+COMMENT ON COLUMN quka_knowledge_link.knowledge_id IS '引用方知识ID';
+COMMENT ON COLUMN quka_knowledge_link.target_id IS '被引用的知识ID';
+COMMENT ON COLUMN quka_knowledge_link.created_at IS '创建时间，UNIX时间戳';
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_link_target ON quka_knowledge_link (target_id);
+CREATE INDEX IF NOT EXISTS idx_knowledge_link_space ON quka_knowledge_link (space_id);
@@ -0,0 +1,104 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc[*Provider](RegisterKey{}, func(provider *Provider) {
+		provider.stores.KnowledgeRelationStore = NewKnowledgeRelationStore(provider)
+	})
+}
+
+// KnowledgeRelationImpl 处理实体关系表的操作
+type KnowledgeRelationImpl struct {
+	CommonFields
+}
+
+// NewKnowledgeRelationStore 创建新的 KnowledgeRelationStore 实例
+func NewKnowledgeRelationStore(provider SqlProviderAchieve) store.KnowledgeRelationStore {
+	repo := &KnowledgeRelationImpl{}
+	repo.SetProvider(provider)
+	repo.SetTable(types.TABLE_KNOWLEDGE_RELATION)
+	repo.SetAllColumns("id", "space_id", "knowledge_id", "source_id", "target_id", "relation", "created_at")
+	return repo
+}
+
+// BatchCreate 批量写入实体关系
+func (s *KnowledgeRelationImpl) BatchCreate(ctx context.Context, list []types.KnowledgeRelation) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	query := sq.Insert(s.GetTable()).Columns(s.GetAllColumns()...)
+	for _, v := range list {
+		if v.CreatedAt == 0 {
+			v.CreatedAt = now
+		}
+		query = query.Values(v.ID, v.SpaceID, v.KnowledgeID, v.SourceID, v.TargetID, v.Relation, v.CreatedAt)
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// ListByEntity 获取以指定实体为主体或客体的关系
+func (s *KnowledgeRelationImpl) ListByEntity(ctx context.Context, spaceID, entityID string, page, pageSize uint64) ([]types.KnowledgeRelation, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).
+		Where(sq.Eq{"space_id": spaceID}).
+		Where(sq.Or{sq.Eq{"source_id": entityID}, sq.Eq{"target_id": entityID}}).
+		OrderBy("created_at DESC")
+	if page != 0 || pageSize != 0 {
+		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.KnowledgeRelation
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteByKnowledge 删除从指定知识中抽取的全部关系
+func (s *KnowledgeRelationImpl) DeleteByKnowledge(ctx context.Context, spaceID, knowledgeID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "knowledge_id": knowledgeID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// DeleteAll 删除空间下的全部关系
+func (s *KnowledgeRelationImpl) DeleteAll(ctx context.Context, spaceID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
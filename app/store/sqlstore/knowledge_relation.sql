@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS quka_knowledge_relation (
+    id VARCHAR(32) PRIMARY KEY, -- 关系ID
+    space_id VARCHAR(32) NOT NULL, -- 空间ID
+    knowledge_id VARCHAR(32) NOT NULL, -- 来源知识ID
+    source_id VARCHAR(32) NOT NULL, -- 主体实体ID
+    target_id VARCHAR(32) NOT NULL, -- 客体实体ID
+    relation VARCHAR(255) NOT NULL, -- 关系描述
+    created_at BIGINT NOT NULL -- 创建时间
+);
+
+-- 添加字段注释
+COMMENT ON TABLE quka_knowledge_relation IS '知识中抽取的实体关系';
+COMMENT ON COLUMN quka_knowledge_relation.id IS '关系ID';
+COMMENT ON COLUMN quka_knowledge_relation.space_id IS '空间ID';
+COMMENT ON COLUMN quka_knowledge_relation.knowledge_id IS '抽取出该关系的知识ID';
+COMMENT ON COLUMN quka_knowledge_relation.source_id IS '主体实体ID';
+COMMENT ON COLUMN quka_knowledge_relation.target_id IS '客体实体ID';
+COMMENT ON COLUMN quka_knowledge_relation.relation IS '关系描述，如 "founded", "part of"';
+COMMENT ON COLUMN quka_knowledge_relation.created_at IS '创建时间，UNIX时间戳';
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_relation_knowledge ON quka_knowledge_relation (knowledge_id);
+CREATE INDEX IF NOT EXISTS idx_knowledge_relation_source ON quka_knowledge_relation (source_id);
+CREATE INDEX IF NOT EXISTS idx_knowledge_relation_target ON quka_knowledge_relation (target_id);
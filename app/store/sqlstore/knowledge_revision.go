@@ -0,0 +1,140 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc[*Provider](RegisterKey{}, func(provider *Provider) {
+		provider.stores.KnowledgeRevisionStore = NewKnowledgeRevisionStore(provider)
+	})
+}
+
+// KnowledgeRevisionImpl 处理知识修订表的操作
+type KnowledgeRevisionImpl struct {
+	CommonFields
+}
+
+// NewKnowledgeRevisionStore 创建新的 KnowledgeRevisionStore 实例
+func NewKnowledgeRevisionStore(provider SqlProviderAchieve) store.KnowledgeRevisionStore {
+	repo := &KnowledgeRevisionImpl{}
+	repo.SetProvider(provider)
+	repo.SetTable(types.TABLE_KNOWLEDGE_REVISION)
+	repo.SetAllColumns("id", "space_id", "knowledge_id", "version", "title", "resource", "tags", "content", "content_type", "user_id", "source", "created_at")
+	return repo
+}
+
+// Create 创建修订，版本号在知识已有修订的最大版本号上加一，返回新修订的版本号
+// 并发写入同一知识的修订时版本号会冲突，调用方需先通过 KnowledgeStore.LockKnowledge 锁定知识
+func (s *KnowledgeRevisionImpl) Create(ctx context.Context, data types.KnowledgeRevision) (int64, error) {
+	if data.CreatedAt == 0 {
+		data.CreatedAt = time.Now().Unix()
+	}
+	queryString := fmt.Sprintf(`INSERT INTO %s (id, space_id, knowledge_id, version, title, resource, tags, content, content_type, user_id, source, created_at)
+SELECT $1, $2, $3, COALESCE(MAX(version), 0) + 1, $4, $5, $6, $7, $8, $9, $10, $11 FROM %s WHERE knowledge_id = $3
+RETURNING version`, s.GetTable(), s.GetTable())
+
+	var version int64
+	err := s.GetMaster(ctx).QueryRowx(queryString, data.ID, data.SpaceID, data.KnowledgeID, data.Title, data.Resource, pq.Array(data.Tags),
+		data.Content.String(), data.ContentType, data.UserID, data.Source, data.CreatedAt).Scan(&version)
+	return version, err
+}
+
+// Get 获取知识的指定版本
+func (s *KnowledgeRevisionImpl) Get(ctx context.Context, spaceID, knowledgeID string, version int64) (*types.KnowledgeRevision, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).
+		Where(sq.Eq{"space_id": spaceID, "knowledge_id": knowledgeID, "version": version})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res types.KnowledgeRevision
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// List 分页获取知识的修订，按版本号倒序，不包含正文
+func (s *KnowledgeRevisionImpl) List(ctx context.Context, spaceID, knowledgeID string, page, pageSize uint64) ([]types.KnowledgeRevision, error) {
+	query := sq.Select("id", "space_id", "knowledge_id", "version", "title", "resource", "tags", "content_type", "user_id", "source", "created_at").
+		From(s.GetTable()).
+		Where(sq.Eq{"space_id": spaceID, "knowledge_id": knowledgeID}).
+		OrderBy("version DESC")
+	if page != 0 || pageSize != 0 {
+		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.KnowledgeRevision
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Total 获取知识的修订数量
+func (s *KnowledgeRevisionImpl) Total(ctx context.Context, spaceID, knowledgeID string) (int64, error) {
+	query := sq.Select("COUNT(*)").From(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "knowledge_id": knowledgeID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return 0, ErrorSqlBuild(err)
+	}
+
+	var res int64
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// Prune 只保留知识最新的 keep 个修订
+func (s *KnowledgeRevisionImpl) Prune(ctx context.Context, knowledgeID string, keep int) error {
+	queryString := fmt.Sprintf(`DELETE FROM %s WHERE knowledge_id = $1 AND version <= (SELECT COALESCE(MAX(version), 0) FROM %s WHERE knowledge_id = $1) - $2`,
+		s.GetTable(), s.GetTable())
+
+	_, err := s.GetMaster(ctx).Exec(queryString, knowledgeID, keep)
+	return err
+}
+
+// Delete 删除知识的全部修订
+func (s *KnowledgeRevisionImpl) Delete(ctx context.Context, spaceID, knowledgeID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "knowledge_id": knowledgeID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// DeleteAll 删除空间下的全部修订
+func (s *KnowledgeRevisionImpl) DeleteAll(ctx context.Context, spaceID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS quka_knowledge_revision (
+    id VARCHAR(32) PRIMARY KEY, -- 修订ID
+    space_id VARCHAR(32) NOT NULL, -- 空间ID
+    knowledge_id VARCHAR(32) NOT NULL, -- 知识ID
+    version BIGINT NOT NULL, -- 知识内递增的版本号
+    title TEXT NOT NULL DEFAULT '', -- 标题
+    resource VARCHAR(32) NOT NULL DEFAULT '', -- 所属 resource
+    tags TEXT[] NOT NULL DEFAULT '{}', -- 标签
+    content TEXT NOT NULL DEFAULT '', -- 加密后的正文
+    content_type VARCHAR(20) NOT NULL DEFAULT '', -- 正文格式
+    user_id VARCHAR(32) NOT NULL DEFAULT '', -- 修改者
+    source VARCHAR(20) NOT NULL DEFAULT '', -- 修改来源
+    created_at BIGINT NOT NULL -- 创建时间
+);
+
+-- 添加字段注释
+COMMENT ON TABLE quka_knowledge_revision IS '知识的历史版本';
+COMMENT ON COLUMN quka_knowledge_revision.id IS '修订ID';
+COMMENT ON COLUMN quka_knowledge_revision.space_id IS '空间ID';
+COMMENT ON COLUMN quka_knowledge_revision.knowledge_id IS '知识ID';
+COMMENT ON COLUMN quka_knowledge_revision.version IS '知识内递增的版本号';
+COMMENT ON COLUMN quka_knowledge_revision.title IS '修改后的标题';
+COMMENT ON COLUMN quka_knowledge_revision.resource IS '修改后所属的 resource';
+COMMENT ON COLUMN quka_knowledge_revision.tags IS '修改后的标签';
+COMMENT ON COLUMN quka_knowledge_revision.content IS '修改后的正文，与知识正文一样加密存储';
+COMMENT ON COLUMN quka_knowledge_revision.content_type IS '正文格式: markdown, blocks, html';
+COMMENT ON COLUMN quka_knowledge_revision.user_id IS '修改者的用户ID';
+COMMENT ON COLUMN quka_knowledge_revision.source IS '修改来源: original, http, agent, mcp, restore';
+COMMENT ON COLUMN quka_knowledge_revision.created_at IS '创建时间，UNIX时间戳';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_knowledge_revision_version ON quka_knowledge_revision (knowledge_id, version);
+CREATE INDEX IF NOT EXISTS idx_knowledge_revision_space_id ON quka_knowledge_revision (space_id);
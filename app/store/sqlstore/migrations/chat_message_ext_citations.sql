@@ -0,0 +1,5 @@
+-- 引用标记：记录回答中的引用标记及其指向的知识、分片
+ALTER TABLE quka_chat_message_ext ADD COLUMN IF NOT EXISTS citations JSONB;
+
+-- 添加字段注释
+COMMENT ON COLUMN quka_chat_message_ext.citations IS '回答中的引用标记及其指向的知识、分片，JSON 数组';
@@ -0,0 +1,7 @@
+-- 查询扩展：记录检索时使用的查询扩展策略及扩展后的查询
+ALTER TABLE quka_chat_message_ext ADD COLUMN IF NOT EXISTS query_strategy VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE quka_chat_message_ext ADD COLUMN IF NOT EXISTS expanded_queries TEXT[];
+
+-- 添加字段注释
+COMMENT ON COLUMN quka_chat_message_ext.query_strategy IS '检索时使用的查询扩展策略，rewrite / multi_query / hyde';
+COMMENT ON COLUMN quka_chat_message_ext.expanded_queries IS '扩展后用于检索的查询';
@@ -0,0 +1,5 @@
+-- 长文本任务分片方式：可单独指定使用对话模型分片或本地分片
+ALTER TABLE quka_content_task ADD COLUMN IF NOT EXISTS chunker VARCHAR(32) NOT NULL DEFAULT '';
+
+-- 添加字段注释
+COMMENT ON COLUMN quka_content_task.chunker IS '分片方式，llm 或 local，为空时使用空间的分片配置';
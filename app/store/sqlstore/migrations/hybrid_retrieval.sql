@@ -0,0 +1,11 @@
+-- 混合检索：为 quka_vectors 添加全文检索列，为 quka_space 添加空间级配置
+ALTER TABLE quka_vectors ADD COLUMN IF NOT EXISTS keywords tsvector;
+CREATE INDEX IF NOT EXISTS idx_vectors_keywords ON quka_vectors USING gin (keywords);
+
+ALTER TABLE quka_space ADD COLUMN IF NOT EXISTS settings JSONB NOT NULL DEFAULT '{}';
+
+-- 添加字段注释
+COMMENT ON COLUMN quka_vectors.keywords IS '全文检索词向量，由应用层分词后通过 to_tsvector(''simple'', ...) 生成';
+COMMENT ON COLUMN quka_space.settings IS '空间级配置，如检索模式等';
+
+-- 注意：已有向量数据的 keywords 为空，需要重新处理知识（重新 embedding）后才能被关键词检索命中
@@ -0,0 +1,12 @@
+-- 书签导入：记录条目的网页地址用于去重，并记录网页内容的抓取状态
+ALTER TABLE quka_import_task_item ADD COLUMN IF NOT EXISTS url TEXT NOT NULL DEFAULT '';
+ALTER TABLE quka_import_task_item ADD COLUMN IF NOT EXISTS fetch_status VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE quka_import_task_item ADD COLUMN IF NOT EXISTS fetch_error TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_import_task_item_url ON quka_import_task_item (space_id, url) WHERE url != '';
+CREATE INDEX IF NOT EXISTS idx_import_task_item_fetch_pending ON quka_import_task_item (created_at) WHERE fetch_status = 'pending';
+
+-- 添加字段注释
+COMMENT ON COLUMN quka_import_task_item.url IS '书签导入时规范化后的网页地址，用于空间内去重';
+COMMENT ON COLUMN quka_import_task_item.fetch_status IS '网页内容抓取状态: 空字符串表示不抓取, pending, success, failed';
+COMMENT ON COLUMN quka_import_task_item.fetch_error IS '网页内容抓取失败原因';
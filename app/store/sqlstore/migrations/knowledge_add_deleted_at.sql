@@ -0,0 +1,8 @@
+-- 添加 deleted_at 字段到 quka_knowledge 表，用于回收站软删除
+ALTER TABLE quka_knowledge ADD COLUMN IF NOT EXISTS deleted_at BIGINT NOT NULL DEFAULT 0;
+
+-- 添加字段注释
+COMMENT ON COLUMN quka_knowledge.deleted_at IS '移入回收站的时间戳，0表示未删除';
+
+-- 回收站中的知识只占少数，使用部分索引
+CREATE INDEX IF NOT EXISTS idx_quka_knowledge_deleted_at ON quka_knowledge(deleted_at) WHERE deleted_at > 0;
@@ -0,0 +1,2 @@
+-- 标签过滤：为 quka_knowledge.tags 添加 GIN 索引，支持 tags && ARRAY[...] 查询
+CREATE INDEX IF NOT EXISTS idx_quka_knowledge_tags ON quka_knowledge USING gin (tags);
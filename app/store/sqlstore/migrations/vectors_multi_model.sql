@@ -0,0 +1,35 @@
+-- 支持任意维度与多个 embedding 模型：quka_vectors.embedding 不再限制为 vector(1024)，并记录生成向量的模型与维度
+-- 原有的 idx_vectors_embedding 索引绑定了固定维度，需要先删除才能修改列类型
+DROP INDEX IF EXISTS idx_vectors_embedding;
+
+ALTER TABLE quka_vectors ALTER COLUMN embedding TYPE vector;
+ALTER TABLE quka_vectors ADD COLUMN IF NOT EXISTS model VARCHAR(128) NOT NULL DEFAULT '';
+ALTER TABLE quka_vectors ADD COLUMN IF NOT EXISTS dimension INT NOT NULL DEFAULT 0;
+
+UPDATE quka_vectors SET dimension = vector_dims(embedding) WHERE dimension = 0;
+
+-- 添加字段注释
+COMMENT ON COLUMN quka_vectors.embedding IS '文本向量，存储经过编码后的文本向量表示，不限制维度';
+COMMENT ON COLUMN quka_vectors.model IS '生成该向量的 embedding 模型名称';
+COMMENT ON COLUMN quka_vectors.dimension IS '向量维度，每个维度单独建立 HNSW 部分索引(idx_vectors_embedding_{dimension})，由向量重建任务创建，程序写入新维度的向量时也会在后台创建';
+
+-- 已有向量均由升级前配置的 embedding 模型生成，按后台配置的 embedding 模型标记，否则升级后检索不到任何向量
+UPDATE quka_vectors SET model = mc.model_name
+FROM quka_custom_config cc
+JOIN quka_model_config mc ON mc.id = cc.value #>> '{}'
+WHERE quka_vectors.model = '' AND cc.name = 'ai_usage_embedding' AND cc.status = 1;
+
+-- 未配置 embedding 用途时，程序使用唯一启用的 embedding 模型
+UPDATE quka_vectors SET model = mc.model_name
+FROM quka_model_config mc
+WHERE quka_vectors.model = '' AND mc.model_type = 'embedding' AND mc.status = 1
+  AND (SELECT COUNT(*) FROM quka_model_config WHERE model_type = 'embedding' AND status = 1) = 1;
+
+CREATE INDEX IF NOT EXISTS idx_vectors_space_id_model ON quka_vectors (space_id, model);
+
+-- 为已有的 1024 维数据建立索引，其他维度的索引由向量重建任务创建
+-- CONCURRENTLY 不能在事务中执行，请使用 psql 等逐条提交的方式执行本脚本
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_vectors_embedding_1024 ON quka_vectors USING hnsw ((embedding::vector(1024)) vector_cosine_ops) WITH (m = 32, ef_construction = 128) WHERE dimension = 1024;
+
+-- 注意：仍未标记 model 的向量(未找到已启用的 embedding 模型配置)不会被检索，可手动标记(替换为实际的模型名称)或重新生成向量
+-- UPDATE quka_vectors SET model = 'BAAI/bge-m3' WHERE model = '';
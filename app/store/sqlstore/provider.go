@@ -28,6 +28,13 @@ func GetProvider() *Provider {
 	return provider
 }
 
+// NewProviderWithStores 使用给定的 store 实现创建 Provider，不连接数据库，用于在测试中替换 store
+func NewProviderWithStores(stores *Stores) *Provider {
+	return &Provider{
+		stores: stores,
+	}
+}
+
 type Provider struct {
 	*sqlstore.SqlProvider
 	stores  *Stores
@@ -72,6 +79,16 @@ type Stores struct {
 	store.RSSUserInterestStore
 	store.RSSDailyDigestStore
 	store.PodcastStore
+	store.ReembedJobStore
+	store.KnowledgeDuplicateStore
+	store.KnowledgeEntityStore
+	store.KnowledgeEntityMentionStore
+	store.KnowledgeRelationStore
+	store.KnowledgeLinkStore
+	store.ImportTaskStore
+	store.ImportTaskItemStore
+	store.SpaceExportStore
+	store.KnowledgeRevisionStore
 }
 
 func (s *Provider) batchExecStoreFuncs(fname string) {
@@ -333,6 +350,46 @@ func (p *Provider) PodcastStore() store.PodcastStore {
 	return p.stores.PodcastStore
 }
 
+func (p *Provider) ReembedJobStore() store.ReembedJobStore {
+	return p.stores.ReembedJobStore
+}
+
+func (p *Provider) KnowledgeDuplicateStore() store.KnowledgeDuplicateStore {
+	return p.stores.KnowledgeDuplicateStore
+}
+
+func (p *Provider) KnowledgeEntityStore() store.KnowledgeEntityStore {
+	return p.stores.KnowledgeEntityStore
+}
+
+func (p *Provider) KnowledgeEntityMentionStore() store.KnowledgeEntityMentionStore {
+	return p.stores.KnowledgeEntityMentionStore
+}
+
+func (p *Provider) KnowledgeRelationStore() store.KnowledgeRelationStore {
+	return p.stores.KnowledgeRelationStore
+}
+
+func (p *Provider) KnowledgeLinkStore() store.KnowledgeLinkStore {
+	return p.stores.KnowledgeLinkStore
+}
+
+func (p *Provider) ImportTaskStore() store.ImportTaskStore {
+	return p.stores.ImportTaskStore
+}
+
+func (p *Provider) ImportTaskItemStore() store.ImportTaskItemStore {
+	return p.stores.ImportTaskItemStore
+}
+
+func (p *Provider) SpaceExportStore() store.SpaceExportStore {
+	return p.stores.SpaceExportStore
+}
+
+func (p *Provider) KnowledgeRevisionStore() store.KnowledgeRevisionStore {
+	return p.stores.KnowledgeRevisionStore
+}
+
 // Cache 实现 Author 接口的 Cache 方法
 func (p *Provider) Cache() types.Cache {
 	if p.coreRef != nil && p.coreRef.getCacheFunc != nil {
@@ -364,3 +421,7 @@ func (c *EmptyCache) SetEx(ctx context.Context, key, value string, expiresAt tim
 func (c *EmptyCache) Expire(ctx context.Context, key string, expiration time.Duration) error {
 	return nil
 }
+
+func (c *EmptyCache) Del(ctx context.Context, keys ...string) error {
+	return nil
+}
@@ -0,0 +1,143 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc[*Provider](RegisterKey{}, func(provider *Provider) {
+		provider.stores.ReembedJobStore = NewReembedJobStore(provider)
+	})
+}
+
+// ReembedJobImpl 处理重新生成向量任务表的操作
+type ReembedJobImpl struct {
+	CommonFields
+}
+
+// NewReembedJobStore 创建新的 ReembedJobStore 实例
+func NewReembedJobStore(provider SqlProviderAchieve) store.ReembedJobStore {
+	repo := &ReembedJobImpl{}
+	repo.SetProvider(provider)
+	repo.SetTable(types.TABLE_REEMBED_JOB)
+	repo.SetAllColumns("id", "model", "status", "last_knowledge_id", "total", "processed", "failed", "batch_size", "interval_ms", "error", "created_at", "updated_at", "finished_at")
+	return repo
+}
+
+// Create 创建新的任务
+func (s *ReembedJobImpl) Create(ctx context.Context, data types.ReembedJob) error {
+	now := time.Now().Unix()
+	if data.CreatedAt == 0 {
+		data.CreatedAt = now
+	}
+	if data.UpdatedAt == 0 {
+		data.UpdatedAt = now
+	}
+	query := sq.Insert(s.GetTable()).
+		Columns(s.GetAllColumns()...).
+		Values(data.ID, data.Model, data.Status, data.Cursor, data.Total, data.Processed, data.Failed, data.BatchSize, data.Interval, data.Error, data.CreatedAt, data.UpdatedAt, data.FinishedAt)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// Get 根据ID获取任务
+func (s *ReembedJobImpl) Get(ctx context.Context, id string) (*types.ReembedJob, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"id": id})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res types.ReembedJob
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetLatest 获取最近创建的任务
+func (s *ReembedJobImpl) GetLatest(ctx context.Context) (*types.ReembedJob, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).OrderBy("created_at DESC").Limit(1)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res types.ReembedJob
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListByStatus 获取指定状态的任务
+func (s *ReembedJobImpl) ListByStatus(ctx context.Context, status string) ([]types.ReembedJob, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"status": status}).OrderBy("created_at")
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.ReembedJob
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UpdateProgress 更新任务进度
+func (s *ReembedJobImpl) UpdateProgress(ctx context.Context, id, cursor string, processed, failed int64) error {
+	query := sq.Update(s.GetTable()).
+		Set("last_knowledge_id", cursor).
+		Set("processed", processed).
+		Set("failed", failed).
+		Set("updated_at", time.Now().Unix()).
+		Where(sq.Eq{"id": id})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// UpdateStatus 更新任务状态，任务结束时记录完成时间
+func (s *ReembedJobImpl) UpdateStatus(ctx context.Context, id, status, errMsg string) error {
+	now := time.Now().Unix()
+	query := sq.Update(s.GetTable()).
+		Set("status", status).
+		Set("error", errMsg).
+		Set("updated_at", now).
+		Where(sq.Eq{"id": id})
+
+	switch status {
+	case types.REEMBED_JOB_STATUS_FINISHED, types.REEMBED_JOB_STATUS_CANCELED, types.REEMBED_JOB_STATUS_FAILED:
+		query = query.Set("finished_at", now)
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
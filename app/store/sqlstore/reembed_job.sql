@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS quka_reembed_job (
+    id VARCHAR(32) PRIMARY KEY, -- 任务ID
+    model VARCHAR(128) NOT NULL, -- 目标 embedding 模型
+    status VARCHAR(20) NOT NULL, -- 任务状态
+    last_knowledge_id VARCHAR(32) NOT NULL DEFAULT '', -- 最后处理完成的 knowledge id
+    total BIGINT NOT NULL DEFAULT 0, -- 需要处理的 knowledge 总数
+    processed BIGINT NOT NULL DEFAULT 0, -- 已处理数量
+    failed BIGINT NOT NULL DEFAULT 0, -- 处理失败数量
+    batch_size INT NOT NULL DEFAULT 0, -- 每批处理的 knowledge 数量
+    interval_ms BIGINT NOT NULL DEFAULT 0, -- 每批之间的间隔(毫秒)
+    error TEXT NOT NULL DEFAULT '', -- 任务失败原因
+    created_at BIGINT NOT NULL, -- 创建时间
+    updated_at BIGINT NOT NULL, -- 更新时间
+    finished_at BIGINT NOT NULL DEFAULT 0 -- 完成时间
+);
+
+-- 添加字段注释
+COMMENT ON TABLE quka_reembed_job IS '切换 embedding 模型后重新生成知识向量的任务';
+COMMENT ON COLUMN quka_reembed_job.id IS '任务ID';
+COMMENT ON COLUMN quka_reembed_job.model IS '目标 embedding 模型';
+COMMENT ON COLUMN quka_reembed_job.status IS '任务状态: running, paused, canceled, finished, failed';
+COMMENT ON COLUMN quka_reembed_job.last_knowledge_id IS '最后处理完成的 knowledge id，用于断点续跑';
+COMMENT ON COLUMN quka_reembed_job.total IS '需要处理的 knowledge 总数';
+COMMENT ON COLUMN quka_reembed_job.processed IS '已处理数量（包含失败与跳过）';
+COMMENT ON COLUMN quka_reembed_job.failed IS '处理失败数量';
+COMMENT ON COLUMN quka_reembed_job.batch_size IS '每批处理的 knowledge 数量';
+COMMENT ON COLUMN quka_reembed_job.interval_ms IS '每批之间的间隔(毫秒)，用于限流';
+COMMENT ON COLUMN quka_reembed_job.error IS '任务失败原因';
+COMMENT ON COLUMN quka_reembed_job.created_at IS '创建时间，UNIX时间戳';
+COMMENT ON COLUMN quka_reembed_job.updated_at IS '更新时间，UNIX时间戳';
+COMMENT ON COLUMN quka_reembed_job.finished_at IS '完成时间，UNIX时间戳';
+
+CREATE INDEX IF NOT EXISTS idx_reembed_job_status ON quka_reembed_job (status);
+CREATE INDEX IF NOT EXISTS idx_reembed_job_created_at ON quka_reembed_job (created_at);
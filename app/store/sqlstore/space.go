@@ -26,7 +26,7 @@ func NewSpaceStore(provider SqlProviderAchieve) *SpaceStore {
 	repo := &SpaceStore{}
 	repo.SetProvider(provider)
 	repo.SetTable(types.TABLE_SPACE)
-	repo.SetAllColumns("space_id", "title", "base_prompt", "chat_prompt", "description", "created_at")
+	repo.SetAllColumns("space_id", "title", "base_prompt", "chat_prompt", "description", "settings", "created_at")
 	return repo
 }
 
@@ -36,8 +36,8 @@ func (s *SpaceStore) Create(ctx context.Context, data types.Space) error {
 		data.CreatedAt = time.Now().Unix()
 	}
 	query := sq.Insert(s.GetTable()).
-		Columns("space_id", "title", "base_prompt", "chat_prompt", "description", "created_at").
-		Values(data.SpaceID, data.Title, data.BasePrompt, data.ChatPrompt, data.Description, data.CreatedAt)
+		Columns("space_id", "title", "base_prompt", "chat_prompt", "description", "settings", "created_at").
+		Values(data.SpaceID, data.Title, data.BasePrompt, data.ChatPrompt, data.Description, data.Settings, data.CreatedAt)
 
 	queryString, args, err := query.ToSql()
 	if err != nil {
@@ -89,6 +89,21 @@ func (s *SpaceStore) Update(ctx context.Context, spaceID, title, desc, basePromp
 	return err
 }
 
+// UpdateSettings 更新空间级配置
+func (s *SpaceStore) UpdateSettings(ctx context.Context, spaceID string, settings types.SpaceSettings) error {
+	query := sq.Update(s.GetTable()).
+		Set("settings", settings).
+		Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
 func (s *SpaceStore) Delete(ctx context.Context, spaceID string) error {
 	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
 
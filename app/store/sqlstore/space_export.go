@@ -0,0 +1,155 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func init() {
+	register.RegisterFunc[*Provider](RegisterKey{}, func(provider *Provider) {
+		provider.stores.SpaceExportStore = NewSpaceExportStore(provider)
+	})
+}
+
+// SpaceExportImpl 处理空间导出任务表的操作
+type SpaceExportImpl struct {
+	CommonFields
+}
+
+// NewSpaceExportStore 创建新的 SpaceExportStore 实例
+func NewSpaceExportStore(provider SqlProviderAchieve) store.SpaceExportStore {
+	repo := &SpaceExportImpl{}
+	repo.SetProvider(provider)
+	repo.SetTable(types.TABLE_SPACE_EXPORT)
+	repo.SetAllColumns("id", "space_id", "user_id", "status", "file", "file_size", "error", "created_at", "updated_at", "finished_at")
+	return repo
+}
+
+// Create 创建新的导出任务
+func (s *SpaceExportImpl) Create(ctx context.Context, data types.SpaceExport) error {
+	now := time.Now().Unix()
+	if data.CreatedAt == 0 {
+		data.CreatedAt = now
+	}
+	if data.UpdatedAt == 0 {
+		data.UpdatedAt = now
+	}
+	query := sq.Insert(s.GetTable()).
+		Columns(s.GetAllColumns()...).
+		Values(data.ID, data.SpaceID, data.UserID, data.Status, data.File, data.FileSize, data.Error, data.CreatedAt, data.UpdatedAt, data.FinishedAt)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// Get 获取空间下的导出任务
+func (s *SpaceExportImpl) Get(ctx context.Context, spaceID, id string) (*types.SpaceExport, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"space_id": spaceID, "id": id})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res types.SpaceExport
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// List 分页获取空间下的导出任务，按创建时间倒序
+func (s *SpaceExportImpl) List(ctx context.Context, spaceID string, page, pageSize uint64) ([]types.SpaceExport, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"space_id": spaceID}).OrderBy("created_at DESC")
+	if page != 0 || pageSize != 0 {
+		query = query.Limit(pageSize).Offset((page - 1) * pageSize)
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.SpaceExport
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Total 获取空间下的导出任务总数
+func (s *SpaceExportImpl) Total(ctx context.Context, spaceID string) (int64, error) {
+	query := sq.Select("COUNT(*)").From(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return 0, ErrorSqlBuild(err)
+	}
+
+	var res int64
+	if err = s.GetReplica(ctx).Get(&res, queryString, args...); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// ListByStatus 获取指定状态的导出任务
+func (s *SpaceExportImpl) ListByStatus(ctx context.Context, status string) ([]types.SpaceExport, error) {
+	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Where(sq.Eq{"status": status}).OrderBy("created_at")
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.SpaceExport
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Finish 结束导出任务，成功时记录归档路径与大小
+func (s *SpaceExportImpl) Finish(ctx context.Context, id, status, file string, fileSize int64, errMsg string) error {
+	now := time.Now().Unix()
+	query := sq.Update(s.GetTable()).
+		Set("status", status).
+		Set("file", file).
+		Set("file_size", fileSize).
+		Set("error", errMsg).
+		Set("updated_at", now).
+		Set("finished_at", now).
+		Where(sq.Eq{"id": id})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
+// DeleteAll 删除空间下的全部导出任务
+func (s *SpaceExportImpl) DeleteAll(ctx context.Context, spaceID string) error {
+	query := sq.Delete(s.GetTable()).Where(sq.Eq{"space_id": spaceID})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS quka_space_export (
+    id VARCHAR(32) PRIMARY KEY, -- 任务ID
+    space_id VARCHAR(32) NOT NULL, -- 空间ID
+    user_id VARCHAR(32) NOT NULL, -- 发起导出的用户ID
+    status VARCHAR(20) NOT NULL, -- 任务状态
+    file VARCHAR(255) NOT NULL DEFAULT '', -- 归档在对象存储中的路径
+    file_size BIGINT NOT NULL DEFAULT 0, -- 归档大小
+    error TEXT NOT NULL DEFAULT '', -- 任务失败原因
+    created_at BIGINT NOT NULL, -- 创建时间
+    updated_at BIGINT NOT NULL, -- 更新时间
+    finished_at BIGINT NOT NULL DEFAULT 0 -- 完成时间
+);
+
+-- 添加字段注释
+COMMENT ON TABLE quka_space_export IS '将空间数据导出为备份归档的任务';
+COMMENT ON COLUMN quka_space_export.id IS '任务ID';
+COMMENT ON COLUMN quka_space_export.space_id IS '空间ID';
+COMMENT ON COLUMN quka_space_export.user_id IS '发起导出的用户ID';
+COMMENT ON COLUMN quka_space_export.status IS '任务状态: running, finished, failed';
+COMMENT ON COLUMN quka_space_export.file IS '归档在对象存储中的路径';
+COMMENT ON COLUMN quka_space_export.file_size IS '归档大小，单位为字节';
+COMMENT ON COLUMN quka_space_export.error IS '任务失败原因';
+COMMENT ON COLUMN quka_space_export.created_at IS '创建时间，UNIX时间戳';
+COMMENT ON COLUMN quka_space_export.updated_at IS '更新时间，UNIX时间戳';
+COMMENT ON COLUMN quka_space_export.finished_at IS '完成时间，UNIX时间戳';
+
+CREATE INDEX IF NOT EXISTS idx_space_export_space_id ON quka_space_export (space_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_space_export_status ON quka_space_export (status);
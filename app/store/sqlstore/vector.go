@@ -2,12 +2,16 @@ package sqlstore
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pgvector/pgvector-go"
 
 	"github.com/quka-ai/quka-ai/pkg/register"
+	"github.com/quka-ai/quka-ai/pkg/safe"
 	"github.com/quka-ai/quka-ai/pkg/types"
 )
 
@@ -19,6 +23,74 @@ func init() {
 
 type VectorStore struct {
 	CommonFields
+	// 已确认存在的维度索引
+	dimensionIndexes sync.Map
+	// 已发起后台创建的维度索引
+	indexBuilding sync.Map
+}
+
+const (
+	// pgvector 的 HNSW 索引最多支持 2000 维的 vector 类型，更高维度(最多 4000 维)需转换为 halfvec 建立索引
+	maxVectorIndexDimension  = 2000
+	maxHalfvecIndexDimension = 4000
+)
+
+// embeddingExpr 返回指定维度下的向量表达式，建索引与查询时必须保持一致才能命中索引
+func embeddingExpr(dimension int) string {
+	if dimension > maxVectorIndexDimension {
+		return fmt.Sprintf("embedding::halfvec(%d)", dimension)
+	}
+	return fmt.Sprintf("embedding::vector(%d)", dimension)
+}
+
+func vectorType(dimension int) string {
+	if dimension > maxVectorIndexDimension {
+		return fmt.Sprintf("halfvec(%d)", dimension)
+	}
+	return fmt.Sprintf("vector(%d)", dimension)
+}
+
+// EnsureDimensionIndex 确保对应维度的 HNSW 部分索引存在
+// embedding 列不限制维度，不同维度的向量分别建立 WHERE dimension = N 的表达式索引。
+// 索引使用 CONCURRENTLY 创建，不阻塞写入，但耗时较长，不能在事务中调用
+func (s *VectorStore) EnsureDimensionIndex(ctx context.Context, dimension int) error {
+	if dimension <= 0 || dimension > maxHalfvecIndexDimension {
+		return nil
+	}
+	if _, exist := s.dimensionIndexes.Load(dimension); exist {
+		return nil
+	}
+
+	opsClass := "vector_cosine_ops"
+	if dimension > maxVectorIndexDimension {
+		opsClass = "halfvec_cosine_ops"
+	}
+
+	_, err := s.provider.GetMaster().ExecContext(ctx, fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_vectors_embedding_%d ON %s USING hnsw ((%s) %s) WITH (m = 32, ef_construction = 128) WHERE dimension = %d",
+		dimension, s.GetTable(), embeddingExpr(dimension), opsClass, dimension))
+	if err != nil {
+		return err
+	}
+	s.dimensionIndexes.Store(dimension, struct{}{})
+	return nil
+}
+
+// ensureDimensionIndexAsync 写入新维度的向量时在后台创建索引，不阻塞写入流程
+// 正常情况下索引已由迁移脚本或向量重建任务创建，这里只作为兜底
+func (s *VectorStore) ensureDimensionIndexAsync(dimension int) {
+	if dimension <= 0 || dimension > maxHalfvecIndexDimension {
+		return
+	}
+	if _, loaded := s.indexBuilding.LoadOrStore(dimension, struct{}{}); loaded {
+		return
+	}
+	go safe.Run(func() {
+		if err := s.EnsureDimensionIndex(context.Background(), dimension); err != nil {
+			// 失败后允许下次写入时重试
+			s.indexBuilding.Delete(dimension)
+			slog.Error("Failed to create vector dimension index", slog.Int("dimension", dimension), slog.String("error", err.Error()))
+		}
+	})
 }
 
 // NewBwVectorStore 创建新的 BwVectorStore 实例
@@ -26,10 +98,18 @@ func NewVectorStore(provider SqlProviderAchieve) *VectorStore {
 	repo := &VectorStore{}
 	repo.SetProvider(provider)
 	repo.SetTable(types.TABLE_VECTORS)
-	repo.SetAllColumns("id", "knowledge_id", "space_id", "user_id", "embedding", "original_length", "created_at", "updated_at")
+	repo.SetAllColumns("id", "knowledge_id", "space_id", "user_id", "embedding", "model", "dimension", "original_length", "created_at", "updated_at")
 	return repo
 }
 
+// keywordsExpr 将应用层分词后的文本转换为 tsvector，空文本写入 NULL
+func keywordsExpr(keywords string) any {
+	if keywords == "" {
+		return nil
+	}
+	return sq.Expr("to_tsvector('simple', ?)", keywords)
+}
+
 // Create 创建新的文本向量记录
 func (s *VectorStore) Create(ctx context.Context, data types.Vector) error {
 	if data.CreatedAt == 0 {
@@ -38,9 +118,12 @@ func (s *VectorStore) Create(ctx context.Context, data types.Vector) error {
 	if data.UpdatedAt == 0 {
 		data.UpdatedAt = time.Now().Unix()
 	}
+	data.Dimension = len(data.Embedding.Slice())
+	s.ensureDimensionIndexAsync(data.Dimension)
+
 	query := sq.Insert(s.GetTable()).
-		Columns("id", "knowledge_id", "space_id", "user_id", "resource", "embedding", "original_length", "created_at", "updated_at").
-		Values(data.ID, data.KnowledgeID, data.SpaceID, data.UserID, data.Resource, data.Embedding, data.OriginalLength, data.CreatedAt, data.UpdatedAt)
+		Columns("id", "knowledge_id", "space_id", "user_id", "resource", "embedding", "model", "dimension", "original_length", "keywords", "created_at", "updated_at").
+		Values(data.ID, data.KnowledgeID, data.SpaceID, data.UserID, data.Resource, data.Embedding, data.Model, data.Dimension, data.OriginalLength, keywordsExpr(data.Keywords), data.CreatedAt, data.UpdatedAt)
 
 	queryString, args, err := query.ToSql()
 	if err != nil {
@@ -59,7 +142,7 @@ func (s *VectorStore) Create(ctx context.Context, data types.Vector) error {
 // BatchCreate 批量创建新的文本向量记录
 func (s *VectorStore) BatchCreate(ctx context.Context, datas []types.Vector) error {
 	query := sq.Insert(s.GetTable()).
-		Columns("id", "knowledge_id", "space_id", "user_id", "resource", "embedding", "original_length", "created_at", "updated_at")
+		Columns("id", "knowledge_id", "space_id", "user_id", "resource", "embedding", "model", "dimension", "original_length", "keywords", "created_at", "updated_at")
 
 	for _, data := range datas {
 		if data.CreatedAt == 0 {
@@ -68,7 +151,9 @@ func (s *VectorStore) BatchCreate(ctx context.Context, datas []types.Vector) err
 		if data.UpdatedAt == 0 {
 			data.UpdatedAt = time.Now().Unix()
 		}
-		query = query.Values(data.ID, data.KnowledgeID, data.SpaceID, data.UserID, data.Resource, data.Embedding, data.OriginalLength, data.CreatedAt, data.UpdatedAt)
+		data.Dimension = len(data.Embedding.Slice())
+		s.ensureDimensionIndexAsync(data.Dimension)
+		query = query.Values(data.ID, data.KnowledgeID, data.SpaceID, data.UserID, data.Resource, data.Embedding, data.Model, data.Dimension, data.OriginalLength, keywordsExpr(data.Keywords), data.CreatedAt, data.UpdatedAt)
 	}
 
 	queryString, args, err := query.ToSql()
@@ -99,10 +184,15 @@ func (s *VectorStore) GetVector(ctx context.Context, spaceID, knowledgeID string
 	return &res, nil
 }
 
-// Update 更新文本向量记录
-func (s *VectorStore) Update(ctx context.Context, spaceID, knowledgeID, id string, vector pgvector.Vector) error {
+// Update 更新文本向量记录，model 为生成该向量的模型，与向量维度一同更新
+func (s *VectorStore) Update(ctx context.Context, spaceID, knowledgeID, id, model string, vector pgvector.Vector) error {
+	dimension := len(vector.Slice())
+	s.ensureDimensionIndexAsync(dimension)
+
 	query := sq.Update(s.GetTable()).
 		Set("embedding", vector).
+		Set("model", model).
+		Set("dimension", dimension).
 		Set("updated_at", time.Now().Unix()).
 		Where(sq.Eq{"space_id": spaceID, "knowledge_id": knowledgeID, "id": id})
 
@@ -164,6 +254,22 @@ func (s *VectorStore) DeleteAll(ctx context.Context, spaceID string) error {
 	return err
 }
 
+// ClearKeywords 清除空间内所有向量的全文检索词向量
+func (s *VectorStore) ClearKeywords(ctx context.Context, spaceID string) error {
+	query := sq.Update(s.GetTable()).
+		Set("keywords", nil).
+		Where(sq.Eq{"space_id": spaceID}).
+		Where(sq.NotEq{"keywords": nil})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return ErrorSqlBuild(err)
+	}
+
+	_, err = s.GetMaster(ctx).Exec(queryString, args...)
+	return err
+}
+
 // ListBwVectors 分页获取文本向量记录列表
 func (s *VectorStore) ListVectors(ctx context.Context, opts types.GetVectorsOptions, page, pageSize uint64) ([]types.Vector, error) {
 	query := sq.Select(s.GetAllColumns()...).From(s.GetTable()).Limit(pageSize).Offset((page - 1) * pageSize).OrderBy("created_at DESC")
@@ -187,8 +293,16 @@ func (s *VectorStore) Query(ctx context.Context, opts types.GetVectorsOptions, v
 	// <#> - (negative) inner product
 	// <=> - cosine distance
 	// <+> - L1 distance (added in 0.7.0)
-	cosColum, vectorArgs, _ := sq.Expr("1 - (embedding <=> ?) as cos", vectors).ToSql()
-	query := sq.Select("id", "knowledge_id", "original_length", cosColum).From(s.GetTable()).Limit(limit).OrderBy("cos DESC")
+	if opts.Model == "" {
+		return nil, fmt.Errorf("embedding model is required for vector query")
+	}
+
+	// 只在同模型、同维度的向量中检索，表达式需与 EnsureDimensionIndex 中的索引保持一致
+	dimension := len(vectors.Slice())
+	cosColum, vectorArgs, _ := sq.Expr(fmt.Sprintf("1 - (%s <=> ?::%s) as cos", embeddingExpr(dimension), vectorType(dimension)), vectors).ToSql()
+	query := sq.Select("id", "knowledge_id", "original_length", cosColum).From(s.GetTable()).
+		Where(sq.Eq{"dimension": dimension}).
+		Limit(limit).OrderBy("cos DESC")
 	opts.Apply(&query)
 
 	queryString, args, err := query.ToSql()
@@ -206,3 +320,24 @@ func (s *VectorStore) Query(ctx context.Context, opts types.GetVectorsOptions, v
 	}
 	return res, nil
 }
+
+// KeywordQuery 基于全文检索的关键词查询，tsquery 由 utils.FTSQuery 生成
+func (s *VectorStore) KeywordQuery(ctx context.Context, opts types.GetVectorsOptions, tsquery string, limit uint64) ([]types.QueryResult, error) {
+	query := sq.Select("id", "knowledge_id", "original_length").
+		Column(sq.Expr("ts_rank_cd(keywords, to_tsquery('simple', ?)) AS rank", tsquery)).
+		From(s.GetTable()).
+		Where(sq.Expr("keywords @@ to_tsquery('simple', ?)", tsquery)).
+		Limit(limit).OrderBy("rank DESC")
+	opts.Apply(&query)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, ErrorSqlBuild(err)
+	}
+
+	var res []types.QueryResult
+	if err = s.GetReplica(ctx).Select(&res, queryString, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
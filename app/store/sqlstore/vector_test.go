@@ -10,6 +10,7 @@ import (
 
 	"github.com/pgvector/pgvector-go"
 	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
 )
 
 type PGConfig struct {
@@ -46,10 +47,111 @@ func TestQuery(t *testing.T) {
 	if err = json.Unmarshal(raw, &vectors); err != nil {
 		t.Fatal(err)
 	}
-	res, err := provider.stores.VectorStore.Query(ctx, types.GetVectorsOptions{SpaceID: "test"}, pgvector.NewVector(vectors), 5)
+	res, err := provider.stores.VectorStore.Query(ctx, types.GetVectorsOptions{SpaceID: "test", Model: "test"}, pgvector.NewVector(vectors), 5)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Log(res)
 }
+
+func TestKeywordQuery(t *testing.T) {
+	cfg := PGConfig{}
+	cfg.FromENV()
+	provider := MustSetup(cfg)()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+	defer cancel()
+
+	const spaceID = "test_keyword_query"
+	defer provider.stores.VectorStore.DeleteAll(ctx, spaceID)
+
+	embedding := make([]float32, 1024)
+	embedding[0] = 1
+	err := provider.stores.VectorStore.BatchCreate(ctx, []types.Vector{
+		{
+			ID:          "test_keyword_1",
+			KnowledgeID: "test_keyword_1",
+			SpaceID:     spaceID,
+			UserID:      "test",
+			Resource:    "knowledge",
+			Embedding:   pgvector.NewVector(embedding),
+			Model:       "test",
+			Keywords:    utils.FTSDocument("PostgreSQL 全文检索支持中文"),
+		},
+		{
+			ID:          "test_keyword_2",
+			KnowledgeID: "test_keyword_2",
+			SpaceID:     spaceID,
+			UserID:      "test",
+			Resource:    "knowledge",
+			Embedding:   pgvector.NewVector(embedding),
+			Model:       "test",
+			Keywords:    utils.FTSDocument("今天天气不错"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := provider.stores.VectorStore.KeywordQuery(ctx, types.GetVectorsOptions{SpaceID: spaceID}, utils.FTSQuery("中文检索"), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != 1 || res[0].KnowledgeID != "test_keyword_1" {
+		t.Fatalf("unexpected keyword query result: %v", res)
+	}
+
+	if res[0].Rank <= 0 {
+		t.Fatalf("expected positive rank, got %f", res[0].Rank)
+	}
+}
+
+func TestQueryMultiModel(t *testing.T) {
+	cfg := PGConfig{}
+	cfg.FromENV()
+	provider := MustSetup(cfg)()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+	defer cancel()
+
+	const spaceID = "test_query_multi_model"
+	defer provider.stores.VectorStore.DeleteAll(ctx, spaceID)
+
+	newEmbedding := func(dimension int) pgvector.Vector {
+		embedding := make([]float32, dimension)
+		embedding[0] = 1
+		return pgvector.NewVector(embedding)
+	}
+
+	err := provider.stores.VectorStore.BatchCreate(ctx, []types.Vector{
+		{ID: "test_multi_768", KnowledgeID: "test_multi_768", SpaceID: spaceID, UserID: "test", Resource: "knowledge", Embedding: newEmbedding(768), Model: "model-768"},
+		{ID: "test_multi_1024", KnowledgeID: "test_multi_1024", SpaceID: spaceID, UserID: "test", Resource: "knowledge", Embedding: newEmbedding(1024), Model: "model-1024"},
+		{ID: "test_multi_1536", KnowledgeID: "test_multi_1536", SpaceID: spaceID, UserID: "test", Resource: "knowledge", Embedding: newEmbedding(1536), Model: "model-1536"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := provider.stores.VectorStore.Query(ctx, types.GetVectorsOptions{SpaceID: spaceID, Model: "model-768"}, newEmbedding(768), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].ID != "test_multi_768" {
+		t.Fatalf("unexpected query result: %v", res)
+	}
+
+	// 模型与维度不匹配时不应返回其他模型的向量
+	res, err = provider.stores.VectorStore.Query(ctx, types.GetVectorsOptions{SpaceID: spaceID, Model: "model-768"}, newEmbedding(1536), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 0 {
+		t.Fatalf("expected no result, got: %v", res)
+	}
+
+	if _, err = provider.stores.VectorStore.Query(ctx, types.GetVectorsOptions{SpaceID: spaceID}, newEmbedding(768), 5); err == nil {
+		t.Fatal("expected error when embedding model is not specified")
+	}
+}
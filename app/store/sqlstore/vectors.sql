@@ -5,8 +5,11 @@ CREATE TABLE IF NOT EXISTS quka_vectors (
     space_id VARCHAR(32) NOT NULL,
     user_id VARCHAR(32) NOT NULL,
     resource VARCHAR(32) NOT NULL,
-    embedding vector(1024) NOT NULL,
+    embedding vector NOT NULL,
+    model VARCHAR(128) NOT NULL DEFAULT '',
+    dimension INT NOT NULL DEFAULT 0,
     original_length INT NOT NULL DEFAULT 0,
+    keywords tsvector,
     created_at BIGINT NOT NULL,
     updated_at BIGINT NOT NULL
 );
@@ -15,12 +18,16 @@ CREATE TABLE IF NOT EXISTS quka_vectors (
 COMMENT ON COLUMN quka_vectors.id IS '主键，自增ID';
 COMMENT ON COLUMN quka_vectors.space_id IS '空间ID，用于标识所属空间';
 COMMENT ON COLUMN quka_vectors.user_id IS '用户ID，用于标识向量所属用户';
-COMMENT ON COLUMN quka_vectors.embedding IS '文本向量，存储经过编码后的文本向量表示';
+COMMENT ON COLUMN quka_vectors.embedding IS '文本向量，存储经过编码后的文本向量表示，不限制维度';
+COMMENT ON COLUMN quka_vectors.model IS '生成该向量的 embedding 模型名称';
+COMMENT ON COLUMN quka_vectors.dimension IS '向量维度，每个维度单独建立 HNSW 部分索引(idx_vectors_embedding_{dimension})，由向量重建任务创建，程序写入新维度的向量时也会在后台创建';
 COMMENT ON COLUMN quka_vectors.resource IS '资源类型';
 COMMENT ON COLUMN quka_vectors.original_length IS '关联知识点长度';
+COMMENT ON COLUMN quka_vectors.keywords IS '全文检索词向量，由应用层分词后通过 to_tsvector(''simple'', ...) 生成';
 COMMENT ON COLUMN quka_vectors.created_at IS '创建时间，UNIX时间戳';
 COMMENT ON COLUMN quka_vectors.updated_at IS '更新时间，UNIX时间戳';
 
 
 CREATE INDEX IF NOT EXISTS idx_vectors_space_id_resource_knowledge_id ON quka_vectors (space_id, resource, knowledge_id);
-CREATE INDEX IF NOT EXISTS idx_vectors_embedding ON quka_vectors USING hnsw (embedding vector_ip_ops) WITH (m = 32, ef_construction = 128);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_vectors_space_id_model ON quka_vectors (space_id, model);
+CREATE INDEX IF NOT EXISTS idx_vectors_keywords ON quka_vectors USING gin (keywords);
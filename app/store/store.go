@@ -20,12 +20,16 @@ type KnowledgeStore interface {
 	BatchCreate(ctx context.Context, datas []*types.Knowledge) error
 	// GetKnowledge 根据ID获取知识记录
 	GetKnowledge(ctx context.Context, spaceID, id string) (*types.Knowledge, error)
+	// LockKnowledge 在事务中锁定知识记录，串行化同一知识的并发修改
+	LockKnowledge(ctx context.Context, spaceID, id string) error
 	// GetByRelDocID 根据 rel_doc_id 获取 Knowledge（用于 RSS 文章关联）
 	GetByRelDocID(ctx context.Context, userID, relDocID string) (*types.Knowledge, error)
 	// BatchGetByRelDocIDs 批量根据 rel_doc_id 获取 Knowledge 映射（用于 RSS 文章关联）
 	BatchGetByRelDocIDs(ctx context.Context, userID string, relDocIDs []string) (map[string]*types.Knowledge, error)
 	// Update 更新知识记录
 	Update(ctx context.Context, spaceID, id string, data types.UpdateKnowledgeArgs) error
+	// SetDeletedAt 设置知识移入回收站的时间，deletedAt 为 0 时表示从回收站恢复
+	SetDeletedAt(ctx context.Context, spaceID, id string, deletedAt int64) error
 	// Delete 删除知识记录
 	Delete(ctx context.Context, spaceID, id string) error
 	DeleteAll(ctx context.Context, spaceID string) error
@@ -33,6 +37,8 @@ type KnowledgeStore interface {
 	// ListKnowledges 分页获取知识记录列表
 	ListKnowledges(ctx context.Context, opts types.GetKnowledgeOptions, page, pageSize uint64) ([]*types.Knowledge, error)
 	ListKnowledgeIDs(ctx context.Context, opts types.GetKnowledgeOptions, page, pageSize uint64) ([]string, error)
+	// ListKnowledgesAfterID 按 id 升序获取 afterID 之后的知识记录，用于需要断点续跑的全量遍历
+	ListKnowledgesAfterID(ctx context.Context, opts types.GetKnowledgeOptions, afterID string, limit uint64) ([]*types.Knowledge, error)
 	Total(ctx context.Context, opts types.GetKnowledgeOptions) (uint64, error)
 	ListLiteKnowledges(ctx context.Context, opts types.GetKnowledgeOptions, page, pageSize uint64) ([]*types.KnowledgeLite, error)
 	FinishedStageSummarize(ctx context.Context, spaceID, id string, summary ai.ChunkResult) error
@@ -42,6 +48,12 @@ type KnowledgeStore interface {
 	ListFailedKnowledges(ctx context.Context, stage types.KnowledgeStage, retryTimes int, page, pageSize uint64) ([]types.Knowledge, error)
 	// UpdateExpiredAt 更新单个knowledge的过期时间
 	UpdateExpiredAt(ctx context.Context, knowledgeID string, expiredAt int64) error
+	// ListTags 统计符合条件的知识中各标签的使用数量
+	ListTags(ctx context.Context, opts types.GetKnowledgeOptions) ([]types.KnowledgeTag, error)
+	// ReplaceTags 将空间内 from 中的标签统一替换为 to，返回受影响的知识数量
+	ReplaceTags(ctx context.Context, spaceID string, from []string, to string) (int64, error)
+	// RemoveTags 从空间内所有知识中移除指定标签，返回受影响的知识数量
+	RemoveTags(ctx context.Context, spaceID string, tags []string) (int64, error)
 }
 
 // KnowledgeChunkStore 定义 KnowledgeChunkStore 的接口
@@ -56,6 +68,8 @@ type KnowledgeChunkStore interface {
 	BatchDelete(ctx context.Context, spaceID, knowledgeID string) error
 	BatchDeleteByIDs(ctx context.Context, knowledgeIDs []string) error
 	List(ctx context.Context, spaceID, knowledgeID string) ([]types.KnowledgeChunk, error)
+	// ListByIDs 根据分片ID批量获取分片
+	ListByIDs(ctx context.Context, spaceID string, ids []string) ([]types.KnowledgeChunk, error)
 }
 
 // TODO support other vector db
@@ -66,13 +80,16 @@ type VectorStore interface {
 	Create(ctx context.Context, data types.Vector) error
 	BatchCreate(ctx context.Context, datas []types.Vector) error
 	GetVector(ctx context.Context, spaceID, knowledgeID string) (*types.Vector, error)
-	Update(ctx context.Context, spaceID, knowledgeID, id string, vector pgvector.Vector) error
+	Update(ctx context.Context, spaceID, knowledgeID, id, model string, vector pgvector.Vector) error
 	Delete(ctx context.Context, spaceID, knowledgeID, id string) error
 	BatchDelete(ctx context.Context, spaceID string, knowledgeIDs []string) error
 	DeleteAll(ctx context.Context, spaceID string) error
 	DeleteByResource(ctx context.Context, spaceID, resource string) error
+	ClearKeywords(ctx context.Context, spaceID string) error
 	ListVectors(ctx context.Context, opts types.GetVectorsOptions, page, pageSize uint64) ([]types.Vector, error)
 	Query(ctx context.Context, opts types.GetVectorsOptions, vectors pgvector.Vector, limit uint64) ([]types.QueryResult, error)
+	KeywordQuery(ctx context.Context, opts types.GetVectorsOptions, tsquery string, limit uint64) ([]types.QueryResult, error)
+	EnsureDimensionIndex(ctx context.Context, dimension int) error
 }
 
 type AccessTokenStore interface {
@@ -104,6 +121,7 @@ type SpaceStore interface {
 	Create(ctx context.Context, data types.Space) error
 	GetSpace(ctx context.Context, spaceID string) (*types.Space, error)
 	Update(ctx context.Context, spaceID, title, desc, basePrompt, chatPrompt string) error
+	UpdateSettings(ctx context.Context, spaceID string, settings types.SpaceSettings) error
 	Delete(ctx context.Context, spaceID string) error
 	List(ctx context.Context, spaceIDs []string, page, pageSize uint64) ([]types.Space, error)
 }
@@ -200,6 +218,8 @@ type ChatMessageExtStore interface {
 	GetChatMessageExt(ctx context.Context, spaceID, sessionID, messageID string) (*types.ChatMessageExt, error)
 	ListChatMessageExts(ctx context.Context, messageIDs []string) ([]types.ChatMessageExt, error)
 	Update(ctx context.Context, id string, data types.ChatMessageExt) error
+	UpdateRetrieval(ctx context.Context, spaceID, messageID, strategy string, queries, relDocs []string) error
+	UpdateCitations(ctx context.Context, spaceID, messageID string, citations types.ChatMessageCitations) error
 	Delete(ctx context.Context, id string) error
 	DeleteAll(ctx context.Context, spaceID string) error
 	DeleteSessionMessageExt(ctx context.Context, spaceID, sessionID string) error
@@ -431,3 +451,104 @@ type PodcastStore interface {
 	ListPendingTasks(ctx context.Context, limit int) ([]*types.Podcast, error)
 	ListFailedTasksForRetry(ctx context.Context, maxRetries int, limit int) ([]*types.Podcast, error)
 }
+
+type ReembedJobStore interface {
+	sqlstore.SqlCommons
+	Create(ctx context.Context, data types.ReembedJob) error
+	Get(ctx context.Context, id string) (*types.ReembedJob, error)
+	GetLatest(ctx context.Context) (*types.ReembedJob, error)
+	ListByStatus(ctx context.Context, status string) ([]types.ReembedJob, error)
+	UpdateProgress(ctx context.Context, id, cursor string, processed, failed int64) error
+	UpdateStatus(ctx context.Context, id, status, errMsg string) error
+}
+
+type KnowledgeDuplicateStore interface {
+	sqlstore.SqlCommons
+	BatchCreate(ctx context.Context, list []types.KnowledgeDuplicate) error
+	List(ctx context.Context, opts types.ListKnowledgeDuplicateOptions, page, pageSize uint64) ([]types.KnowledgeDuplicate, error)
+	ListClusters(ctx context.Context, opts types.ListKnowledgeDuplicateOptions, page, pageSize uint64) ([]string, error)
+	TotalClusters(ctx context.Context, opts types.ListKnowledgeDuplicateOptions) (int64, error)
+	UpdateStatus(ctx context.Context, spaceID string, ids []string, status string) error
+	DeletePendingByKnowledge(ctx context.Context, spaceID, knowledgeID string) error
+}
+
+type KnowledgeEntityStore interface {
+	sqlstore.SqlCommons
+	Upsert(ctx context.Context, list []types.KnowledgeEntity) ([]types.KnowledgeEntity, error)
+	Get(ctx context.Context, spaceID, id string) (*types.KnowledgeEntityItem, error)
+	List(ctx context.Context, opts types.ListKnowledgeEntityOptions, page, pageSize uint64) ([]types.KnowledgeEntityItem, error)
+	Total(ctx context.Context, opts types.ListKnowledgeEntityOptions) (int64, error)
+	DeleteAll(ctx context.Context, spaceID string) error
+}
+
+type KnowledgeEntityMentionStore interface {
+	sqlstore.SqlCommons
+	BatchCreate(ctx context.Context, list []types.KnowledgeEntityMention) error
+	ListKnowledgeIDs(ctx context.Context, spaceID, entityID string, page, pageSize uint64) ([]string, error)
+	ListCoMentionedKnowledgeIDs(ctx context.Context, spaceID string, knowledgeIDs []string, limit uint64) ([]string, error)
+	DeleteByKnowledge(ctx context.Context, spaceID, knowledgeID string) error
+	DeleteAll(ctx context.Context, spaceID string) error
+}
+
+type KnowledgeRelationStore interface {
+	sqlstore.SqlCommons
+	BatchCreate(ctx context.Context, list []types.KnowledgeRelation) error
+	ListByEntity(ctx context.Context, spaceID, entityID string, page, pageSize uint64) ([]types.KnowledgeRelation, error)
+	DeleteByKnowledge(ctx context.Context, spaceID, knowledgeID string) error
+	DeleteAll(ctx context.Context, spaceID string) error
+}
+
+type KnowledgeLinkStore interface {
+	sqlstore.SqlCommons
+	BatchCreate(ctx context.Context, list []types.KnowledgeLink) error
+	ListTargetIDs(ctx context.Context, spaceID string, knowledgeIDs []string) ([]string, error)
+	ListBacklinkIDs(ctx context.Context, spaceID string, knowledgeIDs []string) ([]string, error)
+	DeleteByKnowledge(ctx context.Context, spaceID, knowledgeID string) error
+	DeleteByTarget(ctx context.Context, spaceID, knowledgeID string) error
+	DeleteAll(ctx context.Context, spaceID string) error
+}
+
+type ImportTaskStore interface {
+	sqlstore.SqlCommons
+	Create(ctx context.Context, data types.ImportTask) error
+	Get(ctx context.Context, spaceID, id string) (*types.ImportTask, error)
+	List(ctx context.Context, spaceID string, page, pageSize uint64) ([]types.ImportTask, error)
+	Total(ctx context.Context, spaceID string) (int64, error)
+	ListByStatus(ctx context.Context, status string) ([]types.ImportTask, error)
+	UpdateProgress(ctx context.Context, id string, processed, failed int64) error
+	UpdateStatus(ctx context.Context, id, status, errMsg string) error
+	DeleteAll(ctx context.Context, spaceID string) error
+}
+
+type ImportTaskItemStore interface {
+	sqlstore.SqlCommons
+	Create(ctx context.Context, data types.ImportTaskItem) error
+	List(ctx context.Context, taskID, status string, page, pageSize uint64) ([]types.ImportTaskItem, error)
+	Total(ctx context.Context, taskID, status string) (int64, error)
+	ListByURLs(ctx context.Context, spaceID string, urls []string) ([]types.ImportTaskItem, error)
+	ListPendingFetch(ctx context.Context, limit uint64) ([]types.ImportTaskItem, error)
+	UpdateFetchStatus(ctx context.Context, id, status, errMsg string) error
+	DeleteAll(ctx context.Context, spaceID string) error
+}
+
+type SpaceExportStore interface {
+	sqlstore.SqlCommons
+	Create(ctx context.Context, data types.SpaceExport) error
+	Get(ctx context.Context, spaceID, id string) (*types.SpaceExport, error)
+	List(ctx context.Context, spaceID string, page, pageSize uint64) ([]types.SpaceExport, error)
+	Total(ctx context.Context, spaceID string) (int64, error)
+	ListByStatus(ctx context.Context, status string) ([]types.SpaceExport, error)
+	Finish(ctx context.Context, id, status, file string, fileSize int64, errMsg string) error
+	DeleteAll(ctx context.Context, spaceID string) error
+}
+
+type KnowledgeRevisionStore interface {
+	sqlstore.SqlCommons
+	Create(ctx context.Context, data types.KnowledgeRevision) (int64, error)
+	Get(ctx context.Context, spaceID, knowledgeID string, version int64) (*types.KnowledgeRevision, error)
+	List(ctx context.Context, spaceID, knowledgeID string, page, pageSize uint64) ([]types.KnowledgeRevision, error)
+	Total(ctx context.Context, spaceID, knowledgeID string) (int64, error)
+	Prune(ctx context.Context, knowledgeID string, keep int) error
+	Delete(ctx context.Context, spaceID, knowledgeID string) error
+	DeleteAll(ctx context.Context, spaceID string) error
+}
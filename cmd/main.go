@@ -19,7 +19,7 @@ func main() {
 		},
 	}
 
-	root.AddCommand(service.NewCommand(), service.NewProcessCommand())
+	root.AddCommand(service.NewCommand(), service.NewProcessCommand(), service.NewReembedCommand(), service.NewEvalCommand(), service.NewImportCommand(), service.NewExportCommand())
 
 	if err := root.Execute(); err != nil {
 		os.Exit(1)
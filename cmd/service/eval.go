@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/eval"
+	"github.com/quka-ai/quka-ai/pkg/plugins"
+)
+
+type EvalOptions struct {
+	Options
+	SpaceID   string
+	UserID    string
+	Tags      []string
+	Golden    string
+	Ks        []int
+	NoEnhance bool
+	Judge     bool
+	JSON      bool
+}
+
+// NewEvalCommand 使用评估集离线评估空间的检索效果
+// 评估集为 JSONL 文件，每行格式：{"question": "...", "expected_ids": ["knowledge id"], "reference_answer": "可选"}
+func NewEvalCommand() *cobra.Command {
+	opts := &EvalOptions{}
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "evaluate retrieval quality of a space with a golden set",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunEval(opts)
+		},
+	}
+	opts.AddFlags(cmd.Flags())
+	cmd.Flags().StringVar(&opts.SpaceID, "space", "", "space id to evaluate")
+	cmd.Flags().StringVar(&opts.UserID, "user", "", "only retrieve knowledge created by the given user")
+	cmd.Flags().StringSliceVar(&opts.Tags, "tag", nil, "only retrieve knowledge with any of the given tags")
+	cmd.Flags().StringVar(&opts.Golden, "golden", "", "golden set file in JSONL format")
+	cmd.Flags().IntSliceVar(&opts.Ks, "k", eval.DefaultKs, "k values of recall@k and nDCG@k")
+	cmd.Flags().BoolVar(&opts.NoEnhance, "no-enhance", false, "skip query enhancement")
+	cmd.Flags().BoolVar(&opts.Judge, "judge", false, "score answers against reference answers with the chat model")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "print the report as JSON")
+	cmd.MarkFlagRequired("space")
+	cmd.MarkFlagRequired("golden")
+	return cmd
+}
+
+func RunEval(opts *EvalOptions) error {
+	cases, err := eval.LoadCasesFromFile(opts.Golden)
+	if err != nil {
+		return fmt.Errorf("failed to load golden set: %w", err)
+	}
+
+	app := core.MustSetupCore(core.MustLoadBaseConfig(opts.ConfigPath))
+	plugins.Setup(app.InstallPlugins, opts.Init)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var judge eval.Judge
+	if opts.Judge {
+		judge = eval.NewLLMJudge(app.Srv().AI().GetChatAI(false), nil)
+	}
+
+	retriever := eval.NewCoreRetriever(app, opts.SpaceID, opts.UserID, opts.Tags, !opts.NoEnhance)
+	report, err := eval.NewRunner(retriever, judge, opts.Ks...).Run(ctx, cases)
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	report.Print(os.Stdout)
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/plugins"
+)
+
+type ExportOptions struct {
+	Options
+	SpaceID string
+	UserID  string
+	Output  string
+}
+
+// NewExportCommand 在前台将空间数据导出为备份归档，写入本地文件
+func NewExportCommand() *cobra.Command {
+	opts := &ExportOptions{}
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "export a space into a backup archive",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunSpaceExport(opts)
+		},
+	}
+	opts.AddFlags(cmd.Flags())
+	cmd.Flags().StringVar(&opts.SpaceID, "space", "", "space id to export")
+	cmd.Flags().StringVar(&opts.UserID, "user", "", "user whose butler tables are exported, defaults to the space chief")
+	cmd.Flags().StringVar(&opts.Output, "output", "", "backup archive path, defaults to <space id>.zip")
+	cmd.MarkFlagRequired("space")
+	return cmd
+}
+
+func RunSpaceExport(opts *ExportOptions) error {
+	app := core.MustSetupCore(core.MustLoadBaseConfig(opts.ConfigPath))
+	plugins.Setup(app.InstallPlugins, opts.Init)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	userID, err := resolveImportUser(ctx, app, &ImportOptions{SpaceID: opts.SpaceID, UserID: opts.UserID})
+	if err != nil {
+		return err
+	}
+
+	output := opts.Output
+	if output == "" {
+		output = opts.SpaceID + ".zip"
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer f.Close()
+
+	manifest, err := process.NewSpaceExporter(app, opts.SpaceID, userID).Export(ctx, f)
+	if err != nil {
+		f.Close()
+		os.Remove(output)
+		return err
+	}
+
+	counts := manifest.Counts
+	fmt.Printf("Space %s exported to %s: knowledge %d, resources %d, journals %d, chat sessions %d, butler tables %d, rss subscriptions %d, files %d\n",
+		opts.SpaceID, output, counts.Knowledges, counts.Resources, counts.Journals, counts.ChatSessions, counts.ButlerTables, counts.Subscriptions, counts.Files)
+	return nil
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/backup"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/i18n"
+	"github.com/quka-ai/quka-ai/pkg/importer"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// ImportArchiveRequest 上传归档导入时的表单参数，归档文件通过 file 字段上传
+type ImportArchiveRequest struct {
+	Resource   string `form:"resource"`    // 默认写入的 resource
+	FolderMode string `form:"folder_mode"` // 目录结构的映射方式: 空字符串, resource, tags
+	Fetch      bool   `form:"fetch"`       // 书签导入时是否在后台抓取网页内容
+}
+
+// ImportObsidian 上传 zip 格式的 Obsidian vault 或 markdown 文件夹，在后台逐篇导入为知识
+func (s *HttpSrv) ImportObsidian(c *gin.Context) {
+	var req ImportArchiveRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	fileName, data, err := readImportArchive(c, importer.MAX_ARCHIVE_SIZE)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	task, err := v1.NewImportLogic(c, s.Core).StartObsidianImport(spaceID, fileName, v1.ImportOptions{
+		Resource:   req.Resource,
+		FolderMode: req.FolderMode,
+	}, data)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, task)
+}
+
+// ImportNotion 上传 Notion 的 Markdown & CSV 导出归档，在后台导入为知识，进度通过 Centrifuge 推送
+func (s *HttpSrv) ImportNotion(c *gin.Context) {
+	var req ImportArchiveRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	fileName, data, err := readImportArchive(c, importer.MAX_ARCHIVE_SIZE)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	task, err := v1.NewImportLogic(c, s.Core).StartNotionImport(spaceID, fileName, v1.ImportOptions{
+		Resource:   req.Resource,
+		FolderMode: req.FolderMode,
+	}, data)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, task)
+}
+
+// ImportBookmarks 上传浏览器导出的书签 HTML 或 Pocket / Instapaper 导出的 CSV，在后台导入为链接知识
+func (s *HttpSrv) ImportBookmarks(c *gin.Context) {
+	var req ImportArchiveRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	fileName, data, err := readImportArchive(c, importer.MAX_ARCHIVE_SIZE)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	task, err := v1.NewImportLogic(c, s.Core).StartBookmarkImport(spaceID, fileName, v1.ImportOptions{
+		Resource:   req.Resource,
+		FolderMode: req.FolderMode,
+		Fetch:      req.Fetch,
+	}, data)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, task)
+}
+
+// ImportBackup 上传空间导出的备份归档，在后台恢复到当前空间
+func (s *HttpSrv) ImportBackup(c *gin.Context) {
+	fileName, data, err := readImportArchive(c, backup.MAX_BACKUP_SIZE)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	task, err := v1.NewImportLogic(c, s.Core).StartBackupImport(spaceID, fileName, data)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, task)
+}
+
+// RestoreSpace 上传空间导出的备份归档，按归档中的空间信息创建新空间并在后台恢复
+func (s *HttpSrv) RestoreSpace(c *gin.Context) {
+	fileName, data, err := readImportArchive(c, backup.MAX_BACKUP_SIZE)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	task, err := v1.NewImportLogic(c, s.Core).StartBackupImport("", fileName, data)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, task)
+}
+
+func readImportArchive(c *gin.Context, limit int64) (string, []byte, error) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		return "", nil, errors.New("readImportArchive.FormFile", i18n.ERROR_INVALIDARGUMENT, err).Code(http.StatusBadRequest)
+	}
+	defer file.Close()
+
+	if header.Size > limit {
+		return "", nil, errors.New("readImportArchive.Size", i18n.ERROR_INVALIDARGUMENT, importer.ErrArchiveTooLarge).Code(http.StatusBadRequest)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		return "", nil, errors.New("readImportArchive.ReadAll", i18n.ERROR_INTERNAL, err)
+	}
+	return path.Base(header.Filename), data, nil
+}
+
+type ListImportTasksRequest struct {
+	Page     uint64 `json:"page" form:"page" binding:"required"`
+	Pagesize uint64 `json:"pagesize" form:"pagesize" binding:"required,lte=50"`
+}
+
+type ListImportTasksResponse struct {
+	List  []types.ImportTask `json:"list"`
+	Total int64              `json:"total"`
+}
+
+// ListImportTasks 分页获取空间下的导入任务
+func (s *HttpSrv) ListImportTasks(c *gin.Context) {
+	var req ListImportTasksRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	list, total, err := v1.NewImportLogic(c, s.Core).ListTasks(spaceID, req.Page, req.Pagesize)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, ListImportTasksResponse{
+		List:  list,
+		Total: total,
+	})
+}
+
+// GetImportTask 获取导入任务的进度
+func (s *HttpSrv) GetImportTask(c *gin.Context) {
+	spaceID, _ := v1.InjectSpaceID(c)
+	task, err := v1.NewImportLogic(c, s.Core).GetTask(spaceID, c.Param("id"))
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, task)
+}
+
+type ListImportTaskItemsRequest struct {
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=success failed skipped"`
+	Page     uint64 `json:"page" form:"page" binding:"required"`
+	Pagesize uint64 `json:"pagesize" form:"pagesize" binding:"required,lte=100"`
+}
+
+type ListImportTaskItemsResponse struct {
+	List  []types.ImportTaskItem `json:"list"`
+	Total int64                  `json:"total"`
+}
+
+// ListImportTaskItems 分页获取导入任务中每个文件的处理结果
+func (s *HttpSrv) ListImportTaskItems(c *gin.Context) {
+	var req ListImportTaskItemsRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	list, total, err := v1.NewImportLogic(c, s.Core).ListTaskItems(spaceID, c.Param("id"), req.Status, req.Page, req.Pagesize)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, ListImportTaskItemsResponse{
+		List:  list,
+		Total: total,
+	})
+}
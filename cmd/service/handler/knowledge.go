@@ -108,7 +108,8 @@ func (s *HttpSrv) GetKnowledge(c *gin.Context) {
 	}
 
 	spaceID, _ := v1.InjectSpaceID(c)
-	knowledge, err := v1.NewKnowledgeLogic(c, s.Core).GetKnowledge(spaceID, req.ID)
+	logic := v1.NewKnowledgeLogic(c, s.Core)
+	knowledge, err := logic.GetKnowledge(spaceID, req.ID)
 	if err != nil {
 		response.APIError(c, err)
 		return
@@ -119,14 +120,21 @@ func (s *HttpSrv) GetKnowledge(c *gin.Context) {
 		return
 	}
 
-	response.APISuccess(c, KnowledgeToKnowledgeResponse(knowledge))
+	res := KnowledgeToKnowledgeResponse(knowledge)
+	if res.DuplicateOf, err = logic.GetKnowledgeDuplicates(spaceID, knowledge.ID); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, res)
 }
 
 type ListKnowledgeRequest struct {
-	Resource string `json:"resource" form:"resource"`
-	Keywords string `json:"keywords" form:"keywords"`
-	Page     uint64 `json:"page" form:"page" binding:"required"`
-	PageSize uint64 `json:"pagesize" form:"pagesize" binding:"required,lte=50"`
+	Resource string   `json:"resource" form:"resource"`
+	Keywords string   `json:"keywords" form:"keywords"`
+	Tags     []string `json:"tags" form:"tags"`
+	Page     uint64   `json:"page" form:"page" binding:"required"`
+	PageSize uint64   `json:"pagesize" form:"pagesize" binding:"required,lte=50"`
 }
 
 type ListKnowledgeResponse struct {
@@ -150,7 +158,7 @@ func (s *HttpSrv) ListKnowledge(c *gin.Context) {
 	}
 
 	spaceID, _ := v1.InjectSpaceID(c)
-	list, total, err := v1.NewKnowledgeLogic(c, s.Core).ListUserKnowledges(spaceID, req.Keywords, resource, req.Page, req.PageSize)
+	list, total, err := v1.NewKnowledgeLogic(c, s.Core).ListUserKnowledges(spaceID, req.Keywords, resource, req.Tags, req.Page, req.PageSize)
 	if err != nil {
 		response.APIError(c, err)
 		return
@@ -273,6 +281,7 @@ func KnowledgeToKnowledgeResponseLite(item *types.Knowledge) *types.KnowledgeRes
 		Stage:       item.Stage,
 		UpdatedAt:   item.UpdatedAt,
 		CreatedAt:   item.CreatedAt,
+		DeletedAt:   item.DeletedAt,
 	}
 
 	if result.ContentType == types.KNOWLEDGE_CONTENT_TYPE_BLOCKS {
@@ -365,3 +374,45 @@ func (s *HttpSrv) GetDateCreatedKnowledge(c *gin.Context) {
 	}
 	response.APISuccess(c, result)
 }
+
+type SearchKnowledgeRequest struct {
+	Query     string                `json:"query" form:"query" binding:"required"`
+	Kind      []types.KnowledgeKind `json:"kind" form:"kind"`
+	Source    string                `json:"source" form:"source"`
+	Resource  string                `json:"resource" form:"resource"`
+	Tags      []string              `json:"tags" form:"tags"`
+	StartTime int64                 `json:"start_time" form:"start_time"`
+	EndTime   int64                 `json:"end_time" form:"end_time"`
+	StartDate string                `json:"start_date" form:"start_date"`
+	EndDate   string                `json:"end_date" form:"end_date"`
+	Limit     int                   `json:"limit" form:"limit" binding:"omitempty,lte=50"`
+}
+
+// SearchKnowledge 检索知识，返回命中片段与分面统计
+func (s *HttpSrv) SearchKnowledge(c *gin.Context) {
+	var req SearchKnowledgeRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	result, err := v1.NewKnowledgeLogic(c, s.Core).Search(spaceID, v1.KnowledgeSearchArgs{
+		Query:     req.Query,
+		Kind:      req.Kind,
+		Source:    req.Source,
+		Resource:  req.Resource,
+		Tags:      req.Tags,
+		CreatedSt: req.StartTime,
+		CreatedEt: req.EndTime,
+		DateSt:    req.StartDate,
+		DateEt:    req.EndDate,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, result)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type SearchAllSpacesRequest struct {
+	Query     string                `json:"query" form:"query" binding:"required"`
+	SpaceIDs  []string              `json:"space_ids" form:"space_ids"`
+	Kind      []types.KnowledgeKind `json:"kind" form:"kind"`
+	Source    string                `json:"source" form:"source"`
+	Tags      []string              `json:"tags" form:"tags"`
+	StartTime int64                 `json:"start_time" form:"start_time"`
+	EndTime   int64                 `json:"end_time" form:"end_time"`
+	StartDate string                `json:"start_date" form:"start_date"`
+	EndDate   string                `json:"end_date" form:"end_date"`
+	Limit     int                   `json:"limit" form:"limit" binding:"omitempty,lte=50"`
+}
+
+// SearchAllSpaces 在用户可查看的所有空间中检索知识，结果标记所属空间
+func (s *HttpSrv) SearchAllSpaces(c *gin.Context) {
+	var req SearchAllSpacesRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	result, err := v1.NewKnowledgeLogic(c, s.Core).SearchAllSpaces(req.SpaceIDs, v1.KnowledgeSearchArgs{
+		Query:     req.Query,
+		Kind:      req.Kind,
+		Source:    req.Source,
+		Tags:      req.Tags,
+		CreatedSt: req.StartTime,
+		CreatedEt: req.EndTime,
+		DateSt:    req.StartDate,
+		DateEt:    req.EndDate,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, result)
+}
+
+type QueryAllSpacesRequest struct {
+	Query    string   `json:"query" binding:"required"`
+	SpaceIDs []string `json:"space_ids"`
+	Tags     []string `json:"tags"`
+}
+
+// QueryAllSpaces 基于用户可查看的所有空间中的知识回答问题
+func (s *HttpSrv) QueryAllSpaces(c *gin.Context) {
+	var req QueryAllSpacesRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	result, err := v1.NewKnowledgeLogic(c, s.Core).QueryAllSpaces(req.SpaceIDs, req.Query, req.Tags)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, result)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type ListKnowledgeDuplicatesRequest struct {
+	Status   string `json:"status" form:"status"`
+	Page     uint64 `json:"page" form:"page" binding:"required"`
+	PageSize uint64 `json:"pagesize" form:"pagesize" binding:"required,lte=50"`
+}
+
+type ListKnowledgeDuplicatesResponse struct {
+	List  []types.KnowledgeDuplicateCluster `json:"list"`
+	Total int64                             `json:"total"`
+}
+
+// ListKnowledgeDuplicates 按已有知识分组列出空间内疑似重复的知识
+func (s *HttpSrv) ListKnowledgeDuplicates(c *gin.Context) {
+	var (
+		err error
+		req ListKnowledgeDuplicatesRequest
+	)
+	if err = utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	list, total, err := v1.NewKnowledgeLogic(c, s.Core).ListDuplicateClusters(spaceID, req.Status, req.Page, req.PageSize)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, ListKnowledgeDuplicatesResponse{
+		List:  list,
+		Total: total,
+	})
+}
+
+type ResolveKnowledgeDuplicatesRequest struct {
+	DuplicateOf string   `json:"duplicate_of" binding:"required"`
+	IDs         []string `json:"ids"`
+	Action      string   `json:"action" binding:"required,oneof=merge ignore"`
+}
+
+// ResolveKnowledgeDuplicates 合并或忽略疑似重复的知识，ids 为空时处理该分组下的全部记录
+func (s *HttpSrv) ResolveKnowledgeDuplicates(c *gin.Context) {
+	var (
+		err error
+		req ResolveKnowledgeDuplicatesRequest
+	)
+	if err = utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	if err = v1.NewKnowledgeLogic(c, s.Core).ResolveDuplicates(spaceID, req.DuplicateOf, req.IDs, req.Action); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, nil)
+}
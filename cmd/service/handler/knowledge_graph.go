@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type ListKnowledgeEntitiesRequest struct {
+	Keywords string `json:"keywords" form:"keywords"`
+	Type     string `json:"type" form:"type"`
+	Page     uint64 `json:"page" form:"page" binding:"required"`
+	PageSize uint64 `json:"pagesize" form:"pagesize" binding:"required,lte=50"`
+}
+
+type ListKnowledgeEntitiesResponse struct {
+	List  []types.KnowledgeEntityItem `json:"list"`
+	Total int64                       `json:"total"`
+}
+
+// ListKnowledgeEntities 分页获取空间内知识提及的实体
+func (s *HttpSrv) ListKnowledgeEntities(c *gin.Context) {
+	var (
+		err error
+		req ListKnowledgeEntitiesRequest
+	)
+	if err = utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	list, total, err := v1.NewKnowledgeLogic(c, s.Core).ListEntities(spaceID, req.Keywords, req.Type, req.Page, req.PageSize)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, ListKnowledgeEntitiesResponse{
+		List:  list,
+		Total: total,
+	})
+}
+
+type GetKnowledgeEntityRequest struct {
+	ID       string `json:"id" form:"id" binding:"required"`
+	Page     uint64 `json:"page" form:"page" binding:"required"`
+	PageSize uint64 `json:"pagesize" form:"pagesize" binding:"required,lte=50"`
+}
+
+// GetKnowledgeEntity 获取实体详情及提及该实体的知识
+func (s *HttpSrv) GetKnowledgeEntity(c *gin.Context) {
+	var (
+		err error
+		req GetKnowledgeEntityRequest
+	)
+	if err = utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	detail, err := v1.NewKnowledgeLogic(c, s.Core).GetEntity(spaceID, req.ID, req.Page, req.PageSize)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, detail)
+}
+
+type GetKnowledgeLinksRequest struct {
+	ID string `json:"id" form:"id" binding:"required"`
+}
+
+// GetKnowledgeLinks 获取知识的引用、反向引用及提及的实体
+func (s *HttpSrv) GetKnowledgeLinks(c *gin.Context) {
+	var (
+		err error
+		req GetKnowledgeLinksRequest
+	)
+	if err = utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	links, err := v1.NewKnowledgeLogic(c, s.Core).GetKnowledgeLinks(spaceID, req.ID)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, links)
+}
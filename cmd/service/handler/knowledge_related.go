@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type ListRelatedKnowledgeRequest struct {
+	ID    string `json:"id" form:"id" binding:"required"`
+	Limit int    `json:"limit" form:"limit" binding:"omitempty,lte=50"`
+}
+
+type ListRelatedKnowledgeResponse struct {
+	List []types.RelatedKnowledge `json:"list"`
+}
+
+// ListRelatedKnowledge 获取与指定知识语义相近的知识
+func (s *HttpSrv) ListRelatedKnowledge(c *gin.Context) {
+	var (
+		err error
+		req ListRelatedKnowledgeRequest
+	)
+	if err = utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	list, err := v1.NewKnowledgeLogic(c, s.Core).ListRelatedKnowledges(spaceID, req.ID, req.Limit)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, ListRelatedKnowledgeResponse{
+		List: list,
+	})
+}
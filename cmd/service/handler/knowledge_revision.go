@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type ListKnowledgeRevisionsRequest struct {
+	ID       string `json:"id" form:"id" binding:"required"`
+	Page     uint64 `json:"page" form:"page" binding:"required"`
+	PageSize uint64 `json:"pagesize" form:"pagesize" binding:"required,lte=50"`
+}
+
+type ListKnowledgeRevisionsResponse struct {
+	List  []types.KnowledgeRevision `json:"list"`
+	Total int64                     `json:"total"`
+}
+
+// ListKnowledgeRevisions 知识的修订历史
+func (s *HttpSrv) ListKnowledgeRevisions(c *gin.Context) {
+	var req ListKnowledgeRevisionsRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	list, total, err := v1.NewKnowledgeLogic(c, s.Core).ListRevisions(spaceID, req.ID, req.Page, req.PageSize)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, ListKnowledgeRevisionsResponse{
+		List:  list,
+		Total: total,
+	})
+}
+
+type GetKnowledgeRevisionRequest struct {
+	ID      string `json:"id" form:"id" binding:"required"`
+	Version int64  `json:"version" form:"version" binding:"required"`
+}
+
+// GetKnowledgeRevision 获取知识指定修订的完整内容
+func (s *HttpSrv) GetKnowledgeRevision(c *gin.Context) {
+	var req GetKnowledgeRevisionRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	revision, err := v1.NewKnowledgeLogic(c, s.Core).GetRevision(spaceID, req.ID, req.Version)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, revision)
+}
+
+type DiffKnowledgeRevisionsRequest struct {
+	ID   string `json:"id" form:"id" binding:"required"`
+	From int64  `json:"from" form:"from" binding:"required"`
+	To   int64  `json:"to" form:"to" binding:"required"`
+}
+
+// DiffKnowledgeRevisions 对比知识的两个修订
+func (s *HttpSrv) DiffKnowledgeRevisions(c *gin.Context) {
+	var req DiffKnowledgeRevisionsRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	diff, err := v1.NewKnowledgeLogic(c, s.Core).DiffRevisions(spaceID, req.ID, req.From, req.To)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, diff)
+}
+
+type RestoreKnowledgeRevisionRequest struct {
+	ID      string `json:"id" binding:"required"`
+	Version int64  `json:"version" binding:"required"`
+}
+
+// RestoreKnowledgeRevision 将知识恢复到指定修订
+func (s *HttpSrv) RestoreKnowledgeRevision(c *gin.Context) {
+	var req RestoreKnowledgeRevisionRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	if err := v1.NewKnowledgeLogic(c, s.Core).RestoreRevision(spaceID, req.ID, req.Version); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, nil)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type ListTagsResponse struct {
+	List []types.KnowledgeTag `json:"list"`
+}
+
+// ListTags 获取空间内的标签及使用数量
+func (s *HttpSrv) ListTags(c *gin.Context) {
+	spaceID, _ := v1.InjectSpaceID(c)
+	list, err := v1.NewKnowledgeLogic(c, s.Core).ListSpaceTags(spaceID)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, ListTagsResponse{
+		List: list,
+	})
+}
+
+type UpdateTagsResponse struct {
+	Affected int64 `json:"affected"`
+}
+
+type RenameTagRequest struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
+
+// RenameTag 重命名标签
+func (s *HttpSrv) RenameTag(c *gin.Context) {
+	var req RenameTagRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	affected, err := v1.NewKnowledgeLogic(c, s.Core).RenameTag(spaceID, req.From, req.To)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, UpdateTagsResponse{
+		Affected: affected,
+	})
+}
+
+type MergeTagsRequest struct {
+	Sources []string `json:"sources" binding:"required,min=1"`
+	Target  string   `json:"target" binding:"required"`
+}
+
+// MergeTags 将多个标签合并为一个
+func (s *HttpSrv) MergeTags(c *gin.Context) {
+	var req MergeTagsRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	affected, err := v1.NewKnowledgeLogic(c, s.Core).MergeTags(spaceID, req.Sources, req.Target)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, UpdateTagsResponse{
+		Affected: affected,
+	})
+}
+
+type DeleteTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1"`
+}
+
+// DeleteTags 批量删除标签
+func (s *HttpSrv) DeleteTags(c *gin.Context) {
+	var req DeleteTagsRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	affected, err := v1.NewKnowledgeLogic(c, s.Core).DeleteTags(spaceID, req.Tags)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, UpdateTagsResponse{
+		Affected: affected,
+	})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+type ListKnowledgeTrashRequest struct {
+	Keywords string `json:"keywords" form:"keywords"`
+	Page     uint64 `json:"page" form:"page" binding:"required"`
+	PageSize uint64 `json:"pagesize" form:"pagesize" binding:"required,lte=50"`
+}
+
+// ListKnowledgeTrash 空间回收站中的知识列表
+func (s *HttpSrv) ListKnowledgeTrash(c *gin.Context) {
+	var req ListKnowledgeTrashRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	list, total, err := v1.NewKnowledgeLogic(c, s.Core).ListTrash(spaceID, req.Keywords, req.Page, req.PageSize)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, ListKnowledgeResponse{
+		List: lo.Map(list, func(item *types.Knowledge, _ int) *types.KnowledgeResponse {
+			return KnowledgeToKnowledgeResponseLite(item)
+		}),
+		Total: total,
+	})
+}
+
+type KnowledgeTrashRequest struct {
+	ID string `json:"id" binding:"required"`
+}
+
+// RestoreKnowledgeFromTrash 从回收站恢复知识
+func (s *HttpSrv) RestoreKnowledgeFromTrash(c *gin.Context) {
+	var req KnowledgeTrashRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	if err := v1.NewKnowledgeLogic(c, s.Core).RestoreFromTrash(spaceID, req.ID); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, nil)
+}
+
+// PurgeKnowledgeFromTrash 彻底删除回收站中的知识
+func (s *HttpSrv) PurgeKnowledgeFromTrash(c *gin.Context) {
+	var req KnowledgeTrashRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	if err := v1.NewKnowledgeLogic(c, s.Core).PurgeFromTrash(spaceID, req.ID); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, nil)
+}
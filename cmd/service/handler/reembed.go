@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// StartReembedRequest 启动向量重建任务请求
+type StartReembedRequest struct {
+	BatchSize int   `json:"batch_size" binding:"omitempty,gte=1,lte=500"` // 每批处理的知识数量
+	Interval  int64 `json:"interval" binding:"omitempty,gte=0"`           // 每批之间的间隔(毫秒)
+}
+
+// StartReembedJob 使用当前 embedding 模型重建全部知识向量，存在未完成任务时继续执行
+func (s *HttpSrv) StartReembedJob(c *gin.Context) {
+	var req StartReembedRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	job, err := v1.NewReembedLogic(c, s.Core).StartJob(req.BatchSize, req.Interval)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, job)
+}
+
+// GetReembedJob 获取最近一次向量重建任务的进度
+func (s *HttpSrv) GetReembedJob(c *gin.Context) {
+	job, err := v1.NewReembedLogic(c, s.Core).GetLatestJob()
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, job)
+}
+
+// PauseReembedJob 暂停向量重建任务
+func (s *HttpSrv) PauseReembedJob(c *gin.Context) {
+	if err := v1.NewReembedLogic(c, s.Core).PauseJob(c.Param("id")); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, nil)
+}
+
+// CancelReembedJob 取消向量重建任务
+func (s *HttpSrv) CancelReembedJob(c *gin.Context) {
+	if err := v1.NewReembedLogic(c, s.Core).CancelJob(c.Param("id")); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, nil)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// GetSpaceRetrievalProfile 获取空间的检索参数
+func (s *HttpSrv) GetSpaceRetrievalProfile(c *gin.Context) {
+	spaceID, _ := v1.InjectSpaceID(c)
+	profile, err := v1.NewSpaceLogic(c, s.Core).GetRetrievalProfile(spaceID)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, profile)
+}
+
+type UpdateSpaceRetrievalProfileRequest struct {
+	Profile *types.RetrievalProfileConfig `json:"profile"`
+}
+
+// UpdateSpaceRetrievalProfile 更新空间的检索参数，profile 为空时恢复使用系统默认参数
+func (s *HttpSrv) UpdateSpaceRetrievalProfile(c *gin.Context) {
+	var (
+		err error
+		req UpdateSpaceRetrievalProfileRequest
+	)
+	if err = utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	logic := v1.NewSpaceLogic(c, s.Core)
+	if err = logic.UpdateRetrievalProfile(spaceID, req.Profile); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	profile, err := logic.GetRetrievalProfile(spaceID)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, profile)
+}
+
+// GetSystemRetrievalProfile 获取系统默认的检索参数
+func (s *HttpSrv) GetSystemRetrievalProfile(c *gin.Context) {
+	response.APISuccess(c, v1.NewCustomConfigLogic(c, s.Core).GetSystemRetrievalProfile())
+}
+
+// UpdateSystemRetrievalProfile 更新系统默认的检索参数
+func (s *HttpSrv) UpdateSystemRetrievalProfile(c *gin.Context) {
+	var (
+		err error
+		req types.RetrievalProfileConfig
+	)
+	if err = utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	profile, err := v1.NewCustomConfigLogic(c, s.Core).UpdateSystemRetrievalProfile(req)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, profile)
+}
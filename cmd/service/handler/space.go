@@ -133,6 +133,36 @@ func (s *HttpSrv) UpdateSpace(c *gin.Context) {
 	response.APISuccess(c, nil)
 }
 
+func (s *HttpSrv) GetSpaceSettings(c *gin.Context) {
+	spaceID, _ := v1.InjectSpaceID(c)
+	settings, err := v1.NewSpaceLogic(c, s.Core).GetSpaceSettings(spaceID)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, settings)
+}
+
+func (s *HttpSrv) UpdateSpaceSettings(c *gin.Context) {
+	var (
+		err error
+		req types.SpaceSettings
+	)
+	if err = utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	if err = v1.NewSpaceLogic(c, s.Core).UpdateSpaceSettings(spaceID, req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, nil)
+}
+
 func (s *HttpSrv) DeleteUserSpace(c *gin.Context) {
 	spaceID, _ := v1.InjectSpaceID(c)
 	err := v1.NewSpaceLogic(c, s.Core).DeleteUserSpace(spaceID)
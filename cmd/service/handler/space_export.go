@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/response"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// ExportSpace 创建空间导出任务，在后台生成备份归档，完成后通过 Centrifuge 推送
+func (s *HttpSrv) ExportSpace(c *gin.Context) {
+	spaceID, _ := v1.InjectSpaceID(c)
+	task, err := v1.NewSpaceExportLogic(c, s.Core).StartExport(spaceID)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, task)
+}
+
+type ListSpaceExportsRequest struct {
+	Page     uint64 `json:"page" form:"page" binding:"required"`
+	Pagesize uint64 `json:"pagesize" form:"pagesize" binding:"required,lte=50"`
+}
+
+type ListSpaceExportsResponse struct {
+	List  []types.SpaceExport `json:"list"`
+	Total int64               `json:"total"`
+}
+
+// ListSpaceExports 分页获取空间下的导出任务
+func (s *HttpSrv) ListSpaceExports(c *gin.Context) {
+	var req ListSpaceExportsRequest
+	if err := utils.BindArgsWithGin(c, &req); err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	spaceID, _ := v1.InjectSpaceID(c)
+	list, total, err := v1.NewSpaceExportLogic(c, s.Core).ListExports(spaceID, req.Page, req.Pagesize)
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, ListSpaceExportsResponse{
+		List:  list,
+		Total: total,
+	})
+}
+
+// GetSpaceExport 获取导出任务，任务完成时返回归档的下载地址
+func (s *HttpSrv) GetSpaceExport(c *gin.Context) {
+	spaceID, _ := v1.InjectSpaceID(c)
+	task, err := v1.NewSpaceExportLogic(c, s.Core).GetExport(spaceID, c.Param("id"))
+	if err != nil {
+		response.APIError(c, err)
+		return
+	}
+
+	response.APISuccess(c, task)
+}
@@ -13,6 +13,7 @@ type CreateFileChunkTaskRequest struct {
 	FileUrl  string `json:"file_url" binding:"required"`
 	Resource string `json:"resource" binding:"required"`
 	MetaInfo string `json:"meta_info"`
+	Chunker  string `json:"chunker"`
 }
 
 func (s *HttpSrv) CreateFileChunkTask(c *gin.Context) {
@@ -27,7 +28,7 @@ func (s *HttpSrv) CreateFileChunkTask(c *gin.Context) {
 	}
 
 	spaceID, _ := v1.InjectSpaceID(c)
-	if err = v1.NewAIFileDisposeLogic(c, s.Core).CreateLongContentTask(spaceID, req.Resource, req.MetaInfo, req.FileName, req.FileUrl); err != nil {
+	if err = v1.NewAIFileDisposeLogic(c, s.Core).CreateLongContentTask(spaceID, req.Resource, req.MetaInfo, req.FileName, req.FileUrl, req.Chunker); err != nil {
 		response.APIError(c, err)
 		return
 	}
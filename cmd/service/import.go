@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/importer"
+	"github.com/quka-ai/quka-ai/pkg/plugins"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+type ImportOptions struct {
+	Options
+	SpaceID    string
+	UserID     string
+	File       string
+	Resource   string
+	FolderMode string
+	Fetch      bool
+}
+
+func (o *ImportOptions) addImportFlags(cmd *cobra.Command) {
+	o.AddFlags(cmd.Flags())
+	cmd.Flags().StringVar(&o.SpaceID, "space", "", "space id to import into")
+	cmd.Flags().StringVar(&o.UserID, "user", "", "owner of the imported knowledge, defaults to the space chief")
+	cmd.Flags().StringVar(&o.File, "file", "", "file to import")
+	cmd.Flags().StringVar(&o.Resource, "resource", "", "default resource id of the imported knowledge")
+	cmd.Flags().StringVar(&o.FolderMode, "folder-mode", "", "how folders are mapped: empty to ignore, resource or tags")
+	cmd.MarkFlagRequired("space")
+	cmd.MarkFlagRequired("file")
+}
+
+// NewImportCommand 在前台将外部笔记工具导出的归档导入空间
+func NewImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "import notes exported from other tools into a space",
+	}
+
+	obsidian := &ImportOptions{}
+	obsidianCmd := &cobra.Command{
+		Use:   "obsidian",
+		Short: "import a zipped Obsidian vault or markdown folder",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunObsidianImport(obsidian)
+		},
+	}
+	obsidian.addImportFlags(obsidianCmd)
+
+	notion := &ImportOptions{}
+	notionCmd := &cobra.Command{
+		Use:   "notion",
+		Short: "import a Notion \"Markdown & CSV\" export archive",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunNotionImport(notion)
+		},
+	}
+	notion.addImportFlags(notionCmd)
+
+	bookmarks := &ImportOptions{}
+	bookmarksCmd := &cobra.Command{
+		Use:   "bookmarks",
+		Short: "import browser bookmarks (Netscape HTML) or Pocket / Instapaper CSV exports",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunBookmarkImport(bookmarks)
+		},
+	}
+	bookmarks.addImportFlags(bookmarksCmd)
+	bookmarksCmd.Flags().BoolVar(&bookmarks.Fetch, "fetch", false, "fetch page content with the reader in the background process")
+
+	restore := &ImportOptions{}
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "restore a space backup archive into an existing space, or into a new space when --space is empty",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunBackupImport(restore)
+		},
+	}
+	restore.AddFlags(backupCmd.Flags())
+	backupCmd.Flags().StringVar(&restore.SpaceID, "space", "", "space id to restore into, a new space is created when empty")
+	backupCmd.Flags().StringVar(&restore.UserID, "user", "", "user who restores the backup, required when creating a new space")
+	backupCmd.Flags().StringVar(&restore.File, "file", "", "backup archive exported from a space")
+	backupCmd.MarkFlagRequired("file")
+
+	cmd.AddCommand(obsidianCmd, notionCmd, bookmarksCmd, backupCmd)
+	return cmd
+}
+
+func RunObsidianImport(opts *ImportOptions) error {
+	return runVaultImport(opts, (*v1.ImportLogic).PrepareObsidianImport)
+}
+
+func RunNotionImport(opts *ImportOptions) error {
+	return runVaultImport(opts, (*v1.ImportLogic).PrepareNotionImport)
+}
+
+// RunBookmarkImport 在前台导入书签，开启抓取时网页内容由 process 服务在后台抓取
+func RunBookmarkImport(opts *ImportOptions) error {
+	data, err := os.ReadFile(opts.File)
+	if err != nil {
+		return fmt.Errorf("failed to read bookmark file: %w", err)
+	}
+
+	app := core.MustSetupCore(core.MustLoadBaseConfig(opts.ConfigPath))
+	plugins.Setup(app.InstallPlugins, opts.Init)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	userID, err := resolveImportUser(ctx, app, opts)
+	if err != nil {
+		return err
+	}
+
+	task, bookmarks, err := v1.NewImportLogic(ctx, app).PrepareBookmarkImport(opts.SpaceID, userID, filepath.Base(opts.File), v1.ImportOptions{
+		Resource:   opts.Resource,
+		FolderMode: opts.FolderMode,
+		Fetch:      opts.Fetch,
+	}, data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Import task %s started, bookmarks: %d\n", task.ID, task.Total)
+	return process.NewBookmarkImportTask(app, task, bookmarks, opts.Fetch).OnProgress(printImportProgress).Run(ctx)
+}
+
+// RunBackupImport 在前台恢复空间备份归档，未指定空间时为 --user 创建新空间
+func RunBackupImport(opts *ImportOptions) error {
+	if opts.SpaceID == "" && opts.UserID == "" {
+		return fmt.Errorf("--user is required when restoring into a new space")
+	}
+
+	data, err := os.ReadFile(opts.File)
+	if err != nil {
+		return fmt.Errorf("failed to read backup archive: %w", err)
+	}
+
+	app := core.MustSetupCore(core.MustLoadBaseConfig(opts.ConfigPath))
+	plugins.Setup(app.InstallPlugins, opts.Init)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	userID := opts.UserID
+	if opts.SpaceID != "" {
+		if userID, err = resolveImportUser(ctx, app, opts); err != nil {
+			return err
+		}
+	}
+
+	task, reader, err := v1.NewImportLogic(ctx, app).PrepareBackupImport(opts.SpaceID, userID, filepath.Base(opts.File), data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restore task %s started, space: %s, items: %d\n", task.ID, task.SpaceID, task.Total)
+	return process.NewBackupRestoreTask(app, task, reader).OnProgress(printImportProgress).Run(ctx)
+}
+
+type prepareVaultImportFunc func(l *v1.ImportLogic, spaceID, userID, fileName string, opts v1.ImportOptions, data []byte) (*types.ImportTask, *importer.Vault, error)
+
+// runVaultImport 在前台执行 markdown 归档导入并打印进度
+func runVaultImport(opts *ImportOptions, prepare prepareVaultImportFunc) error {
+	data, err := os.ReadFile(opts.File)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	app := core.MustSetupCore(core.MustLoadBaseConfig(opts.ConfigPath))
+	plugins.Setup(app.InstallPlugins, opts.Init)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	userID, err := resolveImportUser(ctx, app, opts)
+	if err != nil {
+		return err
+	}
+
+	task, vault, err := prepare(v1.NewImportLogic(ctx, app), opts.SpaceID, userID, filepath.Base(opts.File), v1.ImportOptions{
+		Resource:   opts.Resource,
+		FolderMode: opts.FolderMode,
+	}, data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Import task %s started, source: %s, notes: %d\n", task.ID, task.Source, task.Total)
+	return process.NewVaultImportTask(app, task, vault).OnProgress(printImportProgress).Run(ctx)
+}
+
+// resolveImportUser 校验导入用户是否为空间成员，未指定时使用空间创建者
+func resolveImportUser(ctx context.Context, app *core.Core, opts *ImportOptions) (string, error) {
+	if opts.UserID == "" {
+		chief, err := app.Store().UserSpaceStore().GetSpaceChief(ctx, opts.SpaceID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get space chief: %w", err)
+		}
+		return chief.UserID, nil
+	}
+
+	if _, err := app.Store().UserSpaceStore().GetUserSpaceRole(ctx, opts.UserID, opts.SpaceID); err != nil {
+		return "", fmt.Errorf("user %s is not a member of space %s: %w", opts.UserID, opts.SpaceID, err)
+	}
+	return opts.UserID, nil
+}
+
+func printImportProgress(task types.ImportTask) {
+	fmt.Printf("[%s] processed %d/%d, failed %d\n", task.Status, task.Processed, task.Total, task.Failed)
+	if task.Error != "" {
+		fmt.Printf("error: %s\n", task.Error)
+	}
+}
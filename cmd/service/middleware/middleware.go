@@ -282,7 +282,7 @@ func VerifySpaceIDPermission(core *core.Core, permission string) gin.HandlerFunc
 
 		claims, _ := v1.InjectTokenClaim(ctx)
 
-		result, err := core.Store().UserSpaceStore().GetUserSpaceRole(ctx, claims.User, spaceID)
+		result, err := core.GetUserSpaceRole(ctx, claims.User, spaceID)
 		if err != nil && err != sql.ErrNoRows {
 			response.APIError(ctx, errors.New("middleware.VerifySpaceIDPermission.UserSpaceStore.GetUserSpaceRole", i18n.ERROR_INTERNAL, err))
 			return
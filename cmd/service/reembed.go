@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/plugins"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+type ReembedOptions struct {
+	Options
+	BatchSize int
+	Interval  int64
+}
+
+// NewReembedCommand 切换 embedding 模型后，在前台使用新模型重建全部知识向量
+// 中断(Ctrl+C)后任务会被暂停，再次执行时从中断处继续
+func NewReembedCommand() *cobra.Command {
+	opts := &ReembedOptions{}
+	cmd := &cobra.Command{
+		Use:   "reembed",
+		Short: "re-embed all knowledge with the current embedding model",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunReembed(opts)
+		},
+	}
+	opts.AddFlags(cmd.Flags())
+	cmd.Flags().IntVar(&opts.BatchSize, "batch-size", process.REEMBED_DEFAULT_BATCH_SIZE, "knowledge count per batch")
+	cmd.Flags().Int64Var(&opts.Interval, "interval", 0, "sleep milliseconds between batches")
+	return cmd
+}
+
+func RunReembed(opts *ReembedOptions) error {
+	app := core.MustSetupCore(core.MustLoadBaseConfig(opts.ConfigPath))
+	plugins.Setup(app.InstallPlugins, opts.Init)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	job, err := v1.NewReembedLogic(ctx, app).PrepareJob(opts.BatchSize, opts.Interval)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Reembed job %s started, model: %s, total: %d, processed: %d\n", job.ID, job.Model, job.Total, job.Processed)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		cancel()
+	}()
+
+	err = process.NewReembedTask(app, job.ID).OnProgress(func(job types.ReembedJob) {
+		fmt.Printf("[%s] processed %d/%d, failed %d\n", job.Status, job.Processed, job.Total, job.Failed)
+	}).Run(ctx)
+
+	if ctx.Err() != nil {
+		// 被中断时暂停任务，以便下次继续
+		if err := app.Store().ReembedJobStore().UpdateStatus(context.Background(), job.ID, types.REEMBED_JOB_STATUS_PAUSED, ""); err != nil {
+			return err
+		}
+		fmt.Println("Reembed job paused, run the command again to resume")
+		return nil
+	}
+	return err
+}
@@ -159,6 +159,9 @@ func setupHttpRouter(s *handler.HttpSrv) {
 			user.POST("/secret/token", s.CreateAccessToken)
 			user.GET("/secret/tokens", s.GetUserAccessTokens)
 			user.DELETE("/secret/tokens", s.DeleteAccessTokens)
+			// 跨空间检索，仅包含用户拥有查看权限的空间
+			user.GET("/knowledge/search", userLimit("knowledge_list"), s.SearchAllSpaces)
+			user.POST("/knowledge/query", userLimit("chat_message"), s.QueryAllSpaces)
 		}
 
 		// space 相关路由
@@ -168,6 +171,7 @@ func setupHttpRouter(s *handler.HttpSrv) {
 			space.DELETE("/:spaceid/leave", middleware.VerifySpaceIDPermission(s.Core, srv.PermissionView), s.LeaveSpace)
 
 			space.POST("", userLimit("modify_space"), s.CreateUserSpace)
+			space.POST("/restore", userLimit("modify_space"), s.RestoreSpace) // 上传备份归档恢复为新空间
 
 			editorSpace := space.Group("/:spaceid").Use(middleware.VerifySpaceIDPermission(s.Core, srv.PermissionEdit))
 			editorSpace.POST("/task/file-chunk", aiLimit("file_chunk", core.WithLimit(10), core.WithRange(time.Hour)), s.CreateFileChunkTask)
@@ -179,11 +183,18 @@ func setupHttpRouter(s *handler.HttpSrv) {
 			space.Use(middleware.VerifySpaceIDPermission(s.Core, srv.PermissionAdmin))
 			space.DELETE("/:spaceid", s.DeleteUserSpace)
 			space.PUT("/:spaceid", userLimit("modify_space"), s.UpdateSpace)
+			space.GET("/:spaceid/settings", s.GetSpaceSettings)
+			space.PUT("/:spaceid/settings", userLimit("modify_space"), s.UpdateSpaceSettings)
+			space.GET("/:spaceid/retrieval/profile", s.GetSpaceRetrievalProfile)
+			space.PUT("/:spaceid/retrieval/profile", userLimit("modify_space"), s.UpdateSpaceRetrievalProfile)
 			space.PUT("/:spaceid/user/role", userLimit("modify_space"), s.SetUserSpaceRole)
 			space.GET("/:spaceid/users", s.ListSpaceUsers)
 			space.GET("/:spaceid/application/users", s.GetSpaceApplicationWaitingList)
 			space.PUT("/:spaceid/application/handler", s.HandlerSpaceApplication)
 			space.DELETE("/:spaceid/user/:userid", s.RemoveSpaceUser)
+			space.POST("/:spaceid/export", userLimit("modify_space"), s.ExportSpace) // 导出空间备份归档
+			space.GET("/:spaceid/export/tasks", s.ListSpaceExports)
+			space.GET("/:spaceid/export/tasks/:id", s.GetSpaceExport)
 			// share
 			space.POST("/:spaceid/knowledge/share", middleware.PaymentRequired, s.CreateKnowledgeShareToken)
 			space.POST("/:spaceid/session/share", middleware.PaymentRequired, s.CreateSessionShareToken)
@@ -215,7 +226,17 @@ func setupHttpRouter(s *handler.HttpSrv) {
 				viewScope.GET("/chunk/list", spaceLimit("knowledge_list"), s.ListContentTask)
 				viewScope.GET("/chunk/knowledge", spaceLimit("knowledge_list"), s.GetTaskKnowledge)
 				viewScope.POST("/query", spaceLimit("chat_message"), s.Query)
+				viewScope.GET("/search", spaceLimit("knowledge_list"), s.SearchKnowledge)
 				viewScope.GET("/time/list", spaceLimit("knowledge_list"), s.GetDateCreatedKnowledge)
+				viewScope.GET("/duplicates", spaceLimit("knowledge_list"), s.ListKnowledgeDuplicates)
+				viewScope.GET("/related", spaceLimit("knowledge_list"), s.ListRelatedKnowledge)
+				viewScope.GET("/links", spaceLimit("knowledge_list"), s.GetKnowledgeLinks)
+				viewScope.GET("/entities", spaceLimit("knowledge_list"), s.ListKnowledgeEntities)
+				viewScope.GET("/entity", spaceLimit("knowledge_list"), s.GetKnowledgeEntity)
+				viewScope.GET("/revisions", spaceLimit("knowledge_list"), s.ListKnowledgeRevisions)
+				viewScope.GET("/revision", spaceLimit("knowledge_list"), s.GetKnowledgeRevision)
+				viewScope.GET("/revisions/diff", spaceLimit("knowledge_list"), s.DiffKnowledgeRevisions)
+				viewScope.GET("/trash", spaceLimit("knowledge_list"), s.ListKnowledgeTrash)
 			}
 
 			editScope := knowledge.Group("")
@@ -224,9 +245,37 @@ func setupHttpRouter(s *handler.HttpSrv) {
 				editScope.POST("", aiLimit("create_knowledge"), s.CreateKnowledge)
 				editScope.PUT("", aiLimit("create_knowledge"), s.UpdateKnowledge)
 				editScope.DELETE("", s.DeleteKnowledge)
+				editScope.POST("/duplicates/resolve", s.ResolveKnowledgeDuplicates)
+				editScope.POST("/revisions/restore", aiLimit("create_knowledge"), s.RestoreKnowledgeRevision)
+				editScope.POST("/trash/restore", s.RestoreKnowledgeFromTrash)
+				editScope.DELETE("/trash", s.PurgeKnowledgeFromTrash)
 			}
 		}
 
+		imports := authed.Group("/:spaceid/import")
+		{
+			imports.Use(middleware.VerifySpaceIDPermission(s.Core, srv.PermissionEdit))
+			imports.POST("/obsidian", spaceLimit("knowledge_modify"), s.ImportObsidian)   // 上传 zip 导入 Obsidian vault / markdown 文件夹
+			imports.POST("/notion", spaceLimit("knowledge_modify"), s.ImportNotion)       // 上传 Notion 的 Markdown & CSV 导出
+			imports.POST("/bookmarks", spaceLimit("knowledge_modify"), s.ImportBookmarks) // 上传书签 HTML 或稍后读 CSV
+
+			imports.POST("/backup", middleware.VerifySpaceIDPermission(s.Core, srv.PermissionAdmin), spaceLimit("knowledge_modify"), s.ImportBackup) // 上传空间备份归档恢复到当前空间
+			imports.GET("/tasks", s.ListImportTasks)
+			imports.GET("/tasks/:id", s.GetImportTask)
+			imports.GET("/tasks/:id/items", s.ListImportTaskItems) // 每个文件的导入结果
+		}
+
+		tags := authed.Group("/:spaceid/tags")
+		{
+			tags.Use(middleware.VerifySpaceIDPermission(s.Core, srv.PermissionView))
+			tags.GET("", s.ListTags) // 获取标签及使用数量
+
+			tags.Use(middleware.VerifySpaceIDPermission(s.Core, srv.PermissionEdit), spaceLimit("knowledge_modify"))
+			tags.PUT("", s.RenameTag)        // 重命名标签
+			tags.POST("/merge", s.MergeTags) // 合并标签
+			tags.DELETE("", s.DeleteTags)    // 批量删除标签
+		}
+
 		rss := authed.Group("/:spaceid/rss")
 		{
 			rss.Use(middleware.VerifySpaceIDPermission(s.Core, srv.PermissionView))
@@ -341,6 +390,22 @@ func setupHttpRouter(s *handler.HttpSrv) {
 				aiSystem.GET("/usage", s.GetAIUsage)       // 获取AI使用配置
 			}
 
+			// 知识检索参数的系统默认值
+			retrieval := admin.Group("/retrieval/profile")
+			{
+				retrieval.GET("", s.GetSystemRetrievalProfile)    // 获取系统默认检索参数
+				retrieval.PUT("", s.UpdateSystemRetrievalProfile) // 更新系统默认检索参数
+			}
+
+			// 向量重建（切换 embedding 模型后使用）
+			reembed := admin.Group("/embedding/reindex")
+			{
+				reembed.POST("", s.StartReembedJob)             // 启动或继续重建任务
+				reembed.GET("", s.GetReembedJob)                // 获取最近一次任务进度
+				reembed.POST("/:id/pause", s.PauseReembedJob)   // 暂停任务
+				reembed.POST("/:id/cancel", s.CancelReembedJob) // 取消任务
+			}
+
 			// 用户管理
 			users := admin.Group("/users")
 			{
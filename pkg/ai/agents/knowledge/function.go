@@ -22,6 +22,7 @@ const (
 	FUNCTION_NAME_CREATE_KNOWLEDGE    = "CreateKnowledge"
 	FUNCTION_NAME_UPDATE_KNOWLEDGE    = "UpdateKnowledge"
 	FUNCTION_NAME_LIST_USER_RESOURCES = "ListUserResources"
+	FUNCTION_NAME_RELATED_KNOWLEDGE   = "ListRelatedKnowledge"
 )
 
 // KnowledgeLogicFunctions 知识逻辑层函数接口,用于依赖注入
@@ -29,6 +30,7 @@ type KnowledgeLogicFunctions struct {
 	InsertContentAsyncWithSource func(spaceID, resource string, kind types.KnowledgeKind, content types.KnowledgeContent, contentType types.KnowledgeContentType, source types.KnowledgeSource, sourceRef string) (string, error)
 	GetKnowledge                 func(spaceID, id string) (*types.Knowledge, error)
 	Update                       func(spaceID, id string, args types.UpdateKnowledgeArgs) error
+	ListRelatedKnowledges        func(spaceID, id string, limit int) ([]types.RelatedKnowledge, error)
 }
 
 // ResourceLogicFunctions 资源逻辑层函数接口,用于依赖注入
@@ -48,6 +50,7 @@ func GetKnowledgeToolsWithLogic(
 		NewCreateKnowledgeTool(core, spaceID, sessionID, userID, knowledgeFuncs, resourceFuncs),
 		NewUpdateKnowledgeTool(core, spaceID, userID, knowledgeFuncs, resourceFuncs),
 		NewListUserResourcesTool(core, userID, resourceFuncs),
+		NewRelatedKnowledgeTool(spaceID, knowledgeFuncs),
 	}
 }
 
@@ -0,0 +1,88 @@
+package knowledge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RelatedKnowledgeTool 查找与指定知识语义相近的知识
+type RelatedKnowledgeTool struct {
+	spaceID        string
+	knowledgeFuncs KnowledgeLogicFunctions
+}
+
+func NewRelatedKnowledgeTool(spaceID string, knowledgeFuncs KnowledgeLogicFunctions) *RelatedKnowledgeTool {
+	return &RelatedKnowledgeTool{
+		spaceID:        spaceID,
+		knowledgeFuncs: knowledgeFuncs,
+	}
+}
+
+var _ tool.InvokableTool = (*RelatedKnowledgeTool)(nil)
+
+// Info 实现 BaseTool 接口
+func (t *RelatedKnowledgeTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	params := map[string]*schema.ParameterInfo{
+		"id": {
+			Type:     schema.String,
+			Desc:     "知识 ID",
+			Required: true,
+		},
+		"limit": {
+			Type:     schema.Integer,
+			Desc:     "返回的相关知识数量，默认 10，最多 50",
+			Required: false,
+		},
+	}
+
+	return &schema.ToolInfo{
+		Name:        FUNCTION_NAME_RELATED_KNOWLEDGE,
+		Desc:        "根据知识 ID 查找当前空间内语义相近的其他知识，按相似度从高到低返回知识 ID、标题、标签及相似度。适用于用户希望了解与某条知识相关的内容时使用。",
+		ParamsOneOf: schema.NewParamsOneOfByParams(params),
+	}, nil
+}
+
+type RelatedKnowledgeParams struct {
+	ID    string `json:"id"`
+	Limit int    `json:"limit"`
+}
+
+// InvokableRun 实现 InvokableTool 接口
+func (t *RelatedKnowledgeTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	var params RelatedKnowledgeParams
+	if err := json.Unmarshal([]byte(argumentsInJSON), &params); err != nil {
+		return "Invalid parameters. Please check your input format.", nil
+	}
+
+	if params.ID == "" {
+		return "Error: knowledge ID is required", nil
+	}
+
+	list, err := t.knowledgeFuncs.ListRelatedKnowledges(t.spaceID, params.ID, params.Limit)
+	if err != nil {
+		return fmt.Sprintf("Failed to find related knowledge of %s: %s", params.ID, err.Error()), nil
+	}
+
+	if len(list) == 0 {
+		return "No related knowledge found.", nil
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("Related Knowledge:\n\n")
+	sb.WriteString("| ID | Title | Tags | Similarity |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	for _, v := range list {
+		tags := "-"
+		if len(v.Tags) > 0 {
+			tags = strings.Join(v.Tags, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %.4f |\n", v.ID, v.Title, tags, v.Similarity))
+	}
+
+	return sb.String(), nil
+}
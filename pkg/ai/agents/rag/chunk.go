@@ -0,0 +1,211 @@
+package rag
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// ExpandKnowledgeChunks 将命中的长文分片与其前后相邻的分片合并为一个段落(small-to-big)
+// 同一长文中窗口重叠的命中分片会合并到排名最靠前的分片中，其余分片从结果中移除
+// 扩展数量由 profile.ChunkExpansion 控制，小于等于 0 时不做处理
+func ExpandKnowledgeChunks(ctx context.Context, core *core.Core, knowledges []*types.Knowledge, profile types.RetrievalProfile) ([]*types.Knowledge, error) {
+	if profile.ChunkExpansion <= 0 || len(knowledges) == 0 {
+		return knowledges, nil
+	}
+
+	var chunkIDs []string
+	for _, v := range knowledges {
+		if v.Kind == types.KNOWLEDGE_KIND_CHUNK {
+			chunkIDs = append(chunkIDs, v.ID)
+		}
+	}
+	if len(chunkIDs) == 0 {
+		return knowledges, nil
+	}
+
+	rels, err := core.Store().KnowledgeRelMetaStore().ListKnowledgesMeta(ctx, chunkIDs)
+	if err != nil && err != sql.ErrNoRows {
+		return knowledges, fmt.Errorf("failed to list knowledge rel meta: %w", err)
+	}
+	if len(rels) == 0 {
+		return knowledges, nil
+	}
+	relMap := lo.SliceToMap(rels, func(item *types.KnowledgeRelMeta) (string, *types.KnowledgeRelMeta) {
+		return item.KnowledgeID, item
+	})
+
+	// 按长文分组命中的分片
+	hits := make(map[string][]int)
+	for _, v := range knowledges {
+		if rel, ok := relMap[v.ID]; ok {
+			hits[rel.MetaID] = append(hits[rel.MetaID], rel.ChunkIndex)
+		}
+	}
+
+	var queries []types.MergeDataQuery
+	for metaID, indexes := range hits {
+		var chunkIndexes []int
+		for _, index := range indexes {
+			for i := index - profile.ChunkExpansion; i <= index+profile.ChunkExpansion; i++ {
+				if i > 0 {
+					chunkIndexes = append(chunkIndexes, i)
+				}
+			}
+		}
+		queries = append(queries, types.MergeDataQuery{
+			MetaID:   metaID,
+			ChunkIDs: lo.Uniq(chunkIndexes),
+		})
+	}
+
+	neighbours, err := core.Store().KnowledgeRelMetaStore().ListRelMetaWithKnowledgeContent(ctx, queries)
+	if err != nil && err != sql.ErrNoRows {
+		return knowledges, fmt.Errorf("failed to list neighbour chunks: %w", err)
+	}
+
+	contents := make(map[string]map[int]string)
+	for _, v := range neighbours {
+		content, err := core.DecryptData(v.Content)
+		if err != nil {
+			return knowledges, fmt.Errorf("failed to decrypt chunk data: %w", err)
+		}
+		if contents[v.MetaID] == nil {
+			contents[v.MetaID] = make(map[int]string)
+		}
+		contents[v.MetaID][v.ChunkIndex] = string(content)
+	}
+	// 命中的分片使用已解密的内容
+	for _, v := range knowledges {
+		if rel, ok := relMap[v.ID]; ok {
+			if contents[rel.MetaID] == nil {
+				contents[rel.MetaID] = make(map[int]string)
+			}
+			contents[rel.MetaID][rel.ChunkIndex] = string(v.Content)
+		}
+	}
+
+	// 计算每个命中分片所属的段落，排名最靠前的命中分片作为段落的载体
+	passages := make(map[string][]int)
+	for metaID, indexes := range hits {
+		tokens := lo.MapValues(contents[metaID], func(content string, _ int) int {
+			return utils.EstimateTokens(content)
+		})
+		for _, group := range SelectChunkNeighbours(indexes, tokens, profile.ChunkExpansion, profile.ChunkBudget) {
+			for _, index := range group {
+				passages[chunkKey(metaID, index)] = group
+			}
+		}
+	}
+
+	var (
+		result []*types.Knowledge
+		merged = make(map[string]struct{})
+	)
+	for _, v := range knowledges {
+		rel, ok := relMap[v.ID]
+		if !ok {
+			result = append(result, v)
+			continue
+		}
+		group := passages[chunkKey(rel.MetaID, rel.ChunkIndex)]
+		if len(group) == 0 {
+			result = append(result, v)
+			continue
+		}
+		key := chunkKey(rel.MetaID, group[0])
+		if _, exist := merged[key]; exist {
+			continue
+		}
+		merged[key] = struct{}{}
+
+		v.Content = types.KnowledgeContent(strings.Join(lo.FilterMap(group, func(index int, _ int) (string, bool) {
+			content, ok := contents[rel.MetaID][index]
+			return content, ok && content != ""
+		}), "\n\n"))
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+func chunkKey(metaID string, index int) string {
+	return fmt.Sprintf("%s:%d", metaID, index)
+}
+
+// SelectChunkNeighbours 为同一长文中命中的分片选取相邻分片，窗口重叠或相邻的命中分片合并为一组
+// 每组先保留命中分片，再按与命中分片的距离由近及远加入相邻分片，直到超出 budget(小于等于 0 时不限制)
+// tokens 为各分片的 token 数，不存在的分片会被忽略，返回的每组分片序号按升序排列
+func SelectChunkNeighbours(hits []int, tokens map[int]int, n, budget int) [][]int {
+	hits = lo.Uniq(hits)
+	sort.Ints(hits)
+
+	var (
+		result [][]int
+		group  []int
+	)
+	flush := func() {
+		if len(group) > 0 {
+			result = append(result, selectChunkGroup(group, tokens, n, budget))
+		}
+		group = nil
+	}
+	for _, v := range hits {
+		if len(group) > 0 && v-n > group[len(group)-1]+n+1 {
+			flush()
+		}
+		group = append(group, v)
+	}
+	flush()
+	return result
+}
+
+func selectChunkGroup(hits []int, tokens map[int]int, n, budget int) []int {
+	distance := func(index int) int {
+		d := -1
+		for _, v := range hits {
+			if diff := max(v-index, index-v); d < 0 || diff < d {
+				d = diff
+			}
+		}
+		return d
+	}
+
+	var (
+		selected   []int
+		candidates []int
+		used       int
+	)
+	for i := hits[0] - n; i <= hits[len(hits)-1]+n; i++ {
+		if _, ok := tokens[i]; !ok {
+			continue
+		}
+		if lo.Contains(hits, i) {
+			selected = append(selected, i)
+			used += tokens[i]
+			continue
+		}
+		candidates = append(candidates, i)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return distance(candidates[i]) < distance(candidates[j])
+	})
+	for _, v := range candidates {
+		if budget > 0 && used+tokens[v] > budget {
+			break
+		}
+		selected = append(selected, v)
+		used += tokens[v]
+	}
+
+	sort.Ints(selected)
+	return selected
+}
@@ -0,0 +1,39 @@
+package rag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectChunkNeighbours(t *testing.T) {
+	tokens := map[int]int{1: 100, 2: 100, 3: 100, 4: 100, 5: 100, 6: 100, 7: 100, 8: 100, 9: 100, 10: 100}
+
+	result := SelectChunkNeighbours([]int{3}, tokens, 1, 0)
+	if !reflect.DeepEqual(result, [][]int{{2, 3, 4}}) {
+		t.Errorf("unexpected neighbours: %v", result)
+	}
+
+	// 窗口相邻的命中分片合并为一组，去除重叠部分
+	result = SelectChunkNeighbours([]int{6, 3}, tokens, 1, 0)
+	if !reflect.DeepEqual(result, [][]int{{2, 3, 4, 5, 6, 7}}) {
+		t.Errorf("expected overlapping windows to be merged, got %v", result)
+	}
+
+	result = SelectChunkNeighbours([]int{2, 9}, tokens, 2, 0)
+	if !reflect.DeepEqual(result, [][]int{{1, 2, 3, 4}, {7, 8, 9, 10}}) {
+		t.Errorf("expected distant hits to be separated, got %v", result)
+	}
+
+	// 预算不足时优先保留距离命中分片较近的分片
+	result = SelectChunkNeighbours([]int{5}, tokens, 3, 350)
+	if !reflect.DeepEqual(result, [][]int{{4, 5, 6}}) {
+		t.Errorf("expected budget to limit neighbours, got %v", result)
+	}
+
+	// 命中分片本身不受预算限制，缺失的分片会被忽略
+	delete(tokens, 4)
+	result = SelectChunkNeighbours([]int{5}, tokens, 1, 50)
+	if !reflect.DeepEqual(result, [][]int{{5}}) {
+		t.Errorf("unexpected neighbours: %v", result)
+	}
+}
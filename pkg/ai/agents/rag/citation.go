@@ -0,0 +1,143 @@
+package rag
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// CITATION_SENTENCE_MAX_LENGTH 引用所在句子保存的最大长度(字符)
+const CITATION_SENTENCE_MAX_LENGTH = 300
+
+var citationMarkerRegexp = regexp.MustCompile(`\[\^(\d+)\]`)
+
+type citationCollectorKey struct{}
+
+// WithCitationCollector 将引用收集器注入 ctx，RAG 工具召回的知识会登记到收集器中
+func WithCitationCollector(ctx context.Context, collector *CitationCollector) context.Context {
+	return context.WithValue(ctx, citationCollectorKey{}, collector)
+}
+
+// CitationCollectorFromContext 获取 ctx 中的引用收集器，不存在时返回 nil
+func CitationCollectorFromContext(ctx context.Context) *CitationCollector {
+	collector, _ := ctx.Value(citationCollectorKey{}).(*CitationCollector)
+	return collector
+}
+
+// CitationCollector 收集一次助手请求中 RAG 工具召回的知识并为其分配引用编号
+// 同一请求中多次调用工具时编号连续递增，同一知识始终使用同一个编号
+type CitationCollector struct {
+	mu      sync.Mutex
+	sources []types.ChatMessageCitation
+	index   map[string]int
+}
+
+func NewCitationCollector() *CitationCollector {
+	return &CitationCollector{
+		index: make(map[string]int),
+	}
+}
+
+// Add 登记召回的知识，并将分配的引用编号写入 docs，refs 用于记录知识命中的分片
+func (c *CitationCollector) Add(docs []*types.PassageInfo, refs []types.QueryResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	chunks := make(map[string][]string)
+	for _, v := range refs {
+		chunks[v.KnowledgeID] = append(chunks[v.KnowledgeID], v.ID)
+	}
+
+	for _, v := range docs {
+		if v.ID == "" {
+			continue
+		}
+		i, ok := c.index[v.ID]
+		if !ok {
+			c.sources = append(c.sources, types.ChatMessageCitation{
+				Index:       len(c.sources) + 1,
+				KnowledgeID: v.ID,
+				Title:       v.Title,
+				ChunkIDs:    []string{},
+			})
+			i = len(c.sources) - 1
+			c.index[v.ID] = i
+		}
+		c.sources[i].ChunkIDs = lo.Uniq(append(c.sources[i].ChunkIDs, chunks[v.ID]...))
+		v.Citation = c.sources[i].Index
+	}
+}
+
+// Resolve 解析回答中的引用标记
+func (c *CitationCollector) Resolve(content string) types.ChatMessageCitations {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ExtractCitations(content, c.sources)
+}
+
+// ExtractCitations 解析回答中的 [^n] 引用标记，返回每个标记所在的句子及其指向的知识
+// sources 中第 n 项对应编号 n+1，编号不存在的标记会被忽略，同一句子重复引用同一编号只记录一次
+func ExtractCitations(content string, sources []types.ChatMessageCitation) types.ChatMessageCitations {
+	var (
+		result types.ChatMessageCitations
+		exists = make(map[string]struct{})
+	)
+	for _, loc := range citationMarkerRegexp.FindAllStringSubmatchIndex(content, -1) {
+		index, err := strconv.Atoi(content[loc[2]:loc[3]])
+		if err != nil || index <= 0 || index > len(sources) {
+			continue
+		}
+
+		sentence := citationSentence(content[:loc[0]])
+		key := strconv.Itoa(index) + ":" + sentence
+		if _, ok := exists[key]; ok {
+			continue
+		}
+		exists[key] = struct{}{}
+
+		citation := sources[index-1]
+		citation.ChunkIDs = append([]string{}, citation.ChunkIDs...)
+		citation.Sentence = sentence
+		result = append(result, citation)
+	}
+	return result
+}
+
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '。', '！', '？', '；', '.', '!', '?', ';', '\n':
+		return true
+	}
+	return false
+}
+
+// citationSentence 返回引用标记之前的最后一个句子，标记前紧邻的句末标点与其他引用标记会被跳过
+func citationSentence(prefix string) string {
+	runes := []rune(citationMarkerRegexp.ReplaceAllString(prefix, ""))
+	end := len(runes)
+	for end > 0 && unicode.IsSpace(runes[end-1]) && runes[end-1] != '\n' {
+		end--
+	}
+	if end > 0 && isSentenceEnd(runes[end-1]) && runes[end-1] != '\n' {
+		end--
+	}
+
+	start := end
+	for start > 0 && !isSentenceEnd(runes[start-1]) {
+		start--
+	}
+
+	sentence := strings.TrimSpace(string(runes[start:end]))
+	sentence = strings.TrimLeft(sentence, "-*#> ")
+	if len([]rune(sentence)) > CITATION_SENTENCE_MAX_LENGTH {
+		sentence = string([]rune(sentence)[len([]rune(sentence))-CITATION_SENTENCE_MAX_LENGTH:])
+	}
+	return sentence
+}
@@ -0,0 +1,82 @@
+package rag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func TestCitationCollector(t *testing.T) {
+	collector := NewCitationCollector()
+
+	first := []*types.PassageInfo{{ID: "k1", Title: "a"}, {ID: "k2", Title: "b"}}
+	collector.Add(first, []types.QueryResult{{ID: "c1", KnowledgeID: "k1"}, {ID: "c2", KnowledgeID: "k2"}})
+	if first[0].Citation != 1 || first[1].Citation != 2 {
+		t.Fatalf("unexpected citation index: %d, %d", first[0].Citation, first[1].Citation)
+	}
+
+	// 再次召回的知识沿用已有编号，新知识继续递增
+	second := []*types.PassageInfo{{ID: "k3", Title: "c"}, {ID: "k1", Title: "a"}}
+	collector.Add(second, []types.QueryResult{{ID: "c3", KnowledgeID: "k1"}})
+	if second[0].Citation != 3 || second[1].Citation != 1 {
+		t.Fatalf("unexpected citation index: %d, %d", second[0].Citation, second[1].Citation)
+	}
+
+	citations := collector.Resolve("第一句话[^1]。第二句话。[^3]")
+	if len(citations) != 2 {
+		t.Fatalf("expected 2 citations, got %+v", citations)
+	}
+	if citations[0].KnowledgeID != "k1" || len(citations[0].ChunkIDs) != 2 {
+		t.Errorf("unexpected first citation: %+v", citations[0])
+	}
+	if citations[1].KnowledgeID != "k3" || citations[1].Sentence != "第二句话" {
+		t.Errorf("unexpected second citation: %+v", citations[1])
+	}
+
+	ctx := WithCitationCollector(context.Background(), collector)
+	if CitationCollectorFromContext(ctx) != collector {
+		t.Error("expected collector from context")
+	}
+	if CitationCollectorFromContext(context.Background()) != nil {
+		t.Error("expected nil collector without injection")
+	}
+}
+
+func TestExtractCitations(t *testing.T) {
+	sources := []types.ChatMessageCitation{
+		{Index: 1, KnowledgeID: "k1", ChunkIDs: []string{"c1"}},
+		{Index: 2, KnowledgeID: "k2"},
+	}
+
+	content := "## 总结\n- 项目在三月上线 [^1][^2]\nThe budget was approved.[^2] Unknown claim [^9]. 重复引用[^1][^1]"
+	citations := ExtractCitations(content, sources)
+
+	expected := []struct {
+		id       string
+		sentence string
+	}{
+		{"k1", "项目在三月上线"},
+		{"k2", "项目在三月上线"},
+		{"k2", "The budget was approved"},
+		{"k1", "重复引用"},
+	}
+	if len(citations) != len(expected) {
+		t.Fatalf("expected %d citations, got %+v", len(expected), citations)
+	}
+	for i, v := range expected {
+		if citations[i].KnowledgeID != v.id || citations[i].Sentence != v.sentence {
+			t.Errorf("citation %d: expected %s %q, got %s %q", i, v.id, v.sentence, citations[i].KnowledgeID, citations[i].Sentence)
+		}
+	}
+
+	// 修改结果不影响来源
+	citations[0].ChunkIDs[0] = "changed"
+	if sources[0].ChunkIDs[0] != "c1" {
+		t.Error("expected sources to be unchanged")
+	}
+
+	if len(ExtractCitations("没有引用", sources)) != 0 {
+		t.Error("expected no citations")
+	}
+}
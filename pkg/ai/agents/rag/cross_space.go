@@ -0,0 +1,116 @@
+package rag
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// InterleavePassages 轮流从各空间的检索结果中取出段落合并为一个列表，按知识 ID 去重
+// 各列表需已按相关度排序，合并后每个空间排名靠前的段落都会靠前，避免单个空间占满结果
+func InterleavePassages(lists ...[]*types.PassageInfo) []*types.PassageInfo {
+	var (
+		result []*types.PassageInfo
+		exists = make(map[string]struct{})
+	)
+	for i := 0; ; i++ {
+		var more bool
+		for _, list := range lists {
+			if i >= len(list) {
+				continue
+			}
+			more = true
+			if _, ok := exists[list[i].ID]; ok {
+				continue
+			}
+			exists[list[i].ID] = struct{}{}
+			result = append(result, list[i])
+		}
+		if !more {
+			return result
+		}
+	}
+}
+
+// RerankPassages 使用重排模型对合并后的跨空间段落统一排序
+// 重排模型不可用时保持原有顺序，未出现在重排结果中的段落追加在末尾
+func RerankPassages(ctx context.Context, core *core.Core, query string, passages []*types.PassageInfo) ([]*types.PassageInfo, *ai.Usage, error) {
+	if len(passages) <= 1 {
+		return passages, nil, nil
+	}
+
+	res, usage, err := core.Srv().AI().Rerank(ctx, query, lo.Map(passages, func(item *types.PassageInfo, _ int) *ai.RerankDoc {
+		return &ai.RerankDoc{
+			ID:      item.ID,
+			Content: item.Title + "\n" + item.Content,
+		}
+	}))
+	if err != nil {
+		if errors.Is(err, errors.ERROR_UNSUPPORTED_FEATURE) {
+			return passages, nil, nil
+		}
+		return passages, usage, err
+	}
+	return SortPassagesByRank(passages, res), usage, nil
+}
+
+// SortPassagesByRank 按重排结果的顺序排列段落，未出现在重排结果中的段落保持原有顺序追加在末尾
+func SortPassagesByRank(passages []*types.PassageInfo, ranks []ai.RankDocItem) []*types.PassageInfo {
+	passageMap := lo.SliceToMap(passages, func(item *types.PassageInfo) (string, *types.PassageInfo) {
+		return item.ID, item
+	})
+
+	result := make([]*types.PassageInfo, 0, len(passages))
+	exists := make(map[string]struct{}, len(passages))
+	for _, v := range ranks {
+		item, ok := passageMap[v.ID]
+		if !ok {
+			continue
+		}
+		if _, ok = exists[v.ID]; ok {
+			continue
+		}
+		exists[v.ID] = struct{}{}
+		result = append(result, item)
+	}
+	for _, v := range passages {
+		if _, ok := exists[v.ID]; !ok {
+			exists[v.ID] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// LimitPassages 按检索参数中的文档数量与 token 预算截取合并后的段落，与 LimitKnowledgeDocs 规则一致
+func LimitPassages(passages []*types.PassageInfo, profile types.RetrievalProfile) []*types.PassageInfo {
+	if profile.MaxDocs > 0 && len(passages) > profile.MaxDocs {
+		passages = passages[:profile.MaxDocs]
+	}
+	if profile.DocsTokenBudget <= 0 {
+		return passages
+	}
+
+	budget := profile.DocsTokenBudget
+	for i, v := range passages {
+		tokens := utils.EstimateTokens(v.Title) + utils.EstimateTokens(v.Content)
+		if tokens <= budget {
+			budget -= tokens
+			continue
+		}
+
+		budget -= utils.EstimateTokens(v.Title)
+		if budget < 200 {
+			return passages[:i]
+		}
+		v.Content = utils.SmartTruncateContent(v.Content, budget)
+		return passages[:i+1]
+	}
+	return passages
+}
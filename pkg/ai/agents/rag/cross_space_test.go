@@ -0,0 +1,63 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func passageIDs(list []*types.PassageInfo) []string {
+	return lo.Map(list, func(item *types.PassageInfo, _ int) string { return item.ID })
+}
+
+func TestInterleavePassages(t *testing.T) {
+	a := []*types.PassageInfo{{ID: "a1", SpaceID: "a"}, {ID: "a2", SpaceID: "a"}, {ID: "a3", SpaceID: "a"}}
+	b := []*types.PassageInfo{{ID: "b1", SpaceID: "b"}}
+	c := []*types.PassageInfo{{ID: "c1", SpaceID: "c"}, {ID: "a2", SpaceID: "c"}}
+
+	result := passageIDs(InterleavePassages(a, b, nil, c))
+	expected := []string{"a1", "b1", "c1", "a2", "a3"}
+	if strings.Join(result, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+
+	if len(InterleavePassages()) != 0 {
+		t.Error("expected empty result without lists")
+	}
+}
+
+func TestSortPassagesByRank(t *testing.T) {
+	passages := []*types.PassageInfo{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}}
+	result := passageIDs(SortPassagesByRank(passages, []ai.RankDocItem{
+		{ID: "3", Score: 0.9},
+		{ID: "unknown", Score: 0.8},
+		{ID: "1", Score: 0.5},
+	}))
+
+	expected := []string{"3", "1", "2", "4"}
+	if strings.Join(result, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestLimitPassages(t *testing.T) {
+	passages := []*types.PassageInfo{
+		{ID: "1", Title: "a", Content: strings.Repeat("知", 300)},
+		{ID: "2", Title: "b", Content: strings.Repeat("识", 300)},
+		{ID: "3", Title: "c", Content: strings.Repeat("库", 300)},
+	}
+
+	result := LimitPassages(passages, types.RetrievalProfile{MaxDocs: 2})
+	if len(result) != 2 {
+		t.Fatalf("expected 2 passages, got %d", len(result))
+	}
+
+	result = LimitPassages(passages, types.RetrievalProfile{MaxDocs: 3, DocsTokenBudget: 1})
+	if len(result) != 0 {
+		t.Errorf("expected no passages with tiny budget, got %d", len(result))
+	}
+}
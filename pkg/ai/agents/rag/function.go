@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
+	"github.com/samber/lo"
 
 	"github.com/quka-ai/quka-ai/app/core"
 	"github.com/quka-ai/quka-ai/app/logic/v1/process"
@@ -75,6 +77,11 @@ func (r *RagTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
 			Desc:     "用户的搜索查询内容。这个参数应该包含用户想要在知识库中搜索的关键词或问题。",
 			Required: true,
 		},
+		"tags": {
+			Type:     schema.Array,
+			Desc:     "可选，仅搜索带有其中任一标签的知识。仅在用户明确要求按标签查找时填写。",
+			ElemInfo: &schema.ParameterInfo{Type: schema.String},
+		},
 	}
 
 	// 创建参数描述
@@ -112,7 +119,8 @@ DO NOT use for:
 func (r *RagTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
 	// 解析输入参数
 	var params struct {
-		Query string `json:"query"`
+		Query string   `json:"query"`
+		Tags  []string `json:"tags"`
 	}
 	if err := json.Unmarshal([]byte(argumentsInJSON), &params); err != nil {
 		return "", fmt.Errorf("failed to parse arguments: %w", err)
@@ -122,7 +130,7 @@ func (r *RagTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts
 	ctx, cancel := context.WithTimeout(ctx, time.Second*30)
 	defer cancel()
 
-	res, err := r.Handler(ctx, params.Query)
+	res, err := r.Handler(ctx, params.Query, params.Tags)
 	if err != nil {
 		return "", err
 	}
@@ -133,25 +141,38 @@ func (r *RagTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts
 
 type RagToolHandlerResult struct {
 	EnhanceQuery string               `json:"enhance_query"`
+	Queries      []string             `json:"queries,omitempty"`
 	Result       string               `json:"result"`
 	Count        int                  `json:"count"`
 	Knowledges   []*types.PassageInfo `json:"knowledges,omitempty"`
 }
 
-func (r *RagTool) Handler(ctx context.Context, query string) (*RagToolHandlerResult, error) {
+// Handler 检索与 query 相关的知识，tags 不为空时仅检索带有其中任一标签的知识
+func (r *RagTool) Handler(ctx context.Context, query string, tags []string) (*RagToolHandlerResult, error) {
 	enhanceResult, _ := EnhanceChatQuery(ctx, r.core, query, r.spaceID, r.sessionID, r.messageSequence)
 
 	// 记录查询增强的使用量
 	if enhanceResult.Usage != nil {
-		process.NewRecordChatUsageRequest(enhanceResult.Model, types.USAGE_SUB_TYPE_QUERY_ENHANCE, r.messageID, enhanceResult.Usage)
+		subType := lo.If(enhanceResult.Strategy == types.QUERY_EXPANSION_HYDE, types.USAGE_SUB_TYPE_QUERY_HYDE).Else(types.USAGE_SUB_TYPE_QUERY_ENHANCE)
+		process.NewRecordChatUsageRequest(enhanceResult.Model, subType, r.messageID, enhanceResult.Usage)
 	}
 
 	// 获取相关知识
-	docs, usages, err := GetQueryRelevanceKnowledges(r.core, r.spaceID, r.userID, enhanceResult.ResultQuery(), nil)
+	docs, usages, err := GetQueriesRelevanceKnowledges(r.core, r.spaceID, r.userID, enhanceResult.RerankQuery(), enhanceResult.SearchQueries(), nil, tags)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get query relevance knowledges: %w", err)
 	}
 
+	// 记录扩展后的查询及召回的文档，便于排查文档被召回的原因
+	if messageID := types.ToolMessageIDFromContext(ctx); messageID != "" {
+		relDocs := lo.Uniq(lo.Map(docs.Docs, func(item *types.PassageInfo, _ int) string {
+			return item.ID
+		}))
+		if err := r.core.Store().ChatMessageExtStore().UpdateRetrieval(ctx, r.spaceID, messageID, enhanceResult.Strategy, enhanceResult.SearchQueries(), relDocs); err != nil {
+			slog.Error("Failed to record expanded queries", slog.String("message_id", messageID), slog.String("error", err.Error()))
+		}
+	}
+
 	// 记录使用量
 	if len(usages) > 0 {
 		for _, v := range usages {
@@ -162,6 +183,11 @@ func (r *RagTool) Handler(ctx context.Context, query string) (*RagToolHandlerRes
 	// 补充会话相关文档
 	SupplementSessionChatDocs(r.core, r.spaceID, r.sessionID, docs)
 
+	// 为召回的知识分配引用编号，回答完成后据此解析回答中的引用标记
+	if collector := CitationCollectorFromContext(ctx); collector != nil {
+		collector.Add(docs.Docs, docs.Refs)
+	}
+
 	// 构建 RAG Tool 响应 - 使用 PromptManager 的 RAG Tool Response 模板
 	lang := r.core.Srv().AI().Lang()
 	ragToolResponseTemplate := r.core.PromptManager().GetRAGToolResponseTemplate(lang, docs.Docs)
@@ -169,6 +195,7 @@ func (r *RagTool) Handler(ctx context.Context, query string) (*RagToolHandlerRes
 
 	return &RagToolHandlerResult{
 		EnhanceQuery: enhanceResult.ResultQuery(),
+		Queries:      enhanceResult.Queries,
 		Result:       ragToolResponse,
 		Count:        len(docs.Docs),
 		Knowledges:   docs.Docs,
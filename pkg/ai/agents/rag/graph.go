@@ -0,0 +1,101 @@
+package rag
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils/editorjs"
+)
+
+// ExpandKnowledgeGraph 根据检索结果的显式引用(双向)及共同提及的实体补充关联知识，追加在检索结果之后
+// 补充数量由 profile.GraphExpansion 控制，小于等于 0 时不做处理
+func ExpandKnowledgeGraph(ctx context.Context, core *core.Core, spaceID, userID string, knowledges []*types.Knowledge, profile types.RetrievalProfile) ([]*types.Knowledge, error) {
+	if profile.GraphExpansion <= 0 || len(knowledges) == 0 {
+		return knowledges, nil
+	}
+
+	ids := lo.Map(knowledges, func(item *types.Knowledge, _ int) string {
+		return item.ID
+	})
+
+	targets, err := core.Store().KnowledgeLinkStore().ListTargetIDs(ctx, spaceID, ids)
+	if err != nil && err != sql.ErrNoRows {
+		return knowledges, fmt.Errorf("failed to list knowledge links: %w", err)
+	}
+	backlinks, err := core.Store().KnowledgeLinkStore().ListBacklinkIDs(ctx, spaceID, ids)
+	if err != nil && err != sql.ErrNoRows {
+		return knowledges, fmt.Errorf("failed to list knowledge backlinks: %w", err)
+	}
+
+	candidates := GraphExpansionCandidates(ids, append(targets, backlinks...), nil, profile.GraphExpansion)
+	if len(candidates) < profile.GraphExpansion {
+		coMentioned, err := core.Store().KnowledgeEntityMentionStore().ListCoMentionedKnowledgeIDs(ctx, spaceID, ids, uint64(profile.GraphExpansion))
+		if err != nil && err != sql.ErrNoRows {
+			return knowledges, fmt.Errorf("failed to list co-mentioned knowledges: %w", err)
+		}
+		candidates = GraphExpansionCandidates(ids, candidates, coMentioned, profile.GraphExpansion)
+	}
+	if len(candidates) == 0 {
+		return knowledges, nil
+	}
+
+	list, err := core.Store().KnowledgeStore().ListKnowledges(ctx, types.GetKnowledgeOptions{
+		IDs:     candidates,
+		SpaceID: spaceID,
+		UserID:  userID,
+	}, types.NO_PAGINATION, types.NO_PAGINATION)
+	if err != nil && err != sql.ErrNoRows {
+		return knowledges, fmt.Errorf("failed to list linked knowledges: %w", err)
+	}
+
+	linked := lo.SliceToMap(list, func(item *types.Knowledge) (string, *types.Knowledge) {
+		return item.ID, item
+	})
+	for _, id := range candidates {
+		v, ok := linked[id]
+		if !ok {
+			continue
+		}
+		if v.Content, err = core.DecryptData(v.Content); err != nil {
+			return knowledges, fmt.Errorf("failed to decrypt knowledge data: %w", err)
+		}
+		if v.ContentType == types.KNOWLEDGE_CONTENT_TYPE_BLOCKS {
+			content, err := editorjs.ConvertEditorJSRawToMarkdown(json.RawMessage(v.Content))
+			if err != nil {
+				slog.Error("Failed to convert editor blocks to markdown", slog.String("knowledge_id", v.ID), slog.String("error", err.Error()))
+				continue
+			}
+			v.ContentType = types.KNOWLEDGE_CONTENT_TYPE_MARKDOWN
+			v.Content = types.KnowledgeContent(content)
+		}
+		knowledges = append(knowledges, v)
+	}
+	return knowledges, nil
+}
+
+// GraphExpansionCandidates 合并关联知识ID，显式引用优先于共同实体，排除已在检索结果中的知识，最多返回 limit 条
+func GraphExpansionCandidates(exists, links, coMentioned []string, limit int) []string {
+	seen := lo.SliceToMap(exists, func(item string) (string, struct{}) {
+		return item, struct{}{}
+	})
+
+	var result []string
+	for _, id := range append(append([]string{}, links...), coMentioned...) {
+		if len(result) >= limit {
+			break
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,27 @@
+package rag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphExpansionCandidates(t *testing.T) {
+	exists := []string{"a", "b"}
+	links := []string{"c", "a", "d", "c"}
+	coMentioned := []string{"e", "d", "f"}
+
+	got := GraphExpansionCandidates(exists, links, coMentioned, 4)
+	want := []string{"c", "d", "e", "f"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GraphExpansionCandidates() = %v, want %v", got, want)
+	}
+
+	got = GraphExpansionCandidates(exists, links, coMentioned, 1)
+	if !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("expected explicit links first, got %v", got)
+	}
+
+	if got = GraphExpansionCandidates(exists, nil, nil, 3); len(got) != 0 {
+		t.Errorf("expected no candidates, got %v", got)
+	}
+}
@@ -0,0 +1,114 @@
+package rag
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sort"
+
+	"github.com/pgvector/pgvector-go"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+const (
+	// RRF_K RRF 融合公式 score = Σ 1 / (k + rank) 中的常数 k，60 为论文中的经验值
+	RRF_K = 60
+	// HYBRID_KEYWORD_LIMIT 混合检索时关键词检索召回的数量
+	HYBRID_KEYWORD_LIMIT = 20
+)
+
+// FuseQueryResults 使用 Reciprocal Rank Fusion 融合多路召回结果
+// 以向量记录ID(chunk)为粒度合并，同一条记录保留各路召回中的 Cos 与 Rank，返回结果按融合得分降序排列
+func FuseQueryResults(k int, lists ...[]types.QueryResult) []types.QueryResult {
+	var (
+		index  = make(map[string]int)
+		result []types.QueryResult
+	)
+
+	for _, list := range lists {
+		for rank, v := range list {
+			score := 1 / float64(k+rank+1)
+			i, exist := index[v.ID]
+			if !exist {
+				v.Score = score
+				index[v.ID] = len(result)
+				result = append(result, v)
+				continue
+			}
+
+			item := &result[i]
+			item.Score += score
+			if v.Cos > item.Cos {
+				item.Cos = v.Cos
+			}
+			if v.Rank > item.Rank {
+				item.Rank = v.Rank
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result
+}
+
+// QueryRelevanceRefs 按空间配置的检索模式召回相关的向量记录
+// 空间开启混合检索时，会同时进行关键词检索并与向量检索结果进行 RRF 融合
+func QueryRelevanceRefs(ctx context.Context, core *core.Core, opts types.GetVectorsOptions, query string, vector pgvector.Vector, limit uint64) ([]types.QueryResult, error) {
+	// 只检索当前 embedding 模型生成的向量
+	opts.Model = core.Srv().AI().EmbeddingModel()
+	refs, err := core.Store().VectorStore().Query(ctx, opts, vector, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	space, err := core.GetSpace(ctx, opts.SpaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if space == nil || !space.Settings.IsHybridRetrieval() {
+		return refs, nil
+	}
+
+	tsquery := utils.FTSQuery(query)
+	if tsquery == "" {
+		return refs, nil
+	}
+
+	keywordRefs, err := core.Store().VectorStore().KeywordQuery(ctx, opts, tsquery, HYBRID_KEYWORD_LIMIT)
+	if err != nil {
+		// 关键词检索失败时降级为纯向量检索
+		slog.Error("Failed to query keywords", slog.String("space_id", opts.SpaceID), slog.String("error", err.Error()))
+		return refs, nil
+	}
+
+	return FuseQueryResults(RRF_K, refs, keywordRefs), nil
+}
+
+// TopCos 返回召回结果中的最高相似度
+// 混合检索时结果按融合得分排序，首条记录不一定是相似度最高的记录
+func TopCos(refs []types.QueryResult) float32 {
+	var topCos float32
+	for _, v := range refs {
+		if v.Cos > topCos {
+			topCos = v.Cos
+		}
+	}
+	return topCos
+}
+
+// KnowledgeSimilarity 返回每个知识在召回结果中的最高向量相似度，用于重排
+func KnowledgeSimilarity(refs []types.QueryResult) map[string]float32 {
+	result := make(map[string]float32, len(refs))
+	for _, v := range refs {
+		if v.Cos > result[v.KnowledgeID] {
+			result[v.KnowledgeID] = v.Cos
+		}
+	}
+	return result
+}
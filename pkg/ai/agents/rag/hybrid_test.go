@@ -0,0 +1,48 @@
+package rag
+
+import (
+	"testing"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func TestFuseQueryResults(t *testing.T) {
+	vectorRefs := []types.QueryResult{
+		{ID: "a", KnowledgeID: "k1", Cos: 0.8},
+		{ID: "b", KnowledgeID: "k2", Cos: 0.7},
+		{ID: "c", KnowledgeID: "k3", Cos: 0.6},
+	}
+	keywordRefs := []types.QueryResult{
+		{ID: "c", KnowledgeID: "k3", Rank: 0.9},
+		{ID: "d", KnowledgeID: "k4", Rank: 0.5},
+	}
+
+	result := FuseQueryResults(RRF_K, vectorRefs, keywordRefs)
+	if len(result) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(result))
+	}
+
+	// c 同时被两路召回，融合得分最高
+	if result[0].ID != "c" {
+		t.Fatalf("expected c to be ranked first, got %s", result[0].ID)
+	}
+	if result[0].Cos != 0.6 || result[0].Rank != 0.9 {
+		t.Errorf("expected merged cos and rank, got cos %f rank %f", result[0].Cos, result[0].Rank)
+	}
+
+	for i := 1; i < len(result); i++ {
+		if result[i].Score > result[i-1].Score {
+			t.Errorf("results not sorted by score at %d", i)
+		}
+	}
+
+	if got := TopCos(result); got != 0.8 {
+		t.Errorf("expected top cos 0.8, got %f", got)
+	}
+}
+
+func TestFuseQueryResults_Empty(t *testing.T) {
+	if result := FuseQueryResults(RRF_K, nil, nil); len(result) != 0 {
+		t.Errorf("expected empty result, got %v", result)
+	}
+}
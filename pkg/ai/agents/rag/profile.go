@@ -0,0 +1,71 @@
+package rag
+
+import (
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// HIGH_SCORE_REFS 召回结果中排名靠前的记录数量，这些记录在重排后仍会被保留
+const HIGH_SCORE_REFS = 3
+
+// FilterRelevanceRefs 按检索参数过滤向量召回结果
+// 返回保留的记录以及排名靠前的高分记录
+func FilterRelevanceRefs(refs []types.QueryResult, profile types.RetrievalProfile) ([]types.QueryResult, []types.QueryResult) {
+	var (
+		result    []types.QueryResult
+		highScore []types.QueryResult
+		cosLimit  = profile.CosFloor
+	)
+
+	// 候选较多但最高相似度不高时，以最高相似度为基准放宽下限
+	if topCos := TopCos(refs); len(refs) > 10 && topCos < profile.CosDropBelow {
+		cosLimit = topCos - profile.CosDrop
+	}
+	for i, v := range refs {
+		// 关键词检索命中的记录不受相似度阈值限制
+		if i > 0 && (v.Cos < cosLimit && v.OriginalLength > 200 && v.Rank == 0) {
+			if len(result) > profile.MaxRefs {
+				break
+			}
+			// TODO：more and more verify best ratio
+			continue
+		}
+
+		if i < HIGH_SCORE_REFS {
+			highScore = append(highScore, v)
+		}
+
+		result = append(result, v)
+	}
+	return result, highScore
+}
+
+// LimitKnowledgeDocs 按检索参数中的文档数量与 token 预算截取提供给模型的知识
+// 知识内容需已解密并转换为 markdown，超出预算的最后一篇知识会被截断
+func LimitKnowledgeDocs(knowledges []*types.Knowledge, profile types.RetrievalProfile) []*types.Knowledge {
+	if profile.MaxDocs > 0 && len(knowledges) > profile.MaxDocs {
+		knowledges = knowledges[:profile.MaxDocs]
+	}
+	if profile.DocsTokenBudget <= 0 {
+		return knowledges
+	}
+
+	budget := profile.DocsTokenBudget
+	for i, v := range knowledges {
+		tokens := utils.EstimateTokens(v.Title) + utils.EstimateTokens(string(v.Content))
+		if tokens <= budget {
+			budget -= tokens
+			continue
+		}
+
+		// 剩余预算过少时不再追加截断后的内容
+		budget -= utils.EstimateTokens(v.Title)
+		if budget < 200 {
+			return knowledges[:i]
+		}
+		// 每个 token 至少对应一个字符，按剩余预算的字符数截断不会超出预算
+		v.Content = types.KnowledgeContent(utils.SmartTruncateContent(string(v.Content), budget))
+		return knowledges[:i+1]
+	}
+	return knowledges
+}
@@ -0,0 +1,88 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func TestFilterRelevanceRefs(t *testing.T) {
+	profile := types.DefaultRetrievalProfile()
+
+	newRefs := func(top float32) []types.QueryResult {
+		var refs []types.QueryResult
+		for i := 0; i < 12; i++ {
+			refs = append(refs, types.QueryResult{
+				ID:             string(rune('a' + i)),
+				KnowledgeID:    string(rune('a' + i)),
+				Cos:            top - float32(i)*0.05,
+				OriginalLength: 500,
+			})
+		}
+		// 关键词命中的记录不受相似度阈值限制
+		refs[11].Rank = 0.5
+		return refs
+	}
+
+	// 最高相似度 0.9 不低于 0.7，下限保持为 0.5
+	refs := newRefs(0.9)
+	result, highScore := FilterRelevanceRefs(refs, profile)
+	if len(highScore) != HIGH_SCORE_REFS {
+		t.Fatalf("expected %d high score refs, got %d", HIGH_SCORE_REFS, len(highScore))
+	}
+	if result[len(result)-1].ID != refs[11].ID {
+		t.Errorf("expected keyword matched ref to be kept, got %+v", result)
+	}
+	for _, v := range result {
+		if v.Rank == 0 && v.Cos < 0.5 {
+			t.Errorf("unexpected ref below cos floor: %+v", v)
+		}
+	}
+
+	// 最高相似度 0.65 低于 0.7，下限放宽为 0.55
+	refs = newRefs(0.65)
+	result, _ = FilterRelevanceRefs(refs, profile)
+	for _, v := range result {
+		if v.Rank == 0 && v.Cos < 0.54 {
+			t.Errorf("unexpected ref below cos limit: %+v", v)
+		}
+	}
+	if len(result) != 4 {
+		t.Errorf("expected 4 refs kept, got %d", len(result))
+	}
+
+	profile.CosDrop = 0.5
+	result, _ = FilterRelevanceRefs(refs, profile)
+	if len(result) != len(refs) {
+		t.Errorf("expected all refs kept with larger cos drop, got %d", len(result))
+	}
+}
+
+func TestLimitKnowledgeDocs(t *testing.T) {
+	knowledges := []*types.Knowledge{
+		{ID: "1", Title: "a", Content: types.KnowledgeContent(strings.Repeat("知", 300))},
+		{ID: "2", Title: "b", Content: types.KnowledgeContent(strings.Repeat("识", 300))},
+		{ID: "3", Title: "c", Content: types.KnowledgeContent(strings.Repeat("库", 300))},
+	}
+
+	result := LimitKnowledgeDocs(knowledges, types.RetrievalProfile{MaxDocs: 2})
+	if len(result) != 2 {
+		t.Fatalf("expected 2 docs, got %d", len(result))
+	}
+
+	// 第二篇超出预算后被截断，第三篇被丢弃
+	result = LimitKnowledgeDocs(knowledges, types.RetrievalProfile{MaxDocs: 3, DocsTokenBudget: 550})
+	if len(result) != 2 {
+		t.Fatalf("expected 2 docs, got %d", len(result))
+	}
+	if l := len([]rune(string(result[1].Content))); l >= 300 {
+		t.Errorf("expected second doc to be truncated, got length %d", l)
+	}
+
+	// 剩余预算过少时不追加
+	result = LimitKnowledgeDocs(knowledges[2:], types.RetrievalProfile{DocsTokenBudget: 100})
+	if len(result) != 0 {
+		t.Errorf("expected no docs, got %d", len(result))
+	}
+}
@@ -22,6 +22,7 @@ func EnhanceChatQuery(ctx context.Context, core *core.Core, query string, spaceI
 	var (
 		histories []*types.ChatMessage
 		err       error
+		strategy  = core.RetrievalProfile(ctx, spaceID).QueryExpansion
 	)
 	if sessionID != "" {
 		histories, err = core.Store().ChatMessageStore().ListSessionMessageUpToGivenID(ctx, spaceID, sessionID, msgSequence, 1, 6)
@@ -31,17 +32,22 @@ func EnhanceChatQuery(ctx context.Context, core *core.Core, query string, spaceI
 		}
 
 		if len(histories) <= 1 {
-			return ai.EnhanceQueryResult{
-				Original: query,
-			}, nil
-		}
-
-		histories = lo.Reverse(histories)[:len(histories)-1]
+			// 改写策略依赖上下文补全问题，没有历史记录时直接使用原问题
+			if strategy == types.QUERY_EXPANSION_REWRITE {
+				return ai.EnhanceQueryResult{
+					Strategy: strategy,
+					Original: query,
+				}, nil
+			}
+			histories = nil
+		} else {
+			histories = lo.Reverse(histories)[:len(histories)-1]
 
-		decryptMessageLists(core, histories)
+			decryptMessageLists(core, histories)
+		}
 	}
 
-	return EnhanceQuery(ctx, core, query, histories)
+	return ExpandQuery(ctx, core, strategy, query, histories)
 }
 
 func decryptMessageLists(core *core.Core, messages []*types.ChatMessage) {
@@ -53,6 +59,36 @@ func decryptMessageLists(core *core.Core, messages []*types.ChatMessage) {
 	}
 }
 
+// ExpandQuery 按查询扩展策略处理用户问题，扩展失败时退化为使用原问题检索
+func ExpandQuery(ctx context.Context, core *core.Core, strategy, query string, histories []*types.ChatMessage) (ai.EnhanceQueryResult, error) {
+	var (
+		resp ai.EnhanceQueryResult
+		err  error
+	)
+	switch strategy {
+	case types.QUERY_EXPANSION_MULTI_QUERY:
+		prompt := core.PromptManager().GetEnhanceQueryTemplate(ai.MODEL_BASE_LANGUAGE_CN).Build()
+		resp, err = ai.NewEnhance(ctx, core.Srv().AI().GetEnhanceAI()).
+			WithPrompt(prompt).
+			WithHistories(histories).
+			MultiQuery(query)
+	case types.QUERY_EXPANSION_HYDE:
+		resp, err = ai.NewEnhance(ctx, core.Srv().AI().GetEnhanceAI()).
+			WithHistories(histories).
+			HypotheticalAnswer(query)
+	default:
+		return EnhanceQuery(ctx, core, query, histories)
+	}
+	if err != nil {
+		slog.Error("failed to expand user query", slog.String("query", query), slog.String("strategy", strategy), slog.String("error", err.Error()))
+		resp = ai.EnhanceQueryResult{
+			Strategy: strategy,
+			Original: query,
+		}
+	}
+	return resp, nil
+}
+
 func EnhanceQuery(ctx context.Context, core *core.Core, query string, histories []*types.ChatMessage) (ai.EnhanceQueryResult, error) {
 	// 使用 PromptManager 获取查询增强 Prompt
 	lang := ai.MODEL_BASE_LANGUAGE_CN
@@ -127,7 +163,12 @@ func SupplementSessionChatDocs(core *core.Core, spaceID, sessionID string, docs
 	}
 }
 
-func GetQueryRelevanceKnowledges(core *core.Core, spaceID, userID, query string, resource *types.ResourceQuery) (types.RAGDocs, []ai.UsageItem, error) {
+func GetQueryRelevanceKnowledges(core *core.Core, spaceID, userID, query string, resource *types.ResourceQuery, tags []string) (types.RAGDocs, []ai.UsageItem, error) {
+	return GetQueriesRelevanceKnowledges(core, spaceID, userID, query, []string{query}, resource, tags)
+}
+
+// GetQueriesRelevanceKnowledges 使用多个检索文本分别召回后进行 RRF 融合，query 用于重排
+func GetQueriesRelevanceKnowledges(core *core.Core, spaceID, userID, query string, searchQueries []string, resource *types.ResourceQuery, tags []string) (types.RAGDocs, []ai.UsageItem, error) {
 	var (
 		result types.RAGDocs
 		usages []ai.UsageItem
@@ -136,18 +177,32 @@ func GetQueryRelevanceKnowledges(core *core.Core, spaceID, userID, query string,
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 
-	vector, err := core.Srv().AI().EmbeddingForQuery(ctx, []string{query})
-	if err != nil || len(vector.Data) == 0 {
+	if len(searchQueries) == 0 {
+		searchQueries = []string{query}
+	}
+	vector, err := core.EmbeddingForQuery(ctx, searchQueries)
+	if err != nil || len(vector.Data) != len(searchQueries) {
 		return types.RAGDocs{}, nil, fmt.Errorf("failed to get embedding for query: %w", err)
 	}
 
-	refs, err := core.Store().VectorStore().Query(ctx, types.GetVectorsOptions{
-		SpaceID:  spaceID,
-		UserID:   userID,
-		Resource: resource,
-	}, pgvector.NewVector(vector.Data[0]), 100)
-	if err != nil {
-		return types.RAGDocs{}, nil, fmt.Errorf("failed to query vector store: %w", err)
+	profile := core.RetrievalProfile(ctx, spaceID)
+	var lists [][]types.QueryResult
+	for i, v := range searchQueries {
+		refs, err := QueryRelevanceRefs(ctx, core, types.GetVectorsOptions{
+			SpaceID:  spaceID,
+			UserID:   userID,
+			Resource: resource,
+			Tags:     tags,
+		}, v, pgvector.NewVector(vector.Data[i]), uint64(profile.CandidateLimit))
+		if err != nil {
+			return types.RAGDocs{}, nil, fmt.Errorf("failed to query vector store: %w", err)
+		}
+		lists = append(lists, refs)
+	}
+
+	refs := lists[0]
+	if len(lists) > 1 {
+		refs = FuseQueryResults(RRF_K, lists...)
 	}
 
 	slog.Debug("got query result", slog.String("query", query), slog.Any("result", refs))
@@ -158,28 +213,9 @@ func GetQueryRelevanceKnowledges(core *core.Core, spaceID, userID, query string,
 	// rerank
 	var (
 		knowledgeIDs       []string
-		cosLimit           float32 = 0.5
 		highScoreKnowledge []types.QueryResult
 	)
-
-	if len(refs) > 10 && refs[0].Cos < 0.7 {
-		cosLimit = refs[0].Cos - 0.1
-	}
-	for i, v := range refs {
-		if i > 0 && (v.Cos < cosLimit && v.OriginalLength > 200) {
-			if len(result.Refs) > 20 {
-				break
-			}
-			// TODO：more and more verify best ratio
-			continue
-		}
-
-		if i < 3 {
-			highScoreKnowledge = append(highScoreKnowledge, v)
-		}
-
-		result.Refs = append(result.Refs, v)
-	}
+	result.Refs, highScoreKnowledge = FilterRelevanceRefs(refs, profile)
 
 	result.Refs = lo.UniqBy(result.Refs, func(item types.QueryResult) string {
 		return item.KnowledgeID
@@ -222,7 +258,7 @@ func GetQueryRelevanceKnowledges(core *core.Core, spaceID, userID, query string,
 		return result, usages, nil
 	}
 
-	rankList, usage, err := core.Rerank(query, knowledges)
+	rankList, usage, err := core.Rerank(query, knowledges, KnowledgeSimilarity(refs), profile)
 	if err != nil {
 		slog.Error("Failed to request rerank api", slog.String("error", err.Error()))
 		// return result, usage, errors.New("KnowledgeLogic.Query.Rerank", i18n.ERROR_INTERNAL, err)
@@ -258,6 +294,15 @@ func GetQueryRelevanceKnowledges(core *core.Core, spaceID, userID, query string,
 		})
 	}
 
+	if rankList, err = ExpandKnowledgeChunks(ctx, core, rankList, profile); err != nil {
+		slog.Error("Failed to expand knowledge chunks", slog.String("space_id", spaceID), slog.String("error", err.Error()))
+	}
+
+	if rankList, err = ExpandKnowledgeGraph(ctx, core, spaceID, userID, rankList, profile); err != nil {
+		slog.Error("Failed to expand knowledge graph", slog.String("space_id", spaceID), slog.String("error", err.Error()))
+	}
+
+	rankList = LimitKnowledgeDocs(rankList, profile)
 	if result.Docs, err = core.AppendKnowledgeContentToDocs(result.Docs, rankList); err != nil {
 		return result, usages, fmt.Errorf("failed to append knowledge content to docs: %w", err)
 	}
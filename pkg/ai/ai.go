@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -144,6 +145,52 @@ func (s *EnhanceOptions) EnhanceQuery(query string) (EnhanceQueryResult, error)
 		s.prompt = PROMPT_ENHANCE_QUERY_CN
 	}
 
+	result, content, err := s.generate(query)
+	if err != nil {
+		return EnhanceQueryResult{}, err
+	}
+
+	result.Strategy = types.QUERY_EXPANSION_REWRITE
+	result.News = strings.Split(content, " ")
+	return result, nil
+}
+
+// MultiQuery 从不同角度为原问题生成多个检索词，原问题与检索词分别进行向量检索后合并结果
+func (s *EnhanceOptions) MultiQuery(query string) (EnhanceQueryResult, error) {
+	if s.prompt == "" {
+		s.prompt = PROMPT_ENHANCE_QUERY_CN
+	}
+
+	result, content, err := s.generate(query)
+	if err != nil {
+		return EnhanceQueryResult{}, err
+	}
+
+	result.Strategy = types.QUERY_EXPANSION_MULTI_QUERY
+	result.News = ParseQueryList(content, MULTI_QUERY_MAX_QUERIES)
+	result.Queries = lo.Uniq(append([]string{query}, result.News...))
+	return result, nil
+}
+
+// HypotheticalAnswer 为原问题生成一段假设的回答(HyDE)，使用回答内容进行向量检索
+func (s *EnhanceOptions) HypotheticalAnswer(query string) (EnhanceQueryResult, error) {
+	if s.prompt == "" {
+		s.prompt = PROMPT_HYDE_CN
+	}
+
+	result, content, err := s.generate(query)
+	if err != nil {
+		return EnhanceQueryResult{}, err
+	}
+
+	result.Strategy = types.QUERY_EXPANSION_HYDE
+	if content = strings.TrimSpace(content); content != "" {
+		result.Queries = []string{content}
+	}
+	return result, nil
+}
+
+func (s *EnhanceOptions) generate(query string) (EnhanceQueryResult, string, error) {
 	s.vars[PROMPT_VAR_QUERY] = query
 	for k, v := range s.vars {
 		s.prompt = strings.ReplaceAll(s.prompt, k, v)
@@ -153,20 +200,50 @@ func (s *EnhanceOptions) EnhanceQuery(query string) (EnhanceQueryResult, error)
 		schema.UserMessage(s.prompt),
 	})
 	if err != nil {
-		return EnhanceQueryResult{}, err
+		return EnhanceQueryResult{}, "", err
 	}
 
 	var result EnhanceQueryResult
 
 	result.Original = query
 	result.Model = s._driver.Config().ModelName
-	result.News = strings.Split(res.Content, " ")
-	result.Usage = &openai.Usage{
-		PromptTokens:     res.ResponseMeta.Usage.PromptTokens,
-		CompletionTokens: res.ResponseMeta.Usage.CompletionTokens,
-		TotalTokens:      res.ResponseMeta.Usage.TotalTokens,
+	if res.ResponseMeta != nil && res.ResponseMeta.Usage != nil {
+		result.Usage = &openai.Usage{
+			PromptTokens:     res.ResponseMeta.Usage.PromptTokens,
+			CompletionTokens: res.ResponseMeta.Usage.CompletionTokens,
+			TotalTokens:      res.ResponseMeta.Usage.TotalTokens,
+		}
 	}
-	return result, nil
+	return result, res.Content, nil
+}
+
+// MULTI_QUERY_MAX_QUERIES multi-query 策略最多使用的检索词数量(不含原问题)
+const MULTI_QUERY_MAX_QUERIES = 4
+
+// ParseQueryList 解析模型输出的检索词列表，优先按 JSON 数组解析，失败时按行拆分，最多返回 limit 条
+func ParseQueryList(content string, limit int) []string {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+
+	var list []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &list); err != nil {
+		list = strings.Split(content, "\n")
+	}
+
+	var result []string
+	for _, v := range list {
+		v = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(v), "-*0123456789.、"))
+		if v == "" || lo.Contains(result, v) {
+			continue
+		}
+		result = append(result, v)
+		if len(result) >= limit {
+			break
+		}
+	}
+	return result
 }
 
 func (s *QueryOptions) Query() (*openai.ChatCompletionResponse, error) {
@@ -536,11 +613,21 @@ func convertPassageToPromptTextCN(docs []*types.PassageInfo) string {
 			s.WriteString(v.ID)
 			s.WriteString("\n")
 		}
+		if v.Citation > 0 {
+			s.WriteString("引用标记：[^")
+			s.WriteString(strconv.Itoa(v.Citation))
+			s.WriteString("]\n")
+		}
 		if v.Title != "" {
 			s.WriteString("标题：")
 			s.WriteString(v.Title)
 			s.WriteString("\n")
 		}
+		if v.SpaceTitle != "" {
+			s.WriteString("所属空间：")
+			s.WriteString(v.SpaceTitle)
+			s.WriteString("\n")
+		}
 		if v.Resource != "" {
 			s.WriteString("内容类型：")
 			s.WriteString(v.Resource)
@@ -566,11 +653,21 @@ func convertPassageToPromptTextEN(docs []*types.PassageInfo) string {
 		s.WriteString("ID: ")
 		s.WriteString(v.ID)
 		s.WriteString("\n")
+		if v.Citation > 0 {
+			s.WriteString("Citation: [^")
+			s.WriteString(strconv.Itoa(v.Citation))
+			s.WriteString("]\n")
+		}
 		if v.Title != "" {
 			s.WriteString("Title: ")
 			s.WriteString(v.Title)
 			s.WriteString("\n")
 		}
+		if v.SpaceTitle != "" {
+			s.WriteString("Space: ")
+			s.WriteString(v.SpaceTitle)
+			s.WriteString("\n")
+		}
 		s.WriteString("ResourceKind: ")
 		s.WriteString(v.Resource)
 		s.WriteString("\nContent: ")
@@ -620,12 +717,27 @@ type SummarizeResult struct {
 }
 
 type ChunkResult struct {
-	Title    string        `json:"title"`
-	Tags     []string      `json:"tags"`
-	Chunks   []string      `json:"chunks"`
-	DateTime string        `json:"date_time"`
-	Usage    *openai.Usage `json:"-"`
-	Model    string        `json:"model"`
+	Title     string          `json:"title"`
+	Tags      []string        `json:"tags"`
+	Chunks    []string        `json:"chunks"`
+	DateTime  string          `json:"date_time"`
+	Entities  []ChunkEntity   `json:"entities"`
+	Relations []ChunkRelation `json:"relations"`
+	Usage     *openai.Usage   `json:"-"`
+	Model     string          `json:"model"`
+}
+
+// ChunkEntity 内容中提及的命名实体
+type ChunkEntity struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ChunkRelation 内容中描述的实体关系
+type ChunkRelation struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Relation string `json:"relation"`
 }
 
 type EmbeddingResult struct {
@@ -635,12 +747,30 @@ type EmbeddingResult struct {
 }
 
 type EnhanceQueryResult struct {
+	Strategy string        `json:"strategy,omitempty"` // 使用的查询扩展策略
 	Original string        `json:"original"`
 	News     []string      `json:"news"`
+	Queries  []string      `json:"queries,omitempty"` // 用于向量检索的文本，为空时使用 ResultQuery
 	Model    string        `json:"model"`
 	Usage    *openai.Usage `json:"-"`
 }
 
+// SearchQueries 返回用于向量检索的文本列表
+func (e EnhanceQueryResult) SearchQueries() []string {
+	if len(e.Queries) > 0 {
+		return e.Queries
+	}
+	return []string{e.ResultQuery()}
+}
+
+// RerankQuery 返回用于重排的查询语句，使用了多个检索文本时以原问题进行重排
+func (e EnhanceQueryResult) RerankQuery() string {
+	if len(e.Queries) > 0 {
+		return e.Original
+	}
+	return e.ResultQuery()
+}
+
 func (e EnhanceQueryResult) ResultQuery() string {
 	b := strings.Builder{}
 	b.WriteString(e.Original)
@@ -676,8 +806,9 @@ func dateFormat(t time.Time) string {
 }
 
 type RerankDoc struct {
-	ID      string
-	Content string
+	ID         string
+	Content    string
+	Similarity float64 // 召回阶段的向量相似度，可选，仅本地重排使用
 }
 
 type RankDocItem struct {
@@ -7,3 +7,32 @@ func Test_TimeTpl(t *testing.T) {
 
 	t.Log(tpl)
 }
+
+func TestParseQueryList(t *testing.T) {
+	list := ParseQueryList(`["Nginx 如何下载？","下载 Nginx 需要什么条件？","Nginx 如何下载？"]`, 4)
+	if len(list) != 2 || list[0] != "Nginx 如何下载？" {
+		t.Errorf("unexpected json queries: %v", list)
+	}
+
+	list = ParseQueryList("```json\n[\"a\", \"b\", \"c\"]\n```", 2)
+	if len(list) != 2 || list[1] != "b" {
+		t.Errorf("expected fenced json to be parsed and limited, got %v", list)
+	}
+
+	list = ParseQueryList("1. first query\n- second query\n\n", 4)
+	if len(list) != 2 || list[0] != "first query" || list[1] != "second query" {
+		t.Errorf("expected line based fallback, got %v", list)
+	}
+}
+
+func TestEnhanceQueryResultSearchQueries(t *testing.T) {
+	rewrite := EnhanceQueryResult{Original: "a", News: []string{"b"}}
+	if q := rewrite.SearchQueries(); len(q) != 1 || q[0] != "a b" || rewrite.RerankQuery() != "a b" {
+		t.Errorf("unexpected rewrite queries: %v", q)
+	}
+
+	multi := EnhanceQueryResult{Original: "a", Queries: []string{"a", "b"}}
+	if q := multi.SearchQueries(); len(q) != 2 || multi.RerankQuery() != "a" {
+		t.Errorf("unexpected multi queries: %v", q)
+	}
+}
@@ -0,0 +1,140 @@
+package bm25
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+const (
+	NAME = "bm25"
+
+	// DEFAULT_K1 词频饱和参数
+	DEFAULT_K1 = 1.2
+	// DEFAULT_B 文档长度归一化参数
+	DEFAULT_B = 0.75
+	// DEFAULT_WEIGHT 同时提供向量相似度时，BM25 得分所占的权重
+	DEFAULT_WEIGHT = 0.6
+)
+
+// Driver 本地词法重排，以候选文档自身作为语料计算 BM25 得分，不依赖任何外部服务
+// 候选文档携带向量相似度(RerankDoc.Similarity)时，最终得分为 BM25 归一化得分与相似度的加权和
+type Driver struct {
+	k1     float64
+	b      float64
+	weight float64
+}
+
+func New() *Driver {
+	return &Driver{
+		k1:     DEFAULT_K1,
+		b:      DEFAULT_B,
+		weight: DEFAULT_WEIGHT,
+	}
+}
+
+func (s *Driver) Rerank(ctx context.Context, query string, docs []*ai.RerankDoc) ([]ai.RankDocItem, *ai.Usage, error) {
+	slog.Debug("Rerank", slog.String("driver", NAME))
+	if len(docs) == 0 {
+		return nil, nil, nil
+	}
+
+	scores := s.Score(query, docs)
+
+	var (
+		maxScore      float64
+		hasSimilarity bool
+	)
+	for i, v := range scores {
+		maxScore = math.Max(maxScore, v)
+		if docs[i].Similarity > 0 {
+			hasSimilarity = true
+		}
+	}
+
+	result := make([]ai.RankDocItem, 0, len(docs))
+	for i, doc := range docs {
+		score := 0.0
+		if maxScore > 0 {
+			score = scores[i] / maxScore
+		}
+		if hasSimilarity {
+			score = s.weight*score + (1-s.weight)*doc.Similarity
+		}
+		result = append(result, ai.RankDocItem{
+			ID:    doc.ID,
+			Score: score,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+
+	// 本地计算不产生 token 消耗
+	return result, nil, nil
+}
+
+// Score 计算每个文档相对 query 的 BM25 原始得分，顺序与 docs 一致
+func (s *Driver) Score(query string, docs []*ai.RerankDoc) []float64 {
+	var (
+		queryTerms = uniqueTerms(utils.FTSTerms(query))
+		docTerms   = make([]map[string]int, len(docs))
+		docLength  = make([]int, len(docs))
+		df         = make(map[string]int)
+		totalLen   int
+	)
+
+	for i, doc := range docs {
+		terms := utils.FTSTerms(doc.Content)
+		tf := make(map[string]int)
+		for _, t := range terms {
+			tf[t]++
+		}
+		for t := range tf {
+			df[t]++
+		}
+		docTerms[i] = tf
+		docLength[i] = len(terms)
+		totalLen += len(terms)
+	}
+
+	scores := make([]float64, len(docs))
+	if totalLen == 0 || len(queryTerms) == 0 {
+		return scores
+	}
+
+	var (
+		n      = float64(len(docs))
+		avgLen = float64(totalLen) / n
+	)
+	for i := range docs {
+		for _, t := range queryTerms {
+			tf := float64(docTerms[i][t])
+			if tf == 0 {
+				continue
+			}
+			idf := math.Log(1 + (n-float64(df[t])+0.5)/(float64(df[t])+0.5))
+			norm := tf + s.k1*(1-s.b+s.b*float64(docLength[i])/avgLen)
+			scores[i] += idf * tf * (s.k1 + 1) / norm
+		}
+	}
+	return scores
+}
+
+func uniqueTerms(terms []string) []string {
+	exist := make(map[string]struct{}, len(terms))
+	result := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if _, ok := exist[t]; ok {
+			continue
+		}
+		exist[t] = struct{}{}
+		result = append(result, t)
+	}
+	return result
+}
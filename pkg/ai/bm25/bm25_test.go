@@ -0,0 +1,56 @@
+package bm25
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quka-ai/quka-ai/pkg/ai"
+)
+
+func TestRerank(t *testing.T) {
+	docs := []*ai.RerankDoc{
+		{ID: "1", Content: "今天的天气很好，适合出门散步"},
+		{ID: "2", Content: "向量数据库使用 HNSW 索引加速检索，pgvector 支持 hnsw"},
+		{ID: "3", Content: "混合检索结合了关键词检索与向量检索"},
+	}
+
+	res, usage, err := New().Rerank(context.Background(), "向量检索", docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage != nil {
+		t.Errorf("expected nil usage, got %v", usage)
+	}
+	if len(res) != len(docs) {
+		t.Fatalf("expected %d results, got %d", len(docs), len(res))
+	}
+	if res[0].ID != "3" || res[0].Score != 1 {
+		t.Errorf("expected doc 3 ranked first with score 1, got %+v", res[0])
+	}
+	if res[len(res)-1].ID != "1" || res[len(res)-1].Score != 0 {
+		t.Errorf("expected doc 1 ranked last with score 0, got %+v", res[len(res)-1])
+	}
+}
+
+func TestRerankWithSimilarity(t *testing.T) {
+	docs := []*ai.RerankDoc{
+		{ID: "1", Content: "go go go", Similarity: 0.2},
+		{ID: "2", Content: "rust", Similarity: 0.9},
+	}
+
+	res, _, err := New().Rerank(context.Background(), "go", docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1: 0.6*1 + 0.4*0.2 = 0.68, 2: 0.6*0 + 0.4*0.9 = 0.36
+	if res[0].ID != "1" || res[1].ID != "2" {
+		t.Errorf("unexpected order: %+v", res)
+	}
+}
+
+func TestScore_Empty(t *testing.T) {
+	scores := New().Score("???", []*ai.RerankDoc{{ID: "1", Content: "hello"}})
+	if len(scores) != 1 || scores[0] != 0 {
+		t.Errorf("unexpected scores: %v", scores)
+	}
+}
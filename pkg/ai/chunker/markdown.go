@@ -0,0 +1,508 @@
+package chunker
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+	"github.com/samber/lo"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// MODEL_NAME 本地分片结果中记录的模型名称
+const MODEL_NAME = "local-markdown-chunker"
+
+// TOKEN_ENCODING 统计 token 使用的 tiktoken 编码
+const TOKEN_ENCODING = "cl100k_base"
+
+var (
+	headingRegexp   = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+	tableSepRegexp  = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+	encodingOnce    sync.Once
+	defaultEncoding *tiktoken.Tiktoken
+)
+
+// CountTokens 使用 tiktoken 统计文本的 token 数
+// 编码加载失败时(如离线环境无法下载词表)退回到 utils.EstimateTokens 估算
+func CountTokens(content string) int {
+	encodingOnce.Do(func() {
+		tkm, err := tiktoken.GetEncoding(TOKEN_ENCODING)
+		if err != nil {
+			slog.Warn("Failed to load tiktoken encoding, fallback to estimate", slog.String("encoding", TOKEN_ENCODING), slog.String("error", err.Error()))
+			return
+		}
+		defaultEncoding = tkm
+	})
+	if defaultEncoding == nil {
+		return utils.EstimateTokens(content)
+	}
+	return len(defaultEncoding.Encode(content, nil, nil))
+}
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockCode
+	blockTable
+)
+
+type block struct {
+	kind blockKind
+	text string
+	// path 块所在的标题路径，标题块的路径包含其自身
+	path []string
+}
+
+// MarkdownChunker 识别 markdown 标题层级的本地分片器
+// 分片优先在标题处切分，代码块与表格不会被从中间截断，超长时按行切分并补全代码围栏与表头
+type MarkdownChunker struct {
+	size    int
+	overlap int
+	count   func(string) int
+}
+
+func NewMarkdownChunker(conf types.ChunkerConfig) *MarkdownChunker {
+	conf = conf.WithDefault()
+	return &MarkdownChunker{
+		size:    conf.ChunkSize,
+		overlap: min(conf.ChunkOverlap, conf.ChunkSize/2),
+		count:   CountTokens,
+	}
+}
+
+// Split 将 markdown 内容切分为不超过 ChunkSize 个 token 的分片
+// 相邻分片在同一章节内时会重叠 ChunkOverlap 个 token，每个分片开头都会带上其所属的上级标题路径
+func (c *MarkdownChunker) Split(content string) []string {
+	var (
+		chunks    []string
+		cur       []block
+		curTokens int
+		fresh     bool // 当前分片是否包含重叠部分之外的新内容
+	)
+
+	flush := func(keepOverlap bool) {
+		if !fresh {
+			return
+		}
+		// 末尾的标题会作为标题路径出现在下一个分片中
+		emit := cur
+		for len(emit) > 0 && emit[len(emit)-1].kind == blockHeading {
+			emit = emit[:len(emit)-1]
+		}
+		var tail []block
+		if len(emit) > 0 {
+			chunks = append(chunks, c.render(emit))
+			if keepOverlap {
+				tail = c.overlapTail(emit)
+			}
+		}
+		cur, fresh = tail, false
+		curTokens = c.blocksTokens(cur)
+	}
+
+	add := func(b block) {
+		if len(cur) == 0 {
+			curTokens = c.prefixTokens(b)
+		}
+		cur = append(cur, b)
+		curTokens += c.count(b.text)
+		fresh = true
+	}
+
+	blocks := parseBlocks(content)
+	for i, b := range blocks {
+		if b.kind == blockHeading && fresh {
+			// 章节能放进当前分片时继续合并，避免产生过碎的分片
+			if curTokens+c.sectionTokens(blocks[i:]) > c.size {
+				flush(false)
+			}
+		}
+		if b.kind == blockHeading && !fresh {
+			cur, curTokens = nil, 0
+		}
+
+		for _, piece := range c.splitBlock(b) {
+			tokens := c.count(piece.text)
+			if len(cur) == 0 {
+				tokens += c.prefixTokens(piece)
+			}
+			if fresh && curTokens+tokens > c.size {
+				flush(true)
+				// 重叠部分与新内容放不下时丢弃重叠部分
+				if curTokens+tokens > c.size {
+					cur, curTokens = nil, 0
+				}
+			}
+			add(piece)
+		}
+	}
+	flush(false)
+	return chunks
+}
+
+// render 将分片内的块拼接为文本，并在开头补充分片所属的标题路径
+func (c *MarkdownChunker) render(blocks []block) string {
+	texts := make([]string, 0, len(blocks)+1)
+	if prefix := c.prefix(blocks[0]); prefix != "" {
+		texts = append(texts, prefix)
+	}
+	for _, v := range blocks {
+		texts = append(texts, v.text)
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// prefix 返回块的上级标题路径，路径过长(超过分片大小的一半)时不补充
+func (c *MarkdownChunker) prefix(b block) string {
+	path := b.path
+	if b.kind == blockHeading && len(path) > 0 {
+		path = path[:len(path)-1]
+	}
+	if len(path) == 0 {
+		return ""
+	}
+	prefix := strings.Join(path, "\n")
+	if c.count(prefix) > c.size/2 {
+		return ""
+	}
+	return prefix
+}
+
+func (c *MarkdownChunker) prefixTokens(b block) int {
+	if prefix := c.prefix(b); prefix != "" {
+		return c.count(prefix)
+	}
+	return 0
+}
+
+func (c *MarkdownChunker) blocksTokens(blocks []block) int {
+	if len(blocks) == 0 {
+		return 0
+	}
+	total := c.prefixTokens(blocks[0])
+	for _, v := range blocks {
+		total += c.count(v.text)
+	}
+	return total
+}
+
+// sectionTokens 统计从当前标题开始到下一个标题之前的 token 数
+func (c *MarkdownChunker) sectionTokens(blocks []block) int {
+	total := c.count(blocks[0].text)
+	for _, v := range blocks[1:] {
+		if v.kind == blockHeading {
+			break
+		}
+		total += c.count(v.text)
+	}
+	return total
+}
+
+// overlapTail 返回分片末尾不超过 overlap 个 token 的段落作为下一个分片的开头
+// 代码块、表格与标题不参与重叠，末尾段落过长时只保留其最后几句
+func (c *MarkdownChunker) overlapTail(blocks []block) []block {
+	if c.overlap <= 0 {
+		return nil
+	}
+	var (
+		tail   []block
+		tokens int
+	)
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := blocks[i]
+		if b.kind != blockParagraph {
+			break
+		}
+		t := c.count(b.text)
+		if tokens+t <= c.overlap {
+			tail = append([]block{b}, tail...)
+			tokens += t
+			continue
+		}
+		if text := c.tailSentences(b.text, c.overlap-tokens); text != "" {
+			b.text = text
+			tail = append([]block{b}, tail...)
+		}
+		break
+	}
+	return tail
+}
+
+func (c *MarkdownChunker) tailSentences(text string, limit int) string {
+	sentences := splitSentences(text)
+	var (
+		result string
+		start  = len(sentences)
+	)
+	for start > 0 {
+		next := strings.TrimSpace(strings.Join(sentences[start-1:], ""))
+		if c.count(next) > limit {
+			break
+		}
+		result = next
+		start--
+	}
+	return result
+}
+
+// splitBlock 将超过分片大小的块切分为多个块
+func (c *MarkdownChunker) splitBlock(b block) []block {
+	limit := c.size - c.prefixTokens(b)
+	if limit < c.size/2 {
+		limit = c.size / 2
+	}
+	if c.count(b.text) <= limit {
+		return []block{b}
+	}
+
+	var texts []string
+	switch b.kind {
+	case blockCode:
+		texts = c.splitCode(b.text, limit)
+	case blockTable:
+		texts = c.splitTable(b.text, limit)
+	default:
+		texts = c.splitText(b.text, limit)
+	}
+
+	result := make([]block, 0, len(texts))
+	for _, v := range texts {
+		result = append(result, block{kind: b.kind, text: v, path: b.path})
+	}
+	return result
+}
+
+// splitCode 按行切分代码块，每一段都补全首尾的代码围栏
+func (c *MarkdownChunker) splitCode(text string, limit int) []string {
+	lines := strings.Split(text, "\n")
+	if len(lines) < 2 {
+		return c.splitText(text, limit)
+	}
+	open, end := lines[0], len(lines)
+	closing := strings.Repeat(string(strings.TrimSpace(open)[0]), 3)
+	if isFenceClose(lines[end-1], open) {
+		closing = lines[end-1]
+		end--
+	}
+	body := lines[1:end]
+	fenceTokens := c.count(open + "\n" + closing)
+	return lo.Map(c.packLines(body, limit-fenceTokens), func(item string, _ int) string {
+		return open + "\n" + item + "\n" + closing
+	})
+}
+
+// splitTable 按行切分表格，每一段都重复表头
+func (c *MarkdownChunker) splitTable(text string, limit int) []string {
+	lines := strings.Split(text, "\n")
+	var header string
+	if len(lines) > 2 && tableSepRegexp.MatchString(strings.TrimSpace(lines[1])) {
+		header = lines[0] + "\n" + lines[1]
+		lines = lines[2:]
+	}
+	if header == "" {
+		return c.packLines(lines, limit)
+	}
+	return lo.Map(c.packLines(lines, limit-c.count(header)), func(item string, _ int) string {
+		return header + "\n" + item
+	})
+}
+
+// packLines 将多行文本按 token 上限合并，单行超长时再按字符切分
+func (c *MarkdownChunker) packLines(lines []string, limit int) []string {
+	limit = max(limit, 1)
+	var (
+		result []string
+		cur    []string
+		tokens int
+	)
+	for _, line := range lines {
+		t := c.count(line) + 1
+		if t > limit {
+			if len(cur) > 0 {
+				result = append(result, strings.Join(cur, "\n"))
+				cur, tokens = nil, 0
+			}
+			result = append(result, c.hardSplit(line, limit)...)
+			continue
+		}
+		if tokens+t > limit && len(cur) > 0 {
+			result = append(result, strings.Join(cur, "\n"))
+			cur, tokens = nil, 0
+		}
+		cur = append(cur, line)
+		tokens += t
+	}
+	if len(cur) > 0 {
+		result = append(result, strings.Join(cur, "\n"))
+	}
+	return result
+}
+
+// splitText 按句子切分段落，单句超长时再按字符切分
+func (c *MarkdownChunker) splitText(text string, limit int) []string {
+	var (
+		result []string
+		cur    strings.Builder
+	)
+	for _, sentence := range splitSentences(text) {
+		if c.count(cur.String()+sentence) <= limit {
+			cur.WriteString(sentence)
+			continue
+		}
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			result = append(result, s)
+		}
+		cur.Reset()
+		if c.count(sentence) <= limit {
+			cur.WriteString(sentence)
+			continue
+		}
+		result = append(result, c.hardSplit(strings.TrimSpace(sentence), limit)...)
+	}
+	if s := strings.TrimSpace(cur.String()); s != "" {
+		result = append(result, s)
+	}
+	return result
+}
+
+// hardSplit 按字符切分文本，每段不超过 limit 个 token
+func (c *MarkdownChunker) hardSplit(text string, limit int) []string {
+	var (
+		result []string
+		runes  = []rune(text)
+	)
+	for len(runes) > 0 {
+		n := len(runes)
+		for n > 1 {
+			t := c.count(string(runes[:n]))
+			if t <= limit {
+				break
+			}
+			n = min(n-1, n*limit/t)
+			n = max(n, 1)
+		}
+		if s := strings.TrimSpace(string(runes[:n])); s != "" {
+			result = append(result, s)
+		}
+		runes = runes[n:]
+	}
+	return result
+}
+
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '。', '！', '？', '；', '.', '!', '?', ';', '\n':
+		return true
+	}
+	return false
+}
+
+// splitSentences 按句末标点切分文本，标点保留在句子末尾
+func splitSentences(text string) []string {
+	var (
+		result []string
+		start  int
+		runes  = []rune(text)
+	)
+	for i, r := range runes {
+		if isSentenceEnd(r) && (i+1 == len(runes) || !isSentenceEnd(runes[i+1])) {
+			result = append(result, string(runes[start:i+1]))
+			start = i + 1
+		}
+	}
+	if start < len(runes) {
+		result = append(result, string(runes[start:]))
+	}
+	return result
+}
+
+func isFenceOpen(line string) bool {
+	trim := strings.TrimSpace(line)
+	return strings.HasPrefix(trim, "```") || strings.HasPrefix(trim, "~~~")
+}
+
+func isFenceClose(line, open string) bool {
+	trim := strings.TrimSpace(line)
+	marker := strings.TrimSpace(open)
+	fence := marker[:3]
+	return strings.HasPrefix(trim, fence) && strings.Trim(trim, string(fence[0])) == ""
+}
+
+func isTableLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+// parseBlocks 将 markdown 解析为标题、段落、代码块与表格，并记录每个块所在的标题路径
+func parseBlocks(content string) []block {
+	var (
+		result   []block
+		headings []string
+		levels   []int
+		cur      []string
+		kind     blockKind
+		fence    string
+	)
+
+	path := func() []string {
+		return append([]string{}, headings...)
+	}
+	flush := func() {
+		if len(cur) == 0 {
+			return
+		}
+		text := strings.Join(cur, "\n")
+		if kind == blockCode || strings.TrimSpace(text) != "" {
+			result = append(result, block{kind: kind, text: strings.TrimRight(text, "\n"), path: path()})
+		}
+		cur, kind = nil, blockParagraph
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if fence != "" {
+			cur = append(cur, line)
+			if isFenceClose(line, fence) {
+				flush()
+				fence = ""
+			}
+			continue
+		}
+
+		switch {
+		case isFenceOpen(line):
+			flush()
+			fence, kind = line, blockCode
+			cur = append(cur, line)
+		case headingRegexp.MatchString(strings.TrimSpace(line)):
+			flush()
+			level := len(headingRegexp.FindStringSubmatch(strings.TrimSpace(line))[1])
+			for len(levels) > 0 && levels[len(levels)-1] >= level {
+				levels = levels[:len(levels)-1]
+				headings = headings[:len(headings)-1]
+			}
+			levels = append(levels, level)
+			headings = append(headings, strings.TrimSpace(line))
+			result = append(result, block{kind: blockHeading, text: strings.TrimSpace(line), path: path()})
+		case strings.TrimSpace(line) == "":
+			flush()
+		case isTableLine(line):
+			if kind != blockTable {
+				flush()
+				kind = blockTable
+			}
+			cur = append(cur, line)
+		default:
+			if kind == blockTable {
+				flush()
+			}
+			cur = append(cur, line)
+		}
+	}
+	flush()
+	return result
+}
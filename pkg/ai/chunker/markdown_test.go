@@ -0,0 +1,111 @@
+package chunker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func wordCount(s string) int {
+	return len(strings.Fields(s))
+}
+
+func newTestChunker(size, overlap int) *MarkdownChunker {
+	c := NewMarkdownChunker(types.ChunkerConfig{Mode: types.CHUNKER_LOCAL, ChunkSize: size, ChunkOverlap: overlap})
+	c.count = wordCount
+	return c
+}
+
+func TestSplitHeadings(t *testing.T) {
+	content := "# Guide\n\nintro text here.\n\n## Install\n\nrun the installer now.\n\n## Usage\n\n" + strings.Repeat("use it well. ", 10)
+	chunks := newTestChunker(20, 0).Split(content)
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d: %q", len(chunks), chunks)
+	}
+	if !strings.HasPrefix(chunks[0], "# Guide") || !strings.Contains(chunks[0], "## Install") {
+		t.Errorf("expected small sections to be merged, got %q", chunks[0])
+	}
+	if !strings.HasPrefix(chunks[1], "# Guide\n\n## Usage\n\nuse it well.") {
+		t.Errorf("expected chunk to start at heading, got %q", chunks[1])
+	}
+	if !strings.HasPrefix(chunks[2], "# Guide\n## Usage\n\nuse it well.") {
+		t.Errorf("expected heading path at chunk start, got %q", chunks[2])
+	}
+
+	for _, v := range chunks {
+		if wordCount(v) > 20 {
+			t.Errorf("chunk exceeds size: %d", wordCount(v))
+		}
+	}
+}
+
+func TestSplitOverlapAndHeadingPath(t *testing.T) {
+	var paragraphs []string
+	for range 6 {
+		paragraphs = append(paragraphs, "one two three four five.")
+	}
+	content := "# Title\n\n## Part\n\n" + strings.Join(paragraphs, "\n\n")
+	chunks := newTestChunker(14, 5).Split(content)
+
+	if len(chunks) < 3 {
+		t.Fatalf("expected content to be split, got %q", chunks)
+	}
+	for _, v := range chunks[1:] {
+		if !strings.HasPrefix(v, "# Title\n## Part\n\none two three four five.") {
+			t.Errorf("expected heading path and overlap at chunk start, got %q", v)
+		}
+		if wordCount(v) > 14 {
+			t.Errorf("chunk exceeds size: %d", wordCount(v))
+		}
+	}
+}
+
+func TestSplitCodeAndTable(t *testing.T) {
+	var code, rows []string
+	for range 10 {
+		code = append(code, "fmt.Println(1, 2)")
+		rows = append(rows, "| a b | c d |")
+	}
+	content := "```go\n" + strings.Join(code, "\n") + "\n```\n\n| col one | col two |\n| --- | --- |\n" + strings.Join(rows, "\n")
+	chunks := newTestChunker(16, 0).Split(content)
+
+	var codes, tables int
+	for _, v := range chunks {
+		if strings.Contains(v, "fmt.Println") {
+			codes++
+			if !strings.HasPrefix(v, "```go\n") || !strings.HasSuffix(v, "\n```") {
+				t.Errorf("expected fenced code chunk, got %q", v)
+			}
+		}
+		if strings.Contains(v, "| a b |") {
+			tables++
+			if !strings.HasPrefix(v, "| col one | col two |\n| --- | --- |\n") {
+				t.Errorf("expected table header to be repeated, got %q", v)
+			}
+		}
+		if wordCount(v) > 16 {
+			t.Errorf("chunk exceeds size: %d", wordCount(v))
+		}
+	}
+	if codes < 2 || tables < 2 {
+		t.Errorf("expected code and table to be split, got %d code chunks and %d table chunks", codes, tables)
+	}
+}
+
+func TestSplitCodeHeadingNotParsed(t *testing.T) {
+	content := "```bash\n# not a heading\necho hi\n```\n\n# Real"
+	blocks := parseBlocks(content)
+	if len(blocks) != 2 || blocks[0].kind != blockCode || blocks[1].kind != blockHeading {
+		t.Fatalf("unexpected blocks: %+v", blocks)
+	}
+}
+
+func TestSplitLongSentence(t *testing.T) {
+	content := strings.Repeat("word ", 50)
+	chunks := newTestChunker(10, 0).Split(content)
+	if len(chunks) != 5 {
+		t.Fatalf("expected 5 chunks, got %d", len(chunks))
+	}
+}
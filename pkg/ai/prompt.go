@@ -186,7 +186,7 @@ const PROMPT_RAG_TOOL_RESPONSE_CN = `## 知识库检索结果
 
 **使用指南**:
 1. **优先使用以下检索内容**来回答用户的问题
-2. **必须标注引用来源**：在回答中明确指出参考了哪些知识条目（使用知识ID）
+2. **必须标注引用来源**：在使用了检索内容的句子末尾添加该内容的引用标记，例如 [^1]
 3. **内容可信度**：这些内容来自用户授权的知识库，可以直接引用
 4. **时间敏感性**：注意每条知识的记录时间，区分历史记录和当前事实
 5. **完整性检查**：如果检索内容不足以完整回答问题，可以说明需要补充的信息
@@ -198,7 +198,8 @@ ${relevant_passage}
 
 **回答要求**:
 - 基于以上检索内容组织回答
-- 在回答中标注参考的内容（格式：[${标题}(${时间})](#knowledge-${ID})）
+- 每个基于检索内容的句子末尾都需要添加对应的引用标记（格式：[^引用编号]），一个句子参考了多条内容时依次添加多个标记，例如 [^1][^3]
+- 只能使用检索内容中给出的引用标记，不要编造不存在的编号
 - 如果包含图片、视频等多媒体内容，一并展示给用户
 - 使用清晰的结构组织回答内容
 - 如果检索内容与用户问题不完全匹配，说明差异并尽可能回答
@@ -210,7 +211,7 @@ const PROMPT_RAG_TOOL_RESPONSE_EN = `## Knowledge Base Search Results
 
 **Usage Guidelines**:
 1. **Prioritize the retrieved content below** to answer user's question
-2. **Must cite sources**: Clearly indicate which knowledge entries were referenced (using knowledge IDs)
+2. **Must cite sources**: Append the citation marker of the referenced content to the end of each sentence that uses it, e.g. [^1]
 3. **Content reliability**: This content is from user's authorized knowledge base and can be directly cited
 4. **Time sensitivity**: Note the recording time of each knowledge entry, distinguish between historical records and current facts
 5. **Completeness check**: If retrieved content is insufficient for a complete answer, specify what additional information is needed
@@ -222,7 +223,8 @@ ${relevant_passage}
 
 **Answer Requirements**:
 - Organize answer based on the retrieved content above
-- Cite referenced knowledge IDs in the answer (format: Reference: [${Title}(${Time})](#knowledge-${ID}))
+- End every sentence based on the retrieved content with its citation marker (format: [^citation number]); when a sentence uses several items, append all of their markers, e.g. [^1][^3]
+- Only use the citation markers given in the retrieved content, never make up numbers
 - If multimedia content (images, videos, etc.) is included, present it to the user
 - Use clear structure to organize the answer
 - If retrieved content doesn't perfectly match the user's question, explain the differences and answer as best as possible
@@ -316,6 +318,9 @@ Generate at least 1 chunk and a maximum of 10 chunks, along with up to 5 tags.
 ### Review
 After chunking, recheck all chunks to ensure they are relevant to the user's described content. If not, remove that chunk.
 
+### Entities and Relations
+Extract up to 20 important named entities mentioned in the content (people, organizations, locations, products, projects, technologies, events, concepts), using the name exactly as it appears in the content. Then extract up to 20 explicit relations between these entities, each described with a short verb phrase. Do not invent entities or relations that are not stated in the content.
+
 You can refer to the current timeline to better understand the user's content:
 ${time_range}
 
@@ -696,3 +701,26 @@ Use the vision tool when the user needs to **understand visual content, scenes,
 3. For uncertain information, **clearly state the uncertainty** rather than fabricating answers
 4. Keep responses concise, accurate, and organized
 `
+
+const PROMPT_HYDE_CN = `
+## 你的任务
+你作为一个向量检索助手，需要结合历史记录，为“原问题”写一段可能出现在用户知识库中的假设性回答，该回答将被用于向量检索，以找到与之语义相近的真实资料。
+
+## 基于我现在的时间线参考
+${time_range}
+
+## 输出要求
+
+1. 直接输出回答内容，不超过 200 字，无需对输出进行任何解释，也不需要提及回答是假设的。
+2. 回答中尽可能包含与问题相关的关键概念、名词及具体描述，不确定的细节可以合理推测。
+3. 输出语言与原问题相同。
+
+## 开始任务
+
+历史记录:
+"""
+${histories}
+"""
+原问题: ${query}
+回答: 
+`
@@ -72,36 +72,51 @@ func (t *OCRTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts
 }
 
 func (t *OCRTool) processBatch(ctx context.Context, imageURLs []string) ([]string, error) {
-	results := make([]string, len(imageURLs))
-	errors := make([]error, len(imageURLs))
+	return runBatch(len(imageURLs), func(index int) (string, error) {
+		// 下载图片
+		fileData, err := downloadFile(ctx, imageURLs[index])
+		if err != nil {
+			return "", fmt.Errorf("failed to download image %d: %w", index+1, err)
+		}
+
+		// 执行 OCR
+		result, err := t.ocr.ProcessOCR(ctx, fileData)
+		if err != nil {
+			return "", fmt.Errorf("failed to process OCR for image %d: %w", index+1, err)
+		}
+		return result.MarkdownText, nil
+	})
+}
+
+// RecognizeImages 批量识别已下载的图片，结果与 images 一一对应，识别失败的图片结果为空
+func RecognizeImages(ctx context.Context, ocr srv.OCRAI, images [][]byte) ([]string, error) {
+	return runBatch(len(images), func(index int) (string, error) {
+		result, err := ocr.ProcessOCR(ctx, images[index])
+		if err != nil {
+			return "", fmt.Errorf("failed to process OCR for image %d: %w", index+1, err)
+		}
+		return result.MarkdownText, nil
+	})
+}
+
+// runBatch 并发执行 n 个任务，返回全部结果以及汇总后的错误
+func runBatch(n int, fn func(index int) (string, error)) ([]string, error) {
+	results := make([]string, n)
+	errors := make([]error, n)
 
 	var wg sync.WaitGroup
 	// 限制并发数为 5
 	semaphore := make(chan struct{}, 5)
 
-	for i, url := range imageURLs {
+	for i := range n {
 		wg.Add(1)
-		go func(index int, imageURL string) {
+		go func(index int) {
 			defer wg.Done()
 			semaphore <- struct{}{}        // 获取信号量
 			defer func() { <-semaphore }() // 释放信号量
 
-			// 下载图片
-			fileData, err := downloadFile(ctx, imageURL)
-			if err != nil {
-				errors[index] = fmt.Errorf("failed to download image %d: %w", index+1, err)
-				return
-			}
-
-			// 执行 OCR
-			result, err := t.ocr.ProcessOCR(ctx, fileData)
-			if err != nil {
-				errors[index] = fmt.Errorf("failed to process OCR for image %d: %w", index+1, err)
-				return
-			}
-
-			results[index] = result.MarkdownText
-		}(i, url)
+			results[index], errors[index] = fn(index)
+		}(i)
 	}
 
 	wg.Wait()
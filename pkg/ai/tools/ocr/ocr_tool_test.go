@@ -150,3 +150,14 @@ func TestOCRToolEmptyURLs(t *testing.T) {
 		t.Errorf("Expected 'at least one URL' error, got: %v", err)
 	}
 }
+
+func TestRecognizeImages(t *testing.T) {
+	results, err := RecognizeImages(context.Background(), &mockOCR{}, [][]byte{[]byte("a"), []byte("b")})
+	if err != nil {
+		t.Fatalf("Failed to recognize images: %v", err)
+	}
+
+	if len(results) != 2 || !strings.Contains(results[1], "test OCR result") {
+		t.Errorf("Unexpected OCR results: %v", results)
+	}
+}
@@ -0,0 +1,316 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+const (
+	// FORMAT 备份归档的格式标识，写入 manifest.json
+	FORMAT = "quka-space-backup"
+	// VERSION 当前的备份格式版本，格式发生不兼容变化时递增
+	VERSION = 1
+
+	MAX_BACKUP_SIZE      = 1 << 30   // 备份归档的最大体积
+	MAX_BACKUP_FILE_SIZE = 200 << 20 // 归档内单个文件解压后的最大体积
+)
+
+// 归档内的固定文件
+const (
+	MANIFEST_FILE      = "manifest.json"
+	RESOURCES_FILE     = "resources.json"
+	KNOWLEDGE_FILE     = "knowledge.json"
+	JOURNALS_FILE      = "journals.json"
+	CHAT_SESSIONS_FILE = "chat/sessions.json"
+	BUTLER_FILE        = "butler.json"
+	RSS_FILE           = "rss_subscriptions.json"
+	FILES_FILE         = "files.json"
+)
+
+var (
+	ErrInvalidBackup      = errors.New("not a space backup archive")
+	ErrUnsupportedVersion = errors.New("unsupported backup version")
+	ErrFileTooLarge       = errors.New("backup file is too large")
+)
+
+// Manifest 备份归档的描述信息
+type Manifest struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	ExportedAt int64  `json:"exported_at"`
+	ExportedBy string `json:"exported_by"` // 发起导出的用户ID
+	Space      Space  `json:"space"`
+	Counts     Counts `json:"counts"`
+}
+
+// Space 导出时空间的基础信息与配置
+type Space struct {
+	SpaceID     string              `json:"space_id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	BasePrompt  string              `json:"base_prompt"`
+	ChatPrompt  string              `json:"chat_prompt"`
+	Settings    types.SpaceSettings `json:"settings"`
+}
+
+// Counts 归档中各类数据的数量
+type Counts struct {
+	Resources     int `json:"resources"`
+	Knowledges    int `json:"knowledges"`
+	Journals      int `json:"journals"`
+	ChatSessions  int `json:"chat_sessions"`
+	ChatMessages  int `json:"chat_messages"`
+	ButlerTables  int `json:"butler_tables"`
+	Subscriptions int `json:"rss_subscriptions"`
+	Files         int `json:"files"`
+}
+
+// Total 恢复时需要处理的条目总数，聊天消息随所属会话一起恢复
+func (c Counts) Total() int {
+	return c.Resources + c.Knowledges + c.Journals + c.ChatSessions + c.ButlerTables + c.Subscriptions + c.Files
+}
+
+// Knowledge 知识的元数据，正文以明文保存在 Path 指向的文件中
+type Knowledge struct {
+	ID          string                     `json:"id"`
+	Kind        types.KnowledgeKind        `json:"kind"`
+	Resource    string                     `json:"resource"`
+	Title       string                     `json:"title"`
+	Tags        []string                   `json:"tags"`
+	ContentType types.KnowledgeContentType `json:"content_type"`
+	UserID      string                     `json:"user_id"`
+	MaybeDate   string                     `json:"maybe_date"`
+	Stage       types.KnowledgeStage       `json:"stage"`
+	Source      string                     `json:"source"`
+	SourceRef   string                     `json:"source_ref"`
+	CreatedAt   int64                      `json:"created_at"`
+	UpdatedAt   int64                      `json:"updated_at"`
+	ExpiredAt   int64                      `json:"expired_at"`
+	Path        string                     `json:"path"`             // 正文在归档内的路径，blocks 类型的正文为转换后的 markdown
+	Blocks      json.RawMessage            `json:"blocks,omitempty"` // blocks 类型知识的 editor.js 原始内容
+	Chunks      []Chunk                    `json:"chunks,omitempty"` // 已完成总结的知识分片，恢复时直接用于向量化
+}
+
+// Chunk 明文的知识分片
+type Chunk struct {
+	Chunk          string `json:"chunk"`
+	OriginalLength int    `json:"original_length"`
+}
+
+// Journal 明文的日记
+type Journal struct {
+	UserID    string `json:"user_id"`
+	Date      string `json:"date"`
+	Content   string `json:"content"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// ChatSession 聊天会话，消息保存在 ChatMessagesFile 指向的文件中
+type ChatSession struct {
+	types.ChatSession
+	Messages int `json:"messages"`
+}
+
+// File 空间引用的对象存储文件，内容保存在 ObjectFile 指向的文件中
+type File struct {
+	Path       string `json:"path"`        // 导出时文件在对象存储中的路径
+	UserID     string `json:"user_id"`     // 上传文件的用户，未被文件管理记录的文件为空
+	ObjectType string `json:"object_type"` // 文件管理记录中的功能模块
+	Kind       string `json:"kind"`
+	Size       int64  `json:"size"`
+	Managed    bool   `json:"managed"` // 是否存在文件管理记录
+	CreatedAt  int64  `json:"created_at"`
+}
+
+// ChatMessagesFile 会话消息在归档内的路径
+func ChatMessagesFile(sessionID string) string {
+	return "chat/messages/" + sessionID + ".json"
+}
+
+// ObjectFile 对象存储文件在归档内的路径
+func ObjectFile(objectPath string) string {
+	return "files/" + strings.TrimPrefix(path.Clean("/"+objectPath), "/")
+}
+
+var unsafeNameChars = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_", "\n", " ", "\r", " ", "\t", " ")
+
+// KnowledgePath 知识正文在归档内的路径: knowledge/<resource>/<标题>_<id>.<ext>
+func KnowledgePath(resource, title, id string, contentType types.KnowledgeContentType) string {
+	ext := ".md"
+	if contentType == types.KNOWLEDGE_CONTENT_TYPE_HTML {
+		ext = ".html"
+	}
+	return path.Join("knowledge", safeName(resource, "default"), safeName(title, "untitled")+"_"+id+ext)
+}
+
+func safeName(name, fallback string) string {
+	name = strings.TrimSpace(unsafeNameChars.Replace(name))
+	name = strings.Trim(name, ".")
+	if runes := []rune(name); len(runes) > 80 {
+		name = strings.TrimSpace(string(runes[:80]))
+	}
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// ObjectPaths 返回内容中引用的空间对象存储文件路径
+func ObjectPaths(spaceID, content string) []string {
+	if spaceID == "" || content == "" {
+		return nil
+	}
+	re := regexp.MustCompile(regexp.QuoteMeta(SpaceObjectPrefix(spaceID)) + `[^\s"'()<>\[\]\\?#]+`)
+	return re.FindAllString(content, -1)
+}
+
+// SpaceObjectPrefix 空间文件在对象存储中的路径前缀
+func SpaceObjectPrefix(spaceID string) string {
+	return path.Join(types.FIXED_S3_UPLOAD_PATH_PREFIX, spaceID) + "/"
+}
+
+// Writer 按备份格式写入 zip 归档，Close 时写入 manifest
+type Writer struct {
+	zw *zip.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(w)}
+}
+
+// WriteJSON 将 v 以 JSON 格式写入归档内的 name
+func (w *Writer) WriteJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	return w.WriteFile(name, data)
+}
+
+// WriteFile 写入归档内的 name
+func (w *Writer) WriteFile(name string, data []byte) error {
+	f, err := w.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	if _, err = f.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Close 写入 manifest 并结束归档
+func (w *Writer) Close(manifest Manifest) error {
+	manifest.Format = FORMAT
+	manifest.Version = VERSION
+	if manifest.ExportedAt == 0 {
+		manifest.ExportedAt = time.Now().Unix()
+	}
+	if err := w.WriteJSON(MANIFEST_FILE, manifest); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// Reader 读取备份归档
+type Reader struct {
+	Manifest Manifest
+	files    map[string]*zip.File
+}
+
+// Open 打开备份归档并校验格式与版本
+func Open(data []byte) (*Reader, error) {
+	if len(data) > MAX_BACKUP_SIZE {
+		return nil, ErrFileTooLarge
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+
+	r := &Reader{
+		files: make(map[string]*zip.File, len(zr.File)),
+	}
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() {
+			r.files[strings.TrimPrefix(path.Clean("/"+f.Name), "/")] = f
+		}
+	}
+
+	if _, ok := r.files[MANIFEST_FILE]; !ok {
+		return nil, ErrInvalidBackup
+	}
+	if err = r.ReadJSON(MANIFEST_FILE, &r.Manifest); err != nil {
+		return nil, err
+	}
+	if r.Manifest.Format != FORMAT {
+		return nil, ErrInvalidBackup
+	}
+	if r.Manifest.Version < 1 || r.Manifest.Version > VERSION {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, r.Manifest.Version)
+	}
+	return r, nil
+}
+
+// Has 归档内是否存在 name
+func (r *Reader) Has(name string) bool {
+	_, ok := r.files[name]
+	return ok
+}
+
+// ReadJSON 读取归档内的 JSON 文件，文件不存在时 v 保持不变
+func (r *Reader) ReadJSON(name string, v any) error {
+	if !r.Has(name) {
+		return nil
+	}
+	data, err := r.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// ReadFile 读取归档内的文件
+func (r *Reader) ReadFile(name string) ([]byte, error) {
+	f, ok := r.files[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in backup", name)
+	}
+	if f.UncompressedSize64 > MAX_BACKUP_FILE_SIZE {
+		return nil, ErrFileTooLarge
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, MAX_BACKUP_FILE_SIZE+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if len(data) > MAX_BACKUP_FILE_SIZE {
+		return nil, ErrFileTooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+func TestWriterReader(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	knowledges := []Knowledge{{ID: "k1", Title: "Hello", Path: KnowledgePath("", "Hello", "k1", types.KNOWLEDGE_CONTENT_TYPE_MARKDOWN)}}
+	if err := w.WriteJSON(KNOWLEDGE_FILE, knowledges); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteFile(knowledges[0].Path, []byte("# Hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(Manifest{Space: Space{SpaceID: "s1", Title: "Space"}, Counts: Counts{Knowledges: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Open(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Manifest.Format != FORMAT || r.Manifest.Version != VERSION || r.Manifest.ExportedAt == 0 || r.Manifest.Space.SpaceID != "s1" {
+		t.Errorf("unexpected manifest: %+v", r.Manifest)
+	}
+
+	var got []Knowledge
+	if err = r.ReadJSON(KNOWLEDGE_FILE, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, knowledges) {
+		t.Errorf("unexpected knowledges: %+v", got)
+	}
+	if content, err := r.ReadFile(got[0].Path); err != nil || string(content) != "# Hello" {
+		t.Errorf("unexpected content: %q %v", content, err)
+	}
+
+	// 缺失的文件不会覆盖默认值
+	journals := []Journal{}
+	if err = r.ReadJSON(JOURNALS_FILE, &journals); err != nil || len(journals) != 0 {
+		t.Errorf("unexpected journals: %+v %v", journals, err)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.WriteJSON(MANIFEST_FILE, Manifest{Format: FORMAT, Version: VERSION + 1}); err != nil {
+		t.Fatal(err)
+	}
+	w.zw.Close()
+	if _, err := Open(buf.Bytes()); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+
+	buf.Reset()
+	w = NewWriter(&buf)
+	w.WriteFile("note.md", []byte("hello"))
+	w.zw.Close()
+	if _, err := Open(buf.Bytes()); err != ErrInvalidBackup {
+		t.Errorf("expected ErrInvalidBackup, got %v", err)
+	}
+}
+
+func TestKnowledgePath(t *testing.T) {
+	cases := []struct {
+		resource, title, id string
+		contentType         types.KnowledgeContentType
+		want                string
+	}{
+		{"", "", "1", types.KNOWLEDGE_CONTENT_TYPE_MARKDOWN, "knowledge/default/untitled_1.md"},
+		{"notes", "a/b: c?", "2", types.KNOWLEDGE_CONTENT_TYPE_BLOCKS, "knowledge/notes/a_b_ c__2.md"},
+		{"web", "..page..", "3", types.KNOWLEDGE_CONTENT_TYPE_HTML, "knowledge/web/page_3.html"},
+	}
+	for _, c := range cases {
+		if got := KnowledgePath(c.resource, c.title, c.id, c.contentType); got != c.want {
+			t.Errorf("KnowledgePath(%q, %q) = %q, want %q", c.resource, c.title, got, c.want)
+		}
+	}
+}
+
+func TestObjectPaths(t *testing.T) {
+	content := `![img](/assets/s3/s1/knowledge/20240101/a.png) <img src="https://cdn.example.com/assets/s3/s1/chat/b.jpg?x=1"> /assets/s3/s2/c.png`
+	want := []string{"/assets/s3/s1/knowledge/20240101/a.png", "/assets/s3/s1/chat/b.jpg"}
+	if got := ObjectPaths("s1", content); !reflect.DeepEqual(got, want) {
+		t.Errorf("ObjectPaths() = %v, want %v", got, want)
+	}
+	if got := ObjectFile(want[0]); got != "files/assets/s3/s1/knowledge/20240101/a.png" {
+		t.Errorf("ObjectFile() = %q", got)
+	}
+}
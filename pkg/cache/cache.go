@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// GetObject 读取缓存并反序列化到 v，缓存未命中时返回 false
+func GetObject(ctx context.Context, c types.Cache, key string, v any) (bool, error) {
+	raw, err := c.Get(ctx, key)
+	if err != nil || raw == "" {
+		return false, err
+	}
+	if err = json.Unmarshal([]byte(raw), v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetObject 将 v 序列化后写入缓存
+func SetObject(ctx context.Context, c types.Cache, key string, v any, ttl time.Duration) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.SetEx(ctx, key, string(raw), ttl)
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// DEFAULT_LRU_SIZE 进程内缓存默认最大条目数
+const DEFAULT_LRU_SIZE = 10000
+
+var _ types.Cache = (*LRU)(nil)
+
+type lruEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// LRU 进程内的 LRU 缓存，支持按 key 设置过期时间，适用于单机部署
+// 条目数超过 size 时淘汰最久未访问的条目，过期条目在访问时惰性删除
+type LRU struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+	now   func() time.Time
+}
+
+func NewLRU(size int) *LRU {
+	if size <= 0 {
+		size = DEFAULT_LRU_SIZE
+	}
+	return &LRU{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+		now:   time.Now,
+	}
+}
+
+func (c *LRU) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return "", nil
+	}
+	entry := el.Value.(*lruEntry)
+	if c.expired(entry) {
+		c.remove(el)
+		return "", nil
+	}
+	c.ll.MoveToFront(el)
+	return entry.value, nil
+}
+
+// SetEx 写入缓存，expiresAt 小于等于 0 时不过期
+func (c *LRU) SetEx(ctx context.Context, key, value string, expiresAt time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = c.deadline(expiresAt)
+		c.ll.MoveToFront(el)
+		return nil
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry{
+		key:       key,
+		value:     value,
+		expiresAt: c.deadline(expiresAt),
+	})
+	for c.ll.Len() > c.size {
+		c.remove(c.ll.Back())
+	}
+	return nil
+}
+
+// Expire 重新设置 key 的过期时间，key 不存在时忽略
+func (c *LRU) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+	entry := el.Value.(*lruEntry)
+	if c.expired(entry) {
+		c.remove(el)
+		return nil
+	}
+	entry.expiresAt = c.deadline(expiration)
+	return nil
+}
+
+func (c *LRU) Del(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.remove(el)
+		}
+	}
+	return nil
+}
+
+// Len 返回当前缓存的条目数，包含尚未被惰性删除的过期条目
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *LRU) deadline(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return c.now().Add(ttl)
+}
+
+func (c *LRU) expired(entry *lruEntry) bool {
+	return !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt)
+}
+
+func (c *LRU) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*lruEntry).key)
+}
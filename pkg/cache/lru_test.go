@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUEviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	c.SetEx(ctx, "a", "1", time.Minute)
+	c.SetEx(ctx, "b", "2", time.Minute)
+	// 访问 a 后 b 成为最久未访问的条目
+	if v, _ := c.Get(ctx, "a"); v != "1" {
+		t.Fatalf("expected a=1, got %q", v)
+	}
+	c.SetEx(ctx, "c", "3", time.Minute)
+
+	if v, _ := c.Get(ctx, "b"); v != "" {
+		t.Errorf("expected b to be evicted, got %q", v)
+	}
+	if v, _ := c.Get(ctx, "c"); v != "3" {
+		t.Errorf("expected c=3, got %q", v)
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.Len())
+	}
+
+	c.Del(ctx, "a", "unknown")
+	if v, _ := c.Get(ctx, "a"); v != "" {
+		t.Errorf("expected a to be deleted, got %q", v)
+	}
+}
+
+func TestLRUExpire(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+
+	c.SetEx(ctx, "a", "1", time.Second)
+	c.SetEx(ctx, "b", "2", 0)
+
+	now = now.Add(2 * time.Second)
+	if v, _ := c.Get(ctx, "a"); v != "" {
+		t.Errorf("expected a to be expired, got %q", v)
+	}
+	if v, _ := c.Get(ctx, "b"); v != "2" {
+		t.Errorf("expected b without ttl to be kept, got %q", v)
+	}
+
+	c.Expire(ctx, "b", time.Second)
+	now = now.Add(time.Second)
+	if v, _ := c.Get(ctx, "b"); v != "" {
+		t.Errorf("expected b to be expired after Expire, got %q", v)
+	}
+	if c.Len() != 0 {
+		t.Errorf("expected expired entries to be removed, got %d", c.Len())
+	}
+}
+
+func TestObject(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+
+	type item struct {
+		Name string `json:"name"`
+	}
+	var v item
+	if ok, err := GetObject(ctx, c, "item", &v); ok || err != nil {
+		t.Fatalf("expected miss, got %v %v", ok, err)
+	}
+
+	if err := SetObject(ctx, c, "item", item{Name: "quka"}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := GetObject(ctx, c, "item", &v); !ok || err != nil || v.Name != "quka" {
+		t.Fatalf("expected hit, got %v %v %+v", ok, err, v)
+	}
+}
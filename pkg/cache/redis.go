@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+var _ types.Cache = (*Redis)(nil)
+
+// Redis 基于 Redis 的缓存，多节点部署时各节点共享缓存数据
+type Redis struct {
+	client redis.UniversalClient
+}
+
+func NewRedis(client redis.UniversalClient) *Redis {
+	return &Redis{
+		client: client,
+	}
+}
+
+// Get key 不存在时返回空字符串，与 LRU 行为保持一致
+func (c *Redis) Get(ctx context.Context, key string) (string, error) {
+	res, err := c.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return res, err
+}
+
+func (c *Redis) SetEx(ctx context.Context, key, value string, expiresAt time.Duration) error {
+	return c.client.SetEx(ctx, key, value, expiresAt).Err()
+}
+
+func (c *Redis) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return c.client.Expire(ctx, key, expiration).Err()
+}
+
+func (c *Redis) Del(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
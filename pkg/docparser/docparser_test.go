@@ -0,0 +1,255 @@
+package docparser
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// 1x1 像素的 PNG 图片
+var testPNG, _ = base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==")
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func assertContains(t *testing.T, content string, expected ...string) {
+	t.Helper()
+	for _, v := range expected {
+		if !strings.Contains(content, v) {
+			t.Errorf("expected %q in:\n%s", v, content)
+		}
+	}
+}
+
+func TestParseHTML(t *testing.T) {
+	page := `<html><head><title>测试页面</title><style>.a{}</style></head><body>
+<nav>导航</nav>
+<h1>Getting <em>Started</em></h1>
+<p>Hello <strong>world</strong>, see <a href="https://example.com">docs</a>.</p>
+<ul><li>one<ul><li>nested</li></ul></li><li>two</li></ul>
+<ol><li>first</li><li>second</li></ol>
+<pre><code class="language-go">fmt.Println("hi")
+</code></pre>
+<table><tr><th>Name</th><th>Value</th></tr><tr><td>a|b</td><td>1</td></tr></table>
+<blockquote><p>quoted</p></blockquote>
+<img src="data:image/png;base64,` + base64.StdEncoding.EncodeToString(testPNG) + `">
+<img src="https://example.com/a.png" alt="remote">
+<script>alert(1)</script>
+</body></html>`
+
+	doc, err := Parse("page.html", []byte(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "测试页面" {
+		t.Errorf("unexpected title %q", doc.Title)
+	}
+	assertContains(t, doc.Markdown,
+		"# Getting *Started*",
+		"Hello **world**, see [docs](https://example.com).",
+		"- one\n  - nested\n- two",
+		"1. first\n2. second",
+		"```go\nfmt.Println(\"hi\")\n```",
+		"| Name | Value |\n| --- | --- |\n| a\\|b | 1 |",
+		"> quoted",
+		"![](docparser://image/image-1.png)",
+		"![remote](https://example.com/a.png)",
+	)
+	if strings.Contains(doc.Markdown, "导航") || strings.Contains(doc.Markdown, "alert") {
+		t.Errorf("unexpected navigation or script content:\n%s", doc.Markdown)
+	}
+	if len(doc.Images) != 1 || doc.Images[0].ContentType != "image/png" {
+		t.Fatalf("unexpected images %+v", doc.Images)
+	}
+
+	doc.AppendImageText(map[string]string{"image-1.png": "图片中的文字"})
+	assertContains(t, doc.Markdown, "![](docparser://image/image-1.png)\n\n图片中的文字")
+	doc.ReplaceImageURLs(map[string]string{"image-1.png": "https://cdn.example.com/image-1.png"})
+	assertContains(t, doc.Markdown, "![](https://cdn.example.com/image-1.png)")
+	doc.ReplaceImageURLs(nil)
+	assertContains(t, doc.Markdown, "![](https://cdn.example.com/image-1.png)")
+}
+
+func TestParseDOCX(t *testing.T) {
+	document := `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<w:body>
+<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t>年度报告</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Overview</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Plain </w:t></w:r><w:r><w:t>paragraph</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>step one</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>detail</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>step two</w:t></w:r></w:p>
+<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Q</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Revenue</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>Q1</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>100</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+<w:p><w:r><w:drawing><a:graphic><a:graphicData><a:blip r:embed="rId5"/></a:graphicData></a:graphic></w:drawing></w:r></w:p>
+</w:body></w:document>`
+	numbering := `<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/></w:lvl><w:lvl w:ilvl="1"><w:numFmt w:val="bullet"/></w:lvl></w:abstractNum>
+<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num></w:numbering>`
+	rels := `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId5" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1.png"/></Relationships>`
+
+	data := buildZip(t, map[string]string{
+		"word/document.xml":            document,
+		"word/numbering.xml":           numbering,
+		"word/_rels/document.xml.rels": rels,
+		"word/media/image1.png":        string(testPNG),
+	})
+	doc, err := Parse("report.docx", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "年度报告" {
+		t.Errorf("unexpected title %q", doc.Title)
+	}
+	assertContains(t, doc.Markdown,
+		"# 年度报告",
+		"# Overview",
+		"Plain paragraph",
+		"1. step one\n  - detail\n2. step two",
+		"| Q | Revenue |\n| --- | --- |\n| Q1 | 100 |",
+		"![](docparser://image/image-1.png)",
+	)
+	if len(doc.Images) != 1 {
+		t.Fatalf("expected 1 image, got %d", len(doc.Images))
+	}
+}
+
+func TestParsePPTX(t *testing.T) {
+	slide := func(title, body string) string {
+		return `<p:sld xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><p:cSld><p:spTree>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="title"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>` + title + `</a:t></a:r></a:p></p:txBody></p:sp>
+<p:sp><p:txBody>` + body + `</p:txBody></p:sp>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="sldNum"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>99</a:t></a:r></a:p></p:txBody></p:sp>
+</p:spTree></p:cSld></p:sld>`
+	}
+
+	data := buildZip(t, map[string]string{
+		"ppt/slides/slide2.xml":  slide("Roadmap", `<a:p><a:r><a:t>Q1</a:t></a:r></a:p><a:p><a:pPr lvl="1"/><a:r><a:t>launch</a:t></a:r></a:p>`),
+		"ppt/slides/slide1.xml":  slide("Welcome", `<a:p><a:r><a:t>Intro text</a:t></a:r></a:p>`),
+		"ppt/slides/slide10.xml": slide("", `<a:p><a:r><a:t>Last</a:t></a:r></a:p>`),
+	})
+	doc, err := Parse("deck.pptx", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "Welcome" {
+		t.Errorf("unexpected title %q", doc.Title)
+	}
+	expected := "## Welcome\n\nIntro text\n\n## Roadmap\n\n- Q1\n  - launch\n\n## Slide 3\n\nLast"
+	if doc.Markdown != expected {
+		t.Errorf("unexpected markdown:\n%s", doc.Markdown)
+	}
+}
+
+func TestParseEPUB(t *testing.T) {
+	data := buildZip(t, map[string]string{
+		"META-INF/container.xml": `<container><rootfiles><rootfile full-path="OEBPS/content.opf"/></rootfiles></container>`,
+		"OEBPS/content.opf": `<package xmlns:dc="http://purl.org/dc/elements/1.1/"><metadata><dc:title>Go 之旅</dc:title></metadata>
+<manifest><item id="c2" href="text/ch2.xhtml" media-type="application/xhtml+xml"/><item id="c1" href="text/ch1.xhtml" media-type="application/xhtml+xml"/></manifest>
+<spine><itemref idref="c1"/><itemref idref="c2"/></spine></package>`,
+		"OEBPS/text/ch1.xhtml":   `<html><body><h1>第一章</h1><p>开始</p><img src="../images/cover.png"/></body></html>`,
+		"OEBPS/text/ch2.xhtml":   `<html><body><h1>第二章</h1><p>继续</p><img src="../images/cover.png"/></body></html>`,
+		"OEBPS/images/cover.png": string(testPNG),
+	})
+	doc, err := Parse("book.epub", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "Go 之旅" {
+		t.Errorf("unexpected title %q", doc.Title)
+	}
+	expected := "# 第一章\n\n开始\n\n![](docparser://image/image-1.png)\n\n# 第二章\n\n继续\n\n![](docparser://image/image-1.png)"
+	if doc.Markdown != expected {
+		t.Errorf("unexpected markdown:\n%s", doc.Markdown)
+	}
+	if len(doc.Images) != 1 {
+		t.Errorf("expected the shared image to be collected once, got %d", len(doc.Images))
+	}
+}
+
+// buildPDF 生成最小可用的 PDF，内容流使用 FlateDecode 压缩
+func buildPDF(t *testing.T, content string) []byte {
+	t.Helper()
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write([]byte(content))
+	w.Close()
+
+	cmap := "/CIDInit /ProcSet findresource begin\nbegincmap\n1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"2 beginbfchar <0001> <4E2D> <0002> <6587> endbfchar\nendcmap"
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 4 0 R /F2 6 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /SimSun /Encoding /Identity-H /ToUnicode 7 0 R >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(cmap), cmap),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	for i, v := range objects {
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, v)
+	}
+	buf.WriteString("trailer\n<< /Root 1 0 R /Info << /Title (Quarterly Report) >> >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+func TestParsePDF(t *testing.T) {
+	content := `BT /F1 24 Tf 72 720 Td (Annual Summary) Tj ET
+BT /F1 12 Tf 72 690 Td (The first line of body text) Tj 0 -14 Td [(continues ) -300 (here.)] TJ ET
+BT /F1 12 Tf 72 600 Td (A new paragraph) Tj ET
+BT /F2 12 Tf 72 560 Td <00010002> Tj ET`
+
+	doc, err := Parse("report.pdf", buildPDF(t, content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "Quarterly Report" {
+		t.Errorf("unexpected title %q", doc.Title)
+	}
+	expected := "# Annual Summary\n\nThe first line of body text\ncontinues here.\n\nA new paragraph\n\n中文"
+	if doc.Markdown != expected {
+		t.Errorf("unexpected markdown:\n%s", doc.Markdown)
+	}
+
+	if _, err = Parse("broken.pdf", []byte("not a pdf")); err == nil {
+		t.Error("expected error for invalid pdf")
+	}
+}
+
+func TestParseUnsupported(t *testing.T) {
+	if Supported("a.xlsx") {
+		t.Error("xlsx should not be supported")
+	}
+	if !Supported("A.PDF") {
+		t.Error("extension match should be case insensitive")
+	}
+	if _, err := Parse("a.xlsx", nil); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
@@ -0,0 +1,344 @@
+package docparser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	docxMainPart      = "word/document.xml"
+	docxStylesPart    = "word/styles.xml"
+	docxNumberingPart = "word/numbering.xml"
+)
+
+// ParseDOCX 解析 Word 文档，保留标题、列表、表格与内嵌图片
+func ParseDOCX(data []byte) (*Document, error) {
+	archive, err := openZip(data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := archive.read(docxMainPart)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, errors.New("word/document.xml not found")
+	}
+	rels, err := archive.relationships(docxMainPart)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &docxParser{
+		archive:  archive,
+		rels:     rels,
+		images:   newImageCollector(),
+		headings: docxHeadingStyles(archive),
+		ordered:  docxOrderedLists(archive),
+	}
+	if err = p.parse(body); err != nil {
+		return nil, err
+	}
+	return &Document{
+		Title:    p.title,
+		Markdown: strings.Join(p.blocks, "\n\n"),
+		Images:   p.images.images,
+	}, nil
+}
+
+type docxParser struct {
+	archive  *zipArchive
+	rels     map[string]string
+	images   *imageCollector
+	headings map[string]int
+	ordered  map[string]bool
+
+	title  string
+	blocks []string
+	// list 连续的列表项合并为一个块
+	list    []string
+	counter map[string]int
+}
+
+// docxParagraph 段落解析结果
+type docxParagraph struct {
+	style    string
+	numID    string
+	numLevel int
+	text     strings.Builder
+}
+
+func (p *docxParser) parse(body []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "p":
+			para, err := p.paragraph(decoder)
+			if err != nil {
+				return err
+			}
+			p.addParagraph(para)
+		case "tbl":
+			table, err := p.table(decoder)
+			if err != nil {
+				return err
+			}
+			p.addBlock(table)
+		}
+	}
+	p.flushList()
+	return nil
+}
+
+func (p *docxParser) addBlock(block string) {
+	p.flushList()
+	if strings.TrimSpace(block) != "" {
+		p.blocks = append(p.blocks, block)
+	}
+}
+
+func (p *docxParser) flushList() {
+	if len(p.list) > 0 {
+		p.blocks = append(p.blocks, strings.Join(p.list, "\n"))
+		p.list = nil
+		p.counter = nil
+	}
+}
+
+func (p *docxParser) addParagraph(para *docxParagraph) {
+	text := strings.TrimSpace(para.text.String())
+	if text == "" {
+		return
+	}
+
+	if level, ok := p.headings[para.style]; ok {
+		if level == 0 {
+			if p.title == "" {
+				p.title = collapseSpace(text)
+			}
+			level = 1
+		}
+		p.addBlock(heading(level, text))
+		return
+	}
+
+	if para.numID != "" && para.numID != "0" {
+		if p.counter == nil {
+			p.counter = make(map[string]int)
+		}
+		key := para.numID + ":" + strconv.Itoa(para.numLevel)
+		p.counter[key]++
+		p.list = append(p.list, listItem(para.numLevel, p.ordered[key], p.counter[key], text))
+		return
+	}
+	p.addBlock(text)
+}
+
+// paragraph 读取 <w:p> 直到结束标签
+func (p *docxParser) paragraph(decoder *xml.Decoder) (*docxParagraph, error) {
+	para := &docxParagraph{}
+	var (
+		depth  = 1
+		inText bool
+	)
+	for depth > 0 {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch t.Name.Local {
+			case "pStyle":
+				para.style = xmlAttr(t, "val")
+			case "numId":
+				para.numID = xmlAttr(t, "val")
+			case "ilvl":
+				para.numLevel, _ = strconv.Atoi(xmlAttr(t, "val"))
+			case "t":
+				inText = true
+			case "tab":
+				para.text.WriteString(" ")
+			case "br", "cr":
+				para.text.WriteString("\n")
+			case "blip":
+				if target, ok := p.rels[xmlAttr(t, "embed")]; ok {
+					if data, err := p.archive.read(target); err == nil && data != nil {
+						para.text.WriteString(p.images.add(target, data))
+					}
+				}
+			}
+		case xml.EndElement:
+			depth--
+			if t.Name.Local == "t" {
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				para.text.Write(t)
+			}
+		}
+	}
+	return para, nil
+}
+
+// table 读取 <w:tbl>，单元格中的多个段落以空格连接
+func (p *docxParser) table(decoder *xml.Decoder) (string, error) {
+	var (
+		rows  [][]string
+		row   []string
+		cell  []string
+		depth = 1
+	)
+	for depth > 0 {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tr":
+				row = nil
+			case "tc":
+				cell = nil
+			case "p":
+				para, err := p.paragraph(decoder)
+				if err != nil {
+					return "", err
+				}
+				if text := strings.TrimSpace(para.text.String()); text != "" {
+					cell = append(cell, text)
+				}
+				continue
+			case "tbl":
+				// 嵌套表格展开为所在单元格的文本
+				nested, err := p.table(decoder)
+				if err != nil {
+					return "", err
+				}
+				cell = append(cell, nested)
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			switch t.Name.Local {
+			case "tc":
+				row = append(row, strings.Join(cell, " "))
+			case "tr":
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+	return renderTable(rows), nil
+}
+
+func xmlAttr(start xml.StartElement, local string) string {
+	for _, v := range start.Attr {
+		if v.Name.Local == local {
+			return v.Value
+		}
+	}
+	return ""
+}
+
+// docxHeadingStyles 返回样式 ID 到标题级别的映射，0 表示文档标题
+// 自定义样式名称与内置样式不同，因此通过 styles.xml 中的样式名识别
+func docxHeadingStyles(archive *zipArchive) map[string]int {
+	result := map[string]int{"Title": 0}
+	for i := 1; i <= 9; i++ {
+		result["Heading"+strconv.Itoa(i)] = i
+	}
+
+	data, err := archive.read(docxStylesPart)
+	if err != nil || data == nil {
+		return result
+	}
+	var styles struct {
+		Styles []struct {
+			ID   string `xml:"styleId,attr"`
+			Name struct {
+				Val string `xml:"val,attr"`
+			} `xml:"name"`
+		} `xml:"style"`
+	}
+	if err = xml.Unmarshal(data, &styles); err != nil {
+		return result
+	}
+	for _, v := range styles.Styles {
+		name := strings.ToLower(strings.ReplaceAll(v.Name.Val, " ", ""))
+		if name == "title" {
+			result[v.ID] = 0
+			continue
+		}
+		if level, err := strconv.Atoi(strings.TrimPrefix(name, "heading")); err == nil && strings.HasPrefix(name, "heading") {
+			result[v.ID] = level
+		}
+	}
+	return result
+}
+
+// docxOrderedLists 返回 numId:ilvl 是否为有序列表
+func docxOrderedLists(archive *zipArchive) map[string]bool {
+	result := make(map[string]bool)
+	data, err := archive.read(docxNumberingPart)
+	if err != nil || data == nil {
+		return result
+	}
+
+	type level struct {
+		Ilvl   string `xml:"ilvl,attr"`
+		NumFmt struct {
+			Val string `xml:"val,attr"`
+		} `xml:"numFmt"`
+	}
+	var numbering struct {
+		AbstractNums []struct {
+			ID     string  `xml:"abstractNumId,attr"`
+			Levels []level `xml:"lvl"`
+		} `xml:"abstractNum"`
+		Nums []struct {
+			ID            string `xml:"numId,attr"`
+			AbstractNumID struct {
+				Val string `xml:"val,attr"`
+			} `xml:"abstractNumId"`
+		} `xml:"num"`
+	}
+	if err = xml.Unmarshal(data, &numbering); err != nil {
+		return result
+	}
+
+	abstract := make(map[string][]level, len(numbering.AbstractNums))
+	for _, v := range numbering.AbstractNums {
+		abstract[v.ID] = v.Levels
+	}
+	for _, num := range numbering.Nums {
+		for _, lvl := range abstract[num.AbstractNumID.Val] {
+			format := lvl.NumFmt.Val
+			result[num.ID+":"+lvl.Ilvl] = format != "" && format != "bullet" && format != "none"
+		}
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package docparser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ParseEPUB 按书脊顺序解析 EPUB 电子书的章节，章节内的图片会被提取
+func ParseEPUB(data []byte) (*Document, error) {
+	archive, err := openZip(data)
+	if err != nil {
+		return nil, err
+	}
+
+	opfPath, err := epubPackagePath(archive)
+	if err != nil {
+		return nil, err
+	}
+	opfData, err := archive.read(opfPath)
+	if err != nil {
+		return nil, err
+	}
+	if opfData == nil {
+		return nil, errors.New("epub package document not found")
+	}
+
+	var pkg struct {
+		Title    []string `xml:"metadata>title"`
+		Manifest []struct {
+			ID        string `xml:"id,attr"`
+			Href      string `xml:"href,attr"`
+			MediaType string `xml:"media-type,attr"`
+		} `xml:"manifest>item"`
+		Spine []struct {
+			IDRef  string `xml:"idref,attr"`
+			Linear string `xml:"linear,attr"`
+		} `xml:"spine>itemref"`
+	}
+	if err = xml.Unmarshal(opfData, &pkg); err != nil {
+		return nil, err
+	}
+
+	var (
+		baseDir  = path.Dir(opfPath)
+		manifest = make(map[string]string, len(pkg.Manifest))
+		images   = newImageCollector()
+		chapters []string
+	)
+	for _, v := range pkg.Manifest {
+		if v.MediaType == "application/xhtml+xml" || v.MediaType == "text/html" {
+			href, _ := url.PathUnescape(v.Href)
+			manifest[v.ID] = resolvePath(baseDir, href)
+		}
+	}
+
+	for _, item := range pkg.Spine {
+		if item.Linear == "no" {
+			continue
+		}
+		name, ok := manifest[item.IDRef]
+		if !ok {
+			continue
+		}
+		content, err := archive.read(name)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			continue
+		}
+
+		root, err := html.Parse(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		chapterDir := path.Dir(name)
+		resolve := func(src string) (string, []byte) {
+			src, _ = url.PathUnescape(src)
+			target := resolvePath(chapterDir, src)
+			data, err := archive.read(target)
+			if err != nil {
+				return "", nil
+			}
+			return target, data
+		}
+		if chapter := newHTMLConverter(images, resolve).convert(root); strings.TrimSpace(chapter) != "" {
+			chapters = append(chapters, chapter)
+		}
+	}
+
+	doc := &Document{
+		Markdown: strings.Join(chapters, "\n\n"),
+		Images:   images.images,
+	}
+	if len(pkg.Title) > 0 {
+		doc.Title = strings.TrimSpace(pkg.Title[0])
+	}
+	return doc, nil
+}
+
+// epubPackagePath 从 META-INF/container.xml 中读取 OPF 文件路径
+func epubPackagePath(archive *zipArchive) (string, error) {
+	data, err := archive.read("META-INF/container.xml")
+	if err != nil {
+		return "", err
+	}
+	if data == nil {
+		return "", errors.New("META-INF/container.xml not found")
+	}
+
+	var container struct {
+		Rootfiles []struct {
+			FullPath string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err = xml.Unmarshal(data, &container); err != nil {
+		return "", err
+	}
+	if len(container.Rootfiles) == 0 || container.Rootfiles[0].FullPath == "" {
+		return "", errors.New("epub rootfile not found")
+	}
+	return container.Rootfiles[0].FullPath, nil
+}
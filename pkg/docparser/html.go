@@ -0,0 +1,345 @@
+package docparser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// htmlConverter 将 HTML 转换为 markdown，保留标题、列表、表格、代码块与图片
+type htmlConverter struct {
+	images *imageCollector
+	// resolveImage 读取文档内部引用的图片，返回的 key 用于去重，data 为 nil 时按外链图片处理
+	resolveImage func(src string) (key string, data []byte)
+
+	blocks []string
+	inline strings.Builder
+}
+
+func newHTMLConverter(images *imageCollector, resolveImage func(src string) (string, []byte)) *htmlConverter {
+	return &htmlConverter{
+		images:       images,
+		resolveImage: resolveImage,
+	}
+}
+
+// ParseHTML 解析 HTML 文档，外链图片保留原始地址，data URI 图片会被提取
+func ParseHTML(data []byte) (*Document, error) {
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	images := newImageCollector()
+	markdown := newHTMLConverter(images, nil).convert(root)
+	return &Document{
+		Title:    htmlTitle(root),
+		Markdown: markdown,
+		Images:   images.images,
+	}, nil
+}
+
+// htmlTitle 优先使用 <title>，不存在时使用第一个 <h1>
+func htmlTitle(root *html.Node) string {
+	if n := findElement(root, atom.Title); n != nil {
+		if title := collapseSpace(textContent(n)); strings.TrimSpace(title) != "" {
+			return strings.TrimSpace(title)
+		}
+	}
+	if n := findElement(root, atom.H1); n != nil {
+		return strings.TrimSpace(collapseSpace(textContent(n)))
+	}
+	return ""
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, v := range n.Attr {
+		if v.Key == key {
+			return v.Val
+		}
+	}
+	return ""
+}
+
+var skipElements = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Iframe:   true,
+	atom.Nav:      true,
+	atom.Button:   true,
+	atom.Select:   true,
+}
+
+var inlineElements = map[atom.Atom]bool{
+	atom.A: true, atom.Abbr: true, atom.B: true, atom.Br: true, atom.Cite: true, atom.Code: true,
+	atom.Em: true, atom.Font: true, atom.I: true, atom.Img: true, atom.Kbd: true, atom.Label: true,
+	atom.Mark: true, atom.Q: true, atom.S: true, atom.Small: true, atom.Span: true, atom.Strong: true,
+	atom.Sub: true, atom.Sup: true, atom.Time: true, atom.U: true, atom.Del: true, atom.Ins: true,
+}
+
+func (c *htmlConverter) convert(root *html.Node) string {
+	c.walk(root)
+	c.flushInline()
+	return strings.Join(c.blocks, "\n\n")
+}
+
+func (c *htmlConverter) addBlock(block string) {
+	if strings.TrimSpace(block) != "" {
+		c.blocks = append(c.blocks, block)
+	}
+}
+
+func (c *htmlConverter) flushInline() {
+	text := strings.TrimSpace(c.inline.String())
+	c.inline.Reset()
+	if text != "" {
+		c.addBlock(text)
+	}
+}
+
+// walk 遍历块级元素的子节点，连续的行内内容合并为一个段落
+func (c *htmlConverter) walk(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case html.TextNode:
+			c.inline.WriteString(collapseSpace(child.Data))
+		case html.ElementNode:
+			if skipElements[child.DataAtom] {
+				continue
+			}
+			if inlineElements[child.DataAtom] {
+				c.inline.WriteString(c.inlineText(child))
+				continue
+			}
+			c.flushInline()
+			c.block(child)
+		}
+	}
+}
+
+func (c *htmlConverter) block(n *html.Node) {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		c.addBlock(heading(int(n.Data[1]-'0'), c.inlineText(n)))
+	case atom.P:
+		c.addBlock(strings.TrimSpace(c.inlineText(n)))
+	case atom.Ul, atom.Ol:
+		c.addBlock(strings.Join(c.list(n, 0), "\n"))
+	case atom.Pre:
+		c.addBlock(codeBlock(n))
+	case atom.Table:
+		c.addBlock(c.table(n))
+	case atom.Hr:
+		c.addBlock("---")
+	case atom.Blockquote:
+		sub := newHTMLConverter(c.images, c.resolveImage)
+		lines := strings.Split(sub.convert(n), "\n")
+		for i, v := range lines {
+			lines[i] = strings.TrimRight("> "+v, " ")
+		}
+		c.addBlock(strings.Join(lines, "\n"))
+	default:
+		c.walk(n)
+		c.flushInline()
+	}
+}
+
+// inlineText 将行内元素转换为 markdown 文本，内部的块级元素按行内内容处理
+func (c *htmlConverter) inlineText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return collapseSpace(n.Data)
+	}
+	if n.Type != html.ElementNode || skipElements[n.DataAtom] {
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return "\n"
+	case atom.Img:
+		return c.image(attr(n, "src"), attr(n, "alt"))
+	case atom.Code:
+		if text := strings.TrimSpace(textContent(n)); text != "" {
+			return "`" + text + "`"
+		}
+		return ""
+	}
+
+	var sb strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		sb.WriteString(c.inlineText(child))
+	}
+	inner := sb.String()
+	trimmed := strings.TrimSpace(inner)
+	if trimmed == "" {
+		return inner
+	}
+
+	switch n.DataAtom {
+	case atom.Strong, atom.B:
+		return wrapInline(inner, "**")
+	case atom.Em, atom.I:
+		return wrapInline(inner, "*")
+	case atom.Del, atom.S:
+		return wrapInline(inner, "~~")
+	case atom.A:
+		href := attr(n, "href")
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			return inner
+		}
+		return "[" + trimmed + "](" + href + ")"
+	}
+	return inner
+}
+
+// wrapInline 为文本添加强调标记，标记放在首尾空白之内
+func wrapInline(text, mark string) string {
+	trimmed := strings.TrimSpace(text)
+	start := strings.Index(text, trimmed)
+	return text[:start] + mark + trimmed + mark + text[start+len(trimmed):]
+}
+
+func (c *htmlConverter) image(src, alt string) string {
+	if src == "" {
+		return ""
+	}
+	if strings.HasPrefix(src, "data:") {
+		if data := decodeDataURI(src); data != nil {
+			return c.images.add("data-uri-"+strconv.Itoa(len(c.images.images)+1), data)
+		}
+		return ""
+	}
+	if c.resolveImage != nil {
+		if key, data := c.resolveImage(src); data != nil {
+			return c.images.add(key, data)
+		}
+	}
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "//") {
+		return "![" + strings.TrimSpace(alt) + "](" + src + ")"
+	}
+	return ""
+}
+
+func decodeDataURI(src string) []byte {
+	i := strings.Index(src, ",")
+	if i < 0 || !strings.Contains(src[:i], ";base64") {
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(src[i+1:])
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func (c *htmlConverter) list(n *html.Node, level int) []string {
+	var (
+		lines   []string
+		ordered = n.DataAtom == atom.Ol
+		index   = 1
+	)
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+
+		var (
+			text   strings.Builder
+			nested []string
+		)
+		for child := li.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && (child.DataAtom == atom.Ul || child.DataAtom == atom.Ol) {
+				nested = append(nested, c.list(child, level+1)...)
+				continue
+			}
+			text.WriteString(c.inlineText(child))
+		}
+		lines = append(lines, listItem(level, ordered, index, collapseSpace(text.String())))
+		lines = append(lines, nested...)
+		index++
+	}
+	return lines
+}
+
+func (c *htmlConverter) table(n *html.Node) string {
+	var rows [][]string
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch child.DataAtom {
+			case atom.Tr:
+				var row []string
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.DataAtom == atom.Td || cell.DataAtom == atom.Th) {
+						row = append(row, strings.TrimSpace(c.inlineText(cell)))
+					}
+				}
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			case atom.Table:
+				// 嵌套表格按所在单元格的内容处理
+			default:
+				collect(child)
+			}
+		}
+	}
+	collect(n)
+	if len(rows) == 0 {
+		return ""
+	}
+	return renderTable(rows)
+}
+
+// codeBlock 将 <pre> 转换为代码块，语言取自 <code class="language-xxx">
+func codeBlock(n *html.Node) string {
+	var lang string
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.DataAtom == atom.Code {
+			for _, class := range strings.Fields(attr(child, "class")) {
+				if after, ok := strings.CutPrefix(class, "language-"); ok {
+					lang = after
+				}
+			}
+		}
+	}
+	text := strings.Trim(textContent(n), "\n")
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+	return "```" + lang + "\n" + text + "\n```"
+}
@@ -0,0 +1,85 @@
+package docparser
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// renderTable 将单元格渲染为 markdown 表格，第一行作为表头
+func renderTable(rows [][]string) string {
+	var columns int
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i := range columns {
+			var cell string
+			if i < len(row) {
+				cell = escapeTableCell(row[i])
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(rows[0])
+	sb.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func escapeTableCell(cell string) string {
+	cell = strings.Join(strings.Fields(cell), " ")
+	return strings.ReplaceAll(cell, "|", "\\|")
+}
+
+// heading 生成 markdown 标题，level 超出范围时按最接近的级别处理
+func heading(level int, text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return ""
+	}
+	level = min(max(level, 1), 6)
+	return strings.Repeat("#", level) + " " + text
+}
+
+// listItem 生成 markdown 列表项，level 从 0 开始
+func listItem(level int, ordered bool, index int, text string) string {
+	marker := "- "
+	if ordered {
+		marker = strconv.Itoa(index) + ". "
+	}
+	return strings.Repeat("  ", max(level, 0)) + marker + strings.TrimSpace(text)
+}
+
+// collapseSpace 将连续空白合并为一个空格
+func collapseSpace(text string) string {
+	var (
+		sb    strings.Builder
+		space bool
+	)
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space && sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		space = false
+		sb.WriteRune(r)
+	}
+	if space && sb.Len() > 0 {
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
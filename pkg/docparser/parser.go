@@ -0,0 +1,176 @@
+package docparser
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsupportedFormat 文件格式不支持解析
+var ErrUnsupportedFormat = errors.New("unsupported document format")
+
+// MAX_DOCUMENT_SIZE 允许解析的最大文件大小，压缩包格式同样限制解压后单个文件的大小
+const MAX_DOCUMENT_SIZE = 100 << 20
+
+// IMAGE_PLACEHOLDER_PREFIX 图片在 markdown 中的占位地址前缀，上传到对象存储后通过 Document.ReplaceImageURLs 替换为真实地址
+const IMAGE_PLACEHOLDER_PREFIX = "docparser://image/"
+
+// Image 文档中提取的图片
+type Image struct {
+	Name        string // 图片在文档中的唯一名称，markdown 中以 IMAGE_PLACEHOLDER_PREFIX+Name 引用
+	Data        []byte
+	ContentType string
+}
+
+// Placeholder 返回图片在 markdown 中的占位地址
+func (i Image) Placeholder() string {
+	return IMAGE_PLACEHOLDER_PREFIX + i.Name
+}
+
+// Document 解析后的文档，正文为 markdown 格式
+type Document struct {
+	Title    string
+	Markdown string
+	Images   []Image
+}
+
+// ReplaceImageURLs 将 markdown 中的图片占位地址替换为 urls 中对应的地址，未提供地址的图片引用会被移除
+func (d *Document) ReplaceImageURLs(urls map[string]string) {
+	for _, v := range d.Images {
+		if url, ok := urls[v.Name]; ok {
+			d.Markdown = strings.ReplaceAll(d.Markdown, "("+v.Placeholder()+")", "("+url+")")
+			continue
+		}
+		d.Markdown = strings.ReplaceAll(d.Markdown, "![]("+v.Placeholder()+")", "")
+	}
+}
+
+// AppendImageText 在图片引用之后插入图片中识别出的文字，texts 的键为图片名称
+func (d *Document) AppendImageText(texts map[string]string) {
+	for _, v := range d.Images {
+		text := strings.TrimSpace(texts[v.Name])
+		if text == "" {
+			continue
+		}
+		ref := "![](" + v.Placeholder() + ")"
+		d.Markdown = strings.ReplaceAll(d.Markdown, ref, ref+"\n\n"+text+"\n")
+	}
+}
+
+// imageCollector 收集文档中的图片，同一份资源只保留一次
+type imageCollector struct {
+	images []Image
+	names  map[string]string
+}
+
+func newImageCollector() *imageCollector {
+	return &imageCollector{
+		names: make(map[string]string),
+	}
+}
+
+// add 记录图片并返回其在 markdown 中的引用，key 为图片在文档内的路径或对象编号
+func (c *imageCollector) add(key string, data []byte) string {
+	if name, ok := c.names[key]; ok {
+		return imageMarkdown(name)
+	}
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") {
+		return ""
+	}
+	ext := path.Ext(key)
+	if ext == "" {
+		ext = "." + strings.TrimPrefix(contentType, "image/")
+	}
+	name := fmt.Sprintf("image-%d%s", len(c.images)+1, strings.ToLower(ext))
+	c.names[key] = name
+	c.images = append(c.images, Image{
+		Name:        name,
+		Data:        data,
+		ContentType: contentType,
+	})
+	return imageMarkdown(name)
+}
+
+func imageMarkdown(name string) string {
+	return "![](" + IMAGE_PLACEHOLDER_PREFIX + name + ")"
+}
+
+type parseFunc func(data []byte) (*Document, error)
+
+var parsers = map[string]parseFunc{
+	".pdf":      ParsePDF,
+	".docx":     ParseDOCX,
+	".pptx":     ParsePPTX,
+	".html":     ParseHTML,
+	".htm":      ParseHTML,
+	".xhtml":    ParseHTML,
+	".epub":     ParseEPUB,
+	".md":       parseText,
+	".markdown": parseText,
+	".txt":      parseText,
+}
+
+// Supported 文件是否可以被解析
+func Supported(fileName string) bool {
+	_, ok := parsers[strings.ToLower(filepath.Ext(fileName))]
+	return ok
+}
+
+// Parse 根据文件扩展名解析文档为 markdown
+func Parse(fileName string, data []byte) (*Document, error) {
+	parse, ok := parsers[strings.ToLower(filepath.Ext(fileName))]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, fileName)
+	}
+	if len(data) > MAX_DOCUMENT_SIZE {
+		return nil, fmt.Errorf("document is too large: %d bytes", len(data))
+	}
+
+	doc, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", fileName, err)
+	}
+	doc.Markdown = normalizeMarkdown(doc.Markdown)
+	if doc.Title == "" {
+		doc.Title = strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	}
+	return doc, nil
+}
+
+func parseText(data []byte) (*Document, error) {
+	return &Document{
+		Markdown: string(data),
+	}, nil
+}
+
+// normalizeMarkdown 统一换行符，去除行尾空白并合并多余的空行
+func normalizeMarkdown(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	var (
+		result []string
+		blank  bool
+		fence  bool
+	)
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fence = !fence
+		}
+		if !fence {
+			line = strings.TrimRight(line, " \t")
+		}
+		if !fence && strings.TrimSpace(line) == "" {
+			if !blank && len(result) > 0 {
+				result = append(result, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		result = append(result, line)
+	}
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
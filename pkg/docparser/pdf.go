@@ -0,0 +1,497 @@
+package docparser
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// MAX_PDF_PAGES 单个 PDF 最多解析的页数
+const MAX_PDF_PAGES = 2000
+
+var pdfObjectPattern = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// pdfDocument 不依赖 xref 表，直接扫描文件中的对象，兼容增量更新与损坏的交叉引用表
+type pdfDocument struct {
+	objects  map[int]any
+	trailers []pdfDict
+}
+
+// ParsePDF 提取 PDF 的文本与图片，根据字号识别标题，扫描件等没有文本层的页面只会得到图片
+func ParsePDF(data []byte) (*Document, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\n\f\r "), []byte("%PDF")) {
+		return nil, errors.New("invalid pdf header")
+	}
+
+	doc := loadPDF(data)
+	for _, trailer := range doc.trailers {
+		if _, ok := trailer["Encrypt"]; ok {
+			return nil, errors.New("encrypted pdf is not supported")
+		}
+	}
+
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return nil, errors.New("no pages found")
+	}
+
+	var (
+		images = newImageCollector()
+		layout = &pdfLayout{}
+	)
+	for _, page := range pages {
+		layout.newPage()
+		content := pdfContent{
+			doc:    doc,
+			images: images,
+			layout: layout,
+			fonts:  make(map[string]*pdfFont),
+		}
+		content.run(doc.pageContents(page), doc.dict(page["Resources"]), identityMatrix, 0)
+	}
+
+	result := &Document{
+		Markdown: layout.markdown(),
+		Images:   images.images,
+	}
+	if info := doc.info(); info != nil {
+		if title, ok := doc.resolve(info["Title"]).([]byte); ok {
+			result.Title = decodePDFText(title)
+		}
+	}
+	return result, nil
+}
+
+func loadPDF(data []byte) *pdfDocument {
+	doc := &pdfDocument{
+		objects: make(map[int]any),
+	}
+
+	var (
+		skipUntil int
+		objStms   []*pdfStream
+	)
+	for _, match := range pdfObjectPattern.FindAllSubmatchIndex(data, -1) {
+		if match[0] < skipUntil {
+			continue
+		}
+		num, err := strconv.Atoi(string(data[match[2]:match[3]]))
+		if err != nil {
+			continue
+		}
+
+		lexer := newPDFLexer(data, match[1])
+		value := lexer.parseValue(true)
+		if dict, ok := value.(pdfDict); ok {
+			if t := lexer.next(); t.kind == pdfTokenKeyword && string(t.text) == "stream" {
+				stream := readPDFStream(data, lexer.pos, dict)
+				skipUntil = lexer.pos + len(stream.raw)
+				value = stream
+				switch dict["Type"] {
+				case pdfName("ObjStm"):
+					objStms = append(objStms, stream)
+				case pdfName("XRef"):
+					doc.trailers = append(doc.trailers, dict)
+				}
+			}
+		}
+		// 增量更新时后出现的对象覆盖之前的版本
+		doc.objects[num] = value
+	}
+
+	for _, stream := range objStms {
+		doc.loadObjectStream(stream)
+	}
+
+	for i := 0; ; {
+		j := bytes.Index(data[i:], []byte("trailer"))
+		if j < 0 {
+			break
+		}
+		i += j + len("trailer")
+		if dict, ok := newPDFLexer(data, i).parseValue(true).(pdfDict); ok {
+			doc.trailers = append(doc.trailers, dict)
+		}
+	}
+	return doc
+}
+
+// readPDFStream 读取 stream 关键字之后的数据，Length 不可信时以 endstream 为结束标记
+func readPDFStream(data []byte, pos int, dict pdfDict) *pdfStream {
+	if pos < len(data) && data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(data) && data[pos] == '\n' {
+		pos++
+	}
+
+	if length, ok := dict["Length"].(float64); ok && length >= 0 {
+		end := pos + int(length)
+		if end <= len(data) && bytes.HasPrefix(bytes.TrimLeft(data[end:min(end+32, len(data))], "\x00\t\n\f\r "), []byte("endstream")) {
+			return &pdfStream{dict: dict, raw: data[pos:end]}
+		}
+	}
+
+	end := bytes.Index(data[pos:], []byte("endstream"))
+	if end < 0 {
+		return &pdfStream{dict: dict, raw: data[pos:]}
+	}
+	raw := data[pos : pos+end]
+	raw = bytes.TrimSuffix(raw, []byte("\n"))
+	raw = bytes.TrimSuffix(raw, []byte("\r"))
+	return &pdfStream{dict: dict, raw: raw}
+}
+
+// loadObjectStream 展开对象流中的压缩对象，对象流中的对象不会覆盖已有的直接对象
+func (d *pdfDocument) loadObjectStream(stream *pdfStream) {
+	data, err := d.decodeStream(stream)
+	if err != nil {
+		return
+	}
+	n, _ := d.resolve(stream.dict["N"]).(float64)
+	first, _ := d.resolve(stream.dict["First"]).(float64)
+	if int(first) > len(data) {
+		return
+	}
+
+	header := newPDFLexer(data[:int(first)], 0)
+	for i := 0; i < int(n); i++ {
+		num, offset := header.next(), header.next()
+		if num.kind != pdfTokenNumber || offset.kind != pdfTokenNumber {
+			return
+		}
+		pos := int(first) + int(offset.number)
+		if pos >= len(data) {
+			continue
+		}
+		if _, ok := d.objects[int(num.number)]; !ok {
+			d.objects[int(num.number)] = newPDFLexer(data, pos).parseValue(true)
+		}
+	}
+}
+
+// resolve 解析间接引用
+func (d *pdfDocument) resolve(v any) any {
+	for range 32 {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.objects[ref.num]
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(v any) pdfDict {
+	switch v := d.resolve(v).(type) {
+	case pdfDict:
+		return v
+	case *pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+func (d *pdfDocument) array(v any) []any {
+	arr, _ := d.resolve(v).([]any)
+	return arr
+}
+
+func (d *pdfDocument) number(v any) float64 {
+	n, _ := d.resolve(v).(float64)
+	return n
+}
+
+func (d *pdfDocument) catalog() pdfDict {
+	for i := len(d.trailers) - 1; i >= 0; i-- {
+		if root := d.dict(d.trailers[i]["Root"]); root != nil {
+			return root
+		}
+	}
+	// 没有可用的 trailer 时查找 Catalog 对象
+	var nums []int
+	for num, v := range d.objects {
+		if dict, ok := v.(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+			nums = append(nums, num)
+		}
+	}
+	if len(nums) == 0 {
+		return nil
+	}
+	sort.Ints(nums)
+	return d.objects[nums[len(nums)-1]].(pdfDict)
+}
+
+func (d *pdfDocument) info() pdfDict {
+	for i := len(d.trailers) - 1; i >= 0; i-- {
+		if info := d.dict(d.trailers[i]["Info"]); info != nil {
+			return info
+		}
+	}
+	return nil
+}
+
+// pages 按页面树顺序返回所有页面，页面的 Resources 已按继承规则补全
+func (d *pdfDocument) pages() []pdfDict {
+	var (
+		pages   []pdfDict
+		visited = make(map[int]bool)
+		walk    func(node any, resources any, depth int)
+	)
+	walk = func(node any, resources any, depth int) {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref.num] {
+				return
+			}
+			visited[ref.num] = true
+		}
+		dict := d.dict(node)
+		if dict == nil || depth > 64 || len(pages) >= MAX_PDF_PAGES {
+			return
+		}
+		if r, ok := dict["Resources"]; ok {
+			resources = r
+		}
+		if kids := d.array(dict["Kids"]); kids != nil {
+			for _, kid := range kids {
+				walk(kid, resources, depth+1)
+			}
+			return
+		}
+		if dict["Type"] == pdfName("Page") || dict["Contents"] != nil {
+			page := make(pdfDict, len(dict)+1)
+			for k, v := range dict {
+				page[k] = v
+			}
+			page["Resources"] = resources
+			pages = append(pages, page)
+		}
+	}
+
+	if catalog := d.catalog(); catalog != nil {
+		walk(catalog["Pages"], nil, 0)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	// 页面树损坏时按对象编号顺序查找页面
+	var nums []int
+	for num, v := range d.objects {
+		if dict, ok := v.(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		if len(pages) >= MAX_PDF_PAGES {
+			break
+		}
+		pages = append(pages, d.objects[num].(pdfDict))
+	}
+	return pages
+}
+
+// pageContents 合并页面的所有内容流
+func (d *pdfDocument) pageContents(page pdfDict) []byte {
+	var streams []any
+	switch v := d.resolve(page["Contents"]).(type) {
+	case *pdfStream:
+		streams = []any{v}
+	case []any:
+		streams = v
+	}
+
+	var buf bytes.Buffer
+	for _, v := range streams {
+		stream, ok := d.resolve(v).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := d.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// pdfImageFilters 图片编码，遇到时停止解码并返回原始数据
+var pdfImageFilters = map[pdfName]bool{
+	"DCTDecode":       true,
+	"DCT":             true,
+	"JPXDecode":       true,
+	"JBIG2Decode":     true,
+	"CCITTFaxDecode":  true,
+	"CCF":             true,
+	"RunLengthDecode": true,
+	"RL":              true,
+}
+
+// decodeStream 依次应用流的过滤器，遇到图片编码时返回该编码的原始数据
+func (d *pdfDocument) decodeStream(stream *pdfStream) ([]byte, error) {
+	data, _, err := d.decodeStreamFilters(stream)
+	return data, err
+}
+
+func (d *pdfDocument) decodeStreamFilters(stream *pdfStream) ([]byte, pdfName, error) {
+	var filters []any
+	switch v := d.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []any{v}
+	case []any:
+		filters = v
+	}
+
+	data := stream.raw
+	for i, v := range filters {
+		filter, _ := d.resolve(v).(pdfName)
+		if pdfImageFilters[filter] {
+			return data, filter, nil
+		}
+
+		var err error
+		switch filter {
+		case "FlateDecode", "Fl":
+			if data, err = inflate(data); err == nil {
+				data, err = d.unpredict(data, d.decodeParms(stream, i))
+			}
+		case "ASCIIHexDecode", "AHx":
+			data = newPDFLexer(append(bytes.TrimSpace(data), '>'), 0).scanHexString()
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data)
+		default:
+			err = errors.New("unsupported pdf filter: " + string(filter))
+		}
+		if err != nil {
+			return nil, filter, err
+		}
+	}
+	return data, "", nil
+}
+
+// decodeParms 返回第 i 个过滤器的参数，DecodeParms 为数组时与 Filter 一一对应
+func (d *pdfDocument) decodeParms(stream *pdfStream, i int) pdfDict {
+	if arr := d.array(stream.dict["DecodeParms"]); arr != nil {
+		if i < len(arr) {
+			return d.dict(arr[i])
+		}
+		return nil
+	}
+	return d.dict(stream.dict["DecodeParms"])
+}
+
+// unpredict 还原 PNG 预测器编码的数据
+func (d *pdfDocument) unpredict(data []byte, parms pdfDict) ([]byte, error) {
+	predictor := d.number(parms["Predictor"])
+	if predictor <= 1 {
+		return data, nil
+	}
+	if predictor < 10 {
+		return nil, errors.New("unsupported pdf predictor")
+	}
+
+	colors, bits, columns := d.number(parms["Colors"]), d.number(parms["BitsPerComponent"]), d.number(parms["Columns"])
+	if colors <= 0 {
+		colors = 1
+	}
+	if bits <= 0 {
+		bits = 8
+	}
+	if columns <= 0 {
+		columns = 1
+	}
+	bpp := max(int(colors*bits+7)/8, 1)
+	rowSize := (int(colors*bits*columns) + 7) / 8
+
+	var (
+		result = make([]byte, 0, len(data))
+		prev   = make([]byte, rowSize)
+	)
+	for len(data) > rowSize {
+		filter, row := data[0], data[1:rowSize+1]
+		data = data[rowSize+1:]
+		cur := make([]byte, rowSize)
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = cur[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+			switch filter {
+			case 0:
+				cur[i] = row[i]
+			case 1:
+				cur[i] = row[i] + left
+			case 2:
+				cur[i] = row[i] + up
+			case 3:
+				cur[i] = row[i] + byte((int(left)+int(up))/2)
+			case 4:
+				cur[i] = row[i] + paeth(left, up, upLeft)
+			default:
+				return nil, errors.New("invalid png predictor")
+			}
+		}
+		result = append(result, cur...)
+		prev = cur
+	}
+	return result, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func inflate(data []byte) ([]byte, error) {
+	var reader io.ReadCloser
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		reader = flate.NewReader(bytes.NewReader(data))
+	}
+	defer reader.Close()
+
+	result, err := io.ReadAll(io.LimitReader(reader, MAX_DOCUMENT_SIZE))
+	// 部分 PDF 的压缩流缺少结尾校验，已解压的内容仍然可用
+	if err != nil && len(result) == 0 {
+		return nil, err
+	}
+	return result, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	result := make([]byte, len(data))
+	n, _, err := ascii85.Decode(result, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return result[:n], nil
+}
@@ -0,0 +1,352 @@
+package docparser
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// PDF 对象在解析后使用以下 Go 类型表示：
+// nil、bool、float64、pdfName、[]byte（字符串）、[]any（数组）、pdfDict、pdfRef、*pdfStream
+type (
+	pdfName string
+	pdfDict map[string]any
+	pdfRef  struct {
+		num int
+		gen int
+	}
+	pdfStream struct {
+		dict pdfDict
+		raw  []byte
+	}
+	// pdfKeyword 内容流中的操作符以及 obj、stream、R 等关键字
+	pdfKeyword string
+)
+
+type pdfTokenKind int
+
+const (
+	pdfTokenEOF pdfTokenKind = iota
+	pdfTokenNumber
+	pdfTokenName
+	pdfTokenString
+	pdfTokenKeyword
+	pdfTokenDictStart
+	pdfTokenDictEnd
+	pdfTokenArrayStart
+	pdfTokenArrayEnd
+)
+
+type pdfToken struct {
+	kind   pdfTokenKind
+	number float64
+	text   []byte
+}
+
+// pdfLexer PDF 词法分析器，同时用于文件对象、对象流、内容流与 CMap
+type pdfLexer struct {
+	data []byte
+	pos  int
+	peek []pdfToken
+}
+
+func newPDFLexer(data []byte, pos int) *pdfLexer {
+	return &pdfLexer{data: data, pos: pos}
+}
+
+func isPDFWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// peekToken 预读第 n 个词法单元，n 从 0 开始
+func (l *pdfLexer) peekToken(n int) pdfToken {
+	for len(l.peek) <= n {
+		l.peek = append(l.peek, l.scan())
+	}
+	return l.peek[n]
+}
+
+func (l *pdfLexer) next() pdfToken {
+	if len(l.peek) > 0 {
+		t := l.peek[0]
+		l.peek = l.peek[1:]
+		return t
+	}
+	return l.scan()
+}
+
+func (l *pdfLexer) scan() pdfToken {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return pdfToken{kind: pdfTokenEOF}
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		return pdfToken{kind: pdfTokenName, text: l.scanName()}
+	case c == '(':
+		l.pos++
+		return pdfToken{kind: pdfTokenString, text: l.scanLiteralString()}
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfToken{kind: pdfTokenDictStart}
+		}
+		l.pos++
+		return pdfToken{kind: pdfTokenString, text: l.scanHexString()}
+	case c == '>':
+		l.pos++
+		if l.pos < len(l.data) && l.data[l.pos] == '>' {
+			l.pos++
+			return pdfToken{kind: pdfTokenDictEnd}
+		}
+		return l.scan()
+	case c == '[':
+		l.pos++
+		return pdfToken{kind: pdfTokenArrayStart}
+	case c == ']':
+		l.pos++
+		return pdfToken{kind: pdfTokenArrayEnd}
+	case c == '{' || c == '}' || c == ')':
+		l.pos++
+		return pdfToken{kind: pdfTokenKeyword, text: []byte{c}}
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := l.data[start:l.pos]
+	if n, err := strconv.ParseFloat(string(word), 64); err == nil && (word[0] == '.' || word[0] == '-' || word[0] == '+' || (word[0] >= '0' && word[0] <= '9')) {
+		return pdfToken{kind: pdfTokenNumber, number: n}
+	}
+	return pdfToken{kind: pdfTokenKeyword, text: word}
+}
+
+func (l *pdfLexer) scanName() []byte {
+	var name []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) || isPDFDelimiter(c) {
+			break
+		}
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				name = append(name, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		name = append(name, c)
+		l.pos++
+	}
+	return name
+}
+
+func (l *pdfLexer) scanLiteralString() []byte {
+	var (
+		result []byte
+		depth  = 1
+	)
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return result
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return result
+			}
+			c = l.data[l.pos]
+			l.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					v := int(c - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				}
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+func (l *pdfLexer) scanHexString() []byte {
+	var (
+		result []byte
+		high   = -1
+	)
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			break
+		}
+		v := unhex(c)
+		if v < 0 {
+			continue
+		}
+		if high < 0 {
+			high = v
+			continue
+		}
+		result = append(result, byte(high<<4|v))
+		high = -1
+	}
+	if high >= 0 {
+		result = append(result, byte(high<<4))
+	}
+	return result
+}
+
+func unhex(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10
+	}
+	return -1
+}
+
+// parseValue 读取一个完整的 PDF 对象，refs 为 true 时识别 "num gen R" 形式的间接引用
+func (l *pdfLexer) parseValue(refs bool) any {
+	return l.parseToken(l.next(), refs, 0)
+}
+
+func (l *pdfLexer) parseToken(t pdfToken, refs bool, depth int) any {
+	if depth > 64 {
+		return nil
+	}
+	switch t.kind {
+	case pdfTokenNumber:
+		if refs {
+			if gen, r := l.peekToken(0), l.peekToken(1); gen.kind == pdfTokenNumber && r.kind == pdfTokenKeyword && string(r.text) == "R" {
+				l.next()
+				l.next()
+				return pdfRef{num: int(t.number), gen: int(gen.number)}
+			}
+		}
+		return t.number
+	case pdfTokenName:
+		return pdfName(t.text)
+	case pdfTokenString:
+		return t.text
+	case pdfTokenArrayStart:
+		var arr []any
+		for {
+			next := l.next()
+			if next.kind == pdfTokenArrayEnd || next.kind == pdfTokenEOF {
+				return arr
+			}
+			arr = append(arr, l.parseToken(next, refs, depth+1))
+		}
+	case pdfTokenDictStart:
+		dict := make(pdfDict)
+		for {
+			key := l.next()
+			if key.kind == pdfTokenDictEnd || key.kind == pdfTokenEOF {
+				return dict
+			}
+			if key.kind != pdfTokenName {
+				continue
+			}
+			value := l.next()
+			if value.kind == pdfTokenDictEnd {
+				return dict
+			}
+			dict[string(key.text)] = l.parseToken(value, refs, depth+1)
+		}
+	case pdfTokenKeyword:
+		switch string(t.text) {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return pdfKeyword(t.text)
+	}
+	return nil
+}
+
+// skipInlineImage 跳过内容流中 ID 与 EI 之间的内联图片数据
+func (l *pdfLexer) skipInlineImage() {
+	l.peek = nil
+	if l.pos < len(l.data) && isPDFWhitespace(l.data[l.pos]) {
+		l.pos++
+	}
+	for {
+		i := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if i < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		end := l.pos + i
+		l.pos = end + 2
+		if (end == 0 || isPDFWhitespace(l.data[end-1])) && (l.pos >= len(l.data) || isPDFWhitespace(l.data[l.pos])) {
+			return
+		}
+	}
+}
@@ -0,0 +1,766 @@
+package docparser
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// PDF_MIN_IMAGE_SIZE 宽或高小于该像素的图片视为装饰图标，不做提取
+const PDF_MIN_IMAGE_SIZE = 32
+
+type pdfMatrix [6]float64
+
+var identityMatrix = pdfMatrix{1, 0, 0, 1, 0, 0}
+
+// multiply 返回 m × n
+func (m pdfMatrix) multiply(n pdfMatrix) pdfMatrix {
+	return pdfMatrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func translateMatrix(tx, ty float64) pdfMatrix {
+	return pdfMatrix{1, 0, 0, 1, tx, ty}
+}
+
+// pdfFont 字体的编码信息，用于将字符串中的字符码转换为 Unicode 文本并计算字宽
+type pdfFont struct {
+	// codespace 字符码的字节长度范围，为空时使用固定长度 codeLen
+	codespace  []pdfCodespace
+	codeLen    int
+	toUnicode  map[uint32]string
+	encoding   *[256]string
+	widths     map[uint32]float64
+	defaultW   float64
+	widthScale float64
+}
+
+type pdfCodespace struct {
+	low, high uint32
+	length    int
+}
+
+type pdfGlyph struct {
+	text  string
+	width float64
+	space bool
+}
+
+// decode 将字符串拆分为字形，width 为字体单位换算后的字宽（以字号为 1 计）
+func (f *pdfFont) decode(s []byte) []pdfGlyph {
+	var glyphs []pdfGlyph
+	for len(s) > 0 {
+		n := f.codeLength(s)
+		var code uint32
+		for _, b := range s[:n] {
+			code = code<<8 | uint32(b)
+		}
+		s = s[n:]
+
+		text, ok := f.toUnicode[code]
+		if !ok && f.encoding != nil && code < 256 {
+			text = f.encoding[code]
+		}
+		width, ok := f.widths[code]
+		if !ok {
+			width = f.defaultW
+		}
+		glyphs = append(glyphs, pdfGlyph{
+			text:  text,
+			width: width * f.widthScale,
+			space: n == 1 && code == ' ',
+		})
+	}
+	return glyphs
+}
+
+func (f *pdfFont) codeLength(s []byte) int {
+	for length := 1; length <= 4 && length <= len(s); length++ {
+		var code uint32
+		for _, b := range s[:length] {
+			code = code<<8 | uint32(b)
+		}
+		for _, r := range f.codespace {
+			if r.length == length && code >= r.low && code <= r.high {
+				return length
+			}
+		}
+	}
+	return min(max(f.codeLen, 1), len(s))
+}
+
+// loadFont 读取字体字典，支持 ToUnicode、简单字体编码与 Differences、Type0 字体的 W 数组
+func (d *pdfDocument) loadFont(dict pdfDict) *pdfFont {
+	font := &pdfFont{
+		codeLen:    1,
+		defaultW:   500,
+		widthScale: 0.001,
+		widths:     make(map[uint32]float64),
+	}
+	subtype, _ := d.resolve(dict["Subtype"]).(pdfName)
+
+	if subtype == "Type0" {
+		font.codeLen = 2
+		font.defaultW = 1000
+		if descendants := d.array(dict["DescendantFonts"]); len(descendants) > 0 {
+			d.loadCIDWidths(font, d.dict(descendants[0]))
+		}
+	} else {
+		font.encoding = d.simpleEncoding(dict["Encoding"])
+		first := int(d.number(dict["FirstChar"]))
+		for i, w := range d.array(dict["Widths"]) {
+			font.widths[uint32(first+i)] = d.number(w)
+		}
+		if descriptor := d.dict(dict["FontDescriptor"]); descriptor != nil {
+			if missing := d.number(descriptor["MissingWidth"]); missing > 0 {
+				font.defaultW = missing
+			}
+		}
+		if subtype == "Type3" {
+			if matrix := d.array(dict["FontMatrix"]); len(matrix) > 0 {
+				font.widthScale = d.number(matrix[0])
+			}
+		}
+	}
+
+	if stream, ok := d.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+		if data, err := d.decodeStream(stream); err == nil {
+			font.toUnicode, font.codespace = parseCMap(data)
+		}
+	}
+	return font
+}
+
+func (d *pdfDocument) loadCIDWidths(font *pdfFont, descendant pdfDict) {
+	if dw := d.number(descendant["DW"]); dw > 0 {
+		font.defaultW = dw
+	}
+	w := d.array(descendant["W"])
+	for i := 0; i+1 < len(w); {
+		first := uint32(d.number(w[i]))
+		if list := d.array(w[i+1]); list != nil {
+			for j, v := range list {
+				font.widths[first+uint32(j)] = d.number(v)
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			return
+		}
+		last, width := uint32(d.number(w[i+1])), d.number(w[i+2])
+		for code := first; code <= last && code-first < 0xffff; code++ {
+			font.widths[code] = width
+		}
+		i += 3
+	}
+}
+
+// simpleEncoding 简单字体以 WinAnsi 为基础编码，并应用 Differences 中的字形名
+func (d *pdfDocument) simpleEncoding(v any) *[256]string {
+	var encoding [256]string
+	for i := 32; i < 256; i++ {
+		encoding[i] = string(rune(i))
+	}
+	for i, r := range winAnsiHigh {
+		if r != 0 {
+			encoding[0x80+i] = string(r)
+		}
+	}
+
+	if dict := d.dict(v); dict != nil {
+		code := 0
+		for _, item := range d.array(dict["Differences"]) {
+			switch item := d.resolve(item).(type) {
+			case float64:
+				code = int(item)
+			case pdfName:
+				if code >= 0 && code < 256 {
+					if text, ok := glyphText(string(item)); ok {
+						encoding[code] = text
+					}
+				}
+				code++
+			}
+		}
+	}
+	return &encoding
+}
+
+// winAnsiHigh WinAnsiEncoding 中 0x80-0x9F 与 Latin-1 不同的字符
+var winAnsiHigh = [32]rune{
+	'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+	0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+}
+
+var glyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$", "percent": "%",
+	"ampersand": "&", "quotesingle": "'", "parenleft": "(", "parenright": ")", "asterisk": "*", "plus": "+",
+	"comma": ",", "hyphen": "-", "period": ".", "slash": "/", "zero": "0", "one": "1", "two": "2",
+	"three": "3", "four": "4", "five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9",
+	"colon": ":", "semicolon": ";", "less": "<", "equal": "=", "greater": ">", "question": "?", "at": "@",
+	"bracketleft": "[", "backslash": "\\", "bracketright": "]", "asciicircum": "^", "underscore": "_",
+	"grave": "`", "braceleft": "{", "bar": "|", "braceright": "}", "asciitilde": "~",
+	"quoteleft": "‘", "quoteright": "’", "quotedblleft": "“", "quotedblright": "”", "bullet": "•",
+	"endash": "–", "emdash": "—", "ellipsis": "…", "fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi",
+	"ffl": "ffl", "trademark": "™", "copyright": "©", "registered": "®", "degree": "°", "minus": "−",
+	"nbspace": " ", "periodcentered": "·", "dagger": "†", "section": "§", "paragraph": "¶", "Euro": "€",
+}
+
+// glyphText 将字形名转换为文本，支持 uniXXXX 与 uXXXX 形式
+func glyphText(name string) (string, bool) {
+	if text, ok := glyphNames[name]; ok {
+		return text, true
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		return name, true
+	}
+	for _, prefix := range []string{"uni", "u"} {
+		if hex, ok := strings.CutPrefix(name, prefix); ok && len(hex) >= 4 && len(hex) <= 6 {
+			if v, err := strconv.ParseUint(hex[:4*((len(hex))/4)], 16, 32); err == nil && utf8.ValidRune(rune(v)) {
+				return string(rune(v)), true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseCMap 解析 ToUnicode CMap 中的 codespacerange、bfchar 与 bfrange
+func parseCMap(data []byte) (map[uint32]string, []pdfCodespace) {
+	var (
+		result    = make(map[uint32]string)
+		codespace []pdfCodespace
+		lexer     = newPDFLexer(data, 0)
+		operands  []any
+	)
+	for {
+		t := lexer.next()
+		if t.kind == pdfTokenEOF {
+			break
+		}
+		value := lexer.parseToken(t, false, 0)
+		keyword, ok := value.(pdfKeyword)
+		if !ok {
+			operands = append(operands, value)
+			continue
+		}
+
+		switch keyword {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				low, _ := operands[i].([]byte)
+				high, _ := operands[i+1].([]byte)
+				if len(low) > 0 && len(low) <= 4 {
+					codespace = append(codespace, pdfCodespace{low: bytesToCode(low), high: bytesToCode(high), length: len(low)})
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, _ := operands[i].([]byte)
+				dst, _ := operands[i+1].([]byte)
+				result[bytesToCode(src)] = decodeUTF16(dst)
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				low, _ := operands[i].([]byte)
+				high, _ := operands[i+1].([]byte)
+				lo, hi := bytesToCode(low), bytesToCode(high)
+				if hi < lo || hi-lo > 0xffff {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case []byte:
+					// 目标字符串的最后一个字节随字符码递增
+					for code := lo; code <= hi; code++ {
+						next := append([]byte(nil), dst...)
+						if len(next) > 0 {
+							offset := int(code-lo) + int(next[len(next)-1])
+							next[len(next)-1] = byte(offset)
+							if offset > 0xff && len(next) > 1 {
+								next[len(next)-2] += byte(offset >> 8)
+							}
+						}
+						result[code] = decodeUTF16(next)
+					}
+				case []any:
+					for j, v := range dst {
+						if b, ok := v.([]byte); ok && lo+uint32(j) <= hi {
+							result[lo+uint32(j)] = decodeUTF16(b)
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+	return result, codespace
+}
+
+func bytesToCode(b []byte) uint32 {
+	var code uint32
+	for _, v := range b {
+		code = code<<8 | uint32(v)
+	}
+	return code
+}
+
+func decodeUTF16(b []byte) string {
+	if len(b)%2 == 1 {
+		return string(b)
+	}
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// decodePDFText 解码文档信息中的文本字符串，支持 UTF-16BE 与 UTF-8 BOM，其余按 Latin-1 处理
+func decodePDFText(b []byte) string {
+	switch {
+	case bytes.HasPrefix(b, []byte{0xfe, 0xff}):
+		return strings.TrimSpace(decodeUTF16(b[2:]))
+	case bytes.HasPrefix(b, []byte{0xef, 0xbb, 0xbf}):
+		return strings.TrimSpace(string(b[3:]))
+	}
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return strings.TrimSpace(string(runes))
+}
+
+// pdfGraphicsState q/Q 保存与恢复的图形状态
+type pdfGraphicsState struct {
+	ctm      pdfMatrix
+	font     *pdfFont
+	fontSize float64
+	charSp   float64
+	wordSp   float64
+	scale    float64
+	leading  float64
+}
+
+// pdfContent 解释页面内容流，将文本与图片输出到 pdfLayout
+type pdfContent struct {
+	doc    *pdfDocument
+	images *imageCollector
+	layout *pdfLayout
+	fonts  map[string]*pdfFont
+}
+
+func (c *pdfContent) font(resources pdfDict, name string) *pdfFont {
+	fonts := c.doc.dict(resources["Font"])
+	ref, _ := fonts[name].(pdfRef)
+	key := name
+	if ref.num > 0 {
+		key = "obj:" + strconv.Itoa(ref.num)
+	}
+	if font, ok := c.fonts[key]; ok {
+		return font
+	}
+	var font *pdfFont
+	if dict := c.doc.dict(fonts[name]); dict != nil {
+		font = c.doc.loadFont(dict)
+	}
+	c.fonts[key] = font
+	return font
+}
+
+func (c *pdfContent) run(data []byte, resources pdfDict, ctm pdfMatrix, depth int) {
+	if depth > 8 {
+		return
+	}
+
+	var (
+		lexer    = newPDFLexer(data, 0)
+		operands []any
+		state    = pdfGraphicsState{ctm: ctm, scale: 1}
+		stack    []pdfGraphicsState
+		tm, tlm  = identityMatrix, identityMatrix
+	)
+
+	number := func(i int) float64 {
+		if i < len(operands) {
+			n, _ := operands[i].(float64)
+			return n
+		}
+		return 0
+	}
+	newLine := func(tx, ty float64) {
+		tlm = translateMatrix(tx, ty).multiply(tlm)
+		tm = tlm
+	}
+	show := func(s []byte) {
+		if state.font == nil {
+			return
+		}
+		for _, g := range state.font.decode(s) {
+			m := tm.multiply(state.ctm)
+			trm := pdfMatrix{state.fontSize * state.scale, 0, 0, state.fontSize, 0, 0}.multiply(m)
+			advance := g.width*state.fontSize + state.charSp
+			if g.space {
+				advance += state.wordSp
+			}
+			advance *= state.scale
+			c.layout.glyph(trm[4], trm[5], math.Hypot(trm[2], trm[3]), advance*math.Hypot(m[0], m[1]), g.text)
+			tm = translateMatrix(advance, 0).multiply(tm)
+		}
+	}
+
+	for {
+		t := lexer.next()
+		if t.kind == pdfTokenEOF {
+			return
+		}
+		value := lexer.parseToken(t, false, 0)
+		op, ok := value.(pdfKeyword)
+		if !ok {
+			operands = append(operands, value)
+			continue
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, state)
+		case "Q":
+			if len(stack) > 0 {
+				state = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case "cm":
+			if len(operands) >= 6 {
+				state.ctm = pdfMatrix{number(0), number(1), number(2), number(3), number(4), number(5)}.multiply(state.ctm)
+			}
+		case "BT":
+			tm, tlm = identityMatrix, identityMatrix
+		case "Tf":
+			if len(operands) >= 2 {
+				name, _ := operands[0].(pdfName)
+				state.font = c.font(resources, string(name))
+				state.fontSize = number(1)
+			}
+		case "Tc":
+			state.charSp = number(0)
+		case "Tw":
+			state.wordSp = number(0)
+		case "Tz":
+			state.scale = number(0) / 100
+		case "TL":
+			state.leading = number(0)
+		case "Td":
+			newLine(number(0), number(1))
+		case "TD":
+			state.leading = -number(1)
+			newLine(number(0), number(1))
+		case "Tm":
+			if len(operands) >= 6 {
+				tlm = pdfMatrix{number(0), number(1), number(2), number(3), number(4), number(5)}
+				tm = tlm
+			}
+		case "T*":
+			newLine(0, -state.leading)
+		case "Tj":
+			if len(operands) > 0 {
+				s, _ := operands[0].([]byte)
+				show(s)
+			}
+		case "'", "\"":
+			newLine(0, -state.leading)
+			if op == "\"" && len(operands) >= 3 {
+				state.wordSp, state.charSp = number(0), number(1)
+			}
+			if len(operands) > 0 {
+				s, _ := operands[len(operands)-1].([]byte)
+				show(s)
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				arr, _ := operands[0].([]any)
+				for _, v := range arr {
+					switch v := v.(type) {
+					case []byte:
+						show(v)
+					case float64:
+						tm = translateMatrix(-v/1000*state.fontSize*state.scale, 0).multiply(tm)
+					}
+				}
+			}
+		case "Do":
+			if len(operands) > 0 {
+				name, _ := operands[0].(pdfName)
+				c.xobject(resources, string(name), state.ctm, depth)
+			}
+		case "BI":
+			for {
+				t := lexer.next()
+				if t.kind == pdfTokenEOF || (t.kind == pdfTokenKeyword && string(t.text) == "ID") {
+					break
+				}
+			}
+			lexer.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+// xobject 处理 Do 操作符，表单对象递归解释，图片对象提取为图片
+func (c *pdfContent) xobject(resources pdfDict, name string, ctm pdfMatrix, depth int) {
+	xobjects := c.doc.dict(resources["XObject"])
+	ref, _ := xobjects[name].(pdfRef)
+	stream, ok := c.doc.resolve(xobjects[name]).(*pdfStream)
+	if !ok {
+		return
+	}
+
+	switch c.doc.resolve(stream.dict["Subtype"]) {
+	case pdfName("Form"):
+		data, err := c.doc.decodeStream(stream)
+		if err != nil {
+			return
+		}
+		matrix := identityMatrix
+		if m := c.doc.array(stream.dict["Matrix"]); len(m) == 6 {
+			for i := range matrix {
+				matrix[i] = c.doc.number(m[i])
+			}
+		}
+		formResources := c.doc.dict(stream.dict["Resources"])
+		if formResources == nil {
+			formResources = resources
+		}
+		c.run(data, formResources, matrix.multiply(ctm), depth+1)
+	case pdfName("Image"):
+		key := name
+		if ref.num > 0 {
+			key = fmt.Sprintf("xobject-%d", ref.num)
+		}
+		if data, ext := c.doc.image(stream); data != nil {
+			if markdown := c.images.add(key+ext, data); markdown != "" {
+				c.layout.block(markdown)
+			}
+		}
+	}
+}
+
+// image 提取图片数据，JPEG 原样返回，8 位灰度与 RGB 位图编码为 PNG
+func (d *pdfDocument) image(stream *pdfStream) ([]byte, string) {
+	width, height := int(d.number(stream.dict["Width"])), int(d.number(stream.dict["Height"]))
+	if width < PDF_MIN_IMAGE_SIZE || height < PDF_MIN_IMAGE_SIZE {
+		return nil, ""
+	}
+
+	data, filter, err := d.decodeStreamFilters(stream)
+	if err != nil {
+		return nil, ""
+	}
+	switch filter {
+	case "DCTDecode", "DCT":
+		return data, ".jpg"
+	case "":
+	default:
+		return nil, ""
+	}
+
+	if d.number(stream.dict["BitsPerComponent"]) != 8 {
+		return nil, ""
+	}
+	var components int
+	switch cs := d.resolve(stream.dict["ColorSpace"]).(type) {
+	case pdfName:
+		switch cs {
+		case "DeviceGray", "G":
+			components = 1
+		case "DeviceRGB", "RGB":
+			components = 3
+		}
+	case []any:
+		if len(cs) == 2 && d.resolve(cs[0]) == pdfName("ICCBased") {
+			components = int(d.number(d.dict(cs[1])["N"]))
+		}
+	}
+	if (components != 1 && components != 3) || len(data) < width*height*components {
+		return nil, ""
+	}
+
+	var img image.Image
+	if components == 1 {
+		gray := image.NewGray(image.Rect(0, 0, width, height))
+		copy(gray.Pix, data)
+		img = gray
+	} else {
+		rgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+		for i := 0; i < width*height; i++ {
+			copy(rgba.Pix[i*4:i*4+3], data[i*3:i*3+3])
+			rgba.Pix[i*4+3] = 0xff
+		}
+		img = rgba
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, ""
+	}
+	return buf.Bytes(), ".png"
+}
+
+// pdfLine 同一基线上的文本
+type pdfLine struct {
+	y, size    float64
+	endX       float64
+	text       strings.Builder
+	page       int
+	block      bool
+	characters int
+}
+
+// pdfLayout 将字形按基线合并为行，再根据行距与字号还原段落和标题
+type pdfLayout struct {
+	lines []*pdfLine
+	page  int
+}
+
+func (l *pdfLayout) newPage() {
+	l.page++
+}
+
+func (l *pdfLayout) current() *pdfLine {
+	if len(l.lines) == 0 {
+		return nil
+	}
+	line := l.lines[len(l.lines)-1]
+	if line.page != l.page || line.block {
+		return nil
+	}
+	return line
+}
+
+func (l *pdfLayout) glyph(x, y, size, advance float64, text string) {
+	if text == "" {
+		return
+	}
+	if size <= 0 {
+		size = 1
+	}
+
+	line := l.current()
+	if line == nil || math.Abs(line.y-y) > size*0.5 {
+		line = &pdfLine{y: y, size: size, page: l.page}
+		l.lines = append(l.lines, line)
+	} else if x > line.endX+size*0.2 && !strings.HasSuffix(line.text.String(), " ") && strings.TrimSpace(text) != "" {
+		// 字形之间的间距明显大于字宽时补充空格
+		line.text.WriteString(" ")
+	}
+	line.text.WriteString(text)
+	line.endX = x + advance
+	line.size = max(line.size, size)
+	line.characters += utf8.RuneCountInString(strings.TrimSpace(text))
+}
+
+func (l *pdfLayout) block(markdown string) {
+	line := &pdfLine{page: l.page, block: true}
+	line.text.WriteString(markdown)
+	l.lines = append(l.lines, line)
+}
+
+// bodySize 按字符数统计最常见的字号作为正文字号
+func (l *pdfLayout) bodySize() float64 {
+	counts := make(map[float64]int)
+	for _, line := range l.lines {
+		if !line.block {
+			counts[math.Round(line.size*2)/2] += line.characters
+		}
+	}
+	var (
+		size float64
+		most int
+	)
+	for k, v := range counts {
+		if v > most || (v == most && k < size) {
+			size, most = k, v
+		}
+	}
+	return size
+}
+
+func (l *pdfLayout) markdown() string {
+	var (
+		body      = l.bodySize()
+		blocks    []string
+		paragraph []string
+		prev      *pdfLine
+	)
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, strings.Join(paragraph, "\n"))
+			paragraph = nil
+		}
+	}
+
+	for _, line := range l.lines {
+		text := strings.TrimSpace(line.text.String())
+		if text == "" {
+			continue
+		}
+		if line.block {
+			flush()
+			blocks = append(blocks, text)
+			prev = nil
+			continue
+		}
+
+		if level := headingLevel(line.size, body); level > 0 && utf8.RuneCountInString(text) <= 100 {
+			flush()
+			// 同一标题被拆成多行时合并
+			if prev != nil && prev.page == line.page && len(blocks) > 0 && headingLevel(prev.size, body) == level && math.Abs(prev.y-line.y) < line.size*2 {
+				blocks[len(blocks)-1] += " " + text
+			} else {
+				blocks = append(blocks, heading(level, text))
+			}
+			prev = line
+			continue
+		}
+
+		if prev != nil && headingLevel(prev.size, body) == 0 && prev.page == line.page {
+			gap := prev.y - line.y
+			// 行距过大、向上跳转（分栏）或字号变化时开始新段落
+			if gap > max(prev.size, line.size)*1.8 || gap < 0 || math.Abs(prev.size-line.size) > body*0.15 {
+				flush()
+			}
+		} else {
+			flush()
+		}
+		paragraph = append(paragraph, text)
+		prev = line
+	}
+	flush()
+	return strings.Join(blocks, "\n\n")
+}
+
+// headingLevel 根据字号与正文字号的比例判断标题级别，0 表示正文
+func headingLevel(size, body float64) int {
+	if body <= 0 {
+		return 0
+	}
+	ratio := size / body
+	switch {
+	case ratio >= 1.6:
+		return 1
+	case ratio >= 1.3:
+		return 2
+	case ratio >= 1.15:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,337 @@
+package docparser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParsePPTX 解析 PowerPoint 文档，每页幻灯片作为一个二级标题，保留列表层级、表格与图片
+func ParsePPTX(data []byte) (*Document, error) {
+	archive, err := openZip(data)
+	if err != nil {
+		return nil, err
+	}
+
+	slides := pptxSlides(archive)
+	if len(slides) == 0 {
+		return nil, errors.New("no slides found")
+	}
+
+	var (
+		images = newImageCollector()
+		blocks []string
+		title  string
+	)
+	for i, name := range slides {
+		body, err := archive.read(name)
+		if err != nil {
+			return nil, err
+		}
+		rels, err := archive.relationships(name)
+		if err != nil {
+			return nil, err
+		}
+
+		slide := &pptxSlide{archive: archive, rels: rels, images: images}
+		if err = slide.parse(body); err != nil {
+			return nil, err
+		}
+
+		slideTitle := slide.title
+		if slideTitle == "" {
+			slideTitle = "Slide " + strconv.Itoa(i+1)
+		} else if title == "" {
+			title = slideTitle
+		}
+		blocks = append(blocks, heading(2, slideTitle))
+		blocks = append(blocks, slide.blocks...)
+
+		if notes := pptxNotes(archive, rels); notes != "" {
+			blocks = append(blocks, "> "+strings.ReplaceAll(notes, "\n", "\n> "))
+		}
+	}
+
+	return &Document{
+		Title:    title,
+		Markdown: strings.Join(blocks, "\n\n"),
+		Images:   images.images,
+	}, nil
+}
+
+// pptxSlides 按 presentation.xml 中的顺序返回幻灯片路径，无法读取时按文件编号排序
+func pptxSlides(archive *zipArchive) []string {
+	const presentation = "ppt/presentation.xml"
+	if data, err := archive.read(presentation); err == nil && data != nil {
+		rels, _ := archive.relationships(presentation)
+		var doc struct {
+			Slides []struct {
+				ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+			} `xml:"sldIdLst>sldId"`
+		}
+		if err = xml.Unmarshal(data, &doc); err == nil && len(doc.Slides) > 0 {
+			var slides []string
+			for _, v := range doc.Slides {
+				if target, ok := rels[v.ID]; ok {
+					slides = append(slides, target)
+				}
+			}
+			if len(slides) > 0 {
+				return slides
+			}
+		}
+	}
+
+	var slides []string
+	for name := range archive.files {
+		if strings.HasPrefix(name, "ppt/slides/slide") && path.Ext(name) == ".xml" {
+			slides = append(slides, name)
+		}
+	}
+	sort.Slice(slides, func(i, j int) bool {
+		return pptxSlideNumber(slides[i]) < pptxSlideNumber(slides[j])
+	})
+	return slides
+}
+
+func pptxSlideNumber(name string) int {
+	n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "ppt/slides/slide"), ".xml"))
+	return n
+}
+
+// pptxNotes 读取幻灯片的备注文本
+func pptxNotes(archive *zipArchive, rels map[string]string) string {
+	for _, target := range rels {
+		if !strings.HasPrefix(target, "ppt/notesSlides/") {
+			continue
+		}
+		data, err := archive.read(target)
+		if err != nil || data == nil {
+			return ""
+		}
+		notes := &pptxSlide{archive: archive, images: newImageCollector()}
+		if err = notes.parse(data); err != nil {
+			return ""
+		}
+		return strings.TrimSpace(strings.Join(notes.blocks, "\n"))
+	}
+	return ""
+}
+
+type pptxSlide struct {
+	archive *zipArchive
+	rels    map[string]string
+	images  *imageCollector
+
+	title  string
+	blocks []string
+}
+
+func (s *pptxSlide) parse(body []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "sp":
+			if err = s.shape(decoder); err != nil {
+				return err
+			}
+		case "tbl":
+			table, err := pptxTable(decoder)
+			if err != nil {
+				return err
+			}
+			s.addBlock(table)
+		case "blip":
+			if target, ok := s.rels[xmlAttr(start, "embed")]; ok {
+				if data, err := s.archive.read(target); err == nil && data != nil {
+					s.addBlock(s.images.add(target, data))
+				}
+			}
+		}
+	}
+}
+
+func (s *pptxSlide) addBlock(block string) {
+	if strings.TrimSpace(block) != "" {
+		s.blocks = append(s.blocks, block)
+	}
+}
+
+// shape 读取文本框，标题占位符作为幻灯片标题，其余段落按缩进级别生成列表
+func (s *pptxSlide) shape(decoder *xml.Decoder) error {
+	var (
+		depth      = 1
+		isTitle    bool
+		skip       bool
+		paragraphs []*pptxParagraph
+		current    *pptxParagraph
+		inText     bool
+	)
+	for depth > 0 {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch t.Name.Local {
+			case "ph":
+				typ := xmlAttr(t, "type")
+				isTitle = typ == "title" || typ == "ctrTitle"
+				// 页码、日期、页眉页脚不属于正文
+				skip = typ == "sldNum" || typ == "dt" || typ == "ftr" || typ == "hdr"
+			case "p":
+				current = &pptxParagraph{}
+				paragraphs = append(paragraphs, current)
+			case "pPr":
+				if current != nil {
+					current.level, _ = strconv.Atoi(xmlAttr(t, "lvl"))
+				}
+			case "buAutoNum":
+				if current != nil {
+					current.ordered = true
+				}
+			case "buNone":
+				if current != nil {
+					current.plain = true
+				}
+			case "t":
+				inText = true
+			case "br":
+				if current != nil {
+					current.text.WriteString("\n")
+				}
+			}
+		case xml.EndElement:
+			depth--
+			if t.Name.Local == "t" {
+				inText = false
+			}
+		case xml.CharData:
+			if inText && current != nil {
+				current.text.Write(t)
+			}
+		}
+	}
+
+	if skip {
+		return nil
+	}
+	if isTitle {
+		var lines []string
+		for _, p := range paragraphs {
+			if text := strings.TrimSpace(p.text.String()); text != "" {
+				lines = append(lines, text)
+			}
+		}
+		if title := collapseSpace(strings.Join(lines, " ")); s.title == "" {
+			s.title = title
+		} else {
+			s.addBlock(title)
+		}
+		return nil
+	}
+
+	// 只有一段文本时按普通段落处理，多段时按列表处理
+	var (
+		lines   []string
+		counter = make(map[int]int)
+	)
+	nonEmpty := 0
+	for _, p := range paragraphs {
+		if strings.TrimSpace(p.text.String()) != "" {
+			nonEmpty++
+		}
+	}
+	for _, p := range paragraphs {
+		text := strings.TrimSpace(p.text.String())
+		if text == "" {
+			continue
+		}
+		if nonEmpty == 1 || (p.plain && p.level == 0) {
+			lines = append(lines, text)
+			continue
+		}
+		counter[p.level]++
+		lines = append(lines, listItem(p.level, p.ordered, counter[p.level], text))
+	}
+	s.addBlock(strings.Join(lines, "\n"))
+	return nil
+}
+
+type pptxParagraph struct {
+	level   int
+	ordered bool
+	plain   bool
+	text    strings.Builder
+}
+
+// pptxTable 读取 <a:tbl>
+func pptxTable(decoder *xml.Decoder) (string, error) {
+	var (
+		rows   [][]string
+		row    []string
+		cell   strings.Builder
+		inText bool
+		depth  = 1
+	)
+	for depth > 0 {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch t.Name.Local {
+			case "tr":
+				row = nil
+			case "tc":
+				cell.Reset()
+			case "p":
+				if cell.Len() > 0 {
+					cell.WriteString(" ")
+				}
+			case "t":
+				inText = true
+			}
+		case xml.EndElement:
+			depth--
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "tc":
+				row = append(row, strings.TrimSpace(cell.String()))
+			case "tr":
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			}
+		case xml.CharData:
+			if inText {
+				cell.Write(t)
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+	return renderTable(rows), nil
+}
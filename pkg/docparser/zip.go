@@ -0,0 +1,87 @@
+package docparser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// zipArchive 读取 Office 与 EPUB 这类基于 zip 的文档
+type zipArchive struct {
+	files map[string]*zip.File
+}
+
+func openZip(data []byte) (*zipArchive, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	archive := &zipArchive{
+		files: make(map[string]*zip.File, len(reader.File)),
+	}
+	for _, f := range reader.File {
+		archive.files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+	return archive, nil
+}
+
+// read 读取压缩包内的文件，文件不存在时返回 nil
+func (a *zipArchive) read(name string) ([]byte, error) {
+	f, ok := a.files[strings.TrimPrefix(name, "/")]
+	if !ok {
+		return nil, nil
+	}
+	if f.UncompressedSize64 > MAX_DOCUMENT_SIZE {
+		return nil, fmt.Errorf("%s is too large: %d bytes", name, f.UncompressedSize64)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(io.LimitReader(r, MAX_DOCUMENT_SIZE))
+}
+
+// relationships 读取 Office 文档的关系文件，返回 Id 到目标路径的映射，目标路径已转换为压缩包内的绝对路径
+func (a *zipArchive) relationships(part string) (map[string]string, error) {
+	dir, file := path.Split(part)
+	data, err := a.read(dir + "_rels/" + file + ".rels")
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID         string `xml:"Id,attr"`
+			Target     string `xml:"Target,attr"`
+			TargetMode string `xml:"TargetMode,attr"`
+		} `xml:"Relationship"`
+	}
+	if err = xml.Unmarshal(data, &rels); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(rels.Relationships))
+	for _, v := range rels.Relationships {
+		if v.TargetMode == "External" {
+			continue
+		}
+		result[v.ID] = resolvePath(dir, v.Target)
+	}
+	return result, nil
+}
+
+// resolvePath 将相对 base 目录的引用转换为压缩包内的路径
+func resolvePath(base, target string) string {
+	if i := strings.IndexAny(target, "#?"); i >= 0 {
+		target = target[:i]
+	}
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/")
+	}
+	return strings.TrimPrefix(path.Clean(path.Join("/", base, target)), "/")
+}
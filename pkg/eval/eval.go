@@ -0,0 +1,259 @@
+package eval
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// DefaultKs 默认统计的 recall@k / nDCG@k
+var DefaultKs = []int{1, 3, 5, 10}
+
+// Case 评估集中的一个问题
+type Case struct {
+	Question        string   `json:"question"`
+	ExpectedIDs     []string `json:"expected_ids"`               // 期望召回的知识ID
+	ReferenceAnswer string   `json:"reference_answer,omitempty"` // 参考答案，设置后才会进行答案评分
+}
+
+// LoadCases 读取 JSONL 格式的评估集，每行一个 Case，空行会被忽略
+func LoadCases(r io.Reader) ([]Case, error) {
+	var (
+		cases   []Case
+		scanner = bufio.NewScanner(r)
+		line    int
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var c Case
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if strings.TrimSpace(c.Question) == "" {
+			return nil, fmt.Errorf("line %d: empty question", line)
+		}
+		if len(c.ExpectedIDs) == 0 && c.ReferenceAnswer == "" {
+			return nil, fmt.Errorf("line %d: expected_ids or reference_answer is required", line)
+		}
+		cases = append(cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+// LoadCasesFromFile 从文件读取评估集
+func LoadCasesFromFile(path string) ([]Case, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadCases(f)
+}
+
+// Retriever 检索待评估的知识，返回按相关度排序的参考文档
+type Retriever interface {
+	Retrieve(ctx context.Context, question string) ([]*types.PassageInfo, error)
+}
+
+// Judge 根据参考答案为基于检索结果生成的回答评分，得分范围 [0, 1]
+type Judge interface {
+	Score(ctx context.Context, c Case, docs []*types.PassageInfo) (JudgeResult, error)
+}
+
+// JudgeResult 答案评分结果
+type JudgeResult struct {
+	Answer string  `json:"answer"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+// CaseResult 单个问题的评估结果
+type CaseResult struct {
+	Question  string          `json:"question"`
+	Expected  []string        `json:"expected"`
+	Retrieved []string        `json:"retrieved"`
+	Recall    map[int]float64 `json:"recall"`
+	NDCG      map[int]float64 `json:"ndcg"`
+	RR        float64         `json:"rr"`
+	Judge     *JudgeResult    `json:"judge,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// Summary 评估集整体指标
+type Summary struct {
+	Total       int             `json:"total"`
+	Failed      int             `json:"failed"`       // 检索出错的问题数量
+	Evaluated   int             `json:"evaluated"`    // 参与检索指标统计的问题数量
+	Recall      map[int]float64 `json:"recall"`       // 平均 recall@k
+	NDCG        map[int]float64 `json:"ndcg"`         // 平均 nDCG@k
+	MRR         float64         `json:"mrr"`          // 平均倒数排名
+	Judged      int             `json:"judged"`       // 参与答案评分的问题数量
+	AnswerScore float64         `json:"answer_score"` // 平均答案得分
+}
+
+// Report 评估报告
+type Report struct {
+	Ks      []int        `json:"ks"`
+	Cases   []CaseResult `json:"cases"`
+	Summary Summary      `json:"summary"`
+}
+
+// Runner 依次对评估集中的问题执行检索并计算指标
+type Runner struct {
+	retriever Retriever
+	judge     Judge
+	ks        []int
+}
+
+// NewRunner 创建评估执行器，judge 为空时不进行答案评分
+func NewRunner(retriever Retriever, judge Judge, ks ...int) *Runner {
+	if len(ks) == 0 {
+		ks = DefaultKs
+	}
+	return &Runner{
+		retriever: retriever,
+		judge:     judge,
+		ks:        ks,
+	}
+}
+
+// Run 执行评估，单个问题检索失败不会中断评估，仅在结果中记录错误
+func (r *Runner) Run(ctx context.Context, cases []Case) (*Report, error) {
+	report := &Report{
+		Ks: r.ks,
+		Summary: Summary{
+			Total:  len(cases),
+			Recall: make(map[int]float64, len(r.ks)),
+			NDCG:   make(map[int]float64, len(r.ks)),
+		},
+	}
+
+	for _, c := range cases {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		result := r.runCase(ctx, c)
+		report.Cases = append(report.Cases, result)
+
+		if result.Error != "" {
+			report.Summary.Failed++
+			continue
+		}
+		if len(c.ExpectedIDs) > 0 {
+			report.Summary.Evaluated++
+			report.Summary.MRR += result.RR
+			for _, k := range r.ks {
+				report.Summary.Recall[k] += result.Recall[k]
+				report.Summary.NDCG[k] += result.NDCG[k]
+			}
+		}
+		if result.Judge != nil {
+			report.Summary.Judged++
+			report.Summary.AnswerScore += result.Judge.Score
+		}
+	}
+
+	if n := float64(report.Summary.Evaluated); n > 0 {
+		report.Summary.MRR /= n
+		for _, k := range r.ks {
+			report.Summary.Recall[k] /= n
+			report.Summary.NDCG[k] /= n
+		}
+	}
+	if report.Summary.Judged > 0 {
+		report.Summary.AnswerScore /= float64(report.Summary.Judged)
+	}
+	return report, nil
+}
+
+func (r *Runner) runCase(ctx context.Context, c Case) CaseResult {
+	result := CaseResult{
+		Question: c.Question,
+		Expected: c.ExpectedIDs,
+		Recall:   make(map[int]float64, len(r.ks)),
+		NDCG:     make(map[int]float64, len(r.ks)),
+	}
+
+	docs, err := r.retriever.Retrieve(ctx, c.Question)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	exist := make(map[string]struct{}, len(docs))
+	for _, v := range docs {
+		if _, ok := exist[v.ID]; ok {
+			continue
+		}
+		exist[v.ID] = struct{}{}
+		result.Retrieved = append(result.Retrieved, v.ID)
+	}
+
+	if len(c.ExpectedIDs) > 0 {
+		result.RR = ReciprocalRank(result.Retrieved, c.ExpectedIDs)
+		for _, k := range r.ks {
+			result.Recall[k] = RecallAtK(result.Retrieved, c.ExpectedIDs, k)
+			result.NDCG[k] = NDCGAtK(result.Retrieved, c.ExpectedIDs, k)
+		}
+	}
+
+	if r.judge != nil && c.ReferenceAnswer != "" {
+		judge, err := r.judge.Score(ctx, c, docs)
+		if err != nil {
+			// 评分失败不影响检索指标
+			slog.Error("Failed to judge answer", slog.String("question", c.Question), slog.String("error", err.Error()))
+		} else {
+			result.Judge = &judge
+		}
+	}
+	return result
+}
+
+// Print 以文本形式输出评估报告
+func (r *Report) Print(w io.Writer) {
+	for i, c := range r.Cases {
+		fmt.Fprintf(w, "[%d] %s\n", i+1, c.Question)
+		if c.Error != "" {
+			fmt.Fprintf(w, "    error: %s\n", c.Error)
+			continue
+		}
+		if len(c.Expected) > 0 {
+			fmt.Fprintf(w, "    rr: %.3f", c.RR)
+			for _, k := range r.Ks {
+				fmt.Fprintf(w, "  recall@%d: %.3f", k, c.Recall[k])
+			}
+			fmt.Fprintln(w)
+		}
+		if c.Judge != nil {
+			fmt.Fprintf(w, "    answer score: %.3f %s\n", c.Judge.Score, c.Judge.Reason)
+		}
+	}
+
+	s := r.Summary
+	fmt.Fprintf(w, "\ntotal: %d, evaluated: %d, failed: %d\n", s.Total, s.Evaluated, s.Failed)
+	fmt.Fprintf(w, "MRR: %.4f\n", s.MRR)
+	for _, k := range r.Ks {
+		fmt.Fprintf(w, "recall@%d: %.4f  nDCG@%d: %.4f\n", k, s.Recall[k], k, s.NDCG[k])
+	}
+	if s.Judged > 0 {
+		fmt.Fprintf(w, "answer score: %.4f (%d judged)\n", s.AnswerScore, s.Judged)
+	}
+}
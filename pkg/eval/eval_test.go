@@ -0,0 +1,150 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// fakeRetriever 按问题返回预设的检索结果
+type fakeRetriever map[string][]string
+
+func (r fakeRetriever) Retrieve(ctx context.Context, question string) ([]*types.PassageInfo, error) {
+	ids, ok := r[question]
+	if !ok {
+		return nil, fmt.Errorf("unknown question")
+	}
+
+	var docs []*types.PassageInfo
+	for _, id := range ids {
+		docs = append(docs, &types.PassageInfo{ID: id, Title: id, Content: "content of " + id})
+	}
+	return docs, nil
+}
+
+// fakeChatModel 回答问题时返回固定答案，评审时返回固定评分
+type fakeChatModel struct {
+	answer string
+	judge  string
+}
+
+func (m *fakeChatModel) Generate(ctx context.Context, messages []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	content := m.answer
+	if strings.Contains(messages[len(messages)-1].Content, "参考答案") {
+		content = m.judge
+	}
+	return &schema.Message{Role: schema.Assistant, Content: content}, nil
+}
+
+func (m *fakeChatModel) Stream(ctx context.Context, messages []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, nil
+}
+
+func (m *fakeChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return m, nil
+}
+
+func (m *fakeChatModel) Config() types.ModelConfig {
+	return types.ModelConfig{ModelName: "fake"}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestMetrics(t *testing.T) {
+	retrieved := []string{"a", "b", "c", "d"}
+	expected := []string{"b", "d"}
+
+	if v := RecallAtK(retrieved, expected, 1); v != 0 {
+		t.Errorf("recall@1 = %v, want 0", v)
+	}
+	if v := RecallAtK(retrieved, expected, 3); v != 0.5 {
+		t.Errorf("recall@3 = %v, want 0.5", v)
+	}
+	if v := ReciprocalRank(retrieved, expected); v != 0.5 {
+		t.Errorf("rr = %v, want 0.5", v)
+	}
+
+	// dcg = 1/log2(3) + 1/log2(5), idcg = 1 + 1/log2(3)
+	want := (1/math.Log2(3) + 1/math.Log2(5)) / (1 + 1/math.Log2(3))
+	if v := NDCGAtK(retrieved, expected, 10); !almostEqual(v, want) {
+		t.Errorf("ndcg@10 = %v, want %v", v, want)
+	}
+	if v := NDCGAtK([]string{"b", "d"}, expected, 10); !almostEqual(v, 1) {
+		t.Errorf("ndcg of perfect ranking = %v, want 1", v)
+	}
+}
+
+func TestLoadCases(t *testing.T) {
+	input := `{"question": "q1", "expected_ids": ["a"]}
+
+{"question": "q2", "expected_ids": ["b"], "reference_answer": "answer"}
+`
+	cases, err := LoadCases(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 2 || cases[1].ReferenceAnswer != "answer" {
+		t.Errorf("unexpected cases: %+v", cases)
+	}
+
+	if _, err = LoadCases(strings.NewReader(`{"question": "q1"}`)); err == nil {
+		t.Error("expected error for case without expected ids and reference answer")
+	}
+}
+
+func TestRunner(t *testing.T) {
+	retriever := fakeRetriever{
+		"q1": {"a", "b"},
+		"q2": {"c", "a", "c"},
+	}
+	judge := NewLLMJudge(&fakeChatModel{
+		answer: "answer",
+		judge:  "```json\n{\"score\": 8, \"reason\": \"ok\"}\n```",
+	}, nil)
+
+	cases := []Case{
+		{Question: "q1", ExpectedIDs: []string{"a"}, ReferenceAnswer: "answer"},
+		{Question: "q2", ExpectedIDs: []string{"a"}},
+		{Question: "q3", ExpectedIDs: []string{"a"}},
+	}
+
+	report, err := NewRunner(retriever, judge, 1, 3).Run(context.Background(), cases)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := report.Summary
+	if s.Total != 3 || s.Evaluated != 2 || s.Failed != 1 {
+		t.Fatalf("unexpected summary: %+v", s)
+	}
+	if !almostEqual(s.MRR, 0.75) {
+		t.Errorf("mrr = %v, want 0.75", s.MRR)
+	}
+	if !almostEqual(s.Recall[1], 0.5) || !almostEqual(s.Recall[3], 1) {
+		t.Errorf("unexpected recall: %v", s.Recall)
+	}
+	if s.Judged != 1 || !almostEqual(s.AnswerScore, 0.8) {
+		t.Errorf("unexpected answer score: %d %v", s.Judged, s.AnswerScore)
+	}
+
+	// 重复的检索结果只计算一次
+	if got := report.Cases[1].Retrieved; len(got) != 2 {
+		t.Errorf("expected duplicated docs to be removed, got %v", got)
+	}
+
+	var buf bytes.Buffer
+	report.Print(&buf)
+	if !strings.Contains(buf.String(), "MRR: 0.7500") {
+		t.Errorf("unexpected report output:\n%s", buf.String())
+	}
+}
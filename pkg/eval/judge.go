@@ -0,0 +1,106 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+const (
+	PROMPT_EVAL_ANSWER_CN = `你是一个知识库问答助手，请仅根据以下参考资料回答用户的问题，资料中没有相关内容时请直接说明无法回答。
+参考资料：
+{docs}`
+
+	PROMPT_EVAL_JUDGE_CN = `你是一名严格的评审，需要根据参考答案评估回答的质量。
+评分标准：0 分表示回答错误或没有回答，10 分表示回答与参考答案的关键信息完全一致，遗漏或错误的信息越多得分越低。
+问题：{question}
+参考答案：{reference}
+待评估的回答：{answer}
+
+请仅输出 JSON，格式为 {"score": 0-10 之间的整数, "reason": "简要说明评分理由"}`
+)
+
+// LLMJudge 先使用对话模型基于检索结果回答问题，再由评审模型对照参考答案打分
+type LLMJudge struct {
+	answerModel types.ChatModel
+	judgeModel  types.ChatModel
+}
+
+// NewLLMJudge 创建基于大模型的答案评审，judgeModel 为空时使用 answerModel 评分
+func NewLLMJudge(answerModel, judgeModel types.ChatModel) *LLMJudge {
+	if judgeModel == nil {
+		judgeModel = answerModel
+	}
+	return &LLMJudge{
+		answerModel: answerModel,
+		judgeModel:  judgeModel,
+	}
+}
+
+func (j *LLMJudge) Score(ctx context.Context, c Case, docs []*types.PassageInfo) (JudgeResult, error) {
+	var result JudgeResult
+
+	answer, err := j.answerModel.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(strings.ReplaceAll(PROMPT_EVAL_ANSWER_CN, "{docs}", formatDocs(docs))),
+		schema.UserMessage(c.Question),
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to generate answer: %w", err)
+	}
+	result.Answer = strings.TrimSpace(answer.Content)
+
+	prompt := strings.NewReplacer(
+		"{question}", c.Question,
+		"{reference}", c.ReferenceAnswer,
+		"{answer}", result.Answer,
+	).Replace(PROMPT_EVAL_JUDGE_CN)
+	res, err := j.judgeModel.Generate(ctx, []*schema.Message{
+		schema.UserMessage(prompt),
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to judge answer: %w", err)
+	}
+
+	score, reason, err := parseJudgeResult(res.Content)
+	if err != nil {
+		return result, err
+	}
+	result.Score = score
+	result.Reason = reason
+	return result, nil
+}
+
+func formatDocs(docs []*types.PassageInfo) string {
+	if len(docs) == 0 {
+		return "无"
+	}
+
+	b := strings.Builder{}
+	for i, v := range docs {
+		fmt.Fprintf(&b, "[%d] %s\n%s\n\n", i+1, v.Title, v.Content)
+	}
+	return b.String()
+}
+
+// parseJudgeResult 解析评审模型输出的 JSON，兼容 markdown 代码块等多余内容，得分归一化到 [0, 1]
+func parseJudgeResult(content string) (float64, string, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start == -1 || end <= start {
+		return 0, "", fmt.Errorf("unexpected judge output: %s", content)
+	}
+
+	var res struct {
+		Score  float64 `json:"score"`
+		Reason string  `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &res); err != nil {
+		return 0, "", fmt.Errorf("failed to unmarshal judge output: %w", err)
+	}
+	return min(max(res.Score, 0), 10) / 10, res.Reason, nil
+}
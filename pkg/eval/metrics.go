@@ -0,0 +1,68 @@
+package eval
+
+import "math"
+
+// 检索评估指标，均按二值相关性计算：期望知识集合中的知识相关度为 1，其余为 0
+
+// RecallAtK 前 k 条结果中命中的期望知识占全部期望知识的比例
+func RecallAtK(retrieved, expected []string, k int) float64 {
+	if len(expected) == 0 {
+		return 0
+	}
+
+	relevant := toSet(expected)
+	var hit int
+	for _, id := range topK(retrieved, k) {
+		if _, ok := relevant[id]; ok {
+			hit++
+		}
+	}
+	return float64(hit) / float64(len(relevant))
+}
+
+// ReciprocalRank 第一条命中结果排名的倒数，未命中时为 0，多个问题取平均即为 MRR
+func ReciprocalRank(retrieved, expected []string) float64 {
+	relevant := toSet(expected)
+	for i, id := range retrieved {
+		if _, ok := relevant[id]; ok {
+			return 1 / float64(i+1)
+		}
+	}
+	return 0
+}
+
+// NDCGAtK 前 k 条结果的归一化折损累计增益
+func NDCGAtK(retrieved, expected []string, k int) float64 {
+	relevant := toSet(expected)
+	if len(relevant) == 0 {
+		return 0
+	}
+
+	var dcg float64
+	for i, id := range topK(retrieved, k) {
+		if _, ok := relevant[id]; ok {
+			dcg += 1 / math.Log2(float64(i+2))
+		}
+	}
+
+	var idcg float64
+	for i := 0; i < min(len(relevant), k); i++ {
+		idcg += 1 / math.Log2(float64(i+2))
+	}
+	return dcg / idcg
+}
+
+func topK(list []string, k int) []string {
+	if k > 0 && len(list) > k {
+		return list[:k]
+	}
+	return list
+}
+
+func toSet(list []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(list))
+	for _, v := range list {
+		set[v] = struct{}{}
+	}
+	return set
+}
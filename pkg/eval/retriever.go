@@ -0,0 +1,47 @@
+package eval
+
+import (
+	"context"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/pkg/ai/agents/rag"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// CoreRetriever 使用线上的检索链路(查询增强 -> 召回 -> 重排)检索空间内的知识
+type CoreRetriever struct {
+	core    *core.Core
+	spaceID string
+	userID  string
+	tags    []string
+	enhance bool
+}
+
+// NewCoreRetriever 创建基于 core 的检索器，tags 不为空时仅检索带有其中任一标签的知识，enhance 为 false 时跳过查询增强
+func NewCoreRetriever(core *core.Core, spaceID, userID string, tags []string, enhance bool) *CoreRetriever {
+	return &CoreRetriever{
+		core:    core,
+		spaceID: spaceID,
+		userID:  userID,
+		tags:    tags,
+		enhance: enhance,
+	}
+}
+
+func (r *CoreRetriever) Retrieve(ctx context.Context, question string) ([]*types.PassageInfo, error) {
+	if !r.enhance {
+		docs, _, err := rag.GetQueryRelevanceKnowledges(r.core, r.spaceID, r.userID, question, nil, r.tags)
+		if err != nil {
+			return nil, err
+		}
+		return docs.Docs, nil
+	}
+
+	strategy := r.core.RetrievalProfile(ctx, r.spaceID).QueryExpansion
+	enhanceResult, _ := rag.ExpandQuery(ctx, r.core, strategy, question, nil)
+	docs, _, err := rag.GetQueriesRelevanceKnowledges(r.core, r.spaceID, r.userID, enhanceResult.RerankQuery(), enhanceResult.SearchQueries(), nil, r.tags)
+	if err != nil {
+		return nil, err
+	}
+	return docs.Docs, nil
+}
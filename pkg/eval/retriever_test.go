@@ -0,0 +1,212 @@
+package eval
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pgvector/pgvector-go"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	"github.com/quka-ai/quka-ai/app/core/srv"
+	"github.com/quka-ai/quka-ai/app/store"
+	"github.com/quka-ai/quka-ai/app/store/sqlstore"
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/cache"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+const fakeEmbeddingModel = "fake-embedding"
+
+// fakeEmbedding 按问题中的关键词生成向量，每个关键词对应一个维度
+type fakeEmbedding struct{}
+
+var fakeEmbeddingWords = []string{"golang", "postgres", "cooking"}
+
+func fakeVector(text string) []float32 {
+	vector := make([]float32, len(fakeEmbeddingWords))
+	for i, v := range fakeEmbeddingWords {
+		if strings.Contains(text, v) {
+			vector[i] = 1
+		}
+	}
+	return vector
+}
+
+func (fakeEmbedding) EmbeddingForQuery(ctx context.Context, content []string) (ai.EmbeddingResult, error) {
+	result := ai.EmbeddingResult{Model: fakeEmbeddingModel}
+	for _, v := range content {
+		result.Data = append(result.Data, fakeVector(v))
+	}
+	return result, nil
+}
+
+func (fakeEmbedding) EmbeddingForDocument(ctx context.Context, title string, content []string) (ai.EmbeddingResult, error) {
+	return fakeEmbedding{}.EmbeddingForQuery(ctx, content)
+}
+
+// fakeVectorStore 在内存中按余弦相似度检索知识向量
+type fakeVectorStore struct {
+	store.VectorStore
+	knowledges map[string]*types.Knowledge
+	vectors    map[string][]float32
+	opts       []types.GetVectorsOptions
+}
+
+func cosine(a, b []float32) float32 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i] * b[i])
+		na += float64(a[i] * a[i])
+		nb += float64(b[i] * b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
+}
+
+func (s *fakeVectorStore) Query(ctx context.Context, opts types.GetVectorsOptions, vector pgvector.Vector, limit uint64) ([]types.QueryResult, error) {
+	s.opts = append(s.opts, opts)
+
+	var result []types.QueryResult
+	for id, v := range s.vectors {
+		if len(opts.Tags) > 0 && !slices.ContainsFunc(opts.Tags, func(tag string) bool {
+			return slices.Contains(s.knowledges[id].Tags, tag)
+		}) {
+			continue
+		}
+		result = append(result, types.QueryResult{
+			ID:             id,
+			KnowledgeID:    id,
+			Cos:            cosine(vector.Slice(), v),
+			OriginalLength: 500,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Cos > result[j].Cos
+	})
+	if uint64(len(result)) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+type fakeKnowledgeStore struct {
+	store.KnowledgeStore
+	knowledges map[string]*types.Knowledge
+}
+
+func (s *fakeKnowledgeStore) ListKnowledges(ctx context.Context, opts types.GetKnowledgeOptions, page, pageSize uint64) ([]*types.Knowledge, error) {
+	var result []*types.Knowledge
+	for _, id := range opts.IDs {
+		if v, ok := s.knowledges[id]; ok {
+			knowledge := *v
+			result = append(result, &knowledge)
+		}
+	}
+	return result, nil
+}
+
+type fakeSpaceStore struct {
+	store.SpaceStore
+}
+
+func (fakeSpaceStore) GetSpace(ctx context.Context, spaceID string) (*types.Space, error) {
+	return nil, sql.ErrNoRows
+}
+
+type fakeCustomConfigStore struct {
+	store.CustomConfigStore
+}
+
+func (fakeCustomConfigStore) Get(ctx context.Context, name string) (*types.CustomConfig, error) {
+	return nil, sql.ErrNoRows
+}
+
+// fakePlugins 不加密知识内容，重排时保持召回顺序
+type fakePlugins struct {
+	core.Plugins
+}
+
+func (fakePlugins) DecryptData(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+func (fakePlugins) Rerank(query string, knowledges []*types.Knowledge, similarity map[string]float32, profile types.RetrievalProfile) ([]*types.Knowledge, *ai.Usage, error) {
+	return knowledges, nil, nil
+}
+
+func (fakePlugins) AppendKnowledgeContentToDocs(docs []*types.PassageInfo, knowledges []*types.Knowledge) ([]*types.PassageInfo, error) {
+	for _, v := range knowledges {
+		docs = append(docs, &types.PassageInfo{ID: v.ID, Title: v.Title, Content: string(v.Content)})
+	}
+	return docs, nil
+}
+
+func newFakeCore() (*core.Core, *fakeVectorStore) {
+	knowledges := map[string]*types.Knowledge{
+		"k1": {ID: "k1", Title: "golang", Content: types.KnowledgeContent("golang scheduler"), Tags: []string{"dev"}},
+		"k2": {ID: "k2", Title: "postgres", Content: types.KnowledgeContent("postgres vacuum"), Tags: []string{"db"}},
+		"k3": {ID: "k3", Title: "golang and postgres", Content: types.KnowledgeContent("pgx driver"), Tags: []string{"dev", "db"}},
+		"k4": {ID: "k4", Title: "cooking", Content: types.KnowledgeContent("pasta recipe")},
+	}
+	vectors := make(map[string][]float32)
+	for id, v := range knowledges {
+		vectors[id] = fakeVector(v.Title)
+	}
+
+	vectorStore := &fakeVectorStore{knowledges: knowledges, vectors: vectors}
+	stores := sqlstore.NewProviderWithStores(&sqlstore.Stores{
+		VectorStore:       vectorStore,
+		KnowledgeStore:    &fakeKnowledgeStore{knowledges: knowledges},
+		SpaceStore:        fakeSpaceStore{},
+		CustomConfigStore: fakeCustomConfigStore{},
+	})
+
+	c := core.NewCore(core.CoreConfig{}, stores, srv.SetupSrvs(srv.ApplyEmbeddingAI(fakeEmbeddingModel, fakeEmbedding{})), cache.NewLRU(cache.DEFAULT_LRU_SIZE))
+	c.Plugins = fakePlugins{}
+	return c, vectorStore
+}
+
+func TestCoreRetriever(t *testing.T) {
+	c, vectorStore := newFakeCore()
+
+	cases := []Case{
+		{Question: "how does golang schedule goroutines", ExpectedIDs: []string{"k1", "k3"}},
+		{Question: "postgres vacuum", ExpectedIDs: []string{"k2"}},
+	}
+	report, err := NewRunner(NewCoreRetriever(c, "space", "", nil, false), nil, 1, 3).Run(context.Background(), cases)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := report.Summary
+	if s.Evaluated != 2 || s.Failed != 0 {
+		t.Fatalf("unexpected summary: %+v", s)
+	}
+	if !almostEqual(s.MRR, 1) || !almostEqual(s.Recall[3], 1) {
+		t.Errorf("unexpected metrics: mrr %v, recall %v", s.MRR, s.Recall)
+	}
+	if got := report.Cases[0].Retrieved; !slices.Equal(got, []string{"k1", "k3"}) {
+		t.Errorf("unexpected retrieved knowledges: %v", got)
+	}
+	for _, v := range vectorStore.opts {
+		if v.Model != fakeEmbeddingModel || v.SpaceID != "space" {
+			t.Errorf("unexpected vector query options: %+v", v)
+		}
+	}
+
+	// 标签过滤会传递到向量检索
+	docs, err := NewCoreRetriever(c, "space", "", []string{"db"}, false).Retrieve(context.Background(), "golang")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].ID != "k3" {
+		t.Errorf("expected only tagged knowledge to be retrieved, got %+v", docs)
+	}
+}
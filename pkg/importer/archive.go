@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	MAX_ARCHIVE_SIZE      = 512 << 20 // 导入归档的最大体积
+	MAX_ARCHIVE_FILE_SIZE = 100 << 20 // 归档内单个文件解压后的最大体积
+	MAX_ARCHIVE_FILES     = 20000     // 归档内文件数量上限
+)
+
+var (
+	ErrArchiveTooLarge = errors.New("archive is too large")
+	ErrEmptyArchive    = errors.New("archive contains no importable files")
+)
+
+// Archive 导入使用的 zip 归档，路径已统一为不带前导 / 的 slash 路径
+// 归档只有一个顶层目录时(常见于直接压缩整个 vault 文件夹)，该目录会被去掉
+type Archive struct {
+	files map[string]*zip.File
+	paths []string
+}
+
+// OpenArchive 打开 zip 归档，忽略目录、隐藏文件(如 .obsidian/)与 macOS 生成的元数据
+func OpenArchive(data []byte) (*Archive, error) {
+	if len(data) > MAX_ARCHIVE_SIZE {
+		return nil, ErrArchiveTooLarge
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+
+	archive := &Archive{
+		files: make(map[string]*zip.File),
+	}
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := cleanArchivePath(f.Name)
+		if name == "" || isIgnoredPath(name) {
+			continue
+		}
+		if len(archive.paths) >= MAX_ARCHIVE_FILES {
+			return nil, ErrArchiveTooLarge
+		}
+		if _, exist := archive.files[name]; exist {
+			continue
+		}
+		archive.files[name] = f
+		archive.paths = append(archive.paths, name)
+	}
+
+	if len(archive.paths) == 0 {
+		return nil, ErrEmptyArchive
+	}
+
+	archive.stripRoot()
+	sort.Strings(archive.paths)
+	return archive, nil
+}
+
+// stripRoot 所有文件位于同一个顶层目录下时去掉该目录
+func (a *Archive) stripRoot() {
+	root := ""
+	for _, p := range a.paths {
+		i := strings.Index(p, "/")
+		if i < 0 {
+			return
+		}
+		if root == "" {
+			root = p[:i+1]
+		} else if !strings.HasPrefix(p, root) {
+			return
+		}
+	}
+
+	files := make(map[string]*zip.File, len(a.files))
+	for i, p := range a.paths {
+		a.paths[i] = strings.TrimPrefix(p, root)
+		files[a.paths[i]] = a.files[p]
+	}
+	a.files = files
+}
+
+// Paths 返回归档内全部文件路径
+func (a *Archive) Paths() []string {
+	return a.paths
+}
+
+// Has 判断归档内是否存在该文件
+func (a *Archive) Has(name string) bool {
+	_, ok := a.files[name]
+	return ok
+}
+
+// ModTime 返回文件在归档中记录的修改时间
+func (a *Archive) ModTime(name string) time.Time {
+	if f, ok := a.files[name]; ok {
+		return f.Modified
+	}
+	return time.Time{}
+}
+
+// ReadFile 读取归档内的文件，超过 MAX_ARCHIVE_FILE_SIZE 时返回 ErrArchiveTooLarge
+func (a *Archive) ReadFile(name string) ([]byte, error) {
+	f, ok := a.files[name]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found in archive", name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, MAX_ARCHIVE_FILE_SIZE+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MAX_ARCHIVE_FILE_SIZE {
+		return nil, ErrArchiveTooLarge
+	}
+	return data, nil
+}
+
+func cleanArchivePath(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = path.Clean("/" + name)
+	return strings.TrimPrefix(name, "/")
+}
+
+func isIgnoredPath(name string) bool {
+	for _, seg := range strings.Split(name, "/") {
+		if strings.HasPrefix(seg, ".") || seg == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
+
+// FolderSegments 返回文件所在目录的各级目录名
+func FolderSegments(name string) []string {
+	dir := path.Dir(name)
+	if dir == "." || dir == "/" {
+		return nil
+	}
+	return strings.Split(dir, "/")
+}
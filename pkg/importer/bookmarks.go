@@ -0,0 +1,286 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// 书签文件格式
+const (
+	BOOKMARK_FORMAT_NETSCAPE   = "netscape"   // 浏览器导出的 Netscape 书签 HTML
+	BOOKMARK_FORMAT_POCKET     = "pocket"     // Pocket 导出的 CSV
+	BOOKMARK_FORMAT_INSTAPAPER = "instapaper" // Instapaper 导出的 CSV
+	BOOKMARK_FORMAT_CSV        = "csv"        // 其他包含 url 列的 CSV
+)
+
+var ErrUnknownBookmarkFormat = errors.New("unknown bookmark file format")
+
+// Bookmark 书签或稍后读条目
+type Bookmark struct {
+	URL         string
+	Title       string
+	Description string
+	Folders     []string // 所在书签文件夹的各级名称
+	Tags        []string
+	AddedAt     time.Time
+}
+
+// ParseBookmarks 自动识别 Netscape 书签 HTML 或稍后读 CSV 并解析，返回识别出的格式
+func ParseBookmarks(data []byte) (string, []Bookmark, error) {
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+	head := bytes.ToLower(data[:min(len(data), 1024)])
+	var (
+		format    string
+		bookmarks []Bookmark
+		err       error
+	)
+	if bytes.Contains(head, []byte("<!doctype netscape-bookmark-file")) || bytes.Contains(head, []byte("<dl")) || bytes.Contains(head, []byte("<html")) {
+		format = BOOKMARK_FORMAT_NETSCAPE
+		bookmarks, err = ParseNetscapeBookmarks(data)
+	} else {
+		format, bookmarks, err = ParseReadLaterCSV(data)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if len(bookmarks) == 0 {
+		return "", nil, ErrEmptyArchive
+	}
+	return format, bookmarks, nil
+}
+
+// ParseNetscapeBookmarks 解析浏览器导出的 Netscape 书签 HTML，<H3> 文件夹层级记录在 Bookmark.Folders 中
+// 书签栏、其他书签等浏览器内置的根文件夹不会作为文件夹名称
+func ParseNetscapeBookmarks(data []byte) ([]Bookmark, error) {
+	var (
+		tokenizer = html.NewTokenizer(bytes.NewReader(data))
+		res       []Bookmark
+		folders   []string // 每一层 <DL> 对应的文件夹名称，内置文件夹为空字符串
+		pending   string   // 等待下一个 <DL> 的文件夹名称
+		textOf    atom.Atom
+		text      strings.Builder
+		current   *Bookmark
+	)
+
+	folderPath := func() []string {
+		var res []string
+		for _, f := range folders {
+			if f != "" {
+				res = append(res, f)
+			}
+		}
+		return res
+	}
+
+	flushText := func() {
+		value := strings.TrimSpace(strings.Join(strings.Fields(text.String()), " "))
+		switch textOf {
+		case atom.H3:
+			pending = value
+		case atom.A:
+			if current != nil {
+				current.Title = value
+			}
+		case atom.Dd:
+			if len(res) > 0 && res[len(res)-1].Description == "" {
+				res[len(res)-1].Description = value
+			}
+		}
+		textOf = 0
+		text.Reset()
+	}
+
+	for {
+		tt := tokenizer.Next()
+		switch tt {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return nil, err
+			}
+			flushText()
+			return res, nil
+		case html.TextToken:
+			if textOf != 0 {
+				text.Write(tokenizer.Text())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.DataAtom {
+			case atom.H3:
+				flushText()
+				textOf = atom.H3
+				if tokenAttr(token, "personal_toolbar_folder") == "true" || tokenAttr(token, "unfiled_bookmarks_folder") == "true" {
+					// 内置文件夹不计入层级
+					textOf = 0
+					pending = ""
+				}
+			case atom.Dl:
+				flushText()
+				folders = append(folders, pending)
+				pending = ""
+			case atom.A:
+				flushText()
+				href := strings.TrimSpace(tokenAttr(token, "href"))
+				if !isWebURL(href) {
+					continue
+				}
+				current = &Bookmark{
+					URL:     href,
+					Folders: folderPath(),
+					Tags:    normalizeTags(strings.Split(tokenAttr(token, "tags"), ",")),
+					AddedAt: unixAttr(tokenAttr(token, "add_date")),
+				}
+				textOf = atom.A
+			case atom.Dd:
+				flushText()
+				textOf = atom.Dd
+			case atom.Dt:
+				flushText()
+			}
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			switch token.DataAtom {
+			case atom.H3:
+				flushText()
+			case atom.A:
+				flushText()
+				if current != nil {
+					res = append(res, *current)
+					current = nil
+				}
+			case atom.Dl:
+				flushText()
+				if len(folders) > 0 {
+					folders = folders[:len(folders)-1]
+				}
+			}
+		}
+	}
+}
+
+func tokenAttr(token html.Token, key string) string {
+	for _, attr := range token.Attr {
+		if strings.EqualFold(attr.Key, key) {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// unixAttr 解析秒级时间戳，部分浏览器导出的是微秒级时间戳
+func unixAttr(value string) time.Time {
+	ts, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || ts <= 0 {
+		return time.Time{}
+	}
+	for ts > 1e11 {
+		ts /= 1000
+	}
+	return time.Unix(ts, 0)
+}
+
+func isWebURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// ParseReadLaterCSV 解析 Pocket、Instapaper 或其他包含 url 列的 CSV
+func ParseReadLaterCSV(data []byte) (string, []Bookmark, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\uFEFF"))))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse csv: %w", err)
+	}
+	if len(records) == 0 {
+		return "", nil, ErrUnknownBookmarkFormat
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["url"]; !ok {
+		return "", nil, ErrUnknownBookmarkFormat
+	}
+
+	format := BOOKMARK_FORMAT_CSV
+	if _, ok := columns["time_added"]; ok {
+		format = BOOKMARK_FORMAT_POCKET
+	} else if _, ok := columns["selection"]; ok {
+		format = BOOKMARK_FORMAT_INSTAPAPER
+	}
+
+	column := func(record []string, names ...string) string {
+		for _, name := range names {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+		}
+		return ""
+	}
+
+	var res []Bookmark
+	for _, record := range records[1:] {
+		bookmark := Bookmark{
+			URL:         column(record, "url"),
+			Title:       column(record, "title"),
+			Description: column(record, "selection", "description", "excerpt"),
+		}
+		if !isWebURL(bookmark.URL) {
+			continue
+		}
+
+		switch format {
+		case BOOKMARK_FORMAT_POCKET:
+			// Pocket 的多个标签以 | 分隔
+			bookmark.Tags = normalizeTags(strings.Split(column(record, "tags"), "|"))
+			bookmark.AddedAt = unixAttr(column(record, "time_added"))
+		case BOOKMARK_FORMAT_INSTAPAPER:
+			// Unread / Archive 是 Instapaper 的阅读状态，不作为文件夹
+			if folder := column(record, "folder"); folder != "" && folder != "Unread" && folder != "Archive" {
+				bookmark.Folders = []string{folder}
+			}
+			bookmark.AddedAt = unixAttr(column(record, "timestamp"))
+		default:
+			bookmark.Tags = normalizeTags(strings.Split(column(record, "tags"), ","))
+			if folder := column(record, "folder"); folder != "" {
+				bookmark.Folders = strings.Split(folder, "/")
+			}
+			if t := fieldTime(column(record, "created", "created_at", "date")); !t.IsZero() {
+				bookmark.AddedAt = t
+			} else {
+				bookmark.AddedAt = unixAttr(column(record, "timestamp"))
+			}
+		}
+		res = append(res, bookmark)
+	}
+	return format, res, nil
+}
+
+// NormalizeURL 规范化书签地址用于去重：协议与域名转为小写，去掉 fragment 与根路径结尾的 /
+func NormalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(raw)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "/" {
+		u.Path = ""
+	}
+	return u.String()
+}
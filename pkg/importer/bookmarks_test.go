@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNetscapeBookmarks(t *testing.T) {
+	data := `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><H3 ADD_DATE="1700000000" PERSONAL_TOOLBAR_FOLDER="true">Bookmarks bar</H3>
+    <DL><p>
+        <DT><H3>Dev</H3>
+        <DL><p>
+            <DT><A HREF="https://go.dev/" ADD_DATE="1700000001" TAGS="go,lang">The Go
+            Programming Language</A>
+            <DD>Official site
+            <DT><A HREF="javascript:void(0)">bookmarklet</A>
+        </DL><p>
+        <DT><A HREF="https://example.com/a" ADD_DATE="1700000002000000">Example</A>
+    </DL><p>
+</DL><p>`
+
+	format, bookmarks, err := ParseBookmarks([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != BOOKMARK_FORMAT_NETSCAPE || len(bookmarks) != 2 {
+		t.Fatalf("unexpected result: %s %+v", format, bookmarks)
+	}
+
+	goDev := bookmarks[0]
+	if goDev.Title != "The Go Programming Language" || goDev.Description != "Official site" ||
+		!reflect.DeepEqual(goDev.Folders, []string{"Dev"}) || !reflect.DeepEqual(goDev.Tags, []string{"go", "lang"}) ||
+		goDev.AddedAt.Unix() != 1700000001 {
+		t.Errorf("unexpected bookmark: %+v", goDev)
+	}
+
+	example := bookmarks[1]
+	if example.Title != "Example" || len(example.Folders) != 0 || example.AddedAt.Unix() != 1700000002 {
+		t.Errorf("unexpected bookmark: %+v", example)
+	}
+}
+
+func TestParseReadLaterCSV(t *testing.T) {
+	pocket := "title,url,time_added,tags,status\nGo,https://go.dev,1700000000,go|lang,unread\nBad,not a url,1700000000,,unread\n"
+	format, bookmarks, err := ParseBookmarks([]byte(pocket))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != BOOKMARK_FORMAT_POCKET || len(bookmarks) != 1 || !reflect.DeepEqual(bookmarks[0].Tags, []string{"go", "lang"}) {
+		t.Errorf("unexpected pocket result: %s %+v", format, bookmarks)
+	}
+
+	instapaper := "URL,Title,Selection,Folder,Timestamp\nhttps://a.com,A,quote,Reading,1700000000\nhttps://b.com,B,,Unread,1700000000\n"
+	format, bookmarks, err = ParseBookmarks([]byte(instapaper))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != BOOKMARK_FORMAT_INSTAPAPER || len(bookmarks) != 2 ||
+		!reflect.DeepEqual(bookmarks[0].Folders, []string{"Reading"}) || bookmarks[0].Description != "quote" || len(bookmarks[1].Folders) != 0 {
+		t.Errorf("unexpected instapaper result: %s %+v", format, bookmarks)
+	}
+
+	if _, _, err = ParseBookmarks([]byte("name,link\na,b\n")); err != ErrUnknownBookmarkFormat {
+		t.Errorf("expected ErrUnknownBookmarkFormat, got %v", err)
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	cases := map[string]string{
+		"HTTPS://Example.COM/":       "https://example.com",
+		"https://example.com/a#top":  "https://example.com/a",
+		"https://example.com/a?b=1":  "https://example.com/a?b=1",
+		"  https://example.com/A/  ": "https://example.com/A/",
+	}
+	for in, want := range cases {
+		if got := NormalizeURL(in); got != want {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter markdown 文件头部 YAML 元数据中与知识相关的字段
+type FrontMatter struct {
+	Title     string
+	Tags      []string
+	Aliases   []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Fields    map[string]any // 全部原始字段
+}
+
+var frontMatterDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	// Notion 导出的 CSV 中使用的日期格式
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006",
+}
+
+// SplitFrontMatter 拆分 markdown 头部的 YAML front-matter 与正文
+// 没有 front-matter 或 YAML 无法解析时返回 nil 与原始内容
+func SplitFrontMatter(content string) (*FrontMatter, string) {
+	content = strings.TrimPrefix(content, "\uFEFF")
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return nil, content
+	}
+
+	rest := normalized[4:]
+	var raw, body string
+	for offset := 0; ; {
+		i := strings.Index(rest[offset:], "\n")
+		line := rest[offset:]
+		if i >= 0 {
+			line = rest[offset : offset+i]
+		}
+		if trimmed := strings.TrimRight(line, " \t"); trimmed == "---" || trimmed == "..." {
+			raw = rest[:offset]
+			if i >= 0 {
+				body = rest[offset+i+1:]
+			}
+			break
+		}
+		if i < 0 {
+			return nil, content
+		}
+		offset += i + 1
+	}
+
+	fields := make(map[string]any)
+	if err := yaml.Unmarshal([]byte(raw), &fields); err != nil {
+		return nil, content
+	}
+
+	fm := &FrontMatter{
+		Fields: fields,
+	}
+	for key, value := range fields {
+		name := normalizeFieldName(key)
+		switch name {
+		case "title":
+			fm.Title = strings.TrimSpace(fieldString(value))
+		case "tags", "tag":
+			fm.Tags = append(fm.Tags, fieldList(value, true)...)
+		case "aliases", "alias":
+			fm.Aliases = append(fm.Aliases, fieldList(value, false)...)
+		case "created", "createdat", "creationdate", "createdtime", "date":
+			// date 字段优先级低于明确的创建时间字段
+			if t := fieldTime(value); !t.IsZero() && (fm.CreatedAt.IsZero() || name != "date") {
+				fm.CreatedAt = t
+			}
+		case "updated", "updatedat", "modified", "modifiedat", "lastmodified", "updatedtime":
+			if t := fieldTime(value); !t.IsZero() {
+				fm.UpdatedAt = t
+			}
+		}
+	}
+
+	fm.Tags = normalizeTags(fm.Tags)
+	return fm, strings.TrimLeft(body, "\n")
+}
+
+func normalizeFieldName(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(key)
+}
+
+func fieldString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format("2006-01-02")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// fieldList 兼容 YAML 列表与分隔字符串两种写法，字符串按逗号分隔，splitSpace 时同时按空白分隔
+func fieldList(value any, splitSpace bool) []string {
+	switch v := value.(type) {
+	case []any:
+		var res []string
+		for _, item := range v {
+			if s := strings.TrimSpace(fieldString(item)); s != "" {
+				res = append(res, s)
+			}
+		}
+		return res
+	case string:
+		var res []string
+		for _, item := range strings.FieldsFunc(v, func(r rune) bool {
+			return r == ',' || (splitSpace && (r == ' ' || r == '\t'))
+		}) {
+			if item = strings.TrimSpace(item); item != "" {
+				res = append(res, item)
+			}
+		}
+		return res
+	default:
+		if s := strings.TrimSpace(fieldString(v)); s != "" {
+			return []string{s}
+		}
+		return nil
+	}
+}
+
+func fieldTime(value any) time.Time {
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		v = strings.TrimSpace(v)
+		for _, layout := range frontMatterDateLayouts {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// normalizeTags 去掉标签的 # 前缀并去重
+func normalizeTags(tags []string) []string {
+	var (
+		res  []string
+		seen = make(map[string]bool)
+	)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	return res
+}
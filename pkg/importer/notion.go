@@ -0,0 +1,252 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Notion 导出时在页面与目录名后追加的页面ID，如 "Page 0123456789abcdef0123456789abcdef"
+	notionIDSuffixRegexp = regexp.MustCompile(`\s+[0-9a-fA-F]{32}$`)
+	// 页面正文首行的标题
+	notionHeadingRegexp = regexp.MustCompile(`^#\s+(.+?)\s*$`)
+)
+
+// notion 数据库属性中可映射为知识字段的列名(小写)
+var (
+	notionTagProperties     = map[string]bool{"tags": true, "tag": true, "标签": true}
+	notionCreatedProperties = map[string]bool{"created": true, "created time": true, "date created": true, "创建时间": true}
+	notionUpdatedProperties = map[string]bool{"last edited time": true, "updated": true, "last edited": true, "最后编辑时间": true}
+)
+
+// ParseNotion 解析 Notion 的 "Markdown & CSV" 导出归档
+// 页面的子页面位于与页面同名的目录中，目录层级(去掉页面ID)记录在 Note.Folders 中；
+// 数据库导出为 CSV，每一行作为一篇笔记，列作为 Note.Properties，存在对应行页面时合并页面正文
+func ParseNotion(data []byte) (*Vault, error) {
+	archive, err := openNotionArchive(data)
+	if err != nil {
+		return nil, err
+	}
+
+	vault := newVault(archive)
+	var databases []string
+	for _, p := range archive.Paths() {
+		switch {
+		case isMarkdownFile(p):
+			raw, err := archive.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+			note := &Note{
+				Path:      p,
+				Folders:   notionFolders(p),
+				Title:     NotionTitle(strings.TrimSuffix(path.Base(p), path.Ext(p))),
+				UpdatedAt: archive.ModTime(p),
+			}
+			note.Content = note.trimHeading(string(raw))
+			vault.addNote(note)
+		case strings.EqualFold(path.Ext(p), ".csv"):
+			databases = append(databases, p)
+			vault.addFile(p)
+		default:
+			vault.addFile(p)
+		}
+	}
+
+	for _, p := range databases {
+		// 新版导出同时包含 "xx.csv" 与包含全部行的 "xx_all.csv"，只解析后者
+		if !strings.HasSuffix(p, "_all.csv") && archive.Has(strings.TrimSuffix(p, path.Ext(p))+"_all.csv") {
+			continue
+		}
+		if err = vault.addNotionDatabase(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = vault.build(); err != nil {
+		return nil, err
+	}
+	return vault, nil
+}
+
+// openNotionArchive 打开 Notion 导出归档，体积较大的导出会被拆分为多个内层 zip，此时合并全部内层归档
+func openNotionArchive(data []byte) (*Archive, error) {
+	archive, err := OpenArchive(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range archive.Paths() {
+		if !strings.EqualFold(path.Ext(p), ".zip") {
+			return archive, nil
+		}
+	}
+
+	merged := &Archive{
+		files: make(map[string]*zip.File),
+	}
+	size := 0
+	for _, p := range archive.Paths() {
+		raw, err := archive.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		if size += len(raw); size > MAX_ARCHIVE_SIZE {
+			return nil, ErrArchiveTooLarge
+		}
+		part, err := OpenArchive(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", p, err)
+		}
+		for _, name := range part.Paths() {
+			if _, exist := merged.files[name]; exist {
+				continue
+			}
+			merged.files[name] = part.files[name]
+			merged.paths = append(merged.paths, name)
+		}
+	}
+	if len(merged.paths) > MAX_ARCHIVE_FILES {
+		return nil, ErrArchiveTooLarge
+	}
+	merged.stripRoot()
+	return merged, nil
+}
+
+// NotionTitle 去掉 Notion 文件名或目录名中的页面ID
+func NotionTitle(name string) string {
+	title := strings.TrimSpace(notionIDSuffixRegexp.ReplaceAllString(name, ""))
+	if title == "" {
+		return strings.TrimSpace(name)
+	}
+	return title
+}
+
+func notionFolders(p string) []string {
+	folders := FolderSegments(p)
+	for i, folder := range folders {
+		folders[i] = NotionTitle(folder)
+	}
+	return folders
+}
+
+// trimHeading 去掉页面正文首行与标题重复的一级标题，并以该标题作为笔记标题(文件名中的标题可能被截断或替换了特殊字符)
+func (n *Note) trimHeading(content string) string {
+	content = strings.TrimPrefix(strings.ReplaceAll(content, "\r\n", "\n"), "\uFEFF")
+	first, rest, _ := strings.Cut(content, "\n")
+	if m := notionHeadingRegexp.FindStringSubmatch(first); m != nil {
+		n.Title = m[1]
+		return strings.TrimLeft(rest, "\n")
+	}
+	return content
+}
+
+// addNotionDatabase 将数据库 CSV 的每一行登记为笔记
+func (v *Vault) addNotionDatabase(p string) error {
+	raw, err := v.ReadFile(p)
+	if err != nil {
+		return err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(raw, []byte("\uFEFF"))))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to parse database %s: %w", p, err)
+	}
+	if len(records) < 2 {
+		return nil
+	}
+
+	name := strings.TrimSuffix(strings.TrimSuffix(path.Base(p), path.Ext(p)), "_all")
+	dir := path.Join(path.Dir(p), name)
+	folders := append(notionFolders(p), NotionTitle(name))
+
+	// 行页面位于与数据库同名的目录下，按标题匹配
+	pages := make(map[string]*Note)
+	for _, note := range v.Notes {
+		if path.Dir(note.Path) == dir {
+			pages[note.Title] = note
+		}
+	}
+
+	header := records[0]
+	for i, record := range records[1:] {
+		var properties []Property
+		for j, value := range record {
+			if j == 0 || j >= len(header) || strings.TrimSpace(value) == "" {
+				continue
+			}
+			properties = append(properties, Property{Name: strings.TrimSpace(header[j]), Value: strings.TrimSpace(value)})
+		}
+
+		title := strings.TrimSpace(record[0])
+		note, ok := pages[title]
+		if !ok {
+			if title == "" && len(properties) == 0 {
+				continue
+			}
+			note = &Note{
+				// 没有行页面时使用虚拟路径，保证路径唯一
+				Path:      path.Join(dir, fmt.Sprintf("%s %d.md", title, i+1)),
+				Folders:   folders,
+				Title:     title,
+				UpdatedAt: v.ModTime(p),
+			}
+			if note.Title == "" {
+				note.Title = fmt.Sprintf("%s %d", NotionTitle(name), i+1)
+			}
+			v.addNote(note)
+		}
+		note.Content = trimPropertyLines(note.Content, header)
+		note.setProperties(properties)
+	}
+	return nil
+}
+
+// trimPropertyLines 去掉行页面正文开头 Notion 重复输出的 "列名: 值" 属性行
+func trimPropertyLines(content string, header []string) string {
+	lines := strings.Split(content, "\n")
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		matched := false
+		for _, name := range header {
+			if name = strings.TrimSpace(name); name != "" && strings.HasPrefix(line, name+":") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			break
+		}
+	}
+	return strings.TrimLeft(strings.Join(lines[i:], "\n"), "\n")
+}
+
+// setProperties 设置笔记属性，并将标签与时间列映射到笔记字段
+func (n *Note) setProperties(properties []Property) {
+	n.Properties = properties
+	for _, property := range properties {
+		name := strings.ToLower(property.Name)
+		switch {
+		case notionTagProperties[name]:
+			n.Tags = normalizeTags(append(n.Tags, fieldList(property.Value, false)...))
+		case notionCreatedProperties[name]:
+			if t := fieldTime(property.Value); !t.IsZero() {
+				n.CreatedAt = t
+			}
+		case notionUpdatedProperties[name]:
+			if t := fieldTime(property.Value); !t.IsZero() {
+				n.UpdatedAt = t
+			}
+		}
+	}
+}
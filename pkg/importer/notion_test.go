@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNotion(t *testing.T) {
+	const (
+		pageID = "0123456789abcdef0123456789abcdef"
+		dbID   = "fedcba9876543210fedcba9876543210"
+		rowID  = "00112233445566778899aabbccddeeff"
+	)
+
+	inner := buildZip(t, map[string]string{
+		"Home " + pageID + ".md":                                             "# Home\n\nSee [Tasks](Home%20" + pageID + "/Write%20docs%20" + rowID + ".md) ![](Home%20" + pageID + "/img.png)",
+		"Home " + pageID + "/img.png":                                        "png",
+		"Home " + pageID + "/Tasks " + dbID + ".csv":                         "Name,Status,Tags\nWrite docs,Done,\"a, b\"\n",
+		"Home " + pageID + "/Tasks " + dbID + "_all.csv":                     "\uFEFFName,Status,Tags\nWrite docs,Done,\"a, b\"\nShip,Todo,\n",
+		"Home " + pageID + "/Tasks " + dbID + "/Write docs " + rowID + ".md": "# Write docs\n\nStatus: Done\nTags: a, b\n\nDocs body",
+	})
+	// 大体积导出会被拆分为多个内层 zip
+	vault, err := ParseNotion(buildZip(t, map[string]string{"Export-1-Part-1.zip": string(inner)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notes := make(map[string]*Note)
+	for _, note := range vault.Notes {
+		notes[note.Title] = note
+	}
+	if len(notes) != 3 {
+		t.Fatalf("expected 3 notes, got %d", len(vault.Notes))
+	}
+
+	row := notes["Write docs"]
+	if row == nil || row.Content != "Docs body" {
+		t.Fatalf("unexpected row page: %+v", row)
+	}
+	if !reflect.DeepEqual(row.Folders, []string{"Home", "Tasks"}) || !reflect.DeepEqual(row.Tags, []string{"a", "b"}) {
+		t.Errorf("unexpected row folders or tags: %v %v", row.Folders, row.Tags)
+	}
+	if want := []Property{{Name: "Status", Value: "Done"}, {Name: "Tags", Value: "a, b"}}; !reflect.DeepEqual(row.Properties, want) {
+		t.Errorf("unexpected properties: %v", row.Properties)
+	}
+
+	ship := notes["Ship"]
+	if ship == nil || ship.Content != "" || !reflect.DeepEqual(ship.Properties, []Property{{Name: "Status", Value: "Todo"}}) {
+		t.Errorf("unexpected row without page: %+v", ship)
+	}
+
+	home := notes["Home"]
+	if home == nil || len(home.Folders) != 0 {
+		t.Fatalf("unexpected home page: %+v", home)
+	}
+	got := vault.Rewrite(home, LinkRewriter{
+		Note: func(target *Note, text string) string {
+			return "[[" + target.Title + "|" + text + "]]"
+		},
+		File: func(p string) (string, error) {
+			return "https://static/img.png", nil
+		},
+	})
+	if want := "See [[Write docs|Tasks]] ![](https://static/img.png)"; got != want {
+		t.Errorf("Rewrite() = %q, want %q", got, want)
+	}
+}
+
+func TestNotionTitle(t *testing.T) {
+	if got := NotionTitle("My Page 0123456789abcdef0123456789abcdef"); got != "My Page" {
+		t.Errorf("NotionTitle() = %q", got)
+	}
+	if got := NotionTitle("Plain"); got != "Plain" {
+		t.Errorf("NotionTitle() = %q", got)
+	}
+}
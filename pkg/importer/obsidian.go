@@ -0,0 +1,339 @@
+package importer
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// Note Obsidian vault 或 markdown 文件夹中的一篇笔记
+type Note struct {
+	Path      string   // 归档内路径，如 folder/note.md
+	Folders   []string // 所在目录的各级目录名，位于根目录时为空
+	Title     string   // front-matter 中的 title，缺省为文件名
+	Tags      []string
+	Aliases   []string
+	CreatedAt time.Time // front-matter 中的创建时间，缺省为零值
+	UpdatedAt time.Time // front-matter 中的更新时间，缺省为归档记录的修改时间
+	Content   string    // 去掉 front-matter 后的正文
+
+	// Properties 笔记的结构化属性，如 Notion 数据库中的列，导入时渲染在正文之前
+	Properties []Property
+}
+
+// Property 笔记的一项属性
+type Property struct {
+	Name  string
+	Value string
+}
+
+// Vault 解析后的 markdown 归档(Obsidian vault、Notion 导出等)，笔记之外的文件作为附件按需读取
+type Vault struct {
+	*Archive
+	Notes []*Note
+
+	notesByPath  map[string]*Note   // 小写、不带扩展名的完整路径
+	notesByName  map[string][]*Note // 小写、不带扩展名的文件名或别名
+	filesByName  map[string][]string
+	filesByLower map[string]string
+}
+
+// ParseObsidian 解析 zip 格式的 Obsidian vault 或 markdown 文件夹
+func ParseObsidian(data []byte) (*Vault, error) {
+	archive, err := OpenArchive(data)
+	if err != nil {
+		return nil, err
+	}
+
+	vault := newVault(archive)
+	for _, p := range archive.Paths() {
+		if !isMarkdownFile(p) {
+			vault.addFile(p)
+			continue
+		}
+
+		raw, err := archive.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+
+		note := &Note{
+			Path:      p,
+			Folders:   FolderSegments(p),
+			Title:     strings.TrimSuffix(path.Base(p), path.Ext(p)),
+			UpdatedAt: archive.ModTime(p),
+		}
+		fm, body := SplitFrontMatter(string(raw))
+		note.Content = body
+		if fm != nil {
+			if fm.Title != "" {
+				note.Title = fm.Title
+			}
+			note.Tags = fm.Tags
+			note.Aliases = fm.Aliases
+			note.CreatedAt = fm.CreatedAt
+			if !fm.UpdatedAt.IsZero() {
+				note.UpdatedAt = fm.UpdatedAt
+			}
+		}
+		vault.addNote(note)
+	}
+
+	if err = vault.build(); err != nil {
+		return nil, err
+	}
+	return vault, nil
+}
+
+func newVault(archive *Archive) *Vault {
+	return &Vault{
+		Archive:      archive,
+		notesByPath:  make(map[string]*Note),
+		notesByName:  make(map[string][]*Note),
+		filesByName:  make(map[string][]string),
+		filesByLower: make(map[string]string),
+	}
+}
+
+// addFile 登记笔记之外的附件
+func (v *Vault) addFile(p string) {
+	lower := strings.ToLower(p)
+	v.filesByLower[lower] = p
+	v.filesByName[path.Base(lower)] = append(v.filesByName[path.Base(lower)], p)
+}
+
+// addNote 登记笔记，按路径、文件名与别名建立索引
+func (v *Vault) addNote(note *Note) {
+	v.Notes = append(v.Notes, note)
+	key := strings.ToLower(strings.TrimSuffix(note.Path, path.Ext(note.Path)))
+	v.notesByPath[key] = note
+	v.notesByName[path.Base(key)] = append(v.notesByName[path.Base(key)], note)
+	for _, alias := range note.Aliases {
+		alias = strings.ToLower(alias)
+		v.notesByName[alias] = append(v.notesByName[alias], note)
+	}
+}
+
+// build 全部笔记登记完成后整理索引
+func (v *Vault) build() error {
+	if len(v.Notes) == 0 {
+		return ErrEmptyArchive
+	}
+
+	// 同名笔记优先匹配路径最短的一个，与 Obsidian 的解析规则一致
+	for _, list := range v.notesByName {
+		sort.SliceStable(list, func(i, j int) bool {
+			return len(list[i].Path) < len(list[j].Path)
+		})
+	}
+	for _, list := range v.filesByName {
+		sort.SliceStable(list, func(i, j int) bool {
+			return len(list[i]) < len(list[j])
+		})
+	}
+	return nil
+}
+
+func isMarkdownFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// ResolveNote 按 Obsidian 规则解析链接指向的笔记：相对路径、vault 内完整路径、文件名或别名
+func (v *Vault) ResolveNote(from *Note, target string) *Note {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil
+	}
+	key := strings.ToLower(target)
+	if isMarkdownFile(key) {
+		key = strings.TrimSuffix(key, path.Ext(key))
+	}
+
+	if from != nil {
+		if note, ok := v.notesByPath[cleanArchivePath(path.Join(strings.ToLower(path.Dir(from.Path)), key))]; ok {
+			return note
+		}
+	}
+	if note, ok := v.notesByPath[cleanArchivePath(key)]; ok {
+		return note
+	}
+	if list := v.notesByName[path.Base(key)]; len(list) > 0 {
+		return list[0]
+	}
+	return nil
+}
+
+// ResolveFile 解析链接指向的附件，返回附件在归档内的路径
+func (v *Vault) ResolveFile(from *Note, target string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(target))
+	if key == "" {
+		return "", false
+	}
+
+	if from != nil {
+		if p, ok := v.filesByLower[cleanArchivePath(path.Join(strings.ToLower(path.Dir(from.Path)), key))]; ok {
+			return p, true
+		}
+	}
+	if p, ok := v.filesByLower[cleanArchivePath(key)]; ok {
+		return p, true
+	}
+	if list := v.filesByName[path.Base(key)]; len(list) > 0 {
+		return list[0], true
+	}
+	return "", false
+}
+
+// LinkRewriter 改写笔记中的链接
+type LinkRewriter struct {
+	// Note 返回指向另一篇笔记的链接
+	Note func(target *Note, text string) string
+	// File 返回附件上传后的访问地址，出错时保留原链接
+	File func(path string) (string, error)
+}
+
+var (
+	// 依次匹配：行内代码、wikilink/嵌入、markdown 链接/图片
+	obsidianLinkRegexp = regexp.MustCompile("`[^`\\n]*`|(!?)\\[\\[([^\\[\\]\\n]+)\\]\\]|(!?)\\[([^\\[\\]\\n]*)\\]\\(([^()\\n]+)\\)")
+	// 嵌入图片时 | 后的尺寸参数，如 ![[a.png|300]] 或 ![[a.png|300x200]]
+	embedSizeRegexp = regexp.MustCompile(`^\d+(x\d+)?$`)
+	imageExts       = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".svg": true, ".bmp": true, ".avif": true}
+)
+
+// Rewrite 将笔记中的 wikilink、嵌入与相对路径链接按 rewriter 改写，代码块中的内容保持不变
+// 无法解析的笔记链接转为纯文本，无法解析的附件保留原样
+func (v *Vault) Rewrite(note *Note, rewriter LinkRewriter) string {
+	var (
+		sb      strings.Builder
+		fence   string
+		pending []string
+	)
+
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		text := strings.Join(pending, "\n")
+		sb.WriteString(obsidianLinkRegexp.ReplaceAllStringFunc(text, func(match string) string {
+			return v.rewriteLink(note, match, rewriter)
+		}))
+		sb.WriteString("\n")
+		pending = pending[:0]
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(note.Content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			sb.WriteString(line + "\n")
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+			fence = trimmed[:3]
+			sb.WriteString(line + "\n")
+			continue
+		}
+		pending = append(pending, line)
+	}
+	flush()
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (v *Vault) rewriteLink(note *Note, match string, rewriter LinkRewriter) string {
+	if strings.HasPrefix(match, "`") {
+		return match
+	}
+
+	groups := obsidianLinkRegexp.FindStringSubmatch(match)
+	if strings.HasPrefix(strings.TrimPrefix(match, "!"), "[[") {
+		return v.rewriteWikiLink(note, match, groups[1] == "!", groups[2], rewriter)
+	}
+	return v.rewriteMarkdownLink(note, match, groups[3] == "!", groups[4], groups[5], rewriter)
+}
+
+func (v *Vault) rewriteWikiLink(note *Note, match string, embed bool, inner string, rewriter LinkRewriter) string {
+	target, alias, _ := strings.Cut(inner, "|")
+	target = strings.TrimSpace(target)
+	alias = strings.TrimSpace(alias)
+
+	name, _, _ := strings.Cut(target, "#")
+	if name == "" {
+		// 指向当前笔记内的标题或块
+		return lo.CoalesceOrEmpty(alias, strings.TrimPrefix(target, "#"))
+	}
+
+	if !isMarkdownFile(name) && path.Ext(name) != "" {
+		if p, ok := v.ResolveFile(note, name); ok {
+			if embed && embedSizeRegexp.MatchString(alias) {
+				alias = ""
+			}
+			return v.fileLink(p, lo.CoalesceOrEmpty(alias, path.Base(p)), embed, rewriter, match)
+		}
+	}
+
+	if target := v.ResolveNote(note, name); target != nil {
+		return rewriter.Note(target, sanitizeLinkText(lo.CoalesceOrEmpty(alias, target.Title)))
+	}
+	return lo.CoalesceOrEmpty(alias, target)
+}
+
+func (v *Vault) rewriteMarkdownLink(note *Note, match string, embed bool, text, dest string, rewriter LinkRewriter) string {
+	dest = strings.TrimSpace(dest)
+	if strings.HasPrefix(dest, "<") {
+		if i := strings.Index(dest, ">"); i > 0 {
+			dest = dest[1:i]
+		}
+	} else if i := strings.IndexAny(dest, " \t"); i > 0 {
+		// 去掉链接标题，如 [a](b.png "title")
+		dest = dest[:i]
+	}
+
+	if dest == "" || strings.HasPrefix(dest, "#") || strings.Contains(dest, "://") || strings.HasPrefix(dest, "mailto:") || strings.HasPrefix(dest, "data:") {
+		return match
+	}
+	if unescaped, err := url.PathUnescape(dest); err == nil {
+		dest = unescaped
+	}
+	name, _, _ := strings.Cut(dest, "#")
+
+	if isMarkdownFile(name) {
+		if target := v.ResolveNote(note, name); target != nil {
+			return rewriter.Note(target, sanitizeLinkText(lo.CoalesceOrEmpty(text, target.Title)))
+		}
+		return lo.CoalesceOrEmpty(text, name)
+	}
+
+	if p, ok := v.ResolveFile(note, name); ok {
+		return v.fileLink(p, text, embed, rewriter, match)
+	}
+	return match
+}
+
+func (v *Vault) fileLink(p, text string, embed bool, rewriter LinkRewriter, fallback string) string {
+	link, err := rewriter.File(p)
+	if err != nil || link == "" {
+		return fallback
+	}
+	if embed && imageExts[strings.ToLower(path.Ext(p))] {
+		return "![" + text + "](" + link + ")"
+	}
+	return "[" + lo.CoalesceOrEmpty(text, path.Base(p)) + "](" + link + ")"
+}
+
+// sanitizeLinkText 去掉知识链接显示文本中不允许出现的字符
+func sanitizeLinkText(text string) string {
+	return strings.NewReplacer("[", "(", "]", ")", "\n", " ").Replace(text)
+}
@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSplitFrontMatter(t *testing.T) {
+	fm, body := SplitFrontMatter("---\ntitle: Hello\ntags: [a, '#b']\naliases: hi\ncreated: 2024-05-01 10:30\n---\n\nbody text")
+	if fm == nil {
+		t.Fatal("expected front matter")
+	}
+	if fm.Title != "Hello" || !reflect.DeepEqual(fm.Tags, []string{"a", "b"}) || !reflect.DeepEqual(fm.Aliases, []string{"hi"}) {
+		t.Errorf("unexpected front matter: %+v", fm)
+	}
+	if fm.CreatedAt.Format("2006-01-02 15:04") != "2024-05-01 10:30" {
+		t.Errorf("unexpected created time: %v", fm.CreatedAt)
+	}
+	if body != "body text" {
+		t.Errorf("unexpected body: %q", body)
+	}
+
+	if fm, body := SplitFrontMatter("no front matter"); fm != nil || body != "no front matter" {
+		t.Errorf("expected content without front matter to be kept, got %+v %q", fm, body)
+	}
+}
+
+func TestParseObsidian(t *testing.T) {
+	data := buildZip(t, map[string]string{
+		"vault/Index.md":            "---\ntags: home\n---\nSee [[Daily/Note A|A]], [[Alias B]], [[Missing]] and ![[pic.png|300]].\n```\n[[Index]]\n```",
+		"vault/Daily/Note A.md":     "Back to [index](../Index.md) `[[Index]]`",
+		"vault/Projects/B.md":       "---\ntitle: Project B\naliases: [Alias B]\n---\ncontent",
+		"vault/assets/pic.png":      "png",
+		"vault/.obsidian/app.json":  "{}",
+		"__MACOSX/vault/._Index.md": "",
+	})
+
+	vault, err := ParseObsidian(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vault.Notes) != 3 {
+		t.Fatalf("expected 3 notes, got %d", len(vault.Notes))
+	}
+
+	notes := make(map[string]*Note)
+	for _, note := range vault.Notes {
+		notes[note.Path] = note
+	}
+	index, noteA, noteB := notes["Index.md"], notes["Daily/Note A.md"], notes["Projects/B.md"]
+	if index == nil || noteA == nil || noteB == nil {
+		t.Fatalf("unexpected note paths: %v", vault.Paths())
+	}
+	if noteB.Title != "Project B" || !reflect.DeepEqual(noteA.Folders, []string{"Daily"}) {
+		t.Errorf("unexpected notes: %+v %+v", noteB, noteA)
+	}
+
+	rewriter := LinkRewriter{
+		Note: func(target *Note, text string) string {
+			return "[[" + target.Path + "|" + text + "]]"
+		},
+		File: func(p string) (string, error) {
+			return "https://static/" + p, nil
+		},
+	}
+
+	want := "See [[Daily/Note A.md|A]], [[Projects/B.md|Project B]], Missing and ![pic.png](https://static/assets/pic.png).\n```\n[[Index]]\n```"
+	if got := vault.Rewrite(index, rewriter); got != want {
+		t.Errorf("Rewrite() = %q, want %q", got, want)
+	}
+
+	want = "Back to [[Index.md|index]] `[[Index]]`"
+	if got := vault.Rewrite(noteA, rewriter); got != want {
+		t.Errorf("Rewrite() = %q, want %q", got, want)
+	}
+}
@@ -113,7 +113,7 @@ func ValidateRequest(c *gin.Context, core *core.Core) (*UserContext, error) {
 		return nil, fmt.Errorf("user not found")
 	}
 
-	hasAccess, err := core.Store().UserSpaceStore().GetUserSpaceRole(ctx, accessToken.UserID, spaceID)
+	hasAccess, err := core.GetUserSpaceRole(ctx, accessToken.UserID, spaceID)
 	if err != nil || hasAccess == nil {
 		return nil, fmt.Errorf("user does not have access to this space")
 	}
@@ -92,6 +92,80 @@ func (h *CreateKnowledgeHandler) Handle(
 	}, output, nil
 }
 
+// UpdateKnowledgeInput 修改知识的输入参数
+type UpdateKnowledgeInput struct {
+	ID          string   `json:"id" jsonschema:"The unique identifier of the knowledge to update"`
+	Content     string   `json:"content,omitempty" jsonschema:"New content of the knowledge (markdown), the knowledge will be re-processed when provided"`
+	ContentType string   `json:"content_type,omitempty" jsonschema:"Content format type (markdown or blocks)"`
+	Title       string   `json:"title,omitempty" jsonschema:"New title for the knowledge"`
+	Tags        []string `json:"tags,omitempty" jsonschema:"New tags for the knowledge"`
+}
+
+// UpdateKnowledgeOutput 修改知识的输出结果
+type UpdateKnowledgeOutput struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// UpdateKnowledgeHandler 修改知识的处理器
+type UpdateKnowledgeHandler struct {
+	core *core.Core
+}
+
+// NewUpdateKnowledgeHandler 创建新的知识修改处理器
+func NewUpdateKnowledgeHandler(core *core.Core) *UpdateKnowledgeHandler {
+	return &UpdateKnowledgeHandler{core: core}
+}
+
+// Handle 处理修改知识请求，每次修改都会记录一个来源为 mcp 的修订
+func (h *UpdateKnowledgeHandler) Handle(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	args UpdateKnowledgeInput,
+) (*mcp.CallToolResult, UpdateKnowledgeOutput, error) {
+	userCtx, ok := auth.GetUserContext(ctx)
+	if !ok {
+		return nil, UpdateKnowledgeOutput{}, fmt.Errorf("user context not found")
+	}
+
+	if args.ID == "" {
+		return nil, UpdateKnowledgeOutput{}, fmt.Errorf("knowledge ID is required")
+	}
+	if args.Content == "" && args.Title == "" && len(args.Tags) == 0 {
+		return nil, UpdateKnowledgeOutput{}, fmt.Errorf("at least one of content, title or tags is required")
+	}
+
+	updateArgs := types.UpdateKnowledgeArgs{
+		Title: args.Title,
+		Tags:  args.Tags,
+	}
+	if args.Content != "" {
+		updateArgs.Content = types.KnowledgeContent(args.Content)
+		updateArgs.ContentType = types.StringToKnowledgeContentType(args.ContentType)
+		if updateArgs.ContentType == types.KNOWLEDGE_CONTENT_TYPE_UNKNOWN {
+			updateArgs.ContentType = types.KNOWLEDGE_CONTENT_TYPE_MARKDOWN
+		}
+	}
+
+	logic := v1.NewKnowledgeLogic(ctx, h.core)
+	if err := logic.UpdateWithSource(userCtx.Field("space_id"), args.ID, updateArgs, types.KNOWLEDGE_REVISION_SOURCE_MCP, userCtx.User); err != nil {
+		return nil, UpdateKnowledgeOutput{}, fmt.Errorf("failed to update knowledge: %w", err)
+	}
+
+	output := UpdateKnowledgeOutput{
+		ID:      args.ID,
+		Status:  "processing",
+		Message: "Knowledge updated successfully, processing in background",
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: "Knowledge updated: " + args.ID},
+		},
+	}, output, nil
+}
+
 // GetKnowledgeInput 获取知识的输入参数
 type GetKnowledgeInput struct {
 	ID string `json:"id" jsonschema:"The unique identifier of the knowledge to retrieve"`
@@ -213,6 +287,16 @@ func RegisterCreateKnowledgeTool(server *mcp.Server, core *core.Core) {
 	}, handler.Handle)
 }
 
+// RegisterUpdateKnowledgeTool 注册 update_knowledge 工具
+func RegisterUpdateKnowledgeTool(server *mcp.Server, core *core.Core) {
+	handler := NewUpdateKnowledgeHandler(core)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_knowledge",
+		Description: "Update an existing knowledge entry in QukaAI. Only provided fields are changed. The previous version is kept in the knowledge revision history and can be restored. Updating content triggers re-processing in the background.",
+	}, handler.Handle)
+}
+
 // RegisterGetKnowledgeTool 注册 get_knowledge 工具
 func RegisterGetKnowledgeTool(server *mcp.Server, core *core.Core) {
 	handler := NewGetKnowledgeHandler(core)
@@ -16,7 +16,12 @@ func RegisterTools(server *mcp.Server, core *core.Core) {
 	// 注册 search_knowledge 工具
 	RegisterSearchKnowledgeTool(server, core)
 
+	// 注册 related_knowledges 工具
+	RegisterRelatedKnowledgeTool(server, core)
+
+	// 注册 update_knowledge 工具
+	RegisterUpdateKnowledgeTool(server, core)
+
 	// 未来可添加更多工具
-	// RegisterUpdateKnowledgeTool(server, core)
 	// RegisterDeleteKnowledgeTool(server, core)
 }
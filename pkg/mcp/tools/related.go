@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/quka-ai/quka-ai/app/core"
+	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
+	"github.com/quka-ai/quka-ai/pkg/mcp/auth"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+// RelatedKnowledgeInput 获取相关知识的输入参数
+type RelatedKnowledgeInput struct {
+	ID    string `json:"id" jsonschema:"The unique identifier of the knowledge"`
+	Limit int    `json:"limit,omitempty" jsonschema:"Maximum number of results to return (default: 10, max: 50)"`
+}
+
+// RelatedKnowledgeOutput 获取相关知识的输出
+type RelatedKnowledgeOutput struct {
+	ID   string                   `json:"id"`
+	List []types.RelatedKnowledge `json:"list"`
+}
+
+// RelatedKnowledgeHandler 获取相关知识的处理器
+type RelatedKnowledgeHandler struct {
+	core *core.Core
+}
+
+// NewRelatedKnowledgeHandler 创建新的相关知识处理器
+func NewRelatedKnowledgeHandler(core *core.Core) *RelatedKnowledgeHandler {
+	return &RelatedKnowledgeHandler{core: core}
+}
+
+// Handle 处理获取相关知识请求
+func (h *RelatedKnowledgeHandler) Handle(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	args RelatedKnowledgeInput,
+) (*mcp.CallToolResult, RelatedKnowledgeOutput, error) {
+	userCtx, ok := auth.GetUserContext(ctx)
+	if !ok {
+		return nil, RelatedKnowledgeOutput{}, fmt.Errorf("user context not found")
+	}
+
+	if args.ID == "" {
+		return nil, RelatedKnowledgeOutput{}, fmt.Errorf("knowledge ID is required")
+	}
+
+	list, err := v1.NewKnowledgeLogic(ctx, h.core).ListRelatedKnowledges(userCtx.Field("space_id"), args.ID, args.Limit)
+	if err != nil {
+		return nil, RelatedKnowledgeOutput{}, fmt.Errorf("failed to list related knowledges: %w", err)
+	}
+
+	sb := strings.Builder{}
+	if len(list) == 0 {
+		sb.WriteString("No related knowledge found for: " + args.ID)
+	}
+	for i, v := range list {
+		fmt.Fprintf(&sb, "%d. %s (ID: %s, similarity: %.4f)\n", i+1, v.Title, v.ID, v.Similarity)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: sb.String()},
+		},
+	}, RelatedKnowledgeOutput{
+		ID:   args.ID,
+		List: list,
+	}, nil
+}
+
+// RegisterRelatedKnowledgeTool 注册 related_knowledges 工具
+func RegisterRelatedKnowledgeTool(server *mcp.Server, core *core.Core) {
+	handler := NewRelatedKnowledgeHandler(core)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "related_knowledges",
+		Description: "List knowledges that are semantically related to the given knowledge, ranked by similarity. Expired knowledges are excluded.",
+	}, handler.Handle)
+}
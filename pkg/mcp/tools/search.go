@@ -24,7 +24,8 @@ func RegisterSearchKnowledgeTool(server *mcp.Server, core *core.Core) {
 
 // SearchKnowledgeInput 搜索知识的输入参数
 type SearchKnowledgeInput struct {
-	Query string `json:"query" jsonschema:"The search query or question"`
+	Query string   `json:"query" jsonschema:"The search query or question"`
+	Tags  []string `json:"tags,omitempty" jsonschema:"Only search knowledges with any of these tags"`
 	// MaxResults int    `json:"max_results,omitempty" jsonschema:"Maximum number of results to return (default: 5, max: 20)"`
 }
 
@@ -66,7 +67,7 @@ func (h *SearchKnowledgeHandler) Handle(
 
 	tool := rag.NewRagTool(h.core, nil, userCtx.Field("space_id"), userCtx.User, "", "", 0)
 
-	result, err := tool.Handler(ctx, args.Query)
+	result, err := tool.Handler(ctx, args.Query, args.Tags)
 	if err != nil {
 		return nil, SearchKnowledgeOutput{}, fmt.Errorf("failed to handle rag tool: %w", err)
 	}
@@ -19,6 +19,9 @@ import (
 
 	"github.com/quka-ai/quka-ai/app/core"
 	"github.com/quka-ai/quka-ai/app/logic/v1/process"
+	"github.com/quka-ai/quka-ai/pkg/ai/chunker"
+	"github.com/quka-ai/quka-ai/pkg/ai/tools/ocr"
+	"github.com/quka-ai/quka-ai/pkg/docparser"
 	"github.com/quka-ai/quka-ai/pkg/plugins/selfhost/srv"
 	pb "github.com/quka-ai/quka-ai/pkg/proto/filechunker"
 	"github.com/quka-ai/quka-ai/pkg/safe"
@@ -30,6 +33,7 @@ type ContentTaskProcess struct {
 	core         *core.Core
 	grpcConn     *grpc.ClientConn
 	grpcClient   pb.FileChunkerServiceClient
+	imageOCR     bool
 	PreChunkChan chan *types.ContentTask
 	ChunkChan    chan *types.ContentTask
 }
@@ -37,6 +41,7 @@ type ContentTaskProcess struct {
 func NewContentTaskProcess(core *core.Core, cfg srv.ChunkService) *ContentTaskProcess {
 	p := &ContentTaskProcess{
 		core:         core,
+		imageOCR:     cfg.ImageOCR,
 		PreChunkChan: make(chan *types.ContentTask, 4),
 		ChunkChan:    make(chan *types.ContentTask, 4),
 	}
@@ -115,6 +120,7 @@ func (p *ContentTaskProcess) ProcessTasks() error {
 				AIFileID:   v.AIFileID,
 				Step:       v.Step,
 				TaskType:   v.TaskType,
+				Chunker:    v.Chunker,
 				CreatedAt:  v.CreatedAt,
 				UpdatedAt:  v.UpdatedAt,
 				RetryTimes: v.RetryTimes,
@@ -131,13 +137,7 @@ func (p *ContentTaskProcess) loop() {
 	for range 10 {
 		go safe.Run(func() {
 			for req := range p.ChunkChan {
-				var err error
-				// Use gRPC service
-				if p.grpcClient != nil {
-					err = p.chunkByGRPC(req)
-				} else {
-					err = fmt.Errorf("gRPC chunk service is not available")
-				}
+				err := p.chunk(req)
 
 				if err != nil {
 					slog.Error("Failed to process chunk task", slog.String("error", err.Error()))
@@ -157,6 +157,121 @@ func (p *ContentTaskProcess) loop() {
 	}
 }
 
+// chunk 任务指定了分片方式时以任务为准，否则使用空间的分片配置
+// 未配置 gRPC 分片服务时使用内置的文档解析器与 markdown 分片器
+func (p *ContentTaskProcess) chunk(task *types.ContentTask) error {
+	conf := p.chunkerConfig(task)
+	if conf.IsLocal() || p.grpcClient == nil {
+		return p.chunkByLocal(task, conf)
+	}
+	return p.chunkByGRPC(task)
+}
+
+func (p *ContentTaskProcess) chunkerConfig(task *types.ContentTask) types.ChunkerConfig {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	var conf types.ChunkerConfig
+	space, err := p.core.GetSpace(ctx, task.SpaceID)
+	if err != nil && err != sql.ErrNoRows {
+		slog.Error("Failed to get space chunker config", slog.String("space_id", task.SpaceID), slog.String("task_id", task.TaskID), slog.String("error", err.Error()))
+	}
+	if space != nil {
+		conf = space.Settings.ChunkerConfig()
+	}
+	if task.Chunker != "" {
+		conf.Mode = task.Chunker
+	}
+	return conf.WithDefault()
+}
+
+// chunkByLocal 使用内置的文档解析器将文件转换为 markdown，再由 markdown 分片器切分
+func (p *ContentTaskProcess) chunkByLocal(task *types.ContentTask, conf types.ChunkerConfig) error {
+	if task.FileURL == "" || task.Step != types.LONG_CONTENT_STEP_CREATE_CHUNK {
+		return fmt.Errorf("Failed to do chunk, please dispose pre chunk")
+	}
+
+	if !docparser.Supported(task.FileName) {
+		return fmt.Errorf("Local chunker does not support file: %s", task.FileName)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*10)
+	defer cancel()
+
+	ok, err := p.core.Plugins.TryLock(ctx, fmt.Sprintf("task:chunk:%s", task.TaskID))
+	if err != nil || !ok {
+		return err
+	}
+
+	taskData, err := p.core.Store().ContentTaskStore().GetTask(ctx, task.TaskID)
+	if err != nil {
+		return err
+	}
+
+	if taskData.Step != types.LONG_CONTENT_STEP_CREATE_CHUNK {
+		return nil
+	}
+
+	downloadCtx, downloadCtxCancel := context.WithTimeout(context.Background(), time.Minute)
+	defer downloadCtxCancel()
+	res, err := p.core.Plugins.FileStorage().DownloadFile(downloadCtx, task.FileURL)
+	if err != nil {
+		return err
+	}
+
+	doc, err := docparser.Parse(task.FileName, res.File)
+	if err != nil {
+		return err
+	}
+	p.processDocumentImages(ctx, taskData, doc)
+
+	chunks := chunker.NewMarkdownChunker(conf).Split(doc.Markdown)
+	if len(chunks) == 0 {
+		return fmt.Errorf("Failed to do chunk, file is empty: %s", task.FileName)
+	}
+
+	if err = p.handlerChunks(taskData, chunks); err != nil {
+		return fmt.Errorf("Failed to handler chunks: %w", err)
+	}
+	return nil
+}
+
+// processDocumentImages 将文档中的图片上传到对象存储并替换 markdown 中的引用，开启 ImageOCR 时在图片后附加识别出的文字
+// 图片处理失败不影响正文分片，上传失败的图片引用会被移除
+func (p *ContentTaskProcess) processDocumentImages(ctx context.Context, task *types.ContentTask, doc *docparser.Document) {
+	if len(doc.Images) == 0 {
+		return
+	}
+
+	if ocrAI := p.core.Srv().AI().GetOCRAI(); p.imageOCR && ocrAI != nil {
+		images := lo.Map(doc.Images, func(item docparser.Image, _ int) []byte {
+			return item.Data
+		})
+		results, err := ocr.RecognizeImages(ctx, ocrAI, images)
+		if err != nil {
+			slog.Error("Failed to recognize document images", slog.String("task_id", task.TaskID), slog.String("error", err.Error()))
+		}
+
+		texts := make(map[string]string, len(results))
+		for i, v := range results {
+			texts[doc.Images[i].Name] = v
+		}
+		doc.AppendImageText(texts)
+	}
+
+	storage := p.core.Plugins.FileStorage()
+	urls := make(map[string]string, len(doc.Images))
+	for _, v := range doc.Images {
+		filePath := types.GenS3FilePath(task.SpaceID, "docparser", fmt.Sprintf("%s-%s", task.TaskID, v.Name))
+		if err := storage.SaveFile(filePath, v.Data); err != nil {
+			slog.Error("Failed to save document image", slog.String("task_id", task.TaskID), slog.String("image", v.Name), slog.String("error", err.Error()))
+			continue
+		}
+		urls[v.Name] = fmt.Sprintf("https://%s/%s", storage.GetStaticDomain(), strings.TrimPrefix(filePath, "/"))
+	}
+	doc.ReplaceImageURLs(urls)
+}
+
 func (p *ContentTaskProcess) chunkByGRPC(task *types.ContentTask) error {
 	if task.FileURL == "" || task.Step != types.LONG_CONTENT_STEP_CREATE_CHUNK {
 		return fmt.Errorf("Failed to do chunk, please dispose pre chunk")
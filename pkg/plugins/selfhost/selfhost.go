@@ -16,6 +16,7 @@ import (
 	"github.com/quka-ai/quka-ai/app/core"
 	v1 "github.com/quka-ai/quka-ai/app/logic/v1"
 	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/ai/bm25"
 	"github.com/quka-ai/quka-ai/pkg/errors"
 	"github.com/quka-ai/quka-ai/pkg/mark"
 	"github.com/quka-ai/quka-ai/pkg/plugins"
@@ -69,26 +70,11 @@ func newSelfHostMode() *SelfHostPlugin {
 	}
 }
 
-type Cache struct{}
-
-func (c *Cache) SetEx(ctx context.Context, key, value string, expiresAt time.Duration) error {
-	return nil
-}
-
-func (c *Cache) Expire(ctx context.Context, key string, expiresAt time.Duration) error {
-	return nil
-}
-
-func (c *Cache) Get(ctx context.Context, key string) (string, error) {
-	return "", nil
-}
-
 type SelfHostPlugin struct {
 	core       *srv.PluginCore
 	Appid      string
 	singleLock *SingleLock
 	storage    core.FileStorage
-	cache      *Cache
 }
 
 func (s *SelfHostPlugin) RegisterHTTPEngine(e *gin.Engine) {
@@ -112,8 +98,6 @@ func (s *SelfHostPlugin) Install(c *core.Core) error {
 		return err
 	}
 
-	s.cache = &Cache{}
-
 	defer func() {
 		process.SetupProcess(s.core)
 	}()
@@ -325,49 +309,81 @@ func (s *SelfHostPlugin) AppendKnowledgeContentToDocs(docs []*types.PassageInfo,
 	return docs, nil
 }
 
-func (s *SelfHostPlugin) Rerank(query string, knowledges []*types.Knowledge) ([]*types.Knowledge, *ai.Usage, error) {
+func (s *SelfHostPlugin) Rerank(query string, knowledges []*types.Knowledge, similarity map[string]float32, profile types.RetrievalProfile) ([]*types.Knowledge, *ai.Usage, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	if len(knowledges) > 10 {
-		res, usage, err := s.core.AppCore.Srv().AI().Rerank(ctx, query, lo.Map(knowledges, func(item *types.Knowledge, _ int) *ai.RerankDoc {
+	aiDriver := s.core.AppCore.Srv().AI()
+	return rerankKnowledges(ctx, aiDriver, aiDriver.RerankModel() == bm25.NAME, query, knowledges, similarity, profile)
+}
+
+type reranker interface {
+	Rerank(ctx context.Context, query string, docs []*ai.RerankDoc) ([]ai.RankDocItem, *ai.Usage, error)
+}
+
+// rerankKnowledges 使用 driver 对候选知识重排，local 表示 driver 为本地 bm25 重排
+// driver 不支持重排时退回本地 bm25 重排
+func rerankKnowledges(ctx context.Context, driver reranker, local bool, query string, knowledges []*types.Knowledge, similarity map[string]float32, profile types.RetrievalProfile) ([]*types.Knowledge, *ai.Usage, error) {
+	// 本地重排没有调用成本，任意数量的候选都进行排序；远程重排模型仅在候选较多时调用
+	if len(knowledges) == 0 || (!local && len(knowledges) <= 10) {
+		return knowledges, nil, nil
+	}
+
+	rerankDocs := func(local bool) []*ai.RerankDoc {
+		return lo.Map(knowledges, func(item *types.Knowledge, _ int) *ai.RerankDoc {
 			sw := mark.NewSensitiveWork()
 			content := sw.Do(lo.If(item.ContentType == types.KNOWLEDGE_CONTENT_TYPE_MARKDOWN, string(item.Content)).Else(item.Content.String()))
+			if local {
+				// 标题参与词法匹配
+				content = item.Title + "\n" + content
+			}
 			return &ai.RerankDoc{
-				ID:      item.ID,
-				Content: content,
+				ID:         item.ID,
+				Content:    content,
+				Similarity: float64(similarity[item.ID]),
 			}
-		}))
+		})
+	}
 
-		if err != nil {
-			if errors.Is(err, errors.ERROR_UNSUPPORTED_FEATURE) {
-				return knowledges, nil, nil
-			}
-			return nil, usage, err
-		}
+	res, usage, err := driver.Rerank(ctx, query, rerankDocs(local))
+	if err != nil && !local && errors.Is(err, errors.ERROR_UNSUPPORTED_FEATURE) {
+		local = true
+		res, usage, err = bm25.New().Rerank(ctx, query, rerankDocs(local))
+	}
+	if err != nil {
+		return nil, usage, err
+	}
 
-		firstScore := res[0].Score
-		latestScore := firstScore - 0.2
-		if firstScore < 0.3 {
-			return nil, usage, nil
-		}
-		if firstScore < 0.5 {
-			latestScore = firstScore - 0.05
-		}
+	if len(res) == 0 {
+		return knowledges, usage, nil
+	}
 
-		docsMap := lo.SliceToMap(knowledges, func(item *types.Knowledge) (string, *types.Knowledge) {
-			return item.ID, item
-		})
+	docsMap := lo.SliceToMap(knowledges, func(item *types.Knowledge) (string, *types.Knowledge) {
+		return item.ID, item
+	})
 
-		var result []*types.Knowledge
-		for _, v := range res {
-			if v.Score < latestScore {
-				break
-			}
-			result = append(result, docsMap[v.ID])
-		}
-		return result, usage, nil
+	if local {
+		// 本地重排得分只表示候选之间的相对高低，不做阈值截断，仅调整顺序
+		return lo.Map(res, func(item ai.RankDocItem, _ int) *types.Knowledge {
+			return docsMap[item.ID]
+		}), usage, nil
 	}
 
-	return knowledges, nil, nil
+	firstScore := res[0].Score
+	latestScore := firstScore - profile.RerankDrop
+	if firstScore < profile.RerankMinScore {
+		return nil, usage, nil
+	}
+	if firstScore < profile.RerankLowScore {
+		latestScore = firstScore - profile.RerankLowDrop
+	}
+
+	var result []*types.Knowledge
+	for _, v := range res {
+		if v.Score < latestScore {
+			break
+		}
+		result = append(result, docsMap[v.ID])
+	}
+	return result, usage, nil
 }
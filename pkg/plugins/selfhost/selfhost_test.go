@@ -0,0 +1,53 @@
+package selfhost
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/quka-ai/quka-ai/pkg/ai"
+	"github.com/quka-ai/quka-ai/pkg/errors"
+	"github.com/quka-ai/quka-ai/pkg/types"
+)
+
+type unsupportedReranker struct {
+	called int
+}
+
+func (r *unsupportedReranker) Rerank(ctx context.Context, query string, docs []*ai.RerankDoc) ([]ai.RankDocItem, *ai.Usage, error) {
+	r.called++
+	return nil, nil, errors.ERROR_UNSUPPORTED_FEATURE
+}
+
+func TestRerankKnowledgesFallbackToBM25(t *testing.T) {
+	var (
+		knowledges []*types.Knowledge
+		similarity = make(map[string]float32)
+	)
+	for i := 0; i < 12; i++ {
+		id := fmt.Sprintf("k%d", i)
+		knowledges = append(knowledges, &types.Knowledge{
+			ID:          id,
+			Title:       fmt.Sprintf("note %d", i),
+			Content:     types.KnowledgeContent("some unrelated content about cooking"),
+			ContentType: types.KNOWLEDGE_CONTENT_TYPE_MARKDOWN,
+		})
+		similarity[id] = 0.5
+	}
+	knowledges[7].Content = types.KnowledgeContent("postgres vector index tuning guide")
+
+	driver := &unsupportedReranker{}
+	result, _, err := rerankKnowledges(context.Background(), driver, false, "postgres vector index", knowledges, similarity, types.DefaultRetrievalProfile())
+	if err != nil {
+		t.Fatalf("expected fallback rerank to succeed, got %v", err)
+	}
+	if driver.called != 1 {
+		t.Errorf("expected driver to be called once, got %d", driver.called)
+	}
+	if len(result) != len(knowledges) {
+		t.Fatalf("expected all knowledges to be kept, got %d", len(result))
+	}
+	if result[0].ID != "k7" {
+		t.Errorf("expected lexical match to be ranked first, got %s", result[0].ID)
+	}
+}
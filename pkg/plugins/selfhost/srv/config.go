@@ -14,4 +14,6 @@ type ChunkService struct {
 	Enabled bool   `toml:"enabled"` // gRPC enabled
 	Address string `toml:"address"` // gRPC server address
 	Timeout int    `toml:"timeout"` // timeout in seconds
+	// ImageOCR 未使用 gRPC 服务时，内置文档解析器提取的图片是否交给 OCR 识别文字
+	ImageOCR bool `toml:"image_ocr"`
 }
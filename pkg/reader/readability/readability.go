@@ -0,0 +1,697 @@
+package readability
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/quka-ai/quka-ai/pkg/docparser"
+)
+
+// ErrNoContent 页面中没有可提取的正文
+var ErrNoContent = errors.New("no readable content found")
+
+// Article 从网页中提取的正文与元数据
+type Article struct {
+	Title     string
+	Byline    string // 作者
+	SiteName  string
+	Excerpt   string // 页面摘要，取自 description
+	Published string // 发布日期，能识别时格式化为 2006-01-02
+	Image     string // 头图地址
+	Content   string // markdown 格式的正文，不包含标题与元数据
+}
+
+// Markdown 返回包含标题、作者、发布日期与头图的完整 markdown
+func (a *Article) Markdown() string {
+	var parts []string
+	if a.Title != "" {
+		parts = append(parts, "# "+a.Title)
+	}
+
+	var meta []string
+	if a.Byline != "" {
+		meta = append(meta, "By "+a.Byline)
+	}
+	if a.Published != "" {
+		meta = append(meta, a.Published)
+	}
+	if a.SiteName != "" {
+		meta = append(meta, a.SiteName)
+	}
+	if len(meta) > 0 {
+		parts = append(parts, "*"+strings.Join(meta, " · ")+"*")
+	}
+
+	// 正文中已包含头图时不重复插入
+	if a.Image != "" && !strings.Contains(a.Content, "("+a.Image+")") {
+		parts = append(parts, "![]("+a.Image+")")
+	}
+	if a.Content != "" {
+		parts = append(parts, a.Content)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote|share|subscribe|newsletter|cookie`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveNames      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeNames      = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|footer|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|widget|subscribe|newsletter`)
+	bylineNames        = regexp.MustCompile(`(?i)byline|author|dateline|writtenby|p-author`)
+	titleSeparators    = regexp.MustCompile(`\s+[|\-–—_»·]\s+`)
+)
+
+// removeElements 提取前直接移除的元素
+var removeElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true, atom.Link: true,
+	atom.Iframe: true, atom.Form: true, atom.Button: true, atom.Input: true, atom.Select: true,
+	atom.Textarea: true, atom.Nav: true, atom.Footer: true, atom.Aside: true, atom.Svg: true,
+	atom.Canvas: true, atom.Object: true, atom.Embed: true, atom.Dialog: true,
+}
+
+// blockElements 包含这些子元素的 div 不作为段落参与打分
+var blockElements = map[atom.Atom]bool{
+	atom.Blockquote: true, atom.Dl: true, atom.Div: true, atom.Img: true, atom.Ol: true, atom.P: true,
+	atom.Pre: true, atom.Table: true, atom.Ul: true, atom.Section: true, atom.Article: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true, atom.Figure: true,
+}
+
+// Parse 解析网页并提取正文，pageURL 用于将相对地址转换为绝对地址
+func Parse(data []byte, pageURL *url.URL) (*Article, error) {
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	meta := collectMeta(root)
+	ld := collectJSONLD(root)
+	article := &Article{
+		Title:     articleTitle(root, meta, ld),
+		Byline:    articleByline(root, meta, ld),
+		SiteName:  firstNonEmpty(meta["og:site_name"], ld["publisher"]),
+		Excerpt:   firstNonEmpty(meta["og:description"], meta["twitter:description"], meta["description"], ld["description"]),
+		Published: formatDate(articlePublished(root, meta, ld)),
+		Image:     resolveURL(pageURL, firstNonEmpty(meta["og:image"], meta["og:image:url"], meta["twitter:image"], meta["twitter:image:src"], ld["image"])),
+	}
+
+	body := findElement(root, atom.Body)
+	if body == nil {
+		return nil, ErrNoContent
+	}
+	prepare(body)
+	container := collectSiblings(topCandidate(body))
+	clean(container, article.Title)
+	absolutize(container, pageURL)
+
+	var buf bytes.Buffer
+	buf.WriteString("<html><body>")
+	if err = html.Render(&buf, container); err != nil {
+		return nil, err
+	}
+	buf.WriteString("</body></html>")
+
+	doc, err := docparser.ParseHTML(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	// 正文中的 data URI 图片无法直接引用，移除占位
+	doc.ReplaceImageURLs(nil)
+	article.Content = strings.TrimSpace(doc.Markdown)
+	if article.Content == "" {
+		return nil, ErrNoContent
+	}
+	if article.Image == "" {
+		article.Image = firstImage(container)
+	}
+	return article, nil
+}
+
+// collectMeta 收集 <meta> 中的 name、property 与 itemprop，同名时保留第一个
+func collectMeta(root *html.Node) map[string]string {
+	meta := make(map[string]string)
+	walk(root, func(n *html.Node) bool {
+		if n.DataAtom != atom.Meta {
+			return true
+		}
+		content := strings.TrimSpace(attr(n, "content"))
+		if content == "" {
+			return false
+		}
+		for _, key := range []string{"property", "name", "itemprop"} {
+			for _, name := range strings.Fields(strings.ToLower(attr(n, key))) {
+				if _, ok := meta[name]; !ok {
+					meta[name] = content
+				}
+			}
+		}
+		return false
+	})
+	return meta
+}
+
+// collectJSONLD 从 schema.org 的 JSON-LD 中读取文章的标题、作者、发布时间、头图与站点名称
+func collectJSONLD(root *html.Node) map[string]string {
+	result := make(map[string]string)
+	walk(root, func(n *html.Node) bool {
+		if n.DataAtom != atom.Script || !strings.Contains(strings.ToLower(attr(n, "type")), "ld+json") {
+			return true
+		}
+		var data any
+		if err := json.Unmarshal([]byte(textContent(n)), &data); err != nil {
+			return false
+		}
+		for _, item := range jsonLDItems(data) {
+			typ := strings.ToLower(jsonString(item["@type"]))
+			if !strings.Contains(typ, "article") && !strings.Contains(typ, "posting") && !strings.Contains(typ, "report") {
+				continue
+			}
+			setDefault(result, "headline", jsonString(item["headline"]))
+			setDefault(result, "description", jsonString(item["description"]))
+			setDefault(result, "author", jsonString(item["author"]))
+			setDefault(result, "datePublished", jsonString(item["datePublished"]))
+			setDefault(result, "image", jsonString(item["image"]))
+			setDefault(result, "publisher", jsonString(item["publisher"]))
+		}
+		return false
+	})
+	return result
+}
+
+// jsonLDItems 展开 JSON-LD 中的数组与 @graph
+func jsonLDItems(data any) []map[string]any {
+	switch v := data.(type) {
+	case []any:
+		var items []map[string]any
+		for _, item := range v {
+			items = append(items, jsonLDItems(item)...)
+		}
+		return items
+	case map[string]any:
+		items := []map[string]any{v}
+		if graph, ok := v["@graph"]; ok {
+			items = append(items, jsonLDItems(graph)...)
+		}
+		return items
+	}
+	return nil
+}
+
+// jsonString 读取 JSON-LD 字段的文本值，对象取 name 或 url，数组取第一个
+func jsonString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []any:
+		if len(v) > 0 {
+			return jsonString(v[0])
+		}
+	case map[string]any:
+		if name := jsonString(v["name"]); name != "" {
+			return name
+		}
+		return jsonString(v["url"])
+	}
+	return ""
+}
+
+func setDefault(m map[string]string, key, value string) {
+	if _, ok := m[key]; !ok && value != "" {
+		m[key] = value
+	}
+}
+
+func articleTitle(root *html.Node, meta, ld map[string]string) string {
+	if title := firstNonEmpty(meta["og:title"], meta["twitter:title"], ld["headline"]); title != "" {
+		return collapseSpace(title)
+	}
+
+	if n := findElement(root, atom.Title); n != nil {
+		title := collapseSpace(textContent(n))
+		// 去掉 " - 站点名" 形式的后缀，剩余部分过短时保留原标题
+		if loc := titleSeparators.FindAllStringIndex(title, -1); len(loc) > 0 {
+			if short := title[:loc[len(loc)-1][0]]; len(strings.Fields(short)) >= 3 || utf8.RuneCountInString(short) >= 8 {
+				title = short
+			}
+		}
+		if title != "" {
+			return title
+		}
+	}
+
+	if n := findElement(root, atom.H1); n != nil {
+		return collapseSpace(textContent(n))
+	}
+	return ""
+}
+
+func articleByline(root *html.Node, meta, ld map[string]string) string {
+	author := meta["article:author"]
+	if strings.HasPrefix(author, "http://") || strings.HasPrefix(author, "https://") {
+		author = ""
+	}
+	if byline := firstNonEmpty(meta["author"], author, meta["twitter:creator"], ld["author"]); byline != "" {
+		return collapseSpace(byline)
+	}
+
+	var byline string
+	walk(root, func(n *html.Node) bool {
+		if byline != "" {
+			return false
+		}
+		if attr(n, "rel") == "author" || attr(n, "itemprop") == "author" || bylineNames.MatchString(attr(n, "class")+" "+attr(n, "id")) {
+			if text := collapseSpace(textContent(n)); text != "" && utf8.RuneCountInString(text) < 100 {
+				byline = text
+				return false
+			}
+		}
+		return true
+	})
+	return byline
+}
+
+func articlePublished(root *html.Node, meta, ld map[string]string) string {
+	if published := firstNonEmpty(meta["article:published_time"], ld["datePublished"], meta["datepublished"],
+		meta["pubdate"], meta["publishdate"], meta["date"], meta["dc.date"], meta["dc.date.issued"]); published != "" {
+		return published
+	}
+	if n := findElement(root, atom.Time); n != nil {
+		return firstNonEmpty(attr(n, "datetime"), collapseSpace(textContent(n)))
+	}
+	return ""
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"January 2, 2006",
+	"Jan 2, 2006",
+}
+
+// formatDate 将可识别的日期格式化为 2006-01-02，无法识别时原样返回
+func formatDate(value string) string {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return value
+}
+
+// prepare 移除脚本、导航等非正文元素、隐藏元素与不太可能是正文的容器，并展开懒加载图片
+func prepare(body *html.Node) {
+	var remove []*html.Node
+	walk(body, func(n *html.Node) bool {
+		if removeElements[n.DataAtom] || isHidden(n) {
+			remove = append(remove, n)
+			return false
+		}
+		switch n.DataAtom {
+		case atom.Img:
+			fixLazyImage(n)
+			return false
+		case atom.Body, atom.Article, atom.Main, atom.A, atom.Table, atom.Tbody, atom.Tr, atom.Td, atom.Th, atom.Pre, atom.Code:
+			return true
+		}
+		if names := attr(n, "class") + " " + attr(n, "id"); unlikelyCandidates.MatchString(names) && !maybeCandidate.MatchString(names) || attr(n, "role") == "complementary" {
+			remove = append(remove, n)
+			return false
+		}
+		return true
+	})
+	for _, n := range remove {
+		n.Parent.RemoveChild(n)
+	}
+}
+
+func isHidden(n *html.Node) bool {
+	if _, ok := attrValue(n, "hidden"); ok || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", "")
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+// fixLazyImage 使用 data-src 等属性中的真实地址替换懒加载图片的占位地址
+func fixLazyImage(n *html.Node) {
+	src := attr(n, "src")
+	if src != "" && !strings.HasPrefix(src, "data:") {
+		return
+	}
+	for _, key := range []string{"data-src", "data-original", "data-lazy-src", "data-actualsrc", "data-url"} {
+		if v := strings.TrimSpace(attr(n, key)); v != "" {
+			setAttr(n, "src", v)
+			return
+		}
+	}
+	if srcset := firstNonEmpty(attr(n, "data-srcset"), attr(n, "srcset")); srcset != "" {
+		if fields := strings.Fields(strings.Split(srcset, ",")[0]); len(fields) > 0 {
+			setAttr(n, "src", fields[0])
+		}
+	}
+}
+
+// topCandidate 按段落文本长度与逗号数量为段落的父级与祖父级打分，返回按链接密度修正后得分最高的元素与各元素的得分
+func topCandidate(body *html.Node) (*html.Node, map[*html.Node]float64) {
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	initialize := func(n *html.Node) {
+		if _, ok := scores[n]; ok {
+			return
+		}
+		scores[n] = initialScore(n)
+		candidates = append(candidates, n)
+	}
+
+	walk(body, func(n *html.Node) bool {
+		switch n.DataAtom {
+		case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+		case atom.Div, atom.Section:
+			if hasBlockChild(n) {
+				return true
+			}
+		default:
+			return true
+		}
+
+		text := collapseSpace(textContent(n))
+		length := utf8.RuneCountInString(text)
+		if length < 25 || n.Parent == nil {
+			return false
+		}
+		score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，")+strings.Count(text, "、")) + min(float64(length)/100, 3)
+
+		parent := n.Parent
+		initialize(parent)
+		scores[parent] += score
+		if grand := parent.Parent; grand != nil && grand.Type == html.ElementNode {
+			initialize(grand)
+			scores[grand] += score / 2
+		}
+		return false
+	})
+
+	var (
+		top      *html.Node
+		topScore float64
+	)
+	for _, n := range candidates {
+		score := scores[n] * (1 - linkDensity(n))
+		scores[n] = score
+		if top == nil || score > topScore {
+			top, topScore = n, score
+		}
+	}
+	if top == nil {
+		return body, scores
+	}
+
+	// 得分最高的元素内容过少时，使用文本更多的父级
+	for top.Parent != nil && top.Parent.DataAtom != atom.Html && top.Parent.Type == html.ElementNode {
+		if scores[top.Parent] < topScore*0.75 || len(collapseSpace(textContent(top.Parent))) > 3*len(collapseSpace(textContent(top))) {
+			break
+		}
+		top = top.Parent
+	}
+	return top, scores
+}
+
+func initialScore(n *html.Node) float64 {
+	var score float64
+	switch n.DataAtom {
+	case atom.Div, atom.Article, atom.Main, atom.Section:
+		score = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score = 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		score = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score = -5
+	}
+	return score + classWeight(n)
+}
+
+func classWeight(n *html.Node) float64 {
+	var weight float64
+	for _, name := range []string{attr(n, "class"), attr(n, "id")} {
+		if name == "" {
+			continue
+		}
+		if negativeNames.MatchString(name) {
+			weight -= 25
+		}
+		if positiveNames.MatchString(name) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && blockElements[c.DataAtom] {
+			return true
+		}
+	}
+	return false
+}
+
+// linkDensity 链接文本占全部文本的比例
+func linkDensity(n *html.Node) float64 {
+	total := utf8.RuneCountInString(collapseSpace(textContent(n)))
+	if total == 0 {
+		return 0
+	}
+	var links int
+	walk(n, func(c *html.Node) bool {
+		if c.DataAtom == atom.A {
+			links += utf8.RuneCountInString(collapseSpace(textContent(c)))
+			return false
+		}
+		return true
+	})
+	return float64(links) / float64(total)
+}
+
+// collectSiblings 将得分最高的元素与内容相关的兄弟元素放入同一个容器
+func collectSiblings(top *html.Node, scores map[*html.Node]float64) *html.Node {
+	container := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	if top.Parent == nil || top.DataAtom == atom.Body {
+		moveChildren(container, top)
+		return container
+	}
+
+	threshold := max(10, scores[top]*0.2)
+	var siblings []*html.Node
+	for s := top.Parent.FirstChild; s != nil; s = s.NextSibling {
+		if s.Type != html.ElementNode {
+			continue
+		}
+		if s == top {
+			siblings = append(siblings, s)
+			continue
+		}
+		if score, ok := scores[s]; ok && score+classWeight(top)*0.2 >= threshold {
+			siblings = append(siblings, s)
+			continue
+		}
+		if s.DataAtom == atom.P {
+			text := collapseSpace(textContent(s))
+			length, density := utf8.RuneCountInString(text), linkDensity(s)
+			last, _ := utf8.DecodeLastRuneInString(text)
+			if length > 80 && density < 0.25 || length > 0 && length <= 80 && density == 0 && strings.ContainsRune(".。!?！？", last) {
+				siblings = append(siblings, s)
+			}
+		}
+	}
+	for _, s := range siblings {
+		s.Parent.RemoveChild(s)
+		container.AppendChild(s)
+	}
+	return container
+}
+
+func moveChildren(dst, src *html.Node) {
+	for c := src.FirstChild; c != nil; c = src.FirstChild {
+		src.RemoveChild(c)
+		dst.AppendChild(c)
+	}
+}
+
+// clean 移除正文中与标题重复的标题、链接密度过高或内容过少的容器
+func clean(container *html.Node, title string) {
+	var remove []*html.Node
+	walk(container, func(n *html.Node) bool {
+		if n == container {
+			return true
+		}
+		switch n.DataAtom {
+		case atom.H1, atom.H2:
+			if title != "" && collapseSpace(textContent(n)) == title {
+				remove = append(remove, n)
+			}
+			return false
+		case atom.Div, atom.Section, atom.Ul, atom.Ol, atom.Table, atom.Header:
+			if shouldRemove(n) {
+				remove = append(remove, n)
+				return false
+			}
+		}
+		return true
+	})
+	for _, n := range remove {
+		n.Parent.RemoveChild(n)
+	}
+}
+
+func shouldRemove(n *html.Node) bool {
+	weight := classWeight(n)
+	if weight < 0 {
+		return true
+	}
+
+	text := collapseSpace(textContent(n))
+	length := utf8.RuneCountInString(text)
+	density := linkDensity(n)
+	if density > 0.5 && weight < 25 || density > 0.2 && length < 80 && weight < 25 {
+		return true
+	}
+
+	var media bool
+	walk(n, func(c *html.Node) bool {
+		switch c.DataAtom {
+		case atom.Img, atom.Pre, atom.Video, atom.Picture, atom.Figure:
+			media = true
+		}
+		return !media
+	})
+	return length == 0 && !media
+}
+
+// absolutize 将链接与图片的相对地址转换为绝对地址
+func absolutize(container *html.Node, base *url.URL) {
+	walk(container, func(n *html.Node) bool {
+		switch n.DataAtom {
+		case atom.A:
+			if href := attr(n, "href"); href != "" && !strings.HasPrefix(href, "#") {
+				setAttr(n, "href", resolveURL(base, href))
+			}
+		case atom.Img:
+			if src := attr(n, "src"); src != "" && !strings.HasPrefix(src, "data:") {
+				setAttr(n, "src", resolveURL(base, src))
+			}
+		}
+		return true
+	})
+}
+
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || base == nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+func firstImage(n *html.Node) string {
+	var src string
+	walk(n, func(c *html.Node) bool {
+		if src == "" && c.DataAtom == atom.Img {
+			if v := attr(c, "src"); v != "" && !strings.HasPrefix(v, "data:") {
+				src = v
+			}
+		}
+		return src == ""
+	})
+	return src
+}
+
+// walk 深度优先遍历元素节点，f 返回 false 时不再遍历该元素的子节点
+func walk(n *html.Node, f func(n *html.Node) bool) {
+	if n.Type == html.ElementNode && !f(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; {
+		// f 可能移除或移动当前节点，提前记录下一个节点
+		next := c.NextSibling
+		walk(c, f)
+		c = next
+	}
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	var found *html.Node
+	walk(n, func(c *html.Node) bool {
+		if found == nil && c.DataAtom == a {
+			found = c
+		}
+		return found == nil
+	})
+	return found
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
+func attrValue(n *html.Node, key string) (string, bool) {
+	for _, v := range n.Attr {
+		if v.Key == key {
+			return v.Val, true
+		}
+	}
+	return "", false
+}
+
+func attr(n *html.Node, key string) string {
+	v, _ := attrValue(n, key)
+	return v
+}
+
+func setAttr(n *html.Node, key, value string) {
+	for i, v := range n.Attr {
+		if v.Key == key {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: value})
+}
+
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
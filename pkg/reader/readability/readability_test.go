@@ -0,0 +1,113 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testPage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Understanding Go Interfaces - Example Blog</title>
+	<meta property="og:image" content="/images/cover.png">
+	<meta property="og:site_name" content="Example Blog">
+	<meta name="description" content="A short tour of interfaces.">
+	<script type="application/ld+json">{"@context":"https://schema.org","@graph":[{"@type":"WebSite","name":"Example"},{"@type":"BlogPosting","headline":"Understanding Go Interfaces","author":{"@type":"Person","name":"Jane Doe"},"datePublished":"2024-03-05T10:00:00+08:00"}]}</script>
+</head>
+<body>
+	<header class="site-header"><nav><a href="/">Home</a><a href="/about">About</a></nav></header>
+	<div class="sidebar"><ul><li><a href="/a">Popular post one</a></li><li><a href="/b">Popular post two</a></li></ul></div>
+	<div id="main">
+		<article class="post">
+			<h1>Understanding Go Interfaces</h1>
+			<p>Interfaces in Go are satisfied implicitly, which means a type never declares that it implements an interface, it simply provides the methods.</p>
+			<p>This decoupling, combined with small interfaces, keeps packages independent and makes testing with fakes straightforward, even in large codebases.</p>
+			<img data-src="/images/diagram.png" src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" alt="diagram">
+			<pre><code class="language-go">type Reader interface {
+	Read(p []byte) (n int, err error)
+}</code></pre>
+			<p>Read more in the <a href="https://go.dev/doc/effective_go">Effective Go</a> guide, which covers interfaces, embedding and more.</p>
+			<div class="share-buttons"><a href="/share/x">Share on X</a> <a href="/share/fb">Share on Facebook</a></div>
+		</article>
+		<div class="comments"><p>Great article, thanks a lot for writing this, it helped me a lot, really.</p></div>
+	</div>
+	<footer>Copyright Example Blog</footer>
+</body>
+</html>`
+
+func TestParse(t *testing.T) {
+	pageURL, _ := url.Parse("https://blog.example.com/posts/go-interfaces")
+	article, err := Parse([]byte(testPage), pageURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if article.Title != "Understanding Go Interfaces" || article.Byline != "Jane Doe" || article.Published != "2024-03-05" ||
+		article.SiteName != "Example Blog" || article.Excerpt != "A short tour of interfaces." || article.Image != "https://blog.example.com/images/cover.png" {
+		t.Errorf("unexpected metadata: %+v", article)
+	}
+
+	for _, want := range []string{
+		"Interfaces in Go are satisfied implicitly",
+		"![diagram](https://blog.example.com/images/diagram.png)",
+		"```go\ntype Reader interface {",
+		"[Effective Go](https://go.dev/doc/effective_go)",
+	} {
+		if !strings.Contains(article.Content, want) {
+			t.Errorf("content missing %q:\n%s", want, article.Content)
+		}
+	}
+	for _, unwanted := range []string{"Popular post", "Share on", "Great article", "Copyright", "Home", "# Understanding"} {
+		if strings.Contains(article.Content, unwanted) {
+			t.Errorf("content should not contain %q:\n%s", unwanted, article.Content)
+		}
+	}
+
+	markdown := article.Markdown()
+	if !strings.HasPrefix(markdown, "# Understanding Go Interfaces\n\n*By Jane Doe · 2024-03-05 · Example Blog*\n\n![](https://blog.example.com/images/cover.png)\n\n") {
+		t.Errorf("unexpected markdown header:\n%s", markdown)
+	}
+}
+
+func TestParseTitleFallback(t *testing.T) {
+	page := `<html><head><title>A fairly long article title | Site</title><meta name="author" content="John"></head>
+<body><div><p>Some paragraph that is long enough to be treated as the article content, with a comma.</p></div></body></html>`
+	article, err := Parse([]byte(page), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Title != "A fairly long article title" || article.Byline != "John" || article.Image != "" {
+		t.Errorf("unexpected article: %+v", article)
+	}
+
+	if _, err = Parse([]byte(`<html><body><nav><a href="/">Home</a></nav></body></html>`), nil); !errors.Is(err, ErrNoContent) {
+		t.Errorf("expected ErrNoContent, got %v", err)
+	}
+}
+
+func TestReader(t *testing.T) {
+	r := NewReader()
+	for endpoint, want := range map[string]bool{
+		"https://example.com/a": true,
+		"http://example.com":    true,
+		"ftp://example.com":     false,
+		"example.com":           false,
+	} {
+		if got := r.Match(endpoint); got != want {
+			t.Errorf("Match(%q) = %v, want %v", endpoint, got, want)
+		}
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(testPage))
+	}))
+	defer srv.Close()
+	if _, err := r.Reader(context.Background(), srv.URL); !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("expected ErrPrivateAddress, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/quka-ai/quka-ai/pkg/ai"
+)
+
+const (
+	// MAX_PAGE_SIZE 读取网页的最大体积
+	MAX_PAGE_SIZE = 10 << 20
+	// USER_AGENT 部分站点会拒绝非浏览器的请求
+	USER_AGENT = "Mozilla/5.0 (compatible; QukaAI-Reader/1.0; +https://quka.ai)"
+)
+
+var (
+	ErrUnsupportedContent = errors.New("unsupported content type")
+	ErrPrivateAddress     = errors.New("refusing to read private network address")
+)
+
+// Reader 内置的网页读取器，在本地抓取网页并提取正文，作为未配置第三方 reader 时的兜底实现
+type Reader struct {
+	client *http.Client
+}
+
+// NewReader 创建网页读取器，出于安全考虑不会访问内网、回环与链路本地地址
+func NewReader() *Reader {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Reader{
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 10 {
+					return fmt.Errorf("stopped after 10 redirects")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+// Match 支持所有 http 与 https 地址
+func (r *Reader) Match(endpoint string) bool {
+	u, err := url.Parse(strings.TrimSpace(endpoint))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Reader 抓取网页并返回 markdown 格式的正文，纯文本与 markdown 页面原样返回
+func (r *Reader) Reader(ctx context.Context, endpoint string) (*ai.ReaderResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSpace(endpoint), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", USER_AGENT)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9,*/*;q=0.8")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch page, %s", resp.Status)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "" && mediaType != "text/html" && mediaType != "application/xhtml+xml" &&
+		mediaType != "text/plain" && mediaType != "text/markdown" {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContent, mediaType)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, MAX_PAGE_SIZE), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect page charset: %w", err)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+
+	pageURL := resp.Request.URL
+	if mediaType == "text/plain" || mediaType == "text/markdown" {
+		return &ai.ReaderResult{
+			Url:     pageURL.String(),
+			Content: strings.TrimSpace(string(data)),
+		}, nil
+	}
+
+	article, err := Parse(data, pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract page content: %w", err)
+	}
+	return &ai.ReaderResult{
+		Title:       article.Title,
+		Description: article.Excerpt,
+		Url:         pageURL.String(),
+		Content:     article.Markdown(),
+	}, nil
+}
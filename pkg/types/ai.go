@@ -106,6 +106,15 @@ func (t *ToolTips) Type() MessageType {
 	return MESSAGE_TYPE_TOOL_TIPS
 }
 
+// ToolMessageIDKey 工具调用消息ID在 context 中的 key，工具可据此更新该消息的扩展信息
+type ToolMessageIDKey struct{}
+
+// ToolMessageIDFromContext 获取当前工具调用对应的消息ID，不在工具调用中时返回空字符串
+func ToolMessageIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ToolMessageIDKey{}).(string)
+	return id
+}
+
 // AgentContext 包含了创建和使用 AI Agent 所需的所有上下文信息
 // 实现了 context.Context 接口，可以直接作为 context 使用
 type AgentContext struct {
@@ -6,8 +6,10 @@ import (
 )
 
 // Cache 接口定义了缓存操作的基本方法
+// Get 在 key 不存在或已过期时返回空字符串
 type Cache interface {
 	Get(ctx context.Context, key string) (string, error)
 	SetEx(ctx context.Context, key, value string, expiresAt time.Duration) error
 	Expire(ctx context.Context, key string, expiration time.Duration) error
+	Del(ctx context.Context, keys ...string) error
 }
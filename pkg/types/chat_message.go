@@ -152,11 +152,14 @@ type RAGDocs struct {
 }
 
 type PassageInfo struct {
-	ID       string `json:"id"`
-	Title    string `json:"title"`
-	Content  string `json:"content"`
-	Resource string `json:"resource"`
-	DateTime string `json:"date_time"`
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	Resource   string `json:"resource"`
+	DateTime   string `json:"date_time"`
+	SpaceID    string `json:"space_id,omitempty"`    // 跨空间检索时标记知识所属的空间
+	SpaceTitle string `json:"space_title,omitempty"` // 跨空间检索时标记知识所属空间的名称
+	Citation   int    `json:"citation,omitempty"`    // 引用编号，回答中使用 [^citation] 标记引用该知识
 }
 
 type Undo interface {
@@ -293,12 +296,15 @@ type MessageDetail struct {
 	Ext  *MessageExt  `json:"ext"`
 }
 type MessageExt struct {
-	IsRead           []string     `json:"is_read"`
-	RelDocs          []string     `json:"rel_docs"`
-	Evaluate         EvaluateType `json:"evaluate"`
-	ToolName         string       `json:"tool_name"`
-	ToolArgs         string       `json:"tool_args"`
-	IsEvaluateEnable bool         `json:"is_evaluate_enable"`
+	IsRead           []string              `json:"is_read"`
+	RelDocs          []string              `json:"rel_docs"`
+	Evaluate         EvaluateType          `json:"evaluate"`
+	ToolName         string                `json:"tool_name"`
+	ToolArgs         string                `json:"tool_args"`
+	QueryStrategy    string                `json:"query_strategy,omitempty"`
+	ExpandedQueries  []string              `json:"expanded_queries,omitempty"`
+	Citations        []ChatMessageCitation `json:"citations,omitempty"`
+	IsEvaluateEnable bool                  `json:"is_evaluate_enable"`
 }
 
 type StreamMessage struct {
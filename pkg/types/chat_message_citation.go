@@ -0,0 +1,50 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// ChatMessageCitation 回答中的一处引用，将引用标记所在的句子关联到具体的知识及分片
+type ChatMessageCitation struct {
+	Index       int      `json:"index"`        // 引用编号，对应回答中的 [^index] 标记
+	KnowledgeID string   `json:"knowledge_id"` // 引用的知识ID
+	ChunkIDs    []string `json:"chunk_ids"`    // 检索命中的分片ID
+	Title       string   `json:"title"`        // 知识标题
+	Sentence    string   `json:"sentence"`     // 引用标记所在的句子
+}
+
+// ChatMessageCitations 回答中的引用列表，按引用标记在回答中出现的顺序排列
+type ChatMessageCitations []ChatMessageCitation
+
+// Value implements the driver.Valuer interface.
+func (c ChatMessageCitations) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface.
+func (c *ChatMessageCitations) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return c.scanBytes(src)
+	case string:
+		return c.scanBytes([]byte(src))
+	case nil:
+		*c = nil
+		return nil
+	}
+
+	return fmt.Errorf("pq: cannot convert %T to ChatMessageCitations", src)
+}
+
+func (c *ChatMessageCitations) scanBytes(src []byte) error {
+	if len(src) == 0 {
+		*c = nil
+		return nil
+	}
+	return json.Unmarshal(src, c)
+}
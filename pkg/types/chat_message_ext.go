@@ -14,7 +14,10 @@ type ChatMessageExt struct {
 	ToolName         string               `json:"tool_name" db:"tool_name"`
 	ToolArgs         sql.NullString       `json:"tool_args" db:"tool_args"`
 	GenerationStatus GenerationStatusType `json:"-" db:"generation_status"`
-	RelDocs          pq.StringArray       `json:"rel_docs" db:"rel_docs"` // relevance docs
+	RelDocs          pq.StringArray       `json:"rel_docs" db:"rel_docs"`                 // relevance docs
+	QueryStrategy    string               `json:"query_strategy" db:"query_strategy"`     // 检索时使用的查询扩展策略
+	ExpandedQueries  pq.StringArray       `json:"expanded_queries" db:"expanded_queries"` // 扩展后用于检索的查询
+	Citations        ChatMessageCitations `json:"citations" db:"citations"`               // 回答中的引用标记及其指向的知识
 	CreatedAt        int64                `json:"-" db:"created_at"`
 	UpdatedAt        int64                `json:"-" db:"updated_at"`
 }
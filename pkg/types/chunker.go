@@ -0,0 +1,58 @@
+package types
+
+import "fmt"
+
+// 知识分片方式
+const (
+	CHUNKER_LLM   = "llm"   // 由对话模型分片，同时生成标题、标签与知识图谱（默认）
+	CHUNKER_LOCAL = "local" // 使用内置的 markdown 分片器，按 token 数切分，结果稳定且不消耗模型额度
+
+	DEFAULT_CHUNK_SIZE    = 512  // 本地分片默认的分片大小(token)
+	DEFAULT_CHUNK_OVERLAP = 64   // 本地分片默认的相邻分片重叠大小(token)
+	MAX_CHUNK_SIZE        = 8192 // 本地分片允许的最大分片大小(token)
+)
+
+// ChunkerConfig 知识分片配置，可设置在空间上，也可由长文本任务单独指定
+type ChunkerConfig struct {
+	Mode         string `json:"mode"`                    // 分片方式，为空时使用 CHUNKER_LLM
+	ChunkSize    int    `json:"chunk_size,omitempty"`    // 本地分片的分片大小(token)，为 0 时使用 DEFAULT_CHUNK_SIZE
+	ChunkOverlap int    `json:"chunk_overlap,omitempty"` // 本地分片相邻分片重叠的 token 数，为 0 时使用 DEFAULT_CHUNK_OVERLAP
+}
+
+// IsLocal 是否使用本地分片
+func (c ChunkerConfig) IsLocal() bool {
+	return c.Mode == CHUNKER_LOCAL
+}
+
+// Validate 校验分片方式与分片参数
+func (c ChunkerConfig) Validate() error {
+	switch c.Mode {
+	case "", CHUNKER_LLM, CHUNKER_LOCAL:
+	default:
+		return fmt.Errorf("unknown chunker mode: %s", c.Mode)
+	}
+	if c.ChunkSize < 0 || c.ChunkSize > MAX_CHUNK_SIZE {
+		return fmt.Errorf("chunk_size must be between 0 and %d", MAX_CHUNK_SIZE)
+	}
+	if c.ChunkOverlap < 0 {
+		return fmt.Errorf("chunk_overlap must not be negative")
+	}
+	if c.ChunkOverlap >= c.WithDefault().ChunkSize {
+		return fmt.Errorf("chunk_overlap must be less than chunk_size")
+	}
+	return nil
+}
+
+// WithDefault 返回补全默认值后的分片配置
+func (c ChunkerConfig) WithDefault() ChunkerConfig {
+	if c.Mode == "" {
+		c.Mode = CHUNKER_LLM
+	}
+	if c.ChunkSize == 0 {
+		c.ChunkSize = DEFAULT_CHUNK_SIZE
+	}
+	if c.ChunkOverlap == 0 {
+		c.ChunkOverlap = min(DEFAULT_CHUNK_OVERLAP, c.ChunkSize/4)
+	}
+	return c
+}
@@ -0,0 +1,30 @@
+package types
+
+import "testing"
+
+func TestChunkerConfig(t *testing.T) {
+	conf := SpaceSettings{}.ChunkerConfig()
+	if conf.IsLocal() || conf.ChunkSize != DEFAULT_CHUNK_SIZE || conf.ChunkOverlap != DEFAULT_CHUNK_OVERLAP {
+		t.Errorf("expected default config, got %+v", conf)
+	}
+
+	conf = SpaceSettings{Chunker: &ChunkerConfig{Mode: CHUNKER_LOCAL, ChunkSize: 100}}.ChunkerConfig()
+	if !conf.IsLocal() || conf.ChunkSize != 100 || conf.ChunkOverlap != 25 {
+		t.Errorf("expected local chunker with scaled overlap, got %+v", conf)
+	}
+}
+
+func TestChunkerConfigValidate(t *testing.T) {
+	if err := (ChunkerConfig{}).Validate(); err != nil {
+		t.Errorf("expected empty config to be valid, got %v", err)
+	}
+	if err := (ChunkerConfig{Mode: "grpc"}).Validate(); err == nil {
+		t.Error("expected unknown mode to be rejected")
+	}
+	if err := (ChunkerConfig{Mode: CHUNKER_LOCAL, ChunkSize: MAX_CHUNK_SIZE + 1}).Validate(); err == nil {
+		t.Error("expected oversized chunk to be rejected")
+	}
+	if err := (ChunkerConfig{Mode: CHUNKER_LOCAL, ChunkSize: 100, ChunkOverlap: 100}).Validate(); err == nil {
+		t.Error("expected overlap not less than chunk size to be rejected")
+	}
+}
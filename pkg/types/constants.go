@@ -24,6 +24,9 @@ const (
 	MODEL_TYPE_ENHANCE    = "enhance"
 	MODEL_TYPE_READER     = "reader" // 虚拟模型类型，用于标识Reader提供商
 	MODEL_TYPE_OCR        = "ocr"    // 虚拟模型类型，用于标识OCR提供商
+
+	// RERANK_MODEL_BUILTIN_BM25 内置的本地 BM25 重排，可作为 AI 使用配置中 rerank 的取值
+	RERANK_MODEL_BUILTIN_BM25 = "builtin-bm25"
 )
 
 // AI使用配置描述
@@ -27,6 +27,7 @@ type ContentTask struct {
 	AIFileID   string `json:"ai_file_id" db:"ai_file_id"` // AI 服务中对应该文件的id
 	Step       int    `json:"step" db:"step"`             // 任务的当前阶段，例如：1-待处理，2-处理中，3-已完成等
 	TaskType   string `json:"task_type" db:"task_type"`   // 任务类型，表示任务的目的或用途
+	Chunker    string `json:"chunker" db:"chunker"`       // 分片方式，为空时使用空间的分片配置
 	CreatedAt  int64  `json:"created_at" db:"created_at"` // 任务创建时间，时间戳格式
 	UpdatedAt  int64  `json:"updated_at" db:"updated_at"` // 任务创建时间，时间戳格式
 	RetryTimes int    `json:"retry_times" db:"retry_times"`
@@ -0,0 +1,82 @@
+package types
+
+// 导入来源
+const (
+	IMPORT_SOURCE_OBSIDIAN = "obsidian" // Obsidian vault 或 markdown 文件夹
+	IMPORT_SOURCE_NOTION   = "notion"   // Notion 的 Markdown & CSV 导出
+	IMPORT_SOURCE_BOOKMARK = "bookmark" // 浏览器书签 HTML 或 Pocket / Instapaper 等稍后读 CSV
+	IMPORT_SOURCE_BACKUP   = "backup"   // 空间导出的备份归档
+)
+
+// 导入任务状态
+const (
+	IMPORT_TASK_STATUS_RUNNING  = "running"
+	IMPORT_TASK_STATUS_FINISHED = "finished"
+	IMPORT_TASK_STATUS_FAILED   = "failed"
+)
+
+// 导入条目状态
+const (
+	IMPORT_ITEM_STATUS_SUCCESS = "success"
+	IMPORT_ITEM_STATUS_FAILED  = "failed"
+	IMPORT_ITEM_STATUS_SKIPPED = "skipped"
+)
+
+// 导入条目网页内容的抓取状态，仅书签导入且开启抓取时使用
+const (
+	IMPORT_FETCH_STATUS_NONE    = ""
+	IMPORT_FETCH_STATUS_PENDING = "pending"
+	IMPORT_FETCH_STATUS_SUCCESS = "success"
+	IMPORT_FETCH_STATUS_FAILED  = "failed"
+)
+
+// 导入时目录结构的映射方式
+const (
+	IMPORT_FOLDER_MODE_NONE     = ""         // 忽略目录结构
+	IMPORT_FOLDER_MODE_RESOURCE = "resource" // 顶层目录映射为 resource，子目录映射为标签
+	IMPORT_FOLDER_MODE_TAGS     = "tags"     // 各级目录均映射为标签
+)
+
+// IsValidImportFolderMode 校验目录映射方式
+func IsValidImportFolderMode(mode string) bool {
+	switch mode {
+	case IMPORT_FOLDER_MODE_NONE, IMPORT_FOLDER_MODE_RESOURCE, IMPORT_FOLDER_MODE_TAGS:
+		return true
+	}
+	return false
+}
+
+// ImportTask 从外部笔记工具导入知识的任务
+type ImportTask struct {
+	ID         string `json:"id" db:"id"`
+	SpaceID    string `json:"space_id" db:"space_id"`
+	UserID     string `json:"user_id" db:"user_id"`
+	Source     string `json:"source" db:"source"`           // 导入来源
+	FileName   string `json:"file_name" db:"file_name"`     // 上传的归档文件名
+	Resource   string `json:"resource" db:"resource"`       // 默认写入的 resource
+	FolderMode string `json:"folder_mode" db:"folder_mode"` // 目录结构的映射方式
+	Status     string `json:"status" db:"status"`           // 任务状态
+	Total      int64  `json:"total" db:"total"`             // 需要导入的条目总数
+	Processed  int64  `json:"processed" db:"processed"`     // 已处理数量（包含失败与跳过）
+	Failed     int64  `json:"failed" db:"failed"`           // 导入失败数量
+	Error      string `json:"error" db:"error"`             // 任务失败原因
+	CreatedAt  int64  `json:"created_at" db:"created_at"`
+	UpdatedAt  int64  `json:"updated_at" db:"updated_at"`
+	FinishedAt int64  `json:"finished_at" db:"finished_at"`
+}
+
+// ImportTaskItem 导入任务中单个文件/条目的处理结果
+type ImportTaskItem struct {
+	ID          string `json:"id" db:"id"`
+	TaskID      string `json:"task_id" db:"task_id"`
+	SpaceID     string `json:"space_id" db:"space_id"`
+	Path        string `json:"path" db:"path"`                 // 条目在归档内的路径
+	Title       string `json:"title" db:"title"`               // 导入后的知识标题
+	KnowledgeID string `json:"knowledge_id" db:"knowledge_id"` // 导入成功后创建的知识ID
+	Status      string `json:"status" db:"status"`             // 处理结果
+	Error       string `json:"error" db:"error"`               // 失败原因
+	URL         string `json:"url" db:"url"`                   // 书签导入时规范化后的网页地址，用于去重
+	FetchStatus string `json:"fetch_status" db:"fetch_status"` // 网页内容抓取状态
+	FetchError  string `json:"fetch_error" db:"fetch_error"`   // 网页内容抓取失败原因
+	CreatedAt   int64  `json:"created_at" db:"created_at"`
+}
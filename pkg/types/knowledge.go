@@ -71,6 +71,7 @@ const (
 	KNOWLEDGE_SOURCE_PODCAST  KnowledgeSource = "podcast" // 播客
 	KNOWLEDGE_SOURCE_MCP      KnowledgeSource = "mcp"     // MCP 工具
 	KNOWLEDGE_SOURCE_CHAT     KnowledgeSource = "chat"    // 聊天会话
+	KNOWLEDGE_SOURCE_IMPORT   KnowledgeSource = "import"  // 从外部笔记工具导入
 )
 
 func (s KnowledgeSource) String() string {
@@ -80,7 +81,7 @@ func (s KnowledgeSource) String() string {
 // IsValid 验证 source 是否是有效值
 func (s KnowledgeSource) IsValid() bool {
 	switch s {
-	case KNOWLEDGE_SOURCE_PLATFORM, KNOWLEDGE_SOURCE_RSS, KNOWLEDGE_SOURCE_PODCAST, KNOWLEDGE_SOURCE_MCP, KNOWLEDGE_SOURCE_CHAT:
+	case KNOWLEDGE_SOURCE_PLATFORM, KNOWLEDGE_SOURCE_RSS, KNOWLEDGE_SOURCE_PODCAST, KNOWLEDGE_SOURCE_MCP, KNOWLEDGE_SOURCE_CHAT, KNOWLEDGE_SOURCE_IMPORT:
 		return true
 	default:
 		return false
@@ -126,6 +127,12 @@ type KnowledgeLite struct {
 	SourceRef string         `json:"source_ref" db:"source_ref"`
 }
 
+// RelatedKnowledge 与指定知识语义相近的知识
+type RelatedKnowledge struct {
+	KnowledgeLite
+	Similarity float32 `json:"similarity"`
+}
+
 type KnowledgeResponse struct {
 	ID          string               `json:"id" db:"id"`
 	SpaceID     string               `json:"space_id" db:"space_id"`
@@ -144,6 +151,8 @@ type KnowledgeResponse struct {
 	IsExpired   bool                 `json:"is_expired,omitempty" db:"-"`
 	Source      string               `json:"source" db:"source"`
 	SourceRef   string               `json:"source_ref" db:"source_ref"`
+	DuplicateOf []string             `json:"duplicate_of,omitempty" db:"-"`        // 存在待处理重复关系的其他知识
+	DeletedAt   int64                `json:"deleted_at,omitempty" db:"deleted_at"` // 移入回收站的时间
 }
 
 type Knowledge struct {
@@ -166,6 +175,7 @@ type Knowledge struct {
 	RelDocID    string               `json:"rel_doc_id,omitempty" db:"rel_doc_id"` // 关联的文档任务ID，如果是用户直接录入则为空
 	Source      string               `json:"source" db:"source"`
 	SourceRef   string               `json:"source_ref" db:"source_ref"`
+	DeletedAt   int64                `json:"deleted_at" db:"deleted_at"` // 移入回收站的时间，0 表示未删除
 }
 
 type RawMessage = KnowledgeContent
@@ -260,15 +270,23 @@ type GetKnowledgeOptions struct {
 	Stage       KnowledgeStage
 	RetryTimes  int
 	Keywords    string
-	RelDocID    string // 关联的文档任务ID
-	Source      string // 来源类型过滤
-	SourceRef   string // 来源引用过滤
+	RelDocID    string   // 关联的文档任务ID
+	Source      string   // 来源类型过滤
+	SourceRef   string   // 来源引用过滤
+	Tags        []string // 标签过滤，命中任一标签即可
 	TimeRange   *struct {
 		St int64
 		Et int64
 	}
+	// MaybeDateRange 按 maybe_date 过滤，格式为 2006-01-02，包含 St 不包含 Et
+	MaybeDateRange *struct {
+		St string
+		Et string
+	}
 	IncludeExpired bool // 是否包含过期内容，默认false
 	ExpiredOnly    bool // 只返回过期内容
+	IncludeDeleted bool // 是否包含回收站中的内容，默认false
+	DeletedOnly    bool // 只返回回收站中的内容
 }
 
 func (opts GetKnowledgeOptions) Apply(query *sq.SelectBuilder) {
@@ -315,9 +333,20 @@ func (opts GetKnowledgeOptions) Apply(query *sq.SelectBuilder) {
 	if opts.SourceRef != "" {
 		*query = query.Where(sq.Eq{"source_ref": opts.SourceRef})
 	}
+	if len(opts.Tags) > 0 {
+		*query = query.Where(sq.Expr("tags && ?", pq.StringArray(opts.Tags)))
+	}
 	if opts.TimeRange != nil {
 		*query = query.Where(sq.And{sq.GtOrEq{"created_at": opts.TimeRange.St}, sq.LtOrEq{"created_at": opts.TimeRange.Et}})
 	}
+	if opts.MaybeDateRange != nil {
+		if opts.MaybeDateRange.St != "" {
+			*query = query.Where(sq.GtOrEq{"maybe_date": opts.MaybeDateRange.St})
+		}
+		if opts.MaybeDateRange.Et != "" {
+			*query = query.Where(sq.Lt{"maybe_date": opts.MaybeDateRange.Et})
+		}
+	}
 
 	// 过期检查逻辑（预计算方案，默认排除过期内容）
 	now := GetCurrentTimestamp()
@@ -335,6 +364,19 @@ func (opts GetKnowledgeOptions) Apply(query *sq.SelectBuilder) {
 		})
 	}
 	// 如果 IncludeExpired=true 且 ExpiredOnly=false，则不添加过期条件，返回所有内容
+
+	// 回收站检查逻辑，默认排除已删除内容
+	if opts.DeletedOnly {
+		*query = query.Where(sq.Gt{"deleted_at": 0})
+	} else if !opts.IncludeDeleted {
+		*query = query.Where(sq.Eq{"deleted_at": 0})
+	}
+}
+
+// KnowledgeTag 空间内的标签及使用该标签的知识数量
+type KnowledgeTag struct {
+	Tag   string `json:"tag" db:"tag"`
+	Count int64  `json:"count" db:"count"`
 }
 
 type ResourceQuery struct {
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// 近似重复知识的处理状态
+const (
+	KNOWLEDGE_DUPLICATE_STATUS_PENDING = "pending" // 待处理
+	KNOWLEDGE_DUPLICATE_STATUS_MERGED  = "merged"  // 已合并，重复的知识已被删除
+	KNOWLEDGE_DUPLICATE_STATUS_IGNORED = "ignored" // 已忽略，两条知识均保留
+)
+
+// 空间的重复检测模式
+const (
+	DUPLICATE_DETECTION_OFF   = "off"   // 不检测
+	DUPLICATE_DETECTION_FLAG  = "flag"  // 仅标记疑似重复（默认）
+	DUPLICATE_DETECTION_MERGE = "merge" // 自动合并到已存在的知识
+
+	// DEFAULT_DUPLICATE_THRESHOLD 判定为近似重复的默认相似度
+	DEFAULT_DUPLICATE_THRESHOLD float32 = 0.95
+)
+
+// DuplicateDetection 空间的近似重复检测配置
+type DuplicateDetection struct {
+	Mode      string  `json:"mode"`                // 检测模式，为空时使用 DUPLICATE_DETECTION_FLAG
+	Threshold float32 `json:"threshold,omitempty"` // 判定为重复的相似度，为 0 时使用 DEFAULT_DUPLICATE_THRESHOLD
+}
+
+// Validate 校验检测模式与相似度阈值
+func (d DuplicateDetection) Validate() error {
+	switch d.Mode {
+	case "", DUPLICATE_DETECTION_OFF, DUPLICATE_DETECTION_FLAG, DUPLICATE_DETECTION_MERGE:
+	default:
+		return fmt.Errorf("unknown duplicate detection mode: %s", d.Mode)
+	}
+	if d.Threshold < 0 || d.Threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1")
+	}
+	return nil
+}
+
+// KnowledgeDuplicate 新写入的知识与空间内已有知识的近似重复关系
+type KnowledgeDuplicate struct {
+	ID          string  `json:"id" db:"id"`
+	SpaceID     string  `json:"space_id" db:"space_id"`
+	KnowledgeID string  `json:"knowledge_id" db:"knowledge_id"` // 新写入的知识
+	DuplicateOf string  `json:"duplicate_of" db:"duplicate_of"` // 与之重复的已有知识
+	Similarity  float32 `json:"similarity" db:"similarity"`
+	Status      string  `json:"status" db:"status"`
+	CreatedAt   int64   `json:"created_at" db:"created_at"`
+	UpdatedAt   int64   `json:"updated_at" db:"updated_at"`
+}
+
+type ListKnowledgeDuplicateOptions struct {
+	SpaceID      string
+	Status       string
+	KnowledgeID  string   // 新写入的知识或已有知识任一方为该知识
+	DuplicateOfs []string // 按已有知识过滤
+}
+
+func (opts ListKnowledgeDuplicateOptions) Apply(query *sq.SelectBuilder) {
+	if opts.SpaceID != "" {
+		*query = query.Where(sq.Eq{"space_id": opts.SpaceID})
+	}
+	if opts.Status != "" {
+		*query = query.Where(sq.Eq{"status": opts.Status})
+	}
+	if opts.KnowledgeID != "" {
+		*query = query.Where(sq.Or{sq.Eq{"knowledge_id": opts.KnowledgeID}, sq.Eq{"duplicate_of": opts.KnowledgeID}})
+	}
+	if len(opts.DuplicateOfs) > 0 {
+		*query = query.Where(sq.Eq{"duplicate_of": opts.DuplicateOfs})
+	}
+}
+
+// KnowledgeDuplicateCluster 以已有知识为中心的一组疑似重复知识
+type KnowledgeDuplicateCluster struct {
+	Knowledge  *KnowledgeLite             `json:"knowledge"`
+	Duplicates []KnowledgeDuplicateMember `json:"duplicates"`
+}
+
+type KnowledgeDuplicateMember struct {
+	ID         string         `json:"id"` // 重复关系记录ID
+	Knowledge  *KnowledgeLite `json:"knowledge"`
+	Similarity float32        `json:"similarity"`
+	Status     string         `json:"status"`
+}
@@ -0,0 +1,27 @@
+package types
+
+import "testing"
+
+func TestDuplicateDetectionConfig(t *testing.T) {
+	conf := SpaceSettings{}.DuplicateDetectionConfig()
+	if conf.Mode != DUPLICATE_DETECTION_FLAG || conf.Threshold != DEFAULT_DUPLICATE_THRESHOLD {
+		t.Errorf("expected default config, got %+v", conf)
+	}
+
+	conf = SpaceSettings{DuplicateDetection: &DuplicateDetection{Mode: DUPLICATE_DETECTION_MERGE}}.DuplicateDetectionConfig()
+	if conf.Mode != DUPLICATE_DETECTION_MERGE || conf.Threshold != DEFAULT_DUPLICATE_THRESHOLD {
+		t.Errorf("expected merge mode with default threshold, got %+v", conf)
+	}
+}
+
+func TestDuplicateDetectionValidate(t *testing.T) {
+	if err := (DuplicateDetection{}).Validate(); err != nil {
+		t.Errorf("expected empty config to be valid, got %v", err)
+	}
+	if err := (DuplicateDetection{Mode: "auto"}).Validate(); err == nil {
+		t.Error("expected unknown mode to be rejected")
+	}
+	if err := (DuplicateDetection{Mode: DUPLICATE_DETECTION_FLAG, Threshold: 1.2}).Validate(); err == nil {
+		t.Error("expected invalid threshold to be rejected")
+	}
+}
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// KnowledgeEntity 空间内的命名实体，由知识总结阶段抽取
+type KnowledgeEntity struct {
+	ID        string `json:"id" db:"id"`
+	SpaceID   string `json:"space_id" db:"space_id"`
+	Name      string `json:"name" db:"name"`
+	NameKey   string `json:"-" db:"name_key"` // 归一化后的名称，空间内唯一
+	Type      string `json:"type" db:"type"`
+	CreatedAt int64  `json:"created_at" db:"created_at"`
+	UpdatedAt int64  `json:"updated_at" db:"updated_at"`
+}
+
+// KnowledgeEntityItem 实体及提及该实体的知识数量
+type KnowledgeEntityItem struct {
+	KnowledgeEntity
+	KnowledgeCount int64 `json:"knowledge_count" db:"knowledge_count"`
+}
+
+// KnowledgeEntityMention 知识与其提及的实体
+type KnowledgeEntityMention struct {
+	SpaceID     string `json:"space_id" db:"space_id"`
+	EntityID    string `json:"entity_id" db:"entity_id"`
+	KnowledgeID string `json:"knowledge_id" db:"knowledge_id"`
+	CreatedAt   int64  `json:"created_at" db:"created_at"`
+}
+
+// KnowledgeRelation 知识中抽取的实体关系
+type KnowledgeRelation struct {
+	ID          string `json:"id" db:"id"`
+	SpaceID     string `json:"space_id" db:"space_id"`
+	KnowledgeID string `json:"knowledge_id" db:"knowledge_id"` // 关系的来源知识
+	SourceID    string `json:"source_id" db:"source_id"`       // 主体实体
+	TargetID    string `json:"target_id" db:"target_id"`       // 客体实体
+	Relation    string `json:"relation" db:"relation"`
+	CreatedAt   int64  `json:"created_at" db:"created_at"`
+}
+
+// KnowledgeLink 知识内容中通过 [[knowledge-id]] 显式引用的其他知识
+type KnowledgeLink struct {
+	SpaceID     string `json:"space_id" db:"space_id"`
+	KnowledgeID string `json:"knowledge_id" db:"knowledge_id"` // 引用方
+	TargetID    string `json:"target_id" db:"target_id"`       // 被引用方
+	CreatedAt   int64  `json:"created_at" db:"created_at"`
+}
+
+type ListKnowledgeEntityOptions struct {
+	SpaceID     string
+	IDs         []string
+	Type        string
+	Keywords    string
+	KnowledgeID string // 被指定知识提及的实体
+}
+
+func (opts ListKnowledgeEntityOptions) Apply(query *sq.SelectBuilder) {
+	if opts.SpaceID != "" {
+		*query = query.Where(sq.Eq{"space_id": opts.SpaceID})
+	}
+	if len(opts.IDs) > 0 {
+		*query = query.Where(sq.Eq{"id": opts.IDs})
+	}
+	if opts.Type != "" {
+		*query = query.Where(sq.Eq{"type": opts.Type})
+	}
+	if opts.Keywords != "" {
+		*query = query.Where(sq.Like{"name_key": "%" + EntityNameKey(opts.Keywords) + "%"})
+	}
+	if opts.KnowledgeID != "" {
+		*query = query.Where(sq.Expr("id IN (SELECT entity_id FROM "+TABLE_KNOWLEDGE_ENTITY_MENTION.Name()+" WHERE knowledge_id = ?)", opts.KnowledgeID))
+	}
+}
+
+// EntityNameKey 归一化实体名称，用于合并大小写及空白不同的同名实体
+func EntityNameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// KnowledgeEntityDetail 实体详情，包含提及该实体的知识及相关的实体关系
+type KnowledgeEntityDetail struct {
+	Entity     KnowledgeEntityItem     `json:"entity"`
+	Knowledges []*KnowledgeLite        `json:"knowledges"`
+	Relations  []KnowledgeRelationItem `json:"relations"`
+}
+
+// KnowledgeRelationItem 带实体名称的实体关系
+type KnowledgeRelationItem struct {
+	KnowledgeRelation
+	SourceName string `json:"source_name"`
+	TargetName string `json:"target_name"`
+}
+
+// KnowledgeGraphLinks 知识的引用、反向引用及提及的实体
+type KnowledgeGraphLinks struct {
+	Links     []*KnowledgeLite      `json:"links"`
+	Backlinks []*KnowledgeLite      `json:"backlinks"`
+	Entities  []KnowledgeEntityItem `json:"entities"`
+}
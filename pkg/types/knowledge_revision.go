@@ -0,0 +1,67 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// 知识修订的来源
+const (
+	KNOWLEDGE_REVISION_SOURCE_ORIGINAL = "original" // 首次修改前的原始内容
+	KNOWLEDGE_REVISION_SOURCE_HTTP     = "http"     // 通过接口编辑
+	KNOWLEDGE_REVISION_SOURCE_AGENT    = "agent"    // 对话中 AI 调用知识工具修改
+	KNOWLEDGE_REVISION_SOURCE_MCP      = "mcp"      // 通过 MCP 工具修改
+	KNOWLEDGE_REVISION_SOURCE_RESTORE  = "restore"  // 恢复历史版本
+)
+
+const (
+	DEFAULT_KNOWLEDGE_REVISION_RETENTION = 50   // 每条知识默认保留的修订数量
+	MAX_KNOWLEDGE_REVISION_RETENTION     = 1000 // 每条知识允许保留的最大修订数量
+)
+
+// KnowledgeRevision 知识的历史版本，记录每次修改后的标题、标签与加密后的正文
+type KnowledgeRevision struct {
+	ID          string               `json:"id" db:"id"`
+	SpaceID     string               `json:"space_id" db:"space_id"`
+	KnowledgeID string               `json:"knowledge_id" db:"knowledge_id"`
+	Version     int64                `json:"version" db:"version"` // 知识内递增的版本号
+	Title       string               `json:"title" db:"title"`
+	Resource    string               `json:"resource" db:"resource"`
+	Tags        pq.StringArray       `json:"tags" db:"tags"`
+	Content     KnowledgeContent     `json:"content,omitempty" db:"content"`
+	ContentType KnowledgeContentType `json:"content_type" db:"content_type"`
+	UserID      string               `json:"user_id" db:"user_id"` // 修改者
+	Source      string               `json:"source" db:"source"`   // 修改来源
+	CreatedAt   int64                `json:"created_at" db:"created_at"`
+}
+
+// KnowledgeRevisionDiff 两个修订之间的差异，正文差异为 unified diff 格式
+type KnowledgeRevisionDiff struct {
+	From         *KnowledgeRevision `json:"from"`
+	To           *KnowledgeRevision `json:"to"`
+	TitleChanged bool               `json:"title_changed"`
+	TagsChanged  bool               `json:"tags_changed"`
+	Diff         string             `json:"diff"`
+}
+
+// RevisionConfig 知识修订的保留配置
+type RevisionConfig struct {
+	Retention int `json:"retention"` // 每条知识保留的修订数量，为 0 时使用 DEFAULT_KNOWLEDGE_REVISION_RETENTION
+}
+
+// Validate 校验修订保留数量
+func (c RevisionConfig) Validate() error {
+	if c.Retention < 0 || c.Retention > MAX_KNOWLEDGE_REVISION_RETENTION {
+		return fmt.Errorf("retention must be between 0 and %d", MAX_KNOWLEDGE_REVISION_RETENTION)
+	}
+	return nil
+}
+
+// WithDefault 返回补全默认值后的修订配置
+func (c RevisionConfig) WithDefault() RevisionConfig {
+	if c.Retention == 0 {
+		c.Retention = DEFAULT_KNOWLEDGE_REVISION_RETENTION
+	}
+	return c
+}
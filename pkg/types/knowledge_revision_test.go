@@ -0,0 +1,22 @@
+package types
+
+import "testing"
+
+func TestRevisionConfig(t *testing.T) {
+	if conf := (SpaceSettings{}).RevisionConfig(); conf.Retention != DEFAULT_KNOWLEDGE_REVISION_RETENTION {
+		t.Errorf("expected default retention, got %+v", conf)
+	}
+	if conf := (SpaceSettings{Revision: &RevisionConfig{Retention: 5}}).RevisionConfig(); conf.Retention != 5 {
+		t.Errorf("expected retention 5, got %+v", conf)
+	}
+
+	if err := (RevisionConfig{}).Validate(); err != nil {
+		t.Errorf("expected empty config to be valid, got %v", err)
+	}
+	if err := (RevisionConfig{Retention: -1}).Validate(); err == nil {
+		t.Error("expected negative retention to be rejected")
+	}
+	if err := (RevisionConfig{Retention: MAX_KNOWLEDGE_REVISION_RETENTION + 1}).Validate(); err == nil {
+		t.Error("expected oversized retention to be rejected")
+	}
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"github.com/lib/pq"
+
+	"github.com/quka-ai/quka-ai/pkg/utils"
+)
+
+// KnowledgeSearchSnippet 检索命中的知识片段
+type KnowledgeSearchSnippet struct {
+	ChunkID    string                 `json:"chunk_id"`
+	Text       string                 `json:"text"`
+	Highlights []utils.HighlightRange `json:"highlights"` // 检索词在 Text 中的位置，按字符(rune)计算
+}
+
+// KnowledgeSearchHit 检索命中的知识
+type KnowledgeSearchHit struct {
+	ID         string                   `json:"id"`
+	SpaceID    string                   `json:"space_id,omitempty"`    // 跨空间检索时标记知识所属的空间
+	SpaceTitle string                   `json:"space_title,omitempty"` // 跨空间检索时标记知识所属空间的名称
+	Title      string                   `json:"title"`
+	Kind       KnowledgeKind            `json:"kind"`
+	Resource   string                   `json:"resource"`
+	Source     string                   `json:"source"`
+	Tags       pq.StringArray           `json:"tags"`
+	MaybeDate  string                   `json:"maybe_date"`
+	CreatedAt  int64                    `json:"created_at"`
+	Score      float64                  `json:"score"`      // 相关度得分，混合检索时为 RRF 融合得分，否则为向量相似度
+	Similarity float32                  `json:"similarity"` // 命中分片的最高向量相似度
+	Snippets   []KnowledgeSearchSnippet `json:"snippets"`
+}
+
+// FacetCount 分面统计项
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// KnowledgeSearchFacets 检索结果在各过滤维度上的分布
+type KnowledgeSearchFacets struct {
+	Kind     []FacetCount `json:"kind"`
+	Source   []FacetCount `json:"source"`
+	Resource []FacetCount `json:"resource"`
+	Tags     []FacetCount `json:"tags"`
+}
+
+// SpaceSearchCount 跨空间检索时各空间命中的知识数量
+type SpaceSearchCount struct {
+	SpaceID string `json:"space_id"`
+	Title   string `json:"title"`
+	Count   int    `json:"count"`
+}
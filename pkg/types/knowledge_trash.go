@@ -0,0 +1,37 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DEFAULT_TRASH_RETENTION_DAYS = 30  // 回收站中的知识默认保留天数
+	MAX_TRASH_RETENTION_DAYS     = 365 // 回收站中的知识允许保留的最大天数
+)
+
+// TrashConfig 知识回收站配置
+type TrashConfig struct {
+	RetentionDays int `json:"retention_days"` // 移入回收站的知识保留天数，超过后被彻底删除，为 0 时使用 DEFAULT_TRASH_RETENTION_DAYS
+}
+
+// Validate 校验回收站保留天数
+func (c TrashConfig) Validate() error {
+	if c.RetentionDays < 0 || c.RetentionDays > MAX_TRASH_RETENTION_DAYS {
+		return fmt.Errorf("retention_days must be between 0 and %d", MAX_TRASH_RETENTION_DAYS)
+	}
+	return nil
+}
+
+// WithDefault 返回补全默认值后的回收站配置
+func (c TrashConfig) WithDefault() TrashConfig {
+	if c.RetentionDays == 0 {
+		c.RetentionDays = DEFAULT_TRASH_RETENTION_DAYS
+	}
+	return c
+}
+
+// PurgeBefore 删除时间早于返回值的知识已超过保留期，应被彻底删除
+func (c TrashConfig) PurgeBefore(now time.Time) int64 {
+	return now.AddDate(0, 0, -c.WithDefault().RetentionDays).Unix()
+}
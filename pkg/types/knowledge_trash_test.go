@@ -0,0 +1,51 @@
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func TestTrashConfig(t *testing.T) {
+	if conf := (SpaceSettings{}).TrashConfig(); conf.RetentionDays != DEFAULT_TRASH_RETENTION_DAYS {
+		t.Errorf("expected default retention days, got %+v", conf)
+	}
+
+	now := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	if before := (TrashConfig{RetentionDays: 7}).PurgeBefore(now); before != time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("unexpected purge deadline %d", before)
+	}
+
+	if err := (TrashConfig{}).Validate(); err != nil {
+		t.Errorf("expected empty config to be valid, got %v", err)
+	}
+	if err := (TrashConfig{RetentionDays: MAX_TRASH_RETENTION_DAYS + 1}).Validate(); err == nil {
+		t.Error("expected oversized retention to be rejected")
+	}
+}
+
+func TestGetKnowledgeOptionsDeleted(t *testing.T) {
+	tests := []struct {
+		opts GetKnowledgeOptions
+		want string
+	}{
+		{opts: GetKnowledgeOptions{IncludeExpired: true}, want: "WHERE deleted_at = ?"},
+		{opts: GetKnowledgeOptions{IncludeExpired: true, DeletedOnly: true}, want: "WHERE deleted_at > ?"},
+		{opts: GetKnowledgeOptions{IncludeExpired: true, IncludeDeleted: true}, want: ""},
+	}
+
+	for _, tt := range tests {
+		query := sq.Select("id").From("t")
+		tt.opts.Apply(&query)
+
+		sql, _, err := query.ToSql()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want == "" && strings.Contains(sql, "deleted_at") || tt.want != "" && !strings.HasSuffix(sql, tt.want) {
+			t.Errorf("unexpected sql %q for %+v", sql, tt.opts)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package types
+
+// 重新生成向量任务状态
+const (
+	REEMBED_JOB_STATUS_RUNNING  = "running"
+	REEMBED_JOB_STATUS_PAUSED   = "paused"
+	REEMBED_JOB_STATUS_CANCELED = "canceled"
+	REEMBED_JOB_STATUS_FINISHED = "finished"
+	REEMBED_JOB_STATUS_FAILED   = "failed"
+)
+
+// ReembedJob 切换 embedding 模型后，使用新模型重新生成全部知识向量的任务
+type ReembedJob struct {
+	ID         string `json:"id" db:"id"`
+	Model      string `json:"model" db:"model"`              // 目标 embedding 模型
+	Status     string `json:"status" db:"status"`            // 任务状态
+	Cursor     string `json:"cursor" db:"last_knowledge_id"` // 最后处理完成的 knowledge id，用于断点续跑
+	Total      int64  `json:"total" db:"total"`              // 需要处理的 knowledge 总数
+	Processed  int64  `json:"processed" db:"processed"`      // 已处理数量（包含失败与跳过）
+	Failed     int64  `json:"failed" db:"failed"`            // 处理失败数量
+	BatchSize  int    `json:"batch_size" db:"batch_size"`    // 每批处理的 knowledge 数量
+	Interval   int64  `json:"interval" db:"interval_ms"`     // 每批之间的间隔(毫秒)，用于限流
+	Error      string `json:"error" db:"error"`              // 任务失败原因
+	CreatedAt  int64  `json:"created_at" db:"created_at"`    // 创建时间
+	UpdatedAt  int64  `json:"updated_at" db:"updated_at"`    // 更新时间
+	FinishedAt int64  `json:"finished_at" db:"finished_at"`  // 完成时间
+}
+
+// IsActive 任务是否仍可继续执行
+func (j ReembedJob) IsActive() bool {
+	return j.Status == REEMBED_JOB_STATUS_RUNNING || j.Status == REEMBED_JOB_STATUS_PAUSED
+}
@@ -0,0 +1,151 @@
+package types
+
+import (
+	"fmt"
+)
+
+// 检索参数系统默认值的配置项，存储在 custom_config 中
+const (
+	RETRIEVAL_PROFILE_CATEGORY    = "retrieval"
+	RETRIEVAL_PROFILE_CONFIG_NAME = "retrieval_profile"
+	RETRIEVAL_PROFILE_CONFIG_DESC = "知识检索参数的系统默认值"
+)
+
+// 查询扩展策略
+const (
+	QUERY_EXPANSION_REWRITE     = "rewrite"     // 将原问题改写为一条增强后的检索语句
+	QUERY_EXPANSION_MULTI_QUERY = "multi_query" // 生成多个不同角度的检索词，分别检索后合并结果
+	QUERY_EXPANSION_HYDE        = "hyde"        // 生成假设性回答，使用回答内容进行检索
+)
+
+// RetrievalProfile 生效的知识检索参数，由 RetrievalProfileConfig 逐级补全得到：
+// 空间配置 -> 系统默认(custom_config) -> 内置默认值
+type RetrievalProfile struct {
+	CandidateLimit  int     `json:"candidate_limit"`   // 向量召回的候选数量
+	CosFloor        float32 `json:"cos_floor"`         // 候选的最低相似度
+	CosDropBelow    float32 `json:"cos_drop_below"`    // 候选较多且最高相似度低于该值时，改用最高相似度减去 CosDrop 作为相似度下限
+	CosDrop         float32 `json:"cos_drop"`          // 相似度下限相对最高相似度的差值
+	MaxRefs         int     `json:"max_refs"`          // 低于相似度下限后最多保留的引用数量
+	RerankMinScore  float64 `json:"rerank_min_score"`  // 重排首条得分低于该值时丢弃全部结果
+	RerankLowScore  float64 `json:"rerank_low_score"`  // 重排首条得分低于该值时使用 RerankLowDrop 截断
+	RerankDrop      float64 `json:"rerank_drop"`       // 保留与首条得分差距在该值以内的结果
+	RerankLowDrop   float64 `json:"rerank_low_drop"`   // 首条得分较低时保留的得分差距
+	MaxDocs         int     `json:"max_docs"`          // 提供给模型的参考文档数量上限，0 表示不限制
+	DocsTokenBudget int     `json:"docs_token_budget"` // 提供给模型的参考文档 token 预算，0 表示不限制
+	GraphExpansion  int     `json:"graph_expansion"`   // 通过引用及共同实体补充的关联知识数量，0 表示不启用
+	ChunkExpansion  int     `json:"chunk_expansion"`   // 长文分片命中后向前后各扩展的相邻分片数量，0 表示不启用
+	ChunkBudget     int     `json:"chunk_budget"`      // 相邻分片合并后单个段落的 token 预算
+	QueryExpansion  string  `json:"query_expansion"`   // 查询扩展策略，rewrite / multi_query / hyde
+}
+
+// DefaultRetrievalProfile 内置的检索参数默认值，与引入检索参数配置前的行为保持一致
+func DefaultRetrievalProfile() RetrievalProfile {
+	return RetrievalProfile{
+		CandidateLimit: 100,
+		CosFloor:       0.5,
+		CosDropBelow:   0.7,
+		CosDrop:        0.1,
+		MaxRefs:        20,
+		RerankMinScore: 0.3,
+		RerankLowScore: 0.5,
+		RerankDrop:     0.2,
+		RerankLowDrop:  0.05,
+		ChunkBudget:    4000,
+		QueryExpansion: QUERY_EXPANSION_REWRITE,
+	}
+}
+
+// RetrievalProfileConfig 空间或系统设置的检索参数，字段为 nil 时表示未设置，使用上一级配置，
+// 显式设置为 0 的字段会覆盖上一级配置
+type RetrievalProfileConfig struct {
+	CandidateLimit  *int     `json:"candidate_limit,omitempty"`
+	CosFloor        *float32 `json:"cos_floor,omitempty"`
+	CosDropBelow    *float32 `json:"cos_drop_below,omitempty"`
+	CosDrop         *float32 `json:"cos_drop,omitempty"`
+	MaxRefs         *int     `json:"max_refs,omitempty"`
+	RerankMinScore  *float64 `json:"rerank_min_score,omitempty"`
+	RerankLowScore  *float64 `json:"rerank_low_score,omitempty"`
+	RerankDrop      *float64 `json:"rerank_drop,omitempty"`
+	RerankLowDrop   *float64 `json:"rerank_low_drop,omitempty"`
+	MaxDocs         *int     `json:"max_docs,omitempty"`
+	DocsTokenBudget *int     `json:"docs_token_budget,omitempty"`
+	GraphExpansion  *int     `json:"graph_expansion,omitempty"`
+	ChunkExpansion  *int     `json:"chunk_expansion,omitempty"`
+	ChunkBudget     *int     `json:"chunk_budget,omitempty"`
+	QueryExpansion  string   `json:"query_expansion,omitempty"`
+}
+
+// IsEmpty 是否未设置任何参数
+func (c RetrievalProfileConfig) IsEmpty() bool {
+	return c == RetrievalProfileConfig{}
+}
+
+// Apply 使用已设置的字段覆盖 base 中的值
+func (c RetrievalProfileConfig) Apply(base RetrievalProfile) RetrievalProfile {
+	override(&base.CandidateLimit, c.CandidateLimit)
+	override(&base.CosFloor, c.CosFloor)
+	override(&base.CosDropBelow, c.CosDropBelow)
+	override(&base.CosDrop, c.CosDrop)
+	override(&base.MaxRefs, c.MaxRefs)
+	override(&base.RerankMinScore, c.RerankMinScore)
+	override(&base.RerankLowScore, c.RerankLowScore)
+	override(&base.RerankDrop, c.RerankDrop)
+	override(&base.RerankLowDrop, c.RerankLowDrop)
+	override(&base.MaxDocs, c.MaxDocs)
+	override(&base.DocsTokenBudget, c.DocsTokenBudget)
+	override(&base.GraphExpansion, c.GraphExpansion)
+	override(&base.ChunkExpansion, c.ChunkExpansion)
+	override(&base.ChunkBudget, c.ChunkBudget)
+	if c.QueryExpansion != "" {
+		base.QueryExpansion = c.QueryExpansion
+	}
+	return base
+}
+
+func override[T any](dst *T, v *T) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+func between[T int | float32 | float64](v *T, lower, upper T) bool {
+	return v == nil || (*v >= lower && *v <= upper)
+}
+
+// Validate 校验参数取值范围，未设置的字段不做校验
+func (c RetrievalProfileConfig) Validate() error {
+	switch {
+	case !between(c.CandidateLimit, 1, 500):
+		return fmt.Errorf("candidate_limit must be between 1 and 500")
+	case !between(c.CosFloor, 0, 1):
+		return fmt.Errorf("cos_floor must be between 0 and 1")
+	case !between(c.CosDropBelow, 0, 1):
+		return fmt.Errorf("cos_drop_below must be between 0 and 1")
+	case !between(c.CosDrop, 0, 1):
+		return fmt.Errorf("cos_drop must be between 0 and 1")
+	case c.MaxRefs != nil && *c.MaxRefs < 0:
+		return fmt.Errorf("max_refs must not be negative")
+	case !between(c.RerankMinScore, 0, 1):
+		return fmt.Errorf("rerank_min_score must be between 0 and 1")
+	case !between(c.RerankLowScore, 0, 1):
+		return fmt.Errorf("rerank_low_score must be between 0 and 1")
+	case !between(c.RerankDrop, 0, 1):
+		return fmt.Errorf("rerank_drop must be between 0 and 1")
+	case !between(c.RerankLowDrop, 0, 1):
+		return fmt.Errorf("rerank_low_drop must be between 0 and 1")
+	case !between(c.MaxDocs, 0, 100):
+		return fmt.Errorf("max_docs must be between 0 and 100")
+	case c.DocsTokenBudget != nil && *c.DocsTokenBudget < 0:
+		return fmt.Errorf("docs_token_budget must not be negative")
+	case !between(c.GraphExpansion, 0, 20):
+		return fmt.Errorf("graph_expansion must be between 0 and 20")
+	case !between(c.ChunkExpansion, 0, 10):
+		return fmt.Errorf("chunk_expansion must be between 0 and 10")
+	case c.ChunkBudget != nil && *c.ChunkBudget <= 0:
+		return fmt.Errorf("chunk_budget must be positive")
+	case c.QueryExpansion != "" && c.QueryExpansion != QUERY_EXPANSION_REWRITE &&
+		c.QueryExpansion != QUERY_EXPANSION_MULTI_QUERY && c.QueryExpansion != QUERY_EXPANSION_HYDE:
+		return fmt.Errorf("query_expansion must be one of rewrite, multi_query, hyde")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package types
+
+import "testing"
+
+func TestRetrievalProfileConfigApply(t *testing.T) {
+	var (
+		base           = DefaultRetrievalProfile()
+		candidateLimit = 50
+		cosFloor       = float32(0.3)
+		maxRefs        = 0
+	)
+
+	profile := RetrievalProfileConfig{
+		CandidateLimit: &candidateLimit,
+		CosFloor:       &cosFloor,
+		MaxRefs:        &maxRefs,
+	}.Apply(base)
+
+	if profile.CandidateLimit != 50 || profile.CosFloor != 0.3 {
+		t.Errorf("expected custom values to be kept, got %+v", profile)
+	}
+	// 显式设置为 0 的字段同样覆盖上一级配置
+	if profile.MaxRefs != 0 {
+		t.Errorf("expected explicit zero max_refs to override base, got %d", profile.MaxRefs)
+	}
+	if profile.CosDrop != base.CosDrop || profile.RerankMinScore != base.RerankMinScore {
+		t.Errorf("expected unset values to be filled from base, got %+v", profile)
+	}
+
+	if profile := (RetrievalProfileConfig{}).Apply(base); profile != base {
+		t.Errorf("expected empty config to keep base, got %+v", profile)
+	}
+	if !(RetrievalProfileConfig{}).IsEmpty() || (RetrievalProfileConfig{MaxRefs: &maxRefs}).IsEmpty() {
+		t.Error("unexpected IsEmpty result")
+	}
+}
+
+func TestRetrievalProfileConfigValidate(t *testing.T) {
+	value := func(v int) *int { return &v }
+	cos := func(v float32) *float32 { return &v }
+
+	if err := (RetrievalProfileConfig{}).Validate(); err != nil {
+		t.Errorf("expected empty config to be valid, got %v", err)
+	}
+	if err := (RetrievalProfileConfig{CosFloor: cos(1.5)}).Validate(); err == nil {
+		t.Error("expected invalid cos_floor to be rejected")
+	}
+	if err := (RetrievalProfileConfig{CandidateLimit: value(1000)}).Validate(); err == nil {
+		t.Error("expected invalid candidate_limit to be rejected")
+	}
+	if err := (RetrievalProfileConfig{CandidateLimit: value(0)}).Validate(); err == nil {
+		t.Error("expected zero candidate_limit to be rejected")
+	}
+	if err := (RetrievalProfileConfig{GraphExpansion: value(0)}).Validate(); err != nil {
+		t.Errorf("expected disabled graph_expansion to be valid, got %v", err)
+	}
+	if err := (RetrievalProfileConfig{GraphExpansion: value(50)}).Validate(); err == nil {
+		t.Error("expected invalid graph_expansion to be rejected")
+	}
+	if err := (RetrievalProfileConfig{ChunkExpansion: value(20)}).Validate(); err == nil {
+		t.Error("expected invalid chunk_expansion to be rejected")
+	}
+	if err := (RetrievalProfileConfig{QueryExpansion: QUERY_EXPANSION_HYDE}).Validate(); err != nil {
+		t.Errorf("expected hyde query_expansion to be valid, got %v", err)
+	}
+	if err := (RetrievalProfileConfig{QueryExpansion: "unknown"}).Validate(); err == nil {
+		t.Error("expected unknown query_expansion to be rejected")
+	}
+}
@@ -0,0 +1,23 @@
+package types
+
+// 空间导出任务状态
+const (
+	SPACE_EXPORT_STATUS_RUNNING  = "running"
+	SPACE_EXPORT_STATUS_FINISHED = "finished"
+	SPACE_EXPORT_STATUS_FAILED   = "failed"
+)
+
+// SpaceExport 将空间数据导出为备份归档的任务
+type SpaceExport struct {
+	ID          string `json:"id" db:"id"`
+	SpaceID     string `json:"space_id" db:"space_id"`
+	UserID      string `json:"user_id" db:"user_id"`
+	Status      string `json:"status" db:"status"`       // 任务状态
+	File        string `json:"file" db:"file"`           // 归档在对象存储中的路径
+	FileSize    int64  `json:"file_size" db:"file_size"` // 归档大小，单位为字节
+	Error       string `json:"error" db:"error"`         // 任务失败原因
+	CreatedAt   int64  `json:"created_at" db:"created_at"`
+	UpdatedAt   int64  `json:"updated_at" db:"updated_at"`
+	FinishedAt  int64  `json:"finished_at" db:"finished_at"`
+	DownloadURL string `json:"download_url,omitempty" db:"-"` // 归档的下载地址，仅在任务完成后返回
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// 空间检索模式
+const (
+	RETRIEVAL_MODE_VECTOR = "vector" // 仅向量检索（默认）
+	RETRIEVAL_MODE_HYBRID = "hybrid" // 向量检索 + 关键词检索，RRF 融合排序
+)
+
+// SpaceSettings 空间级配置，以 JSONB 形式存储在 quka_space.settings 中
+type SpaceSettings struct {
+	RetrievalMode      string                  `json:"retrieval_mode"`                // 检索模式，为空时使用 RETRIEVAL_MODE_VECTOR，混合检索会以明文存储关键词索引
+	RetrievalProfile   *RetrievalProfileConfig `json:"retrieval_profile,omitempty"`   // 检索参数，未设置的字段使用系统默认值
+	DuplicateDetection *DuplicateDetection     `json:"duplicate_detection,omitempty"` // 近似重复检测，未设置时仅标记疑似重复
+	Chunker            *ChunkerConfig          `json:"chunker,omitempty"`             // 知识分片方式，未设置时使用对话模型分片
+	Revision           *RevisionConfig         `json:"revision,omitempty"`            // 知识修订的保留数量，未设置时使用默认值
+	Trash              *TrashConfig            `json:"trash,omitempty"`               // 回收站保留天数，未设置时使用默认值
+	KnowledgeGraph     *bool                   `json:"knowledge_graph,omitempty"`     // 是否抽取知识图谱实体与关系，未设置时不抽取，实体名称与关系以明文存储
+}
+
+// IsHybridRetrieval 是否启用混合检索
+// 混合检索依赖由知识正文生成的全文检索词向量，该索引以明文存储，不受内容加密保护。
+// 仅开启混合检索的空间会写入词向量，关闭后已写入的词向量会被清除，
+// 开启前已向量化的知识需重新生成向量后才能被关键词检索命中
+func (s SpaceSettings) IsHybridRetrieval() bool {
+	return s.RetrievalMode == RETRIEVAL_MODE_HYBRID
+}
+
+// IsKnowledgeGraphEnabled 是否抽取知识图谱的实体与关系
+// 实体名称与关系取自知识正文，以明文存储以支持按名称检索，不受内容加密保护，因此需由空间显式开启。
+// 关闭后空间内已有的实体与关系会被清除，知识之间的 [[knowledge-id]] 引用不受影响
+func (s SpaceSettings) IsKnowledgeGraphEnabled() bool {
+	return s.KnowledgeGraph != nil && *s.KnowledgeGraph
+}
+
+// DuplicateDetectionConfig 返回补全默认值后的近似重复检测配置
+func (s SpaceSettings) DuplicateDetectionConfig() DuplicateDetection {
+	var conf DuplicateDetection
+	if s.DuplicateDetection != nil {
+		conf = *s.DuplicateDetection
+	}
+	if conf.Mode == "" {
+		conf.Mode = DUPLICATE_DETECTION_FLAG
+	}
+	if conf.Threshold == 0 {
+		conf.Threshold = DEFAULT_DUPLICATE_THRESHOLD
+	}
+	return conf
+}
+
+// ChunkerConfig 返回补全默认值后的知识分片配置
+func (s SpaceSettings) ChunkerConfig() ChunkerConfig {
+	var conf ChunkerConfig
+	if s.Chunker != nil {
+		conf = *s.Chunker
+	}
+	return conf.WithDefault()
+}
+
+// RevisionConfig 返回补全默认值后的知识修订配置
+func (s SpaceSettings) RevisionConfig() RevisionConfig {
+	var conf RevisionConfig
+	if s.Revision != nil {
+		conf = *s.Revision
+	}
+	return conf.WithDefault()
+}
+
+// TrashConfig 返回补全默认值后的回收站配置
+func (s SpaceSettings) TrashConfig() TrashConfig {
+	var conf TrashConfig
+	if s.Trash != nil {
+		conf = *s.Trash
+	}
+	return conf.WithDefault()
+}
+
+// Value implements the driver.Valuer interface.
+func (s SpaceSettings) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan implements the sql.Scanner interface.
+func (s *SpaceSettings) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return s.scanBytes(src)
+	case string:
+		return s.scanBytes([]byte(src))
+	case nil:
+		*s = SpaceSettings{}
+		return nil
+	}
+
+	return fmt.Errorf("pq: cannot convert %T to SpaceSettings", src)
+}
+
+func (s *SpaceSettings) scanBytes(src []byte) error {
+	if len(src) == 0 {
+		*s = SpaceSettings{}
+		return nil
+	}
+	return json.Unmarshal(src, s)
+}
@@ -43,4 +43,18 @@ const (
 	TABLE_RSS_USER_INTERESTS = TableName("rss_user_interests")
 	TABLE_RSS_DAILY_DIGESTS  = TableName("rss_daily_digests")
 	TABLE_PODCASTS           = TableName("podcasts")
+	TABLE_REEMBED_JOB        = TableName("reembed_job")
+
+	TABLE_KNOWLEDGE_DUPLICATE = TableName("knowledge_duplicate")
+
+	TABLE_KNOWLEDGE_ENTITY         = TableName("knowledge_entity")
+	TABLE_KNOWLEDGE_ENTITY_MENTION = TableName("knowledge_entity_mention")
+	TABLE_KNOWLEDGE_RELATION       = TableName("knowledge_relation")
+	TABLE_KNOWLEDGE_LINK           = TableName("knowledge_link")
+
+	TABLE_IMPORT_TASK      = TableName("import_task")
+	TABLE_IMPORT_TASK_ITEM = TableName("import_task_item")
+	TABLE_SPACE_EXPORT     = TableName("space_export")
+
+	TABLE_KNOWLEDGE_REVISION = TableName("knowledge_revision")
 )
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func TestTagsFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(query *sq.SelectBuilder)
+		want  string
+	}{
+		{
+			name: "knowledge",
+			apply: GetKnowledgeOptions{
+				SpaceID:        "space",
+				Tags:           []string{"go", "rag"},
+				IncludeExpired: true,
+				IncludeDeleted: true,
+			}.Apply,
+			want: "tags && ?",
+		},
+		{
+			name: "vector",
+			apply: GetVectorsOptions{
+				SpaceID: "space",
+				Tags:    []string{"go", "rag"},
+			}.Apply,
+			want: "knowledge_id IN (SELECT id FROM quka_knowledge WHERE tags && ?)",
+		},
+		{
+			name: "vector knowledge filter",
+			apply: GetVectorsOptions{
+				SpaceID: "space",
+				Knowledge: &GetKnowledgeOptions{
+					Source:         "rss",
+					IncludeExpired: true,
+					IncludeDeleted: true,
+				},
+			}.Apply,
+			want: "knowledge_id IN (SELECT id FROM quka_knowledge WHERE source = ?)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := sq.Select("id").From("t")
+			tt.apply(&query)
+
+			sql, args, err := query.ToSql()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("sql %q does not contain %q", sql, tt.want)
+			}
+			if len(args) != 2 {
+				t.Errorf("expected 2 args, got %d", len(args))
+			}
+		})
+	}
+}
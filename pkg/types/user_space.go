@@ -34,13 +34,14 @@ func (opts ListUserSpaceOptions) Apply(query *sq.SelectBuilder) {
 }
 
 type Space struct {
-	SpaceID     string `json:"space_id" db:"space_id"` // 空间ID
-	Title       string `json:"title" db:"title"`
-	Description string `json:"description" db:"description"`
-	BasePrompt  string `json:"base_prompt" db:"base_prompt"`
-	ChatPrompt  string `json:"chat_prompt" db:"chat_prompt"`
-	JoinLeaf    int64  `json:"join_leaf" db:"join_leaf"`
-	CreatedAt   int64  `json:"created_at" db:"created_at"` // 创建时间，存储为时间戳
+	SpaceID     string        `json:"space_id" db:"space_id"` // 空间ID
+	Title       string        `json:"title" db:"title"`
+	Description string        `json:"description" db:"description"`
+	BasePrompt  string        `json:"base_prompt" db:"base_prompt"`
+	ChatPrompt  string        `json:"chat_prompt" db:"chat_prompt"`
+	JoinLeaf    int64         `json:"join_leaf" db:"join_leaf"`
+	Settings    SpaceSettings `json:"settings" db:"settings"`     // 空间级配置
+	CreatedAt   int64         `json:"created_at" db:"created_at"` // 创建时间，存储为时间戳
 }
 
 type UserSpaceDetail struct {
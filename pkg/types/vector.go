@@ -1,7 +1,11 @@
 package types
 
 import (
+	"fmt"
+	"math"
+
 	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 	"github.com/pgvector/pgvector-go"
 )
 
@@ -12,7 +16,10 @@ type Vector struct {
 	Resource       string          `json:"resource" db:"resource"`               // 关联 knowledge resource
 	UserID         string          `json:"user_id" db:"user_id"`                 // 用户ID，用于标识向量所属用户
 	Embedding      pgvector.Vector `json:"embedding" db:"embedding"`             // 文本向量，存储经过编码后的文本向量表示
+	Model          string          `json:"model" db:"model"`                     // 生成该向量的 embedding 模型
+	Dimension      int             `json:"dimension" db:"dimension"`             // 向量维度
 	OriginalLength int             `json:"original_length" db:"original_length"` // 原文长度
+	Keywords       string          `json:"-" db:"-"`                             // 全文检索文本，由 utils.FTSDocument 生成
 	CreatedAt      int64           `json:"created_at" db:"created_at"`           // 创建时间，UNIX时间戳
 	UpdatedAt      int64           `json:"updated_at" db:"updated_at"`           // 更新时间，UNIX时间戳
 }
@@ -21,7 +28,9 @@ type QueryResult struct {
 	ID             string  `json:"id" db:"id"`
 	KnowledgeID    string  `json:"knowledge_id" db:"knowledge_id"`
 	Cos            float32 `json:"cos" db:"cos"`
+	Rank           float32 `json:"rank" db:"rank"` // 关键词检索得分，仅关键词检索命中时有值
 	OriginalLength int     `json:"original_length" db:"original_length"`
+	Score          float64 `json:"score" db:"-"` // 混合检索时的 RRF 融合得分
 }
 
 type GetVectorsOptions struct {
@@ -30,6 +39,12 @@ type GetVectorsOptions struct {
 	UserID      string
 	KnowledgeID string
 	Resource    *ResourceQuery
+	Model       string   // embedding 模型，向量检索时必须指定，避免混用不同模型生成的向量
+	Tags        []string // 按所属知识的标签过滤，命中任一标签即可
+	// Knowledge 按所属知识的属性过滤(类型、来源、时间等)
+	Knowledge *GetKnowledgeOptions
+	// ExcludeKnowledgeIDs 排除指定知识的向量
+	ExcludeKnowledgeIDs []string
 }
 
 func (opts GetVectorsOptions) Apply(query *sq.SelectBuilder) {
@@ -37,7 +52,7 @@ func (opts GetVectorsOptions) Apply(query *sq.SelectBuilder) {
 		*query = query.Where(sq.Eq{"id": opts.ID})
 	}
 	if opts.KnowledgeID != "" {
-		*query = query.Where(sq.Eq{"knowledge_id": opts.ID})
+		*query = query.Where(sq.Eq{"knowledge_id": opts.KnowledgeID})
 	}
 	if opts.SpaceID != "" {
 		*query = query.Where(sq.Eq{"space_id": opts.SpaceID})
@@ -48,4 +63,47 @@ func (opts GetVectorsOptions) Apply(query *sq.SelectBuilder) {
 	if opts.Resource != nil {
 		*query = query.Where(opts.Resource.ToQuery())
 	}
+	if opts.Model != "" {
+		*query = query.Where(sq.Eq{"model": opts.Model})
+	}
+	if len(opts.Tags) > 0 {
+		*query = query.Where(sq.Expr(fmt.Sprintf("knowledge_id IN (SELECT id FROM %s WHERE tags && ?)", TABLE_KNOWLEDGE.Name()), pq.StringArray(opts.Tags)))
+	}
+	if opts.Knowledge != nil {
+		sub := sq.Select("id").From(TABLE_KNOWLEDGE.Name())
+		opts.Knowledge.Apply(&sub)
+		*query = query.Where(sq.Expr("knowledge_id IN (?)", sub))
+	}
+	if len(opts.ExcludeKnowledgeIDs) > 0 {
+		*query = query.Where(sq.NotEq{"knowledge_id": opts.ExcludeKnowledgeIDs})
+	}
+}
+
+// MeanEmbedding 计算多个分片向量的归一化均值，用于以知识整体的语义进行检索
+func MeanEmbedding(vectors []Vector) pgvector.Vector {
+	var sum []float32
+	for _, v := range vectors {
+		embedding := v.Embedding.Slice()
+		if sum == nil {
+			sum = make([]float32, len(embedding))
+		}
+		if len(embedding) != len(sum) {
+			continue
+		}
+		for i, f := range embedding {
+			sum[i] += f
+		}
+	}
+
+	var norm float64
+	for _, f := range sum {
+		norm += float64(f) * float64(f)
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range sum {
+			sum[i] = float32(float64(sum[i]) / norm)
+		}
+	}
+	return pgvector.NewVector(sum)
 }
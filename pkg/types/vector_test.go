@@ -0,0 +1,26 @@
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pgvector/pgvector-go"
+)
+
+func TestMeanEmbedding(t *testing.T) {
+	result := MeanEmbedding([]Vector{
+		{Embedding: pgvector.NewVector([]float32{1, 0})},
+		{Embedding: pgvector.NewVector([]float32{0, 1})},
+		// 维度不一致的向量会被忽略
+		{Embedding: pgvector.NewVector([]float32{1, 1, 1})},
+	}).Slice()
+
+	want := float32(1 / math.Sqrt2)
+	if len(result) != 2 || math.Abs(float64(result[0]-want)) > 1e-6 || math.Abs(float64(result[1]-want)) > 1e-6 {
+		t.Errorf("unexpected mean embedding: %v", result)
+	}
+
+	if len(MeanEmbedding(nil).Slice()) != 0 {
+		t.Error("expected empty embedding without vectors")
+	}
+}
@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 全文检索分词
+// postgres 内置的解析器无法对中日韩文本进行分词，这里在写入和查询前统一在应用层完成切分：
+// 拉丁文等以空白/标点分隔的文字按单词切分并转为小写，中日韩文字按二元组(bigram)切分，
+// 之后以空格拼接交给 to_tsvector('simple', ...) / to_tsquery('simple', ...) 处理
+
+// isCJK 判断是否为需要按二元组切分的文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// FTSTerms 将文本切分为检索词，顺序与原文一致，可能包含重复词
+func FTSTerms(text string) []string {
+	var (
+		terms []string
+		word  []rune
+		cjk   []rune
+	)
+
+	flushWord := func() {
+		if len(word) > 0 {
+			terms = append(terms, string(word))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		switch len(cjk) {
+		case 0:
+		case 1:
+			terms = append(terms, string(cjk))
+		default:
+			for i := 0; i < len(cjk)-1; i++ {
+				terms = append(terms, string(cjk[i:i+2]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, unicode.ToLower(r))
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return terms
+}
+
+// FTSDocument 生成用于 to_tsvector('simple', ?) 的文本
+func FTSDocument(text string) string {
+	return strings.Join(FTSTerms(text), " ")
+}
+
+// FTSQuery 生成用于 to_tsquery('simple', ?) 的查询表达式，各检索词之间为 OR 关系，由 rank 决定相关度
+// 没有可用检索词时返回空字符串
+func FTSQuery(text string) string {
+	var (
+		terms = FTSTerms(text)
+		exist = make(map[string]struct{}, len(terms))
+		items = make([]string, 0, len(terms))
+	)
+	for _, v := range terms {
+		if _, ok := exist[v]; ok {
+			continue
+		}
+		exist[v] = struct{}{}
+		// 检索词只包含字母与数字，使用单引号包裹避免与 tsquery 运算符冲突
+		items = append(items, "'"+v+"'")
+	}
+	return strings.Join(items, " | ")
+}
+
+// HighlightRange 高亮区间，Start/End 为字符(rune)偏移，左闭右开
+type HighlightRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// HighlightSnippet 从文本中截取包含检索词最多的片段，并返回片段内检索词出现的位置
+// 检索词按 FTSTerms 的规则生成，匹配时忽略大小写，相互重叠的命中区间会被合并
+// 没有命中时返回文本开头的片段
+func HighlightSnippet(text string, terms []string, size int) (string, []HighlightRange) {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// 个别字符转小写后长度发生变化，无法保证偏移一致，退化为大小写敏感匹配
+		lower = runes
+	}
+
+	var matches []HighlightRange
+	for _, term := range terms {
+		t := []rune(term)
+		if len(t) == 0 {
+			continue
+		}
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if string(lower[i:i+len(t)]) == term {
+				matches = append(matches, HighlightRange{Start: i, End: i + len(t)})
+			}
+		}
+	}
+	matches = mergeHighlightRanges(matches)
+
+	if size <= 0 || size > len(runes) {
+		size = len(runes)
+	}
+
+	// 滑动窗口，选择覆盖命中数最多的起点
+	start, best := 0, 0
+	for i, m := range matches {
+		winStart := max(0, min(m.Start-size/4, len(runes)-size))
+		count := 0
+		for _, n := range matches[i:] {
+			if n.End > winStart+size {
+				break
+			}
+			count++
+		}
+		if count > best {
+			start, best = winStart, count
+		}
+	}
+	end := start + size
+
+	var highlights []HighlightRange
+	for _, m := range matches {
+		if m.Start < start || m.End > end {
+			continue
+		}
+		highlights = append(highlights, HighlightRange{Start: m.Start - start, End: m.End - start})
+	}
+	return string(runes[start:end]), highlights
+}
+
+func mergeHighlightRanges(ranges []HighlightRange) []HighlightRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+
+	result := []HighlightRange{ranges[0]}
+	for _, v := range ranges[1:] {
+		last := &result[len(result)-1]
+		if v.Start <= last.End {
+			last.End = max(last.End, v.End)
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFTSTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "英文按单词切分并转小写",
+			text: "Hello, World! PostgreSQL 16",
+			want: []string{"hello", "world", "postgresql", "16"},
+		},
+		{
+			name: "中文按二元组切分",
+			text: "知识库检索",
+			want: []string{"知识", "识库", "库检", "检索"},
+		},
+		{
+			name: "中英文混合",
+			text: "使用pgvector做向量检索",
+			want: []string{"使用", "pgvector", "做向", "向量", "量检", "检索"},
+		},
+		{
+			name: "单个汉字",
+			text: "我 和 你",
+			want: []string{"我", "和", "你"},
+		},
+		{
+			name: "空文本",
+			text: " ,.!",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FTSTerms(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FTSTerms(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFTSQuery(t *testing.T) {
+	if got := FTSQuery("向量 向量 Go"); got != "'向量' | 'go'" {
+		t.Errorf("unexpected query: %s", got)
+	}
+
+	if got := FTSQuery("???"); got != "" {
+		t.Errorf("expected empty query, got: %s", got)
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	snippet, highlights := HighlightSnippet("Quka 支持混合检索与向量检索", FTSTerms("向量检索"), 0)
+	if snippet != "Quka 支持混合检索与向量检索" {
+		t.Fatalf("unexpected snippet: %s", snippet)
+	}
+	// "向量" "量检" "检索" 三个二元组合并后为 [12,16)，"检索" 还命中 [9,11)
+	want := []HighlightRange{{Start: 9, End: 11}, {Start: 12, End: 16}}
+	if !reflect.DeepEqual(highlights, want) {
+		t.Errorf("highlights = %v, want %v", highlights, want)
+	}
+
+	text := strings.Repeat("a ", 50) + "Go is fast" + strings.Repeat(" b", 50)
+	snippet, highlights = HighlightSnippet(text, []string{"go"}, 20)
+	if len([]rune(snippet)) != 20 || len(highlights) != 1 {
+		t.Fatalf("unexpected snippet %q highlights %v", snippet, highlights)
+	}
+	if got := string([]rune(snippet)[highlights[0].Start:highlights[0].End]); got != "Go" {
+		t.Errorf("highlight text = %q, want Go", got)
+	}
+
+	if snippet, highlights = HighlightSnippet("no match here", []string{"go"}, 5); snippet != "no ma" || highlights != nil {
+		t.Errorf("unexpected result %q %v", snippet, highlights)
+	}
+}
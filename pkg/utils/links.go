@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"regexp"
+
+	"github.com/samber/lo"
+)
+
+// knowledgeLinkRegexp 匹配 [[knowledge-id]] 或 [[knowledge-id|显示文本]] 形式的知识引用
+var knowledgeLinkRegexp = regexp.MustCompile(`\[\[([A-Za-z0-9_-]{1,64})(?:\|[^\[\]\n]*)?\]\]`)
+
+// ParseKnowledgeLinks 解析内容中引用的知识ID，按首次出现的顺序去重返回
+func ParseKnowledgeLinks(content string) []string {
+	var ids []string
+	for _, match := range knowledgeLinkRegexp.FindAllStringSubmatch(content, -1) {
+		ids = append(ids, match[1])
+	}
+	return lo.Uniq(ids)
+}
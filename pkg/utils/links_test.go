@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKnowledgeLinks(t *testing.T) {
+	content := "参考 [[123456]] 以及 [[abc-def|另一篇笔记]]，重复引用 [[123456]]。\n" +
+		"[[not a link]] [[]] [link](https://example.com)"
+
+	got := ParseKnowledgeLinks(content)
+	want := []string{"123456", "abc-def"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseKnowledgeLinks() = %v, want %v", got, want)
+	}
+
+	if got := ParseKnowledgeLinks("no links"); len(got) != 0 {
+		t.Errorf("expected no links, got %v", got)
+	}
+}
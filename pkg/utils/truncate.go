@@ -186,3 +186,17 @@ func adjustToSentenceBoundary(content string) string {
 
 	return content
 }
+
+// EstimateTokens 粗略估算文本的 token 数量，不依赖具体模型的分词器
+// 中日韩文字按每字 1 个 token 计算，其他字符按每 4 个字符 1 个 token 计算
+func EstimateTokens(content string) int {
+	var cjk, other int
+	for _, r := range content {
+		if isCJK(r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
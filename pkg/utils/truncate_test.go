@@ -305,3 +305,24 @@ func TestHasIncompleteMedia(t *testing.T) {
 		})
 	}
 }
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected int
+	}{
+		{name: "空文本", content: "", expected: 0},
+		{name: "中文", content: "知识检索", expected: 4},
+		{name: "英文", content: "hello world", expected: 3},
+		{name: "中英混合", content: "使用pgvector", expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := EstimateTokens(tt.content); result != tt.expected {
+				t.Errorf("EstimateTokens() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}